@@ -0,0 +1,239 @@
+package cmd
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/dropalltables/cdp/internal/api"
+	"github.com/dropalltables/cdp/internal/config"
+	"github.com/dropalltables/cdp/internal/ui"
+	"github.com/spf13/cobra"
+)
+
+// activityPollInterval is how often --follow checks for new events
+const activityPollInterval = 5 * time.Second
+
+var (
+	// Flags for activity command
+	activityAllFlag    bool
+	activityFollowFlag bool
+	activityLimitFlag  int
+)
+
+var activityCmd = &cobra.Command{
+	Use:   "activity",
+	Short: "Show recent activity",
+	Long: `Show a timeline of recent activity, newest first.
+
+The feed is built from deployment history (including previews, rollbacks,
+and restarts, which Coolify queues as deployments) and application
+configuration changes. By default it covers the linked application; use
+--all for every application on the instance.`,
+	RunE: runActivity,
+}
+
+func init() {
+	rootCmd.AddCommand(activityCmd)
+
+	activityCmd.Flags().BoolVar(&activityAllFlag, "all", false, "Show activity for every application")
+	activityCmd.Flags().BoolVarP(&activityFollowFlag, "follow", "f", false, "Keep watching for new activity")
+	activityCmd.Flags().IntVar(&activityLimitFlag, "limit", 20, "Maximum number of events to show")
+}
+
+// activityEvent is a single entry in the activity feed
+type activityEvent struct {
+	key     string // identifies the event so --follow prints it once
+	time    time.Time
+	app     string
+	kind    string
+	message string
+	status  string
+}
+
+func runActivity(cmd *cobra.Command, args []string) error {
+	if err := checkLogin(); err != nil {
+		return err
+	}
+
+	globalCfg, err := config.LoadGlobal()
+	if err != nil {
+		return fmt.Errorf("failed to load config: %w", err)
+	}
+	client := api.NewClient(globalCfg.CoolifyURL, globalCfg.CoolifyToken)
+
+	appUUID := ""
+	if !activityAllFlag {
+		projectCfg, err := config.LoadProject()
+		if err != nil || projectCfg == nil || projectCfg.AppUUID == "" {
+			ui.Error("No linked application")
+			ui.Dim("Run inside a linked project, or use --all for the whole instance")
+			return fmt.Errorf("not linked to a project")
+		}
+		appUUID = projectCfg.AppUUID
+	}
+
+	var events []activityEvent
+	err = ui.RunTasks([]ui.Task{
+		{
+			Name:         "fetch-activity",
+			ActiveName:   "Fetching activity...",
+			CompleteName: "Fetched activity",
+			Action: func() error {
+				var err error
+				events, err = collectActivity(client, appUUID)
+				return err
+			},
+		},
+	})
+	if err != nil {
+		ui.Error("Failed to fetch activity")
+		return fmt.Errorf("failed to fetch activity: %w", err)
+	}
+
+	seen := map[string]bool{}
+	for _, e := range events {
+		seen[e.key] = true
+	}
+
+	shown := events
+	if activityLimitFlag > 0 && len(shown) > activityLimitFlag {
+		shown = shown[:activityLimitFlag]
+	}
+
+	if len(shown) == 0 {
+		ui.Warning("No activity found")
+	} else {
+		rows := [][]string{}
+		for _, e := range shown {
+			rows = append(rows, activityRow(e))
+		}
+		ui.Spacer()
+		ui.Table([]string{"Time", "Application", "Event", "Details", "Status"}, rows)
+	}
+
+	if !activityFollowFlag {
+		return nil
+	}
+
+	ui.Spacer()
+	ui.Dim("Following new activity (Ctrl+C to stop)...")
+	for {
+		time.Sleep(activityPollInterval)
+
+		latest, err := collectActivity(client, appUUID)
+		if err != nil {
+			continue // Transient API errors should not end the stream
+		}
+
+		// Print oldest first so the stream reads top to bottom
+		for i := len(latest) - 1; i >= 0; i-- {
+			e := latest[i]
+			if seen[e.key] {
+				continue
+			}
+			seen[e.key] = true
+			fmt.Println(strings.Join(activityRow(e), "  "))
+		}
+	}
+}
+
+// collectActivity gathers events for one application, or all when appUUID is empty.
+// Events are returned newest first.
+func collectActivity(client *api.Client, appUUID string) ([]activityEvent, error) {
+	var apps []api.Application
+	if appUUID != "" {
+		app, err := client.GetApplication(appUUID)
+		if err != nil {
+			return nil, err
+		}
+		apps = []api.Application{*app}
+	} else {
+		var err error
+		apps, err = client.ListApplications()
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	var events []activityEvent
+	for _, app := range apps {
+		deployments, err := client.ListDeploymentHistory(app.UUID)
+		if err != nil {
+			if appUUID != "" {
+				return nil, err
+			}
+			continue // Skip apps we cannot read when listing the whole instance
+		}
+		for _, d := range deployments {
+			if e, ok := deploymentEvent(app, d); ok {
+				events = append(events, e)
+			}
+		}
+
+		if t, err := parseTimestamp(app.UpdatedAt); err == nil {
+			events = append(events, activityEvent{
+				key:     "config:" + app.UUID + ":" + app.UpdatedAt,
+				time:    t,
+				app:     app.Name,
+				kind:    "config",
+				message: "Configuration updated",
+			})
+		}
+	}
+
+	sort.SliceStable(events, func(i, j int) bool {
+		return events[i].time.After(events[j].time)
+	})
+	return events, nil
+}
+
+func deploymentEvent(app api.Application, d api.Deployment) (activityEvent, bool) {
+	t, err := parseTimestamp(d.UpdatedAt)
+	if err != nil {
+		if t, err = parseTimestamp(d.CreatedAt); err != nil {
+			return activityEvent{}, false
+		}
+	}
+
+	kind := "deploy"
+	switch {
+	case d.RollbackToUUID != "":
+		kind = "rollback"
+	case deploymentPR(d) > 0:
+		kind = fmt.Sprintf("preview #%d", deploymentPR(d))
+	}
+
+	message := firstLine(d.CommitMessage)
+	if commit := deploymentCommit(d); commit != "" {
+		if len(commit) > 7 {
+			commit = commit[:7]
+		}
+		message = strings.TrimSpace(commit + " " + message)
+	}
+	if len(message) > 50 {
+		message = message[:50] + "..."
+	}
+
+	return activityEvent{
+		// Include status so a deployment reappears when it finishes
+		key:     "deploy:" + d.DeploymentUUID + ":" + d.Status,
+		time:    t,
+		app:     app.Name,
+		kind:    kind,
+		message: message,
+		status:  d.Status,
+	}, true
+}
+
+func activityRow(e activityEvent) []string {
+	message, status := e.message, e.status
+	if message == "" {
+		message = "-"
+	}
+	if status == "" {
+		status = "-"
+	}
+	return []string{e.time.Local().Format("2006-01-02 15:04:05"), e.app, e.kind, message, status}
+}
@@ -0,0 +1,143 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/dropalltables/cdp/internal/config"
+	"github.com/dropalltables/cdp/internal/deploy"
+	"github.com/dropalltables/cdp/internal/ui"
+	"github.com/spf13/cobra"
+)
+
+var (
+	// Flags for apply command
+	applyDryRunFlag bool
+)
+
+var applyCmd = &cobra.Command{
+	Use:   "apply",
+	Short: "Apply app settings from cdp.json to Coolify",
+	Long: `Compare the app settings declared in cdp.json with the linked application
+and update the ones that differ: build and start commands, port, domain,
+branch, build pack, health check, resource limits, and build caching. The
+changes take effect on the next deploy.
+
+Settings left out of cdp.json are not touched. Use --dry-run to see the plan
+without changing anything. Scheduled tasks are applied separately with
+'cron sync'.`,
+	Example: `  cdp apply --dry-run
+  cdp apply`,
+	Args: cobra.NoArgs,
+	RunE: runApply,
+}
+
+func init() {
+	rootCmd.AddCommand(applyCmd)
+
+	applyCmd.Flags().BoolVar(&applyDryRunFlag, "dry-run", false, "Show the changes without applying them")
+}
+
+func runApply(cmd *cobra.Command, args []string) error {
+	appUUID, client, err := getAppUUID()
+	if err != nil {
+		return err
+	}
+
+	projectCfg, err := config.LoadProject()
+	if err != nil {
+		return fmt.Errorf("failed to load project config: %w", err)
+	}
+	projectCfg.AppUUID = appUUID
+
+	var changes []deploy.SettingChange
+	err = ui.RunTasks([]ui.Task{
+		{
+			Name:         "plan-settings",
+			ActiveName:   "Comparing settings with Coolify...",
+			CompleteName: "Compared settings with Coolify",
+			Action: func() error {
+				app, err := client.GetApplicationSettings(appUUID)
+				if err != nil {
+					return err
+				}
+				changes = deploy.PlanAppSettings(projectCfg, app)
+				return nil
+			},
+		},
+	})
+	if err != nil {
+		ui.Error("Failed to fetch application settings")
+		return err
+	}
+
+	if len(changes) == 0 && projectCfg.BuildCache == nil {
+		ui.Success("Coolify matches cdp.json")
+		return nil
+	}
+
+	ui.Spacer()
+	if len(changes) > 0 {
+		remote, local := settingValues(changes)
+		ui.DiffKeyValues(remote, local)
+	}
+	if projectCfg.BuildCache != nil {
+		ui.Dim("build_cache is applied as declared")
+	}
+
+	if applyDryRunFlag {
+		ui.Spacer()
+		ui.Dim("Dry run; nothing was changed")
+		return nil
+	}
+
+	var tasks []ui.Task
+	if len(changes) > 0 {
+		tasks = append(tasks, ui.Task{
+			Name:         "apply-settings",
+			ActiveName:   fmt.Sprintf("Applying %d settings...", len(changes)),
+			CompleteName: fmt.Sprintf("Applied %d settings", len(changes)),
+			Action: func() error {
+				return deploy.ApplySettingChanges(client, appUUID, changes)
+			},
+		})
+	}
+	if projectCfg.BuildCache != nil {
+		tasks = append(tasks, ui.Task{
+			Name:         "apply-build-cache",
+			ActiveName:   "Applying build cache settings...",
+			CompleteName: "Applied build cache settings",
+			Action: func() error {
+				return deploy.ApplyBuildCache(client, projectCfg)
+			},
+		})
+	}
+
+	ui.Spacer()
+	if err := ui.RunTasks(tasks); err != nil {
+		if errors.Is(err, deploy.ErrBuildCacheUnsupported) {
+			ui.Error("Coolify rejected the build cache setting")
+			ui.Dim("  Upgrade Coolify, or remove build_cache.disabled from cdp.json")
+		} else {
+			ui.Error("Failed to apply app settings")
+		}
+		return err
+	}
+
+	ui.Spacer()
+	ui.NextSteps([]string{
+		fmt.Sprintf("Run '%s' to redeploy with the new settings", execName()),
+	})
+	return nil
+}
+
+// settingValues returns the Coolify and cdp.json values of changes, keyed by
+// setting, for ui.DiffKeyValues
+func settingValues(changes []deploy.SettingChange) (remote, local map[string]string) {
+	remote, local = map[string]string{}, map[string]string{}
+	for _, c := range changes {
+		remote[c.Setting] = c.Remote
+		local[c.Setting] = c.Local
+	}
+	return remote, local
+}
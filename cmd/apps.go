@@ -0,0 +1,251 @@
+package cmd
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/dropalltables/cdp/internal/api"
+	"github.com/dropalltables/cdp/internal/config"
+	"github.com/dropalltables/cdp/internal/ui"
+	"github.com/spf13/cobra"
+)
+
+var (
+	// Flags for apps ls command
+	appsProjectFlag string
+	appsServerFlag  string
+)
+
+var appsCmd = &cobra.Command{
+	Use:   "apps",
+	Short: "Work with all applications on the Coolify instance",
+}
+
+var appsLsCmd = &cobra.Command{
+	Use:     "ls",
+	Aliases: []string{"list"},
+	Short:   "List every application on the Coolify instance",
+	Long: `List every application your token can see, with its status, domains,
+server, and project. Unlike 'cdp ls', this doesn't need a linked project.
+
+Filter by project or server name (or UUID) with --project and --server.`,
+	Example: `  cdp apps ls
+  cdp apps ls --project shop --server hetzner-1`,
+	Args: cobra.NoArgs,
+	RunE: runAppsLs,
+}
+
+func init() {
+	rootCmd.AddCommand(appsCmd)
+	appsCmd.AddCommand(appsLsCmd)
+
+	appsLsCmd.Flags().StringVar(&appsProjectFlag, "project", "", "Only show applications in this project")
+	appsLsCmd.Flags().StringVar(&appsServerFlag, "server", "", "Only show applications on this server")
+	_ = appsLsCmd.RegisterFlagCompletionFunc("project", completeProjectNames)
+}
+
+// appLocation is where an application lives on the instance
+type appLocation struct {
+	project     string
+	environment string
+	server      string
+}
+
+func runAppsLs(cmd *cobra.Command, args []string) error {
+	if err := checkLogin(); err != nil {
+		return err
+	}
+
+	globalCfg, err := config.LoadGlobal()
+	if err != nil {
+		return fmt.Errorf("failed to load configuration: %w", err)
+	}
+	client := api.NewClient(globalCfg.CoolifyURL, globalCfg.CoolifyToken)
+
+	var apps []api.Application
+	var projects []api.Project
+	var servers []api.Server
+	serverByApp := map[string]string{}
+	err = ui.RunTasks([]ui.Task{
+		{
+			Name:         "fetch-apps",
+			ActiveName:   "Fetching applications...",
+			CompleteName: "Fetched applications",
+			Action: func() error {
+				var err error
+				apps, err = client.ListApplications()
+				return err
+			},
+		},
+		{
+			Name:         "fetch-projects",
+			ActiveName:   "Looking up projects...",
+			CompleteName: "Looked up projects",
+			Action: func() error {
+				var err error
+				projects, err = projectDetails(client)
+				return err
+			},
+		},
+		{
+			Name:         "fetch-servers",
+			ActiveName:   "Looking up servers...",
+			CompleteName: "Looked up servers",
+			Action: func() error {
+				var err error
+				servers, err = client.ListServers()
+				if err != nil {
+					return err
+				}
+				// Resources are best-effort: an unreachable server just
+				// leaves its apps without a server name
+				for _, s := range servers {
+					resources, err := client.ListServerResources(s.UUID)
+					if err != nil {
+						continue
+					}
+					for _, r := range resources {
+						serverByApp[r.UUID] = s.Name
+					}
+				}
+				return nil
+			},
+		},
+	})
+	if err != nil {
+		ui.Error("Failed to fetch applications")
+		return err
+	}
+
+	// Filters match by name, as that's all the lookups below record
+	wantProject, wantServer := "", ""
+	if appsProjectFlag != "" {
+		p := findProject(projects, appsProjectFlag)
+		if p == nil {
+			ui.Error(fmt.Sprintf("No project named %q", appsProjectFlag))
+			return fmt.Errorf("project not found")
+		}
+		wantProject = p.Name
+	}
+	if appsServerFlag != "" {
+		s := findServer(servers, appsServerFlag)
+		if s == nil {
+			ui.Error(fmt.Sprintf("No server named %q", appsServerFlag))
+			return fmt.Errorf("server not found")
+		}
+		wantServer = s.Name
+	}
+
+	byEnv := map[int]appLocation{}
+	for _, p := range projects {
+		for _, e := range p.Environments {
+			byEnv[e.ID] = appLocation{project: p.Name, environment: e.Name}
+		}
+	}
+
+	type appRow struct {
+		app api.Application
+		loc appLocation
+	}
+	var listed []appRow
+	for _, app := range apps {
+		loc := byEnv[app.EnvironmentID]
+		loc.server = serverByApp[app.UUID]
+		if (wantProject != "" && loc.project != wantProject) || (wantServer != "" && loc.server != wantServer) {
+			continue
+		}
+		listed = append(listed, appRow{app: app, loc: loc})
+	}
+
+	if len(listed) == 0 {
+		ui.Warning("No applications found")
+		return nil
+	}
+
+	sort.Slice(listed, func(i, j int) bool {
+		a, b := listed[i], listed[j]
+		if a.loc.project != b.loc.project {
+			return a.loc.project < b.loc.project
+		}
+		return strings.ToLower(a.app.Name) < strings.ToLower(b.app.Name)
+	})
+
+	rows := make([][]string, len(listed))
+	for i, r := range listed {
+		status := r.app.Status
+		if status == "" {
+			status = "unknown"
+		}
+		project := orDash(r.loc.project)
+		if r.loc.environment != "" {
+			project += " / " + r.loc.environment
+		}
+		rows[i] = []string{r.app.Name, status, orDash(r.app.FQDN), orDash(r.loc.server), project}
+	}
+
+	ui.Spacer()
+	ui.Table([]string{"Application", "Status", "Domains", "Server", "Project"}, rows)
+	ui.Spacer()
+	ui.Dim(fmt.Sprintf("%d applications", len(listed)))
+	return nil
+}
+
+// projectDetails returns every project with its environments, which the
+// project list leaves out
+func projectDetails(client *api.Client) ([]api.Project, error) {
+	projects, err := client.ListProjects()
+	if err != nil {
+		return nil, err
+	}
+
+	queue := make(chan int)
+	var wg sync.WaitGroup
+	for i := 0; i < projectLookupWorkers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range queue {
+				// Keeps the summary when the details can't be fetched
+				if detail, err := client.GetProject(projects[i].UUID); err == nil && detail != nil {
+					projects[i] = *detail
+				}
+			}
+		}()
+	}
+	for i := range projects {
+		queue <- i
+	}
+	close(queue)
+	wg.Wait()
+	return projects, nil
+}
+
+// findProject returns the project with the given name or UUID, or nil
+func findProject(projects []api.Project, nameOrUUID string) *api.Project {
+	for i, p := range projects {
+		if p.UUID == nameOrUUID || strings.EqualFold(p.Name, nameOrUUID) {
+			return &projects[i]
+		}
+	}
+	return nil
+}
+
+// findServer returns the server with the given name or UUID, or nil
+func findServer(servers []api.Server, nameOrUUID string) *api.Server {
+	for i, s := range servers {
+		if s.UUID == nameOrUUID || strings.EqualFold(s.Name, nameOrUUID) {
+			return &servers[i]
+		}
+	}
+	return nil
+}
+
+// orDash returns s, or a dash when it's empty
+func orDash(s string) string {
+	if s == "" {
+		return "-"
+	}
+	return s
+}
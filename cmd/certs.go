@@ -0,0 +1,180 @@
+package cmd
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/dropalltables/cdp/internal/api"
+	"github.com/dropalltables/cdp/internal/config"
+	"github.com/dropalltables/cdp/internal/deploy"
+	"github.com/dropalltables/cdp/internal/ui"
+	"github.com/spf13/cobra"
+)
+
+var certsCmd = &cobra.Command{
+	Use:   "certs",
+	Short: "Check the HTTPS certificates of the app's domains",
+	Long: `Connect to each of the linked app's https domains and report the
+certificate it serves: who issued it, when it expires, and whether it's
+trusted. Use it after a deploy to confirm HTTPS actually works.
+
+A domain served with the proxy's default certificate has no certificate
+from Let's Encrypt yet, which is usually DNS not pointing at the Coolify
+server; cdp checks that for the failing domains. Exits non-zero when any
+https domain doesn't serve a trusted certificate.`,
+	Example: `  cdp certs
+  cdp certs --env staging`,
+	Args: cobra.NoArgs,
+	RunE: runCerts,
+}
+
+func init() {
+	rootCmd.AddCommand(certsCmd)
+	addEnvFlag(certsCmd, false, envProduction)
+}
+
+func runCerts(cmd *cobra.Command, args []string) error {
+	target, err := resolveDeployEnv(cmd)
+	if err != nil {
+		return err
+	}
+	if target.Preview() {
+		ui.Error("Preview certificates can't be checked with certs")
+		return fmt.Errorf("certs doesn't support the preview environment")
+	}
+	appUUID, client, err := deployEnvApp(target)
+	if err != nil {
+		return err
+	}
+
+	app, err := fetchApplication(client, appUUID)
+	if err != nil {
+		return err
+	}
+	domains := parseDomains(app.FQDN)
+	if len(domains) == 0 {
+		ui.Warning("No domains configured")
+		ui.NextSteps([]string{
+			fmt.Sprintf("Run '%s domains add app.example.com' to add one", execName()),
+		})
+		return nil
+	}
+
+	// Coolify only requests certificates for the https domains
+	var hosts []string
+	for _, d := range domains {
+		if !strings.HasPrefix(strings.ToLower(d), "http://") {
+			hosts = append(hosts, domainHost(d))
+		}
+	}
+
+	var checks []deploy.CertCheck
+	if len(hosts) > 0 {
+		err = ui.RunTasks([]ui.Task{
+			{
+				Name:         "check-certs",
+				ActiveName:   "Checking certificates...",
+				CompleteName: "Checked certificates",
+				Action: func() error {
+					checks = deploy.CheckCerts(hosts)
+					return nil
+				},
+			},
+		})
+		if err != nil {
+			return err
+		}
+	}
+	byHost := map[string]deploy.CertCheck{}
+	for _, c := range checks {
+		byHost[c.Host] = c
+	}
+
+	var failed, expiring []string
+	rows := make([][]string, len(domains))
+	for i, d := range domains {
+		host := domainHost(d)
+		c, ok := byHost[host]
+		if !ok {
+			rows[i] = []string{host, "HTTP only", "-", "-"}
+			continue
+		}
+		expires := "-"
+		if !c.NotAfter.IsZero() {
+			expires = fmt.Sprintf("%s (%d days)", c.NotAfter.Format("2006-01-02"), c.DaysLeft())
+		}
+		rows[i] = []string{host, certStatus(c), orDash(c.Issuer), expires}
+		switch {
+		case !c.Valid():
+			failed = append(failed, host)
+		case c.ExpiringSoon():
+			expiring = append(expiring, host)
+		}
+	}
+
+	ui.Spacer()
+	ui.Table([]string{"Domain", "Status", "Issuer", "Expires"}, rows)
+
+	if len(expiring) > 0 {
+		ui.Spacer()
+		ui.Warning(fmt.Sprintf("Expiring soon: %s", strings.Join(expiring, ", ")))
+		ui.Dim("  Let's Encrypt certificates renew 30 days before expiry, so renewal is failing")
+		ui.Dim("  Check the proxy logs on the Coolify server")
+	}
+	if len(failed) == 0 {
+		return nil
+	}
+
+	ui.Spacer()
+	ui.Error(fmt.Sprintf("%d of %d domains don't serve a trusted certificate", len(failed), len(hosts)))
+	printCertFailures(client, checks)
+	return fmt.Errorf("invalid certificate for %s", strings.Join(failed, ", "))
+}
+
+// certStatus describes a certificate check in a word or two
+func certStatus(c deploy.CertCheck) string {
+	switch {
+	case c.Err != nil:
+		return "unreachable"
+	case c.Default:
+		return "not issued"
+	case c.VerifyErr != nil && !c.NotAfter.IsZero() && c.DaysLeft() < 0:
+		return "expired"
+	case c.VerifyErr != nil:
+		return "untrusted"
+	case c.ExpiringSoon():
+		return "expiring"
+	}
+	return "valid"
+}
+
+// printCertFailures explains each failed check, and since a missing
+// certificate is usually DNS, checks where the domains point
+func printCertFailures(client *api.Client, checks []deploy.CertCheck) {
+	var hosts []string
+	for _, c := range checks {
+		switch {
+		case c.Valid():
+			continue
+		case c.Err != nil:
+			ui.Dim(fmt.Sprintf("  %s: %s", c.Host, c.Err.Error()))
+		case c.Default:
+			ui.Dim(fmt.Sprintf("  %s: the proxy is serving its default certificate", c.Host))
+		default:
+			ui.Dim(fmt.Sprintf("  %s: %s", c.Host, c.VerifyErr.Error()))
+		}
+		hosts = append(hosts, c.Host)
+	}
+
+	var serverIPs []string
+	if projectCfg, err := config.LoadProject(); err == nil && projectCfg != nil {
+		serverIPs = deploy.ServerAddresses(client, projectCfg.ServerUUID)
+	}
+	ui.Spacer()
+	if deploy.WarnDNS(deploy.CheckDNS(hosts), serverIPs) {
+		ui.NextSteps([]string{
+			"DNS looks right; check the proxy logs on the Coolify server",
+			fmt.Sprintf("Run '%s' to redeploy, which has the proxy request the certificate again", execName()),
+		})
+	}
+}
@@ -0,0 +1,236 @@
+package cmd
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/dropalltables/cdp/internal/api"
+	"github.com/dropalltables/cdp/internal/config"
+	"github.com/dropalltables/cdp/internal/deploy"
+	"github.com/dropalltables/cdp/internal/ui"
+	"github.com/spf13/cobra"
+)
+
+var (
+	// Flags for clone-app command
+	cloneToFlag      string
+	cloneProjectFlag string
+	cloneNameFlag    string
+	cloneDomainFlag  string
+	cloneCopyEnvFlag bool
+	cloneSetFlags    []string
+	cloneDeployFlag  bool
+)
+
+var cloneAppCmd = &cobra.Command{
+	Use:   "clone-app",
+	Short: "Copy the linked app into another environment or project",
+	Long: `Create a copy of the linked application in another environment or project,
+on the same server, built from the same repository or image with the same
+settings. The environment is created if it doesn't exist.
+
+Domains are not copied; give the copy its own with --domain. Environment
+variables are only copied with --copy-env, and --set adds or overrides
+values. The current directory stays linked to the original app.`,
+	Example: `  cdp clone-app --to staging
+  cdp clone-app --to staging --copy-env --set API_URL=https://api.staging.example.com
+  cdp clone-app --project sandbox --name web-sandbox --deploy`,
+	Args: cobra.NoArgs,
+	RunE: runCloneApp,
+}
+
+func init() {
+	rootCmd.AddCommand(cloneAppCmd)
+
+	cloneAppCmd.Flags().StringVar(&cloneToFlag, "to", "", "Target environment (default production)")
+	cloneAppCmd.Flags().StringVar(&cloneProjectFlag, "project", "", "Target project name or UUID (default the linked project)")
+	cloneAppCmd.Flags().StringVar(&cloneNameFlag, "name", "", "Name of the copy (default the app's name)")
+	cloneAppCmd.Flags().StringVar(&cloneDomainFlag, "domain", "", "Domain for the copy")
+	cloneAppCmd.Flags().BoolVar(&cloneCopyEnvFlag, "copy-env", false, "Copy production environment variables")
+	cloneAppCmd.Flags().StringArrayVar(&cloneSetFlags, "set", nil, "Environment variable KEY=value for the copy (repeatable)")
+	cloneAppCmd.Flags().BoolVar(&cloneDeployFlag, "deploy", false, "Deploy the copy once created")
+}
+
+func runCloneApp(cmd *cobra.Command, args []string) error {
+	if cloneToFlag == "" && cloneProjectFlag == "" {
+		ui.Error("Specify a target with --to, --project, or both")
+		return fmt.Errorf("no clone target")
+	}
+
+	appUUID, client, err := getAppUUID()
+	if err != nil {
+		return err
+	}
+	projectCfg, err := config.LoadProject()
+	if err != nil {
+		return fmt.Errorf("failed to load project config: %w", err)
+	}
+	projectCfg.AppUUID = appUUID
+	if projectCfg.DeployMethod == config.DeployMethodCompose {
+		ui.Error("Compose deployments can't be cloned")
+		return fmt.Errorf("compose deployments can't be cloned")
+	}
+
+	overrides, err := parseCloneSets(cloneSetFlags)
+	if err != nil {
+		ui.Error(err.Error())
+		return err
+	}
+
+	opts := deploy.CloneOptions{
+		ProjectUUID: projectCfg.ProjectUUID,
+		Environment: cloneToFlag,
+		Name:        cloneNameFlag,
+		Domain:      cloneDomainFlag,
+	}
+	if opts.Environment == "" {
+		opts.Environment = envProduction
+	}
+
+	var source *api.Application
+	var envVars []api.EnvVar
+	err = ui.RunTasks([]ui.Task{
+		{
+			Name:         "resolve-target",
+			ActiveName:   "Resolving target...",
+			CompleteName: "Resolved target",
+			Action: func() error {
+				var err error
+				source, err = client.GetApplication(appUUID)
+				if err != nil {
+					return fmt.Errorf("failed to load application: %w", err)
+				}
+				if cloneProjectFlag != "" {
+					opts.ProjectUUID, err = findProjectUUID(client, cloneProjectFlag)
+					if err != nil {
+						return err
+					}
+				}
+				if opts.ProjectUUID == "" {
+					return fmt.Errorf("the linked app's Coolify project is unknown; run '%s link' or pass --project", execName())
+				}
+				if cloneCopyEnvFlag {
+					envVars, err = client.GetApplicationEnvVars(appUUID)
+					if err != nil {
+						return fmt.Errorf("failed to fetch environment variables: %w", err)
+					}
+				}
+				return nil
+			},
+		},
+	})
+	if err != nil {
+		ui.Error(err.Error())
+		return err
+	}
+
+	if opts.Name == "" {
+		opts.Name = source.Name
+	}
+	opts.EnvVars = mergeCloneEnv(envVars, overrides)
+
+	ui.Spacer()
+	ui.KeyValue("Source", source.Name)
+	ui.KeyValue("Target", fmt.Sprintf("%s / %s", cloneProjectLabel(), opts.Environment))
+	ui.Spacer()
+
+	uuid, err := deploy.CloneApp(client, projectCfg, opts)
+	if err != nil {
+		return err
+	}
+
+	if cloneDeployFlag {
+		err := ui.RunTasks([]ui.Task{
+			{
+				Name:         "deploy-clone",
+				ActiveName:   "Triggering deployment...",
+				CompleteName: "Triggered deployment",
+				Action: func() error {
+					_, err := client.Deploy(uuid, false, 0)
+					return err
+				},
+			},
+		})
+		if err != nil {
+			ui.Error("Failed to deploy the copy")
+			return err
+		}
+	}
+
+	ui.Spacer()
+	ui.Success(fmt.Sprintf("Cloned %s into %s", source.Name, opts.Environment))
+	ui.KeyValue("UUID", uuid)
+	steps := []string{
+		fmt.Sprintf("Run '%s env ls --env %s' to review its environment variables", execName(), opts.Environment),
+	}
+	if !cloneDeployFlag {
+		steps = append(steps, "Deploy it from the Coolify dashboard, or clone again with --deploy")
+	}
+	ui.Spacer()
+	ui.NextSteps(steps)
+	return nil
+}
+
+// cloneProjectLabel names the target project for display
+func cloneProjectLabel() string {
+	if cloneProjectFlag != "" {
+		return cloneProjectFlag
+	}
+	return "linked project"
+}
+
+// findProjectUUID resolves a Coolify project by name or UUID
+func findProjectUUID(client *api.Client, nameOrUUID string) (string, error) {
+	projects, err := client.ListProjects()
+	if err != nil {
+		return "", fmt.Errorf("failed to list projects: %w", err)
+	}
+	for _, p := range projects {
+		if p.UUID == nameOrUUID || strings.EqualFold(p.Name, nameOrUUID) {
+			return p.UUID, nil
+		}
+	}
+	return "", fmt.Errorf("no project named %q", nameOrUUID)
+}
+
+// parseCloneSets parses --set KEY=value flags
+func parseCloneSets(sets []string) (map[string]string, error) {
+	overrides := map[string]string{}
+	for _, s := range sets {
+		key, value, ok := strings.Cut(s, "=")
+		if !ok || strings.TrimSpace(key) == "" {
+			return nil, fmt.Errorf("invalid --set %q (use KEY=value)", s)
+		}
+		overrides[strings.TrimSpace(key)] = value
+	}
+	return overrides, nil
+}
+
+// mergeCloneEnv applies overrides to the production variables of the
+// source app, keeping each variable's build-time flag
+func mergeCloneEnv(envVars []api.EnvVar, overrides map[string]string) []api.EnvVar {
+	var merged []api.EnvVar
+	seen := map[string]bool{}
+	for _, env := range envVars {
+		if env.IsPreview {
+			continue
+		}
+		if v, ok := overrides[env.Key]; ok {
+			env.Value = v
+		}
+		seen[env.Key] = true
+		merged = append(merged, env)
+	}
+	var added []string
+	for key := range overrides {
+		if !seen[key] {
+			added = append(added, key)
+		}
+	}
+	sort.Strings(added)
+	for _, key := range added {
+		merged = append(merged, api.EnvVar{Key: key, Value: overrides[key]})
+	}
+	return merged
+}
@@ -0,0 +1,201 @@
+package cmd
+
+import (
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/dropalltables/cdp/internal/api"
+	"github.com/dropalltables/cdp/internal/config"
+	"github.com/spf13/cobra"
+)
+
+var completionCmd = &cobra.Command{
+	Use:   "completion bash|zsh|fish|powershell",
+	Short: "Generate shell completion scripts",
+	Long: `Print a completion script for your shell. Besides commands and flags, it
+completes application and project names and environment variable keys from
+your Coolify instance; names are cached for a minute.
+
+'cdp completion install' sets them up in your shell for you. To do it by
+hand instead:
+
+  bash        source <(cdp completion bash)
+              or save to /etc/bash_completion.d/cdp
+  zsh         cdp completion zsh > "${fpath[1]}/_cdp"
+  fish        cdp completion fish > ~/.config/fish/completions/cdp.fish
+  powershell  cdp completion powershell | Out-String | Invoke-Expression`,
+	ValidArgs: []string{"bash", "zsh", "fish", "powershell"},
+	Args:      cobra.MatchAll(cobra.ExactArgs(1), cobra.OnlyValidArgs),
+	RunE:      runCompletion,
+}
+
+func init() {
+	rootCmd.AddCommand(completionCmd)
+	rootCmd.CompletionOptions.DisableDefaultCmd = true
+
+	logsCmd.ValidArgsFunction = completeAppNames
+	projectRedeployCmd.ValidArgsFunction = firstArgOnly(completeProjectNames)
+	envRmCmd.ValidArgsFunction = firstArgOnly(completeEnvKeys)
+	_ = cloneAppCmd.RegisterFlagCompletionFunc("project", completeProjectNames)
+	_ = appsLsCmd.RegisterFlagCompletionFunc("server", completeServerNames)
+	serversValidateCmd.ValidArgsFunction = firstArgOnly(completeServerNames)
+	serversInfoCmd.ValidArgsFunction = firstArgOnly(completeServerNames)
+}
+
+func runCompletion(cmd *cobra.Command, args []string) error {
+	root := cmd.Root()
+	switch args[0] {
+	case "bash":
+		return root.GenBashCompletionV2(os.Stdout, true)
+	case "zsh":
+		return root.GenZshCompletion(os.Stdout)
+	case "fish":
+		return root.GenFishCompletion(os.Stdout, true)
+	default:
+		return root.GenPowerShellCompletionWithDesc(os.Stdout)
+	}
+}
+
+// isCompletionCmd reports whether cmd generates completions, whose output
+// must not be mixed with warnings
+func isCompletionCmd(cmd *cobra.Command) bool {
+	switch cmd.Name() {
+	case cobra.ShellCompRequestCmd, cobra.ShellCompNoDescRequestCmd:
+		return true
+	}
+	return cmd == completionCmd
+}
+
+// firstArgOnly limits a completion to the first positional argument, for
+// commands that take one
+func firstArgOnly(complete cobra.CompletionFunc) cobra.CompletionFunc {
+	return func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		if len(args) > 0 {
+			return nil, cobra.ShellCompDirectiveNoFileComp
+		}
+		return complete(cmd, args, toComplete)
+	}
+}
+
+// completeAppNames completes the names of all applications
+func completeAppNames(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	names := cachedNames("apps", func(client *api.Client) ([]string, error) {
+		apps, err := client.ListApplications()
+		if err != nil {
+			return nil, err
+		}
+		names := make([]string, len(apps))
+		for i, app := range apps {
+			names[i] = app.Name
+		}
+		return names, nil
+	})
+	return withoutArgs(names, args), cobra.ShellCompDirectiveNoFileComp
+}
+
+// completeProjectNames completes the names of all Coolify projects
+func completeProjectNames(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	names := cachedNames("projects", func(client *api.Client) ([]string, error) {
+		projects, err := client.ListProjects()
+		if err != nil {
+			return nil, err
+		}
+		names := make([]string, len(projects))
+		for i, p := range projects {
+			names[i] = p.Name
+		}
+		return names, nil
+	})
+	return names, cobra.ShellCompDirectiveNoFileComp
+}
+
+// completeServerNames completes the names of all servers
+func completeServerNames(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	names := cachedNames("servers", func(client *api.Client) ([]string, error) {
+		servers, err := client.ListServers()
+		if err != nil {
+			return nil, err
+		}
+		names := make([]string, len(servers))
+		for i, s := range servers {
+			names[i] = s.Name
+		}
+		return names, nil
+	})
+	return names, cobra.ShellCompDirectiveNoFileComp
+}
+
+// completeEnvKeys completes the environment variable keys of the linked app
+func completeEnvKeys(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	projectCfg, err := config.LoadProject()
+	if err != nil || projectCfg == nil || projectCfg.AppUUID == "" {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+	keys := cachedNames("env:"+projectCfg.AppUUID, func(client *api.Client) ([]string, error) {
+		envVars, err := client.GetApplicationEnvVars(projectCfg.AppUUID)
+		if err != nil {
+			return nil, err
+		}
+		var keys []string
+		for _, env := range envVars {
+			keys = append(keys, env.Key)
+		}
+		return keys, nil
+	})
+	return keys, cobra.ShellCompDirectiveNoFileComp
+}
+
+// cachedNames returns the names cached under kind for the configured
+// Coolify instance, fetching them when the cache is stale. Errors yield no
+// names, as completion can't report them.
+func cachedNames(kind string, fetch func(*api.Client) ([]string, error)) []string {
+	cfg, err := config.LoadGlobal()
+	if err != nil || cfg.CoolifyURL == "" || cfg.CoolifyToken == "" {
+		return nil
+	}
+	key := cfg.CoolifyURL + "|" + kind
+	if names, ok := config.CachedCompletions(key); ok {
+		return names
+	}
+
+	names, err := fetch(api.NewClient(cfg.CoolifyURL, cfg.CoolifyToken))
+	if err != nil {
+		return nil
+	}
+	names = uniqueSorted(names)
+	_ = config.SaveCompletions(key, names)
+	return names
+}
+
+// uniqueSorted returns the distinct non-empty names in order
+func uniqueSorted(names []string) []string {
+	seen := map[string]bool{}
+	var out []string
+	for _, name := range names {
+		if name != "" && !seen[name] {
+			seen[name] = true
+			out = append(out, name)
+		}
+	}
+	sort.Strings(out)
+	return out
+}
+
+// withoutArgs drops names already given as arguments
+func withoutArgs(names, args []string) []string {
+	var out []string
+	for _, name := range names {
+		given := false
+		for _, arg := range args {
+			if strings.EqualFold(arg, name) {
+				given = true
+				break
+			}
+		}
+		if !given {
+			out = append(out, name)
+		}
+	}
+	return out
+}
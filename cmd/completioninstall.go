@@ -0,0 +1,231 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+
+	"github.com/dropalltables/cdp/internal/ui"
+	"github.com/spf13/cobra"
+)
+
+// Markers around the snippet cdp adds to a shell's rc file, so it can be
+// found again to update or remove
+const (
+	completionBegin = "# >>> cdp completion >>>"
+	completionEnd   = "# <<< cdp completion <<<"
+)
+
+var completionInstallCmd = &cobra.Command{
+	Use:   "install [bash|zsh|fish]",
+	Short: "Set up completions in your shell",
+	Long: `Set up shell completions so they load in every new shell. The shell is
+detected from $SHELL unless given.
+
+For bash and zsh, a short snippet is added to ~/.bashrc or ~/.zshrc; for
+fish, a file is written to ~/.config/fish/completions. Remove it again with
+'cdp completion uninstall'.`,
+	ValidArgs: []string{"bash", "zsh", "fish"},
+	Args:      cobra.MatchAll(cobra.MaximumNArgs(1), cobra.OnlyValidArgs),
+	RunE:      runCompletionInstall,
+}
+
+var completionUninstallCmd = &cobra.Command{
+	Use:       "uninstall [bash|zsh|fish]",
+	Short:     "Remove completions set up by 'completion install'",
+	ValidArgs: []string{"bash", "zsh", "fish"},
+	Args:      cobra.MatchAll(cobra.MaximumNArgs(1), cobra.OnlyValidArgs),
+	RunE:      runCompletionUninstall,
+}
+
+func init() {
+	completionCmd.AddCommand(completionInstallCmd)
+	completionCmd.AddCommand(completionUninstallCmd)
+}
+
+// completionTarget is where completions for a shell are installed: a
+// marked block in an rc file, or for fish a file of its own
+type completionTarget struct {
+	shell   string
+	path    string
+	snippet string
+	ownFile bool
+}
+
+func runCompletionInstall(cmd *cobra.Command, args []string) error {
+	target, err := resolveCompletionTarget(args)
+	if err != nil {
+		return err
+	}
+
+	existing, err := os.ReadFile(target.path)
+	if err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("failed to read %s: %w", target.path, err)
+	}
+	if target.ownFile && string(existing) == target.snippet ||
+		!target.ownFile && strings.Contains(string(existing), completionBegin) {
+		ui.Success(fmt.Sprintf("Completions for %s are already installed in %s", target.shell, displayPath(target.path)))
+		return nil
+	}
+
+	ui.Dim(fmt.Sprintf("This adds to %s:", displayPath(target.path)))
+	ui.Print(ui.CodeStyle.Render(strings.TrimSpace(target.snippet)))
+	ui.Spacer()
+	confirmed, err := ui.ConfirmWithOptions("Install completions?", ui.ConfirmOptions{Default: true})
+	if err != nil {
+		return err
+	}
+	if !confirmed {
+		ui.Dim("Cancelled")
+		return nil
+	}
+
+	content := target.snippet
+	if !target.ownFile {
+		content = string(existing)
+		if content != "" && !strings.HasSuffix(content, "\n") {
+			content += "\n"
+		}
+		content += "\n" + target.snippet
+	}
+	if err := os.MkdirAll(filepath.Dir(target.path), 0755); err != nil {
+		return fmt.Errorf("failed to create %s: %w", filepath.Dir(target.path), err)
+	}
+	if err := os.WriteFile(target.path, []byte(content), 0644); err != nil {
+		ui.Error(fmt.Sprintf("Failed to write %s", displayPath(target.path)))
+		return err
+	}
+
+	ui.Success(fmt.Sprintf("Installed %s completions", target.shell))
+	ui.Dim("  Open a new shell to use them")
+	return nil
+}
+
+func runCompletionUninstall(cmd *cobra.Command, args []string) error {
+	target, err := resolveCompletionTarget(args)
+	if err != nil {
+		return err
+	}
+
+	existing, err := os.ReadFile(target.path)
+	if err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("failed to read %s: %w", target.path, err)
+	}
+	remaining, found := removeCompletionBlock(string(existing))
+	if target.ownFile {
+		found = err == nil
+	}
+	if !found {
+		ui.Dim(fmt.Sprintf("No cdp completions found in %s", displayPath(target.path)))
+		return nil
+	}
+
+	confirmed, err := ui.ConfirmWithOptions(fmt.Sprintf("Remove completions from %s?", displayPath(target.path)), ui.ConfirmOptions{Default: true})
+	if err != nil {
+		return err
+	}
+	if !confirmed {
+		ui.Dim("Cancelled")
+		return nil
+	}
+
+	if target.ownFile {
+		err = os.Remove(target.path)
+	} else {
+		err = os.WriteFile(target.path, []byte(remaining), 0644)
+	}
+	if err != nil {
+		ui.Error(fmt.Sprintf("Failed to update %s", displayPath(target.path)))
+		return err
+	}
+	ui.Success(fmt.Sprintf("Removed %s completions", target.shell))
+	return nil
+}
+
+// resolveCompletionTarget picks the shell, from args or $SHELL, and where
+// its completions go
+func resolveCompletionTarget(args []string) (*completionTarget, error) {
+	shell := ""
+	if len(args) == 1 {
+		shell = args[0]
+	} else {
+		shell = filepath.Base(os.Getenv("SHELL"))
+	}
+
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return nil, err
+	}
+	name := execName()
+	switch shell {
+	case "bash":
+		// macOS terminals start login shells, which read .bash_profile
+		rc := filepath.Join(home, ".bashrc")
+		if runtime.GOOS == "darwin" {
+			if _, err := os.Stat(rc); err != nil {
+				rc = filepath.Join(home, ".bash_profile")
+			}
+		}
+		return &completionTarget{shell: shell, path: rc, snippet: completionBlock(
+			fmt.Sprintf("command -v %[1]s >/dev/null && source <(%[1]s completion bash)", name),
+		)}, nil
+	case "zsh":
+		dir := os.Getenv("ZDOTDIR")
+		if dir == "" {
+			dir = home
+		}
+		return &completionTarget{shell: shell, path: filepath.Join(dir, ".zshrc"), snippet: completionBlock(
+			"(( $+functions[compdef] )) || { autoload -Uz compinit && compinit }",
+			fmt.Sprintf("(( $+commands[%[1]s] )) && source <(%[1]s completion zsh)", name),
+		)}, nil
+	case "fish":
+		dir := os.Getenv("XDG_CONFIG_HOME")
+		if dir == "" {
+			dir = filepath.Join(home, ".config")
+		}
+		return &completionTarget{
+			shell:   shell,
+			path:    filepath.Join(dir, "fish", "completions", name+".fish"),
+			snippet: fmt.Sprintf("%s completion fish | source\n", name),
+			ownFile: true,
+		}, nil
+	}
+
+	if shell == "" || shell == "." {
+		ui.Error("Could not detect your shell")
+	} else {
+		ui.Error(fmt.Sprintf("Installing completions for %s isn't supported", shell))
+	}
+	ui.Dim(fmt.Sprintf("Pass bash, zsh or fish, or see '%s completion --help' to set them up by hand", execName()))
+	return nil, fmt.Errorf("unsupported shell")
+}
+
+// completionBlock wraps lines in the markers
+func completionBlock(lines ...string) string {
+	return completionBegin + "\n" + strings.Join(lines, "\n") + "\n" + completionEnd + "\n"
+}
+
+// removeCompletionBlock returns content without the marked block, and
+// whether there was one
+func removeCompletionBlock(content string) (string, bool) {
+	start := strings.Index(content, completionBegin)
+	if start < 0 {
+		return content, false
+	}
+	end := strings.Index(content[start:], completionEnd)
+	if end < 0 {
+		return content, false
+	}
+	end += start + len(completionEnd)
+	if end < len(content) && content[end] == '\n' {
+		end++
+	}
+	// Drop the blank line install put before the block
+	before := content[:start]
+	if strings.HasSuffix(before, "\n\n") {
+		before = before[:len(before)-1]
+	}
+	return before + content[end:], true
+}
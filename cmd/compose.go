@@ -0,0 +1,102 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/dropalltables/cdp/internal/api"
+	"github.com/dropalltables/cdp/internal/config"
+	"github.com/dropalltables/cdp/internal/deploy"
+	"github.com/dropalltables/cdp/internal/ui"
+	"github.com/spf13/cobra"
+)
+
+var composeCmd = &cobra.Command{
+	Use:   "compose",
+	Short: "Deploy Docker Compose projects as Coolify services",
+}
+
+var composeImportCmd = &cobra.Command{
+	Use:   "import [FILE]",
+	Short: "Create a Coolify service from a local compose file",
+	Long: `Upload a compose file as a Coolify service and link this directory to it.
+
+Variables the file references (${VAR}) are set on the service from .env,
+their defaults, or a prompt. Afterwards, 'cdp' uploads the current compose
+file and restarts the service.`,
+	Example: `  cdp compose import
+  cdp compose import docker-compose.prod.yml`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: runComposeImport,
+}
+
+func init() {
+	rootCmd.AddCommand(composeCmd)
+	composeCmd.AddCommand(composeImportCmd)
+}
+
+func runComposeImport(cmd *cobra.Command, args []string) error {
+	if err := checkLogin(); err != nil {
+		return err
+	}
+
+	composeFile := ""
+	if len(args) == 1 {
+		composeFile = args[0]
+	} else {
+		for _, name := range deploy.ComposeFiles {
+			if _, err := os.Stat(name); err == nil {
+				composeFile = name
+				break
+			}
+		}
+	}
+	if composeFile == "" {
+		ui.Error("No compose file found")
+		ui.Dim("Looked for " + strings.Join(deploy.ComposeFiles, ", "))
+		return fmt.Errorf("no compose file found")
+	}
+	if _, err := os.Stat(composeFile); err != nil {
+		ui.Error(fmt.Sprintf("Cannot read %s", composeFile))
+		return err
+	}
+
+	projectCfg, err := config.LoadProject()
+	if err != nil {
+		return fmt.Errorf("failed to load project config: %w", err)
+	}
+	if projectCfg != nil && (projectCfg.AppUUID != "" || projectCfg.ServiceUUID != "") {
+		ui.Error("This directory is already linked")
+		ui.Dim(fmt.Sprintf("Remove %s first to import it as a new service", config.ProjectConfigPath(".")))
+		return fmt.Errorf("already linked")
+	}
+
+	globalCfg, err := config.LoadGlobal()
+	if err != nil {
+		return fmt.Errorf("failed to load config: %w", err)
+	}
+	client := api.NewClient(globalCfg.CoolifyURL, globalCfg.CoolifyToken)
+
+	// Compose reads .env next to the file; use the same values
+	dotEnv := map[string]string{}
+	if envVars, err := readDotEnv(".env"); err == nil {
+		for _, env := range envVars {
+			dotEnv[env.Key] = env.Value
+		}
+	}
+
+	if _, err := deploy.ImportCompose(client, globalCfg, composeFile, dotEnv); err != nil {
+		if strings.Contains(err.Error(), "interrupted") {
+			return nil
+		}
+		return err
+	}
+
+	ui.Spacer()
+	ui.Success(fmt.Sprintf("Imported %s", composeFile))
+	ui.NextSteps([]string{
+		fmt.Sprintf("Run '%s' after editing %s to redeploy", execName(), composeFile),
+	})
+	return nil
+}
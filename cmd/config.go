@@ -0,0 +1,486 @@
+package cmd
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/dropalltables/cdp/internal/config"
+	"github.com/dropalltables/cdp/internal/detect"
+	"github.com/dropalltables/cdp/internal/snapshot"
+	"github.com/dropalltables/cdp/internal/ui"
+	"github.com/spf13/cobra"
+)
+
+const (
+	// configPassphraseEnv supplies the export passphrase without a prompt
+	configPassphraseEnv = "CDP_CONFIG_PASSPHRASE"
+	// configExportVersion is the format version written by 'config export'
+	configExportVersion = 1
+)
+
+var (
+	// Flags for config export command
+	configNoSecretsFlag bool
+
+	// Flags for config set and unset commands
+	configApplyFlag bool
+)
+
+var configCmd = &cobra.Command{
+	Use:   "config",
+	Short: "Manage cdp configuration",
+}
+
+var configExportCmd = &cobra.Command{
+	Use:   "export FILE",
+	Short: "Export the global config to move it to another machine",
+	Long: `Write the global config (Coolify URL and token, GitHub token, registry,
+workspace roots, webhooks) to FILE for 'cdp config import' on another machine.
+
+Tokens and the webhook secret are encrypted with a passphrase, or left out
+with --no-secrets. Registry passwords live in Docker's credential store and
+are never exported. Set ` + configPassphraseEnv + ` to skip the passphrase prompt.`,
+	Example: `  cdp config export cdp-config.json
+  cdp config export cdp-config.json --no-secrets`,
+	Args: cobra.ExactArgs(1),
+	RunE: runConfigExport,
+}
+
+var configImportCmd = &cobra.Command{
+	Use:   "import FILE",
+	Short: "Import a global config exported with 'cdp config export'",
+	Long: `Merge a config exported with 'cdp config export' into this machine's global
+config. Settings in the file replace the ones already saved; settings it
+doesn't have are kept.`,
+	Args: cobra.ExactArgs(1),
+	RunE: runConfigImport,
+}
+
+var configGetCmd = &cobra.Command{
+	Use:   "get [KEY]",
+	Short: "Show project settings from cdp.json",
+	Long: `Print the value of a project setting from cdp.json, or every supported
+setting when no KEY is given.`,
+	Args:      cobra.MaximumNArgs(1),
+	ValidArgs: projectSettingKeys(),
+	RunE:      runConfigGet,
+}
+
+var configSetCmd = &cobra.Command{
+	Use:   "set KEY VALUE",
+	Short: "Change a project setting in cdp.json",
+	Long: `Validate and save a project setting in cdp.json. With --apply, settings
+Coolify also stores (port, branch, domain, commands, limits) are updated on
+the linked app right away; otherwise they take effect on the next deploy or
+'cdp apply'.
+
+Keys: ` + strings.Join(projectSettingKeys(), ", "),
+	Example: `  cdp config set port 8080
+  cdp config set build_command "npm run build" --apply`,
+	Args:      cobra.ExactArgs(2),
+	ValidArgs: projectSettingKeys(),
+	RunE:      runConfigSet,
+}
+
+var configUnsetCmd = &cobra.Command{
+	Use:       "unset KEY",
+	Short:     "Remove a project setting from cdp.json",
+	Long:      "Remove a project setting from cdp.json so cdp's default applies again.",
+	Args:      cobra.ExactArgs(1),
+	ValidArgs: projectSettingKeys(),
+	RunE:      runConfigUnset,
+}
+
+func init() {
+	rootCmd.AddCommand(configCmd)
+	configCmd.AddCommand(configExportCmd)
+	configCmd.AddCommand(configImportCmd)
+	configCmd.AddCommand(configGetCmd)
+	configCmd.AddCommand(configSetCmd)
+	configCmd.AddCommand(configUnsetCmd)
+
+	configExportCmd.Flags().BoolVar(&configNoSecretsFlag, "no-secrets", false, "Leave tokens and secrets out instead of encrypting them")
+	configSetCmd.Flags().BoolVar(&configApplyFlag, "apply", false, "Also update the linked app in Coolify")
+}
+
+// configExport is the file written by 'config export'
+type configExport struct {
+	Version int                  `json:"cdp_config_export"`
+	Config  *config.GlobalConfig `json:"config"`
+	Secrets *snapshot.Encrypted  `json:"secrets,omitempty"` // a GlobalConfig holding only secrets
+}
+
+func runConfigExport(cmd *cobra.Command, args []string) error {
+	cfg, err := config.LoadGlobalFile()
+	if err != nil {
+		return fmt.Errorf("failed to load configuration: %w", err)
+	}
+	if cfg.CoolifyURL == "" && cfg.CoolifyToken == "" {
+		ui.Warning("Nothing to export yet")
+		ui.Dim(fmt.Sprintf("Run '%s login' first", execName()))
+		return nil
+	}
+
+	public, secrets := config.SplitSecrets(cfg)
+	out := configExport{Version: configExportVersion, Config: public}
+	if !configNoSecretsFlag {
+		passphrase, err := readPassphrase(configPassphraseEnv, "Export passphrase", true)
+		if err != nil {
+			return err
+		}
+		plaintext, err := json.Marshal(secrets)
+		if err != nil {
+			return err
+		}
+		if out.Secrets, err = snapshot.Encrypt(plaintext, passphrase); err != nil {
+			ui.Error("Failed to encrypt secrets")
+			return err
+		}
+	}
+
+	data, err := json.MarshalIndent(out, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.WriteFile(args[0], data, 0600); err != nil {
+		ui.Error("Failed to write " + args[0])
+		return err
+	}
+
+	ui.Success("Exported configuration to " + args[0])
+	if configNoSecretsFlag {
+		ui.Dim("Tokens and webhooks were left out; run 'cdp login' and add the webhooks again after importing")
+	}
+	ui.NextSteps([]string{
+		fmt.Sprintf("On the new machine, run '%s config import %s'", execName(), args[0]),
+	})
+	return nil
+}
+
+func runConfigImport(cmd *cobra.Command, args []string) error {
+	data, err := os.ReadFile(args[0])
+	if err != nil {
+		ui.Error("Could not read " + args[0])
+		return err
+	}
+	var in configExport
+	if err := json.Unmarshal(data, &in); err != nil || in.Version == 0 || in.Config == nil {
+		ui.Error(args[0] + " isn't a cdp config export")
+		return fmt.Errorf("not a config export")
+	}
+	if in.Version > configExportVersion {
+		ui.Error("The export was made by a newer version of cdp")
+		ui.Dim(fmt.Sprintf("Run '%s upgrade' and try again", execName()))
+		return fmt.Errorf("unsupported config export version %d", in.Version)
+	}
+
+	var secrets *config.GlobalConfig
+	if in.Secrets != nil {
+		passphrase, err := readPassphrase(configPassphraseEnv, "Export passphrase", false)
+		if err != nil {
+			return err
+		}
+		plaintext, err := snapshot.Decrypt(in.Secrets, passphrase)
+		if err != nil {
+			if errors.Is(err, snapshot.ErrBadPassphrase) {
+				ui.Error("Wrong passphrase, or the file is corrupted")
+			}
+			return err
+		}
+		secrets = &config.GlobalConfig{}
+		if err := json.Unmarshal(plaintext, secrets); err != nil {
+			return fmt.Errorf("failed to read secrets: %w", err)
+		}
+	}
+
+	current, err := config.LoadGlobalFile()
+	if err != nil {
+		return fmt.Errorf("failed to load configuration: %w", err)
+	}
+	ui.KeyValue("Coolify URL", in.Config.CoolifyURL)
+	if in.Config.DockerRegistry != nil {
+		ui.KeyValue("Registry", in.Config.DockerRegistry.URL)
+	}
+	if secrets == nil {
+		ui.KeyValue("Tokens", "not included")
+	}
+	if current.CoolifyURL != "" {
+		ui.Spacer()
+		confirmed, err := ui.ConfirmWithOptions("Replace the settings saved on this machine?", ui.ConfirmOptions{Default: true})
+		if err != nil {
+			return err
+		}
+		if !confirmed {
+			return nil
+		}
+	}
+
+	if err := config.ImportGlobal(in.Config, secrets); err != nil {
+		ui.Error("Failed to save configuration")
+		return err
+	}
+
+	ui.Spacer()
+	ui.Success("Imported configuration")
+	steps := []string{fmt.Sprintf("Run '%s doctor' to check the setup", execName())}
+	if secrets == nil {
+		steps = append([]string{fmt.Sprintf("Run '%s login' to add your tokens", execName())}, steps...)
+	}
+	if in.Config.DockerRegistry != nil {
+		steps = append(steps, fmt.Sprintf("Run 'docker login %s' if pushes need credentials", in.Config.DockerRegistry.URL))
+	}
+	ui.NextSteps(steps)
+	return nil
+}
+
+// projectSetting is a cdp.json field that 'config get/set/unset' can change
+type projectSetting struct {
+	key    string
+	remote string // field updated with --apply, or "" when only cdp uses it
+	get    func(cfg *config.ProjectConfig) string
+	set    func(cfg *config.ProjectConfig, value string) error
+}
+
+// stringSetting is a plain string field, checked by validate when set
+func stringSetting(key, remote string, field func(cfg *config.ProjectConfig) *string, validate func(string) (string, error)) projectSetting {
+	return projectSetting{
+		key:    key,
+		remote: remote,
+		get:    func(cfg *config.ProjectConfig) string { return *field(cfg) },
+		set: func(cfg *config.ProjectConfig, value string) error {
+			if value != "" && validate != nil {
+				var err error
+				if value, err = validate(value); err != nil {
+					return err
+				}
+			}
+			*field(cfg) = value
+			return nil
+		},
+	}
+}
+
+// oneOf accepts only the given values
+func oneOf(values ...string) func(string) (string, error) {
+	return func(v string) (string, error) {
+		for _, allowed := range values {
+			if v == allowed {
+				return v, nil
+			}
+		}
+		return "", fmt.Errorf("must be one of %s", strings.Join(values, ", "))
+	}
+}
+
+var projectSettings = []projectSetting{
+	stringSetting("name", "", func(c *config.ProjectConfig) *string { return &c.Name }, nil),
+	stringSetting("port", "ports_exposes", func(c *config.ProjectConfig) *string { return &c.Port }, func(v string) (string, error) {
+		if n, err := strconv.Atoi(v); err != nil || n < 1 || n > 65535 {
+			return "", fmt.Errorf("must be a port number between 1 and 65535")
+		}
+		return v, nil
+	}),
+	stringSetting("branch", "git_branch", func(c *config.ProjectConfig) *string { return &c.Branch }, func(v string) (string, error) {
+		if strings.ContainsAny(v, " ~^:?*[\\") {
+			return "", fmt.Errorf("isn't a valid git branch name")
+		}
+		return v, nil
+	}),
+	stringSetting("domain", "domains", func(c *config.ProjectConfig) *string { return &c.Domain }, normalizeDomain),
+	stringSetting("install_command", "install_command", func(c *config.ProjectConfig) *string { return &c.InstallCommand }, nil),
+	stringSetting("build_command", "build_command", func(c *config.ProjectConfig) *string { return &c.BuildCommand }, nil),
+	stringSetting("start_command", "start_command", func(c *config.ProjectConfig) *string { return &c.StartCommand }, nil),
+	stringSetting("base_directory", "base_directory", func(c *config.ProjectConfig) *string { return &c.BaseDirectory }, func(v string) (string, error) {
+		if !strings.HasPrefix(v, "/") {
+			return "", fmt.Errorf("must start with /, e.g. /apps/web")
+		}
+		return v, nil
+	}),
+	stringSetting("publish_dir", "publish_directory", func(c *config.ProjectConfig) *string { return &c.PublishDir }, nil),
+	stringSetting("build_pack", "build_pack", func(c *config.ProjectConfig) *string { return &c.BuildPack },
+		oneOf(detect.BuildPackNixpacks, detect.BuildPackStatic, detect.BuildPackDockerfile)),
+	stringSetting("cpu_limit", "limits_cpus", func(c *config.ProjectConfig) *string { return &c.CPULimit }, func(v string) (string, error) {
+		if f, err := strconv.ParseFloat(v, 64); err != nil || f <= 0 {
+			return "", fmt.Errorf("must be a number of CPUs, e.g. 0.5 or 2")
+		}
+		return v, nil
+	}),
+	stringSetting("memory_limit", "limits_memory", func(c *config.ProjectConfig) *string { return &c.MemoryLimit }, func(v string) (string, error) {
+		if !memoryLimitPattern.MatchString(v) {
+			return "", fmt.Errorf("must be a size like 512m or 1g")
+		}
+		return strings.ToLower(v), nil
+	}),
+	{
+		key:    "replicas",
+		remote: "swarm_replicas",
+		get: func(c *config.ProjectConfig) string {
+			if c.Replicas == 0 {
+				return ""
+			}
+			return strconv.Itoa(c.Replicas)
+		},
+		set: func(c *config.ProjectConfig, v string) error {
+			if v == "" {
+				c.Replicas = 0
+				return nil
+			}
+			n, err := strconv.Atoi(v)
+			if err != nil || n < 1 {
+				return fmt.Errorf("must be a whole number of at least 1")
+			}
+			c.Replicas = n
+			return nil
+		},
+	},
+	stringSetting("platform", "", func(c *config.ProjectConfig) *string { return &c.Platform }, func(v string) (string, error) {
+		for _, p := range strings.Split(v, ",") {
+			if !strings.HasPrefix(strings.TrimSpace(p), "linux/") {
+				return "", fmt.Errorf("must look like linux/amd64 or linux/amd64,linux/arm64")
+			}
+		}
+		return v, nil
+	}),
+	stringSetting("docker_image", "", func(c *config.ProjectConfig) *string { return &c.DockerImage }, nil),
+	stringSetting("local_builder", "", func(c *config.ProjectConfig) *string { return &c.LocalBuilder },
+		oneOf(config.LocalBuilderDockerfile, config.LocalBuilderNixpacks)),
+	stringSetting("image_size_budget", "", func(c *config.ProjectConfig) *string { return &c.ImageSizeBudget }, nil),
+}
+
+func projectSettingKeys() []string {
+	keys := make([]string, len(projectSettings))
+	for i, s := range projectSettings {
+		keys[i] = s.key
+	}
+	return keys
+}
+
+// findProjectSetting returns the setting for key, printing the valid keys
+// when there is none
+func findProjectSetting(key string) (*projectSetting, error) {
+	for i := range projectSettings {
+		if projectSettings[i].key == key {
+			return &projectSettings[i], nil
+		}
+	}
+	ui.Error(fmt.Sprintf("Unknown setting %q", key))
+	ui.Dim("  Settings: " + strings.Join(projectSettingKeys(), ", "))
+	return nil, fmt.Errorf("unknown setting %q", key)
+}
+
+// loadLinkedProject loads cdp.json, failing when there is none
+func loadLinkedProject() (*config.ProjectConfig, error) {
+	projectCfg, err := config.LoadProject()
+	if err != nil {
+		return nil, fmt.Errorf("failed to load project config: %w", err)
+	}
+	if projectCfg == nil {
+		ui.Error("No project configuration found")
+		ui.Dim(fmt.Sprintf("Run '%s' or '%s link' first", execName(), execName()))
+		return nil, fmt.Errorf("not linked to a project")
+	}
+	return projectCfg, nil
+}
+
+func runConfigGet(cmd *cobra.Command, args []string) error {
+	projectCfg, err := loadLinkedProject()
+	if err != nil {
+		return err
+	}
+
+	if len(args) == 1 {
+		setting, err := findProjectSetting(args[0])
+		if err != nil {
+			return err
+		}
+		fmt.Println(setting.get(projectCfg))
+		return nil
+	}
+
+	rows := [][]string{}
+	for _, s := range projectSettings {
+		if value := s.get(projectCfg); value != "" {
+			rows = append(rows, []string{s.key, value})
+		}
+	}
+	ui.Table([]string{"Setting", "Value"}, rows)
+	return nil
+}
+
+func runConfigSet(cmd *cobra.Command, args []string) error {
+	return changeProjectSetting(args[0], args[1])
+}
+
+func runConfigUnset(cmd *cobra.Command, args []string) error {
+	return changeProjectSetting(args[0], "")
+}
+
+// changeProjectSetting sets key to value in cdp.json, or removes it when
+// value is empty, and applies it to the linked app with --apply
+func changeProjectSetting(key, value string) error {
+	setting, err := findProjectSetting(key)
+	if err != nil {
+		return err
+	}
+	projectCfg, err := loadLinkedProject()
+	if err != nil {
+		return err
+	}
+
+	if err := setting.set(projectCfg, strings.TrimSpace(value)); err != nil {
+		ui.Error(fmt.Sprintf("%s %v", key, err))
+		return err
+	}
+	if err := config.SaveProject(projectCfg); err != nil {
+		ui.Error("Failed to save cdp.json")
+		return err
+	}
+	value = setting.get(projectCfg)
+	if value == "" {
+		ui.Success(fmt.Sprintf("Removed %s", key))
+	} else {
+		ui.Success(fmt.Sprintf("Set %s to %s", key, value))
+	}
+
+	if !configApplyFlag {
+		return nil
+	}
+	if setting.remote == "" {
+		ui.Dim(fmt.Sprintf("  %s is only used by cdp; nothing to apply", key))
+		return nil
+	}
+	// An unset setting stops being managed; Coolify keeps its value
+	if value == "" {
+		ui.Dim("  Coolify keeps its current value")
+		return nil
+	}
+
+	appUUID, client, err := getAppUUID()
+	if err != nil {
+		return err
+	}
+	var remoteValue interface{} = value
+	if setting.key == "replicas" {
+		remoteValue = projectCfg.Replicas
+	}
+	err = ui.RunTasks([]ui.Task{
+		{
+			Name:         "apply-setting",
+			ActiveName:   fmt.Sprintf("Updating %s in Coolify...", key),
+			CompleteName: fmt.Sprintf("Updated %s in Coolify", key),
+			Action: func() error {
+				return client.UpdateApplication(appUUID, map[string]interface{}{setting.remote: remoteValue})
+			},
+		},
+	})
+	if err != nil {
+		ui.Error("Failed to update the app; cdp.json was saved")
+		return err
+	}
+	ui.Dim("  Takes effect on the next deploy")
+	return nil
+}
@@ -0,0 +1,306 @@
+package cmd
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/dropalltables/cdp/internal/api"
+	"github.com/dropalltables/cdp/internal/config"
+	"github.com/dropalltables/cdp/internal/ui"
+	"github.com/spf13/cobra"
+)
+
+var (
+	// Flags for cron command
+	cronScheduleFlag  string
+	cronCommandFlag   string
+	cronContainerFlag string
+)
+
+var cronCmd = &cobra.Command{
+	Use:   "cron",
+	Short: "Manage scheduled tasks",
+	Long: `Manage scheduled tasks (cron jobs) for your Coolify application.
+
+Jobs added with 'cron add' are declared in cdp.json so they can be
+recreated with 'cron sync'.`,
+}
+
+var cronLsCmd = &cobra.Command{
+	Use:   "ls",
+	Short: "List scheduled tasks",
+	RunE:  runCronLs,
+}
+
+var cronAddCmd = &cobra.Command{
+	Use:     "add NAME",
+	Short:   "Add a scheduled task",
+	Example: `  cdp cron add cleanup --schedule "0 3 * * *" --command "npm run cleanup"`,
+	Args:    cobra.ExactArgs(1),
+	RunE:    runCronAdd,
+}
+
+var cronRmCmd = &cobra.Command{
+	Use:   "rm NAME",
+	Short: "Remove a scheduled task",
+	Args:  cobra.ExactArgs(1),
+	RunE:  runCronRm,
+}
+
+var cronSyncCmd = &cobra.Command{
+	Use:   "sync",
+	Short: "Create scheduled tasks declared in cdp.json",
+	RunE:  runCronSync,
+}
+
+func init() {
+	rootCmd.AddCommand(cronCmd)
+	cronCmd.AddCommand(cronLsCmd)
+	cronCmd.AddCommand(cronAddCmd)
+	cronCmd.AddCommand(cronRmCmd)
+	cronCmd.AddCommand(cronSyncCmd)
+
+	cronAddCmd.Flags().StringVar(&cronScheduleFlag, "schedule", "", "Cron expression (e.g. \"0 * * * *\")")
+	cronAddCmd.Flags().StringVar(&cronCommandFlag, "command", "", "Command to run")
+	cronAddCmd.Flags().StringVar(&cronContainerFlag, "container", "", "Container to run the command in (optional)")
+	cronAddCmd.MarkFlagRequired("schedule")
+	cronAddCmd.MarkFlagRequired("command")
+}
+
+func runCronLs(cmd *cobra.Command, args []string) error {
+	appUUID, client, err := getAppUUID()
+	if err != nil {
+		return err
+	}
+
+	tasks, err := fetchScheduledTasks(client, appUUID)
+	if err != nil {
+		return err
+	}
+
+	projectCfg, _ := config.LoadProject()
+
+	if len(tasks) == 0 {
+		ui.Warning("No scheduled tasks configured")
+		return nil
+	}
+
+	rows := [][]string{}
+	for _, t := range tasks {
+		declared := "no"
+		if projectCfg != nil && findCronJob(projectCfg.CronJobs, t.Name) >= 0 {
+			declared = "yes"
+		}
+		container := t.Container
+		if container == "" {
+			container = "-"
+		}
+		rows = append(rows, []string{t.Name, t.Frequency, t.Command, container, declared})
+	}
+
+	ui.Spacer()
+	ui.Table([]string{"Name", "Schedule", "Command", "Container", "In cdp.json"}, rows)
+
+	return nil
+}
+
+func runCronAdd(cmd *cobra.Command, args []string) error {
+	name := args[0]
+
+	isMacro := strings.HasPrefix(cronScheduleFlag, "@")
+	if !isMacro && len(strings.Fields(cronScheduleFlag)) != 5 {
+		ui.Error(fmt.Sprintf("Invalid schedule: %s", cronScheduleFlag))
+		ui.Dim("Use a 5-field cron expression (e.g. \"0 * * * *\") or a macro like @daily")
+		return fmt.Errorf("invalid cron schedule %q", cronScheduleFlag)
+	}
+
+	appUUID, client, err := getAppUUID()
+	if err != nil {
+		return err
+	}
+
+	job := config.CronJob{
+		Name:      name,
+		Schedule:  cronScheduleFlag,
+		Command:   cronCommandFlag,
+		Container: cronContainerFlag,
+	}
+
+	err = ui.RunTasks([]ui.Task{createCronTask(client, appUUID, job)})
+	if err != nil {
+		ui.Error(fmt.Sprintf("Failed to add %s", name))
+		return fmt.Errorf("failed to create scheduled task: %w", err)
+	}
+
+	projectCfg, err := config.LoadProject()
+	if err == nil && projectCfg != nil {
+		if i := findCronJob(projectCfg.CronJobs, name); i >= 0 {
+			projectCfg.CronJobs[i] = job
+		} else {
+			projectCfg.CronJobs = append(projectCfg.CronJobs, job)
+		}
+		if err := config.SaveProject(projectCfg); err != nil {
+			ui.Warning("Failed to update cdp.json")
+		}
+	}
+
+	return nil
+}
+
+func runCronRm(cmd *cobra.Command, args []string) error {
+	name := args[0]
+
+	appUUID, client, err := getAppUUID()
+	if err != nil {
+		return err
+	}
+
+	tasks, err := fetchScheduledTasks(client, appUUID)
+	if err != nil {
+		return err
+	}
+
+	var target *api.ScheduledTask
+	for i := range tasks {
+		if tasks[i].Name == name {
+			target = &tasks[i]
+			break
+		}
+	}
+
+	projectCfg, _ := config.LoadProject()
+	declared := projectCfg != nil && findCronJob(projectCfg.CronJobs, name) >= 0
+
+	if target == nil && !declared {
+		ui.Error(fmt.Sprintf("Scheduled task '%s' not found", name))
+		return fmt.Errorf("scheduled task '%s' not found", name)
+	}
+
+	confirmed, err := ui.Confirm(fmt.Sprintf("Remove scheduled task '%s'?", name))
+	if err != nil {
+		return err
+	}
+	if !confirmed {
+		return nil
+	}
+
+	if target != nil {
+		err = ui.RunTasks([]ui.Task{
+			{
+				Name:         "delete-cron",
+				ActiveName:   fmt.Sprintf("Removing %s...", name),
+				CompleteName: fmt.Sprintf("Removed %s", name),
+				Action: func() error {
+					return client.DeleteScheduledTask(appUUID, target.UUID)
+				},
+			},
+		})
+		if err != nil {
+			ui.Error(fmt.Sprintf("Failed to remove %s", name))
+			return fmt.Errorf("failed to delete scheduled task: %w", err)
+		}
+	}
+
+	if declared {
+		i := findCronJob(projectCfg.CronJobs, name)
+		projectCfg.CronJobs = append(projectCfg.CronJobs[:i], projectCfg.CronJobs[i+1:]...)
+		if err := config.SaveProject(projectCfg); err != nil {
+			ui.Warning("Failed to update cdp.json")
+		}
+	}
+
+	return nil
+}
+
+func runCronSync(cmd *cobra.Command, args []string) error {
+	appUUID, client, err := getAppUUID()
+	if err != nil {
+		return err
+	}
+
+	projectCfg, err := config.LoadProject()
+	if err != nil {
+		return fmt.Errorf("failed to load project config: %w", err)
+	}
+	if len(projectCfg.CronJobs) == 0 {
+		ui.Warning("No scheduled tasks declared in cdp.json")
+		return nil
+	}
+
+	existing, err := fetchScheduledTasks(client, appUUID)
+	if err != nil {
+		return err
+	}
+	existingNames := map[string]bool{}
+	for _, t := range existing {
+		existingNames[t.Name] = true
+	}
+
+	var tasks []ui.Task
+	for _, job := range projectCfg.CronJobs {
+		if existingNames[job.Name] {
+			continue
+		}
+		tasks = append(tasks, createCronTask(client, appUUID, job))
+	}
+
+	if len(tasks) == 0 {
+		ui.Success("Scheduled tasks are up to date")
+		return nil
+	}
+
+	if err := ui.RunTasks(tasks); err != nil {
+		ui.Error("Failed to sync scheduled tasks")
+		return fmt.Errorf("failed to create scheduled task: %w", err)
+	}
+
+	return nil
+}
+
+func fetchScheduledTasks(client *api.Client, appUUID string) ([]api.ScheduledTask, error) {
+	var tasks []api.ScheduledTask
+	err := ui.RunTasks([]ui.Task{
+		{
+			Name:         "load-cron",
+			ActiveName:   "Loading scheduled tasks...",
+			CompleteName: "Loaded scheduled tasks",
+			Action: func() error {
+				var err error
+				tasks, err = client.ListScheduledTasks(appUUID)
+				return err
+			},
+		},
+	})
+	if err != nil {
+		ui.Error("Failed to load scheduled tasks")
+		return nil, fmt.Errorf("failed to fetch scheduled tasks: %w", err)
+	}
+	return tasks, nil
+}
+
+func createCronTask(client *api.Client, appUUID string, job config.CronJob) ui.Task {
+	return ui.Task{
+		Name:         "create-cron",
+		ActiveName:   fmt.Sprintf("Adding %s...", job.Name),
+		CompleteName: fmt.Sprintf("Added %s (%s)", job.Name, job.Schedule),
+		Action: func() error {
+			_, err := client.CreateScheduledTask(appUUID, &api.CreateScheduledTaskRequest{
+				Name:      job.Name,
+				Command:   job.Command,
+				Frequency: job.Schedule,
+				Container: job.Container,
+				Enabled:   true,
+			})
+			return err
+		},
+	}
+}
+
+func findCronJob(jobs []config.CronJob, name string) int {
+	for i, j := range jobs {
+		if j.Name == name {
+			return i
+		}
+	}
+	return -1
+}
@@ -8,30 +8,72 @@ import (
 	"github.com/dropalltables/cdp/internal/api"
 	"github.com/dropalltables/cdp/internal/config"
 	"github.com/dropalltables/cdp/internal/deploy"
+	"github.com/dropalltables/cdp/internal/docker"
 	"github.com/dropalltables/cdp/internal/ui"
 	"github.com/spf13/cobra"
 )
 
+var (
+	// Flags for deploy command
+	deployAllFlag bool
+)
+
 var deployCmd = &cobra.Command{
 	Use:   "deploy",
 	Short: "Deploy the current directory to Coolify",
 	Long: `Deploy the current project to Coolify.
 
-Manual deploys always go to production.
-Preview deployments are created automatically by Coolify from GitHub Pull Requests.`,
+Deploys go to production unless --env names another Coolify environment of
+the project, e.g. --env staging. The first deploy to an environment offers to
+create its app as a copy of the production app. Preview deployments are
+created automatically by Coolify from GitHub Pull Requests.
+
+In a monorepo, list the app directories in cdp.workspace.json and run
+'cdp deploy --all' from its directory to deploy them all, each after the
+apps named in its depends_on.`,
+	Example: `  cdp deploy
+  cdp deploy --env staging`,
 	RunE: func(cmd *cobra.Command, args []string) error {
-		return runDeploy()
+		target, err := resolveDeployEnv(cmd)
+		if err != nil {
+			return err
+		}
+		if deployAllFlag {
+			return runDeployAll(target)
+		}
+		return runDeploy(target)
 	},
 }
 
+var deployCancelCmd = &cobra.Command{
+	Use:   "cancel",
+	Short: "Cancel the running deployment",
+	Long:  "Find the queued or in-progress deployment for this project and cancel it.",
+	Args:  cobra.NoArgs,
+	RunE:  runDeployCancel,
+}
+
 func init() {
 	rootCmd.AddCommand(deployCmd)
+	deployCmd.AddCommand(deployCancelCmd)
+
+	addEnvFlag(deployCmd, false, envProduction)
+	deployCmd.Flags().BoolVar(&deployAllFlag, "all", false, "Deploy every app in cdp.workspace.json")
+	deployCmd.Flags().BoolVar(&skipPreflightFlag, "skip-preflight", false, "Deploy even if preflight checks fail")
+	deployCmd.Flags().BoolVar(&forceFlag, "force", false, "Deploy a static site even if its output is unchanged")
+	deployCmd.Flags().StringVar(&overridePolicyFlag, "override-policy", "", "Deploy despite org policy violations, giving a reason")
+	deployCmd.Flags().StringArrayVar(&buildArgFlags, "build-arg", nil, "Docker build argument KEY=value (repeatable)")
+	deployCmd.Flags().StringVar(&platformsFlag, "platforms", "", "Build one image per platform, e.g. linux/amd64,linux/arm64")
 }
 
-func runDeploy() error {
+func runDeploy(target deployEnv) error {
 	if err := checkLogin(); err != nil {
 		return err
 	}
+	if target.Preview() {
+		ui.Error("Preview deployments are created by Coolify from pull requests")
+		return fmt.Errorf("can't deploy to the preview environment")
+	}
 
 	globalCfg, err := config.LoadGlobal()
 	if err != nil {
@@ -47,9 +89,17 @@ func runDeploy() error {
 
 	isFirstDeploy := false
 
-	// First-time setup if no project config exists
-	if projectCfg == nil {
-		projectCfg, err = deploy.FirstTimeSetup(client, globalCfg)
+	// Other environments are copies of the production app, so it must exist
+	if target.Named() && (projectCfg == nil || projectCfg.AppUUID == "") {
+		ui.Error(fmt.Sprintf("Deploy to production before deploying to %s", target.Name))
+		ui.Dim(fmt.Sprintf("Run '%s' first", execName()))
+		return fmt.Errorf("no production app to copy")
+	}
+
+	// First-time setup if no project config exists, or if it only carries
+	// build settings (e.g. from a template) and no Coolify server yet
+	if projectCfg == nil || (projectCfg.ServerUUID == "" && projectCfg.AppUUID == "") {
+		projectCfg, err = deploy.FirstTimeSetup(client, globalCfg, projectCfg)
 		if err != nil {
 			// Exit silently on interrupt
 			if strings.Contains(err.Error(), "interrupted") {
@@ -60,14 +110,37 @@ func runDeploy() error {
 		isFirstDeploy = true
 	}
 
-	// All manual deploys go to production (PR 0)
-	// Preview deployments are created automatically by Coolify from GitHub PRs
+	// Manual deploys never target a pull request (PR 0); preview
+	// deployments are created automatically by Coolify from GitHub PRs
 	prNumber := 0
-	deploymentType := "production"
+	if target.Named() {
+		projectCfg, err = envDeployConfig(client, projectCfg, target)
+		if err != nil || projectCfg == nil {
+			return err
+		}
+	}
+
+	// Static sites whose sources are unchanged since the last deploy have
+	// nothing new to ship
+	sourceHash, err := deploy.StaticSourceHash(projectCfg)
+	if err != nil {
+		ui.Warning(fmt.Sprintf("Failed to hash the site's sources: %v", err))
+	}
+	if !isFirstDeploy && !forceFlag && sourceHash != "" && sourceHash == config.LastStaticHash(projectCfg.AppUUID) {
+		ui.Success("No changes to the site's sources since the last deploy")
+		ui.Dim("Run with --force to deploy anyway")
+		return nil
+	}
+
+	// Enforce organization guardrails before asking to confirm a deploy
+	// they'd block
+	if err := deploy.EnforcePolicies(globalCfg, projectCfg, target.Production(), overridePolicyFlag); err != nil {
+		return err
+	}
 
 	// Confirm deployments (except first deploy)
 	if !isFirstDeploy {
-		confirmed, err := ui.Confirm("Deploy to production?")
+		confirmed, err := ui.ConfirmWithOptions(fmt.Sprintf("Deploy to %s?", target.Name), ui.ConfirmOptions{Default: true})
 		if err != nil {
 			return err
 		}
@@ -76,17 +149,202 @@ func runDeploy() error {
 		}
 	}
 
+	// Tag this deploy so its logs can be isolated later; the app must exist
+	// to receive the env var, so first deploys go untraced
+	traceID := ""
+	if projectCfg.TraceDeploys && projectCfg.AppUUID != "" {
+		traceID = deploy.NewTraceID()
+	}
+
 	ui.Spacer()
 	ui.KeyValue("Project", projectCfg.Name)
-	ui.KeyValue("Type", deploymentType)
+	ui.KeyValue("Environment", target.Name)
 	ui.KeyValue("Method", projectCfg.DeployMethod)
+	if traceID != "" {
+		ui.KeyValue("Trace ID", traceID)
+	}
 
 	// Check verbose mode
 	verbose := IsVerbose()
 
+	// Refuse to start a build that will run out of disk
+	if !skipPreflightFlag {
+		if err := deploy.CheckDiskSpace(client, projectCfg.ServerUUID, verbose); err != nil {
+			return err
+		}
+	}
+
+	if traceID != "" {
+		err := ui.RunTasks([]ui.Task{
+			{
+				Name:         "set-trace-id",
+				ActiveName:   "Setting trace ID...",
+				CompleteName: fmt.Sprintf("Set %s", deploy.TraceEnvVar),
+				Action: func() error {
+					return deploy.SetTraceID(client, projectCfg.AppUUID, traceID, target.Preview())
+				},
+			},
+		})
+		if err != nil {
+			ui.Warning("Failed to set trace ID; deploying without it")
+		} else if err := config.SaveTraceID(projectCfg.AppUUID, traceID); err != nil {
+			ui.Warning("Failed to record the trace ID")
+		}
+	}
+
+	build, err := resolveBuildInputs(projectCfg)
+	if err != nil {
+		return err
+	}
+
 	// Deploy based on method
-	if projectCfg.DeployMethod == config.DeployMethodDocker {
-		return deploy.DeployDocker(client, globalCfg, projectCfg, prNumber, verbose)
+	if err := deploy.Run(client, globalCfg, projectCfg, prNumber, build, verbose); err != nil {
+		return err
 	}
-	return deploy.DeployGit(client, globalCfg, projectCfg, prNumber, verbose)
+
+	// Hashed again, as a git deploy commits the changes it shipped
+	if sourceHash != "" && projectCfg.AppUUID != "" {
+		if sourceHash, err = deploy.StaticSourceHash(projectCfg); err == nil && sourceHash != "" {
+			if err := config.SaveStaticHash(projectCfg.AppUUID, sourceHash); err != nil {
+				ui.Warning("Failed to record the deployed source hash")
+			}
+		}
+	}
+	return nil
+}
+
+// resolveBuildInputs merges --build-arg over build_args in cdp.json and
+// reads the values of build_secrets from the environment or .env. Git
+// deploys are built by Coolify, so the flags don't apply to them.
+func resolveBuildInputs(projectCfg *config.ProjectConfig) (deploy.BuildInputs, error) {
+	inputs := deploy.BuildInputs{Args: map[string]string{}, Secrets: map[string]string{}}
+	if projectCfg.DeployMethod != config.DeployMethodDocker {
+		if len(buildArgFlags) > 0 || platformsFlag != "" {
+			ui.Warning("--build-arg and --platforms only apply to Docker-based deploys; ignoring them")
+		}
+		return inputs, nil
+	}
+
+	// --platforms replaces the matrix from cdp.json, with default tag suffixes
+	inputs.Platforms = projectCfg.Platforms
+	if platformsFlag != "" {
+		inputs.Platforms = nil
+		for _, p := range docker.Platforms(platformsFlag) {
+			inputs.Platforms = append(inputs.Platforms, config.PlatformTarget{Platform: p})
+		}
+	}
+
+	for k, v := range projectCfg.BuildArgs {
+		inputs.Args[k] = v
+	}
+	for _, arg := range buildArgFlags {
+		// Like docker, a bare KEY takes its value from the environment
+		key, value, ok := strings.Cut(arg, "=")
+		if !ok {
+			value = os.Getenv(key)
+		}
+		if key == "" {
+			return inputs, fmt.Errorf("invalid --build-arg %q (use KEY=value)", arg)
+		}
+		inputs.Args[key] = value
+	}
+
+	if len(projectCfg.BuildSecrets) == 0 {
+		return inputs, nil
+	}
+	dotEnv := map[string]string{}
+	if vars, err := readDotEnv(".env"); err == nil {
+		for _, v := range vars {
+			dotEnv[v.Key] = v.Value
+		}
+	}
+	for _, name := range projectCfg.BuildSecrets {
+		value, ok := os.LookupEnv(name)
+		if !ok {
+			value, ok = dotEnv[name]
+		}
+		if !ok {
+			ui.Error(fmt.Sprintf("Build secret %s is not set", name))
+			ui.Dim("Export it in your shell or add it to .env")
+			return inputs, fmt.Errorf("build secret %s is not set", name)
+		}
+		inputs.Secrets[name] = value
+	}
+	return inputs, nil
+}
+
+func runDeployCancel(cmd *cobra.Command, args []string) error {
+	appUUID, client, err := getAppUUID()
+	if err != nil {
+		return err
+	}
+
+	var active *api.Deployment
+	err = ui.RunTasks([]ui.Task{
+		{
+			Name:         "find-deployment",
+			ActiveName:   "Looking for running deployment...",
+			CompleteName: "Checked running deployments",
+			Action: func() error {
+				deployments, err := client.ListDeployments(appUUID)
+				if err != nil {
+					return err
+				}
+				for i := range deployments {
+					status := strings.ToLower(deployments[i].Status)
+					if status == "queued" || status == "in_progress" {
+						active = &deployments[i]
+						return nil
+					}
+				}
+				return nil
+			},
+		},
+	})
+	if err != nil {
+		ui.Error("Failed to list deployments")
+		return fmt.Errorf("failed to list deployments: %w", err)
+	}
+
+	if active == nil {
+		ui.Warning("No running deployment found")
+		return nil
+	}
+
+	deployUUID := active.DeploymentUUID
+	if deployUUID == "" {
+		deployUUID = active.UUID
+	}
+
+	ui.KeyValue("Deployment", deployUUID)
+	ui.KeyValue("Status", active.Status)
+	if commit := deploymentCommit(*active); commit != "" {
+		ui.KeyValue("Commit", commit)
+	}
+	ui.Spacer()
+
+	confirmed, err := ui.Confirm("Cancel this deployment?")
+	if err != nil {
+		return err
+	}
+	if !confirmed {
+		return nil
+	}
+
+	err = ui.RunTasks([]ui.Task{
+		{
+			Name:         "cancel-deployment",
+			ActiveName:   "Cancelling deployment...",
+			CompleteName: "Deployment cancelled",
+			Action: func() error {
+				return client.CancelDeployment(deployUUID)
+			},
+		},
+	})
+	if err != nil {
+		ui.Error("Failed to cancel deployment")
+		return fmt.Errorf("failed to cancel deployment: %w", err)
+	}
+
+	return nil
 }
@@ -0,0 +1,211 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/dropalltables/cdp/internal/api"
+	"github.com/dropalltables/cdp/internal/ui"
+	"github.com/spf13/cobra"
+)
+
+var (
+	// Flags for deployments command
+	deploymentsJSONFlag  bool
+	deploymentsLimitFlag int
+)
+
+var deploymentsCmd = &cobra.Command{
+	Use:   "deployments",
+	Short: "Inspect deployment history",
+	Long:  "Inspect the deployment history of the linked application.",
+}
+
+var deploymentsLsCmd = &cobra.Command{
+	Use:   "ls",
+	Short: "List recent deployments",
+	RunE:  runDeploymentsLs,
+}
+
+func init() {
+	rootCmd.AddCommand(deploymentsCmd)
+	deploymentsCmd.AddCommand(deploymentsLsCmd)
+
+	deploymentsLsCmd.Flags().BoolVar(&deploymentsJSONFlag, "json", false, "Output as JSON")
+	deploymentsLsCmd.Flags().IntVar(&deploymentsLimitFlag, "limit", 10, "Maximum number of deployments to show")
+}
+
+// deploymentRecord is the JSON shape emitted by 'deployments ls --json'
+type deploymentRecord struct {
+	UUID       string  `json:"uuid"`
+	Commit     string  `json:"commit"`
+	Message    string  `json:"message"`
+	Status     string  `json:"status"`
+	CreatedAt  string  `json:"created_at"`
+	FinishedAt string  `json:"finished_at"`
+	Duration   float64 `json:"duration_seconds,omitempty"`
+}
+
+func runDeploymentsLs(cmd *cobra.Command, args []string) error {
+	appUUID, client, err := getAppUUID()
+	if err != nil {
+		return err
+	}
+
+	var deployments []api.Deployment
+	fetch := func() error {
+		var err error
+		deployments, err = client.ListDeploymentHistory(appUUID)
+		return err
+	}
+
+	// Keep stdout clean for machine-readable output
+	if deploymentsJSONFlag {
+		err = fetch()
+	} else {
+		err = ui.RunTasks([]ui.Task{
+			{
+				Name:         "fetch-history",
+				ActiveName:   "Fetching deployment history...",
+				CompleteName: "Fetched deployment history",
+				Action:       fetch,
+			},
+		})
+	}
+	if err != nil {
+		ui.Error("Failed to fetch deployment history")
+		return fmt.Errorf("failed to fetch deployment history: %w", err)
+	}
+
+	if deploymentsLimitFlag > 0 && len(deployments) > deploymentsLimitFlag {
+		deployments = deployments[:deploymentsLimitFlag]
+	}
+
+	if deploymentsJSONFlag {
+		records := make([]deploymentRecord, 0, len(deployments))
+		for _, d := range deployments {
+			r := deploymentRecord{
+				UUID:       d.DeploymentUUID,
+				Commit:     deploymentCommit(d),
+				Message:    d.CommitMessage,
+				Status:     d.Status,
+				CreatedAt:  d.CreatedAt,
+				FinishedAt: d.UpdatedAt,
+			}
+			if dur, ok := deploymentDuration(d); ok {
+				r.Duration = dur.Seconds()
+			}
+			records = append(records, r)
+		}
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(records)
+	}
+
+	if len(deployments) == 0 {
+		ui.Warning("No deployments found")
+		return nil
+	}
+
+	rows := [][]string{}
+	for _, d := range deployments {
+		commit := deploymentCommit(d)
+		if len(commit) > 7 {
+			commit = commit[:7]
+		}
+		if commit == "" {
+			commit = "-"
+		}
+
+		msg := firstLine(d.CommitMessage)
+		if len(msg) > 40 {
+			msg = msg[:40] + "..."
+		}
+		if msg == "" {
+			msg = "-"
+		}
+
+		duration := "-"
+		if dur, ok := deploymentDuration(d); ok {
+			duration = dur.Round(time.Second).String()
+		}
+
+		rows = append(rows, []string{commit, msg, d.Status, duration, formatTimestamp(d.CreatedAt)})
+	}
+
+	ui.Spacer()
+	ui.Table([]string{"Commit", "Message", "Status", "Duration", "Started"}, rows)
+
+	return nil
+}
+
+// deploymentCommit returns the commit SHA recorded for a deployment
+func deploymentCommit(d api.Deployment) string {
+	if d.GitCommitSha != "" {
+		return d.GitCommitSha
+	}
+	return d.Commit
+}
+
+// deploymentPR returns the pull request number of a preview deployment,
+// or 0 for production deployments
+func deploymentPR(d api.Deployment) int {
+	switch v := d.PullRequestID.(type) {
+	case float64:
+		return int(v)
+	case string:
+		pr, _ := strconv.Atoi(v)
+		return pr
+	}
+	return 0
+}
+
+// deploymentDuration returns how long a finished deployment took
+func deploymentDuration(d api.Deployment) (time.Duration, bool) {
+	status := strings.ToLower(d.Status)
+	if status == "queued" || status == "in_progress" {
+		return 0, false
+	}
+	start, err := parseTimestamp(d.CreatedAt)
+	if err != nil {
+		return 0, false
+	}
+	end, err := parseTimestamp(d.UpdatedAt)
+	if err != nil || end.Before(start) {
+		return 0, false
+	}
+	return end.Sub(start), true
+}
+
+// parseTimestamp parses timestamps in the formats Coolify returns
+func parseTimestamp(ts string) (time.Time, error) {
+	for _, layout := range []string{time.RFC3339Nano, "2006-01-02 15:04:05"} {
+		if t, err := time.Parse(layout, ts); err == nil {
+			return t, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("invalid timestamp %q", ts)
+}
+
+// formatTimestamp renders a Coolify timestamp in local time
+func formatTimestamp(ts string) string {
+	t, err := parseTimestamp(ts)
+	if err != nil {
+		if ts == "" {
+			return "-"
+		}
+		return ts
+	}
+	return t.Local().Format("2006-01-02 15:04")
+}
+
+func firstLine(s string) string {
+	if i := strings.IndexByte(s, '\n'); i >= 0 {
+		return s[:i]
+	}
+	return s
+}
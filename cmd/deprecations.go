@@ -0,0 +1,116 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/dropalltables/cdp/internal/config"
+	"github.com/dropalltables/cdp/internal/ui"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+)
+
+// flagDeprecation maps a deprecated flag onto its replacement flag
+type flagDeprecation struct {
+	config.Deprecation
+	replacement string // name of the replacement flag
+	value       string // value given to it; empty passes the old value through
+}
+
+var (
+	// Deprecated flags by their definition, shared by subcommands that
+	// inherit them
+	deprecatedFlags = map[*pflag.Flag]flagDeprecation{}
+
+	// Deprecated commands
+	deprecatedCommands = map[*cobra.Command]config.Deprecation{}
+)
+
+// deprecateFlag hides the flag name in flags; when it is used, the
+// replacement flag is set to value instead and a warning is shown. A
+// boolean flag given a value is only mapped when it is true.
+func deprecateFlag(flags *pflag.FlagSet, name, replacement, value string) {
+	f := flags.Lookup(name)
+	if f == nil {
+		panic("deprecateFlag: unknown flag " + name)
+	}
+	f.Hidden = true
+
+	shown := "--" + replacement
+	if value != "" {
+		shown += " " + value
+	}
+	deprecatedFlags[f] = flagDeprecation{
+		Deprecation: config.Deprecation{Kind: config.DeprecatedFlag, Name: "--" + name, Replacement: shown},
+		replacement: replacement,
+		value:       value,
+	}
+}
+
+// deprecateCommand hides cmd from help and warns when it runs
+func deprecateCommand(cmd *cobra.Command, replacement string) {
+	cmd.Hidden = true
+	deprecatedCommands[cmd] = config.Deprecation{
+		Kind:        config.DeprecatedCommand,
+		Name:        fmt.Sprintf("'%s'", strings.TrimPrefix(cmd.CommandPath(), rootCmd.Name()+" ")),
+		Replacement: replacement,
+	}
+}
+
+// applyDeprecations warns about deprecated flags, commands and config
+// fields used by this invocation and maps flags onto their replacements
+func applyDeprecations(cmd *cobra.Command) error {
+	var used []config.Deprecation
+
+	if d, ok := deprecatedCommands[cmd]; ok {
+		used = append(used, d)
+	}
+
+	var mapErr error
+	cmd.Flags().Visit(func(f *pflag.Flag) {
+		d, ok := deprecatedFlags[f]
+		if !ok || mapErr != nil {
+			return
+		}
+		used = append(used, d.Deprecation)
+		if d.value != "" && f.Value.Type() == "bool" && f.Value.String() != "true" {
+			return
+		}
+
+		value := d.value
+		if value == "" {
+			value = f.Value.String()
+		}
+		target := cmd.Flags().Lookup(d.replacement)
+		if target == nil {
+			return
+		}
+		if target.Changed && target.Value.String() != value {
+			mapErr = fmt.Errorf("%s conflicts with --%s %s", d.Name, d.replacement, target.Value.String())
+			return
+		}
+		mapErr = cmd.Flags().Set(d.replacement, value)
+	})
+	if mapErr != nil {
+		ui.Error(mapErr.Error())
+		return mapErr
+	}
+
+	if projectCfg, err := config.LoadProject(); err == nil {
+		used = append(used, config.DeprecatedFieldsIn(projectCfg)...)
+	}
+
+	// Warnings go to stderr, as they print before any command's output
+	warned := false
+	for _, d := range used {
+		if config.ShouldWarnDeprecation(d) {
+			ui.StderrWarning(d.Message())
+			warned = true
+		}
+	}
+	if warned {
+		fmt.Fprintln(os.Stderr)
+	}
+	return nil
+}
@@ -0,0 +1,405 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strings"
+
+	"github.com/dropalltables/cdp/internal/api"
+	"github.com/dropalltables/cdp/internal/config"
+	"github.com/dropalltables/cdp/internal/docker"
+	"github.com/dropalltables/cdp/internal/git"
+	"github.com/dropalltables/cdp/internal/ui"
+	"github.com/spf13/cobra"
+)
+
+var doctorCmd = &cobra.Command{
+	Use:   "doctor",
+	Short: "Diagnose configuration and environment problems",
+	Long: `Run a deep diagnostic of everything cdp depends on: config file permissions,
+Coolify reachability, API token abilities and version, the GitHub token's
+scopes, the Docker daemon and buildx, git, and the validity of cdp.json.
+
+Every failed check is listed with how to fix it. Unlike 'health', doctor
+exits non-zero when a check fails.`,
+	Args: cobra.NoArgs,
+	RunE: runDoctor,
+}
+
+func init() {
+	rootCmd.AddCommand(doctorCmd)
+}
+
+// Doctor check outcomes
+const (
+	doctorOK   = "ok"
+	doctorWarn = "warn"
+	doctorFail = "fail"
+)
+
+// doctorResult is the outcome of one diagnostic check
+type doctorResult struct {
+	name   string
+	status string // doctorOK, doctorWarn or doctorFail
+	detail string
+	fix    string // what to do about a warning or failure
+}
+
+func runDoctor(cmd *cobra.Command, args []string) error {
+	cfg, err := config.LoadGlobal()
+	if err != nil {
+		ui.Error("Failed to load configuration")
+		ui.Dim("  " + err.Error())
+		return fmt.Errorf("failed to load configuration: %w", err)
+	}
+	projectCfg, _ := config.LoadProject()
+
+	var results []doctorResult
+	err = ui.RunTasks([]ui.Task{
+		doctorCheck(&results, "doctor-config", "Checking config file...", checkConfigFile),
+		doctorCheck(&results, "doctor-coolify", "Checking Coolify...", func() []doctorResult { return checkCoolify(cfg) }),
+		doctorCheck(&results, "doctor-github", "Checking GitHub token...", func() []doctorResult { return checkGitHubToken(cfg, projectCfg) }),
+		doctorCheck(&results, "doctor-docker", "Checking Docker...", func() []doctorResult { return checkDockerTools(projectCfg) }),
+		doctorCheck(&results, "doctor-git", "Checking git...", checkGit),
+		doctorCheck(&results, "doctor-project", "Checking project config...", checkProjectConfig),
+	})
+	if err != nil {
+		return err
+	}
+
+	failed, warned := printDoctorResults(results)
+
+	ui.Spacer()
+	if failed > 0 {
+		ui.Error(fmt.Sprintf("%d of %d checks failed", failed, len(results)))
+		return fmt.Errorf("%d checks failed", failed)
+	}
+	if warned > 0 {
+		ui.Warning(fmt.Sprintf("All checks passed with %d warnings", warned))
+		return nil
+	}
+	ui.Success("All checks passed")
+	return nil
+}
+
+// doctorCheck wraps a check as a task that appends to results
+func doctorCheck(results *[]doctorResult, name, activeName string, fn func() []doctorResult) ui.Task {
+	return ui.Task{
+		Name:         name,
+		ActiveName:   activeName,
+		CompleteName: strings.Replace(strings.TrimSuffix(activeName, "..."), "Checking", "Checked", 1),
+		Action: func() error {
+			*results = append(*results, fn()...)
+			return nil
+		},
+	}
+}
+
+// printDoctorResults shows the results as a table followed by how to fix
+// each problem, and counts the failures and warnings
+func printDoctorResults(results []doctorResult) (failed, warned int) {
+	rows := make([][]string, len(results))
+	for i, r := range results {
+		rows[i] = []string{r.name, r.status, r.detail}
+		switch r.status {
+		case doctorFail:
+			failed++
+		case doctorWarn:
+			warned++
+		}
+	}
+	ui.Spacer()
+	ui.Table([]string{"Check", "Status", "Detail"}, rows)
+
+	if failed+warned > 0 {
+		ui.Spacer()
+		ui.Bold("How to fix")
+		for _, r := range results {
+			if r.status == doctorOK || r.fix == "" {
+				continue
+			}
+			if r.status == doctorFail {
+				ui.Error(fmt.Sprintf("%s: %s", r.name, r.detail))
+			} else {
+				ui.Warning(fmt.Sprintf("%s: %s", r.name, r.detail))
+			}
+			ui.Dim("  " + r.fix)
+		}
+	}
+	return failed, warned
+}
+
+// checkConfigFile checks that the global config, which holds the API
+// tokens, exists and is only readable by the user
+func checkConfigFile() []doctorResult {
+	path, err := config.GetConfigPath()
+	if err != nil {
+		return []doctorResult{{name: "Config file", status: doctorFail, detail: err.Error()}}
+	}
+	info, err := os.Stat(path)
+	if os.IsNotExist(err) {
+		if overrides := config.EnvOverrides(); len(overrides) > 0 && config.IsLoggedIn() {
+			return []doctorResult{{
+				name:   "Config file",
+				status: doctorOK,
+				detail: "Not needed; using " + strings.Join(overrides, ", "),
+			}}
+		}
+		return []doctorResult{{
+			name:   "Config file",
+			status: doctorFail,
+			detail: "Not found",
+			fix:    fmt.Sprintf("Run '%s login' to create it", execName()),
+		}}
+	}
+	if err != nil {
+		return []doctorResult{{name: "Config file", status: doctorFail, detail: err.Error()}}
+	}
+
+	// Windows doesn't have Unix permission bits
+	if runtime.GOOS == "windows" {
+		return []doctorResult{{name: "Config file", status: doctorOK, detail: path}}
+	}
+	if perm := info.Mode().Perm(); perm&0077 != 0 {
+		return []doctorResult{{
+			name:   "Config file",
+			status: doctorFail,
+			detail: fmt.Sprintf("%s is readable by others (%04o)", path, perm),
+			fix:    fmt.Sprintf("Run 'chmod 600 %s'", path),
+		}}
+	}
+	results := []doctorResult{{name: "Config file", status: doctorOK, detail: path}}
+	dir := filepath.Dir(path)
+	if dirInfo, err := os.Stat(dir); err == nil && dirInfo.Mode().Perm()&0077 != 0 {
+		results = append(results, doctorResult{
+			name:   "Config directory",
+			status: doctorWarn,
+			detail: fmt.Sprintf("%s is accessible by others (%04o)", dir, dirInfo.Mode().Perm()),
+			fix:    fmt.Sprintf("Run 'chmod 700 %s'", dir),
+		})
+	}
+	return results
+}
+
+// checkCoolify checks that the API is reachable, that the token has the
+// abilities cdp needs, and that the Coolify version is supported
+func checkCoolify(cfg *config.GlobalConfig) []doctorResult {
+	if cfg.CoolifyURL == "" || cfg.CoolifyToken == "" {
+		return []doctorResult{{
+			name:   "Coolify API",
+			status: doctorFail,
+			detail: "Not configured",
+			fix:    fmt.Sprintf("Run '%s login'", execName()),
+		}}
+	}
+
+	client := api.NewClient(cfg.CoolifyURL, cfg.CoolifyToken)
+	abilities, err := client.TokenAbilities()
+	if err != nil {
+		if api.IsUnauthorized(err) {
+			return []doctorResult{{
+				name:   "Coolify API",
+				status: doctorFail,
+				detail: "Token rejected",
+				fix:    fmt.Sprintf("Create a new API token in Coolify under Keys & Tokens, then run '%s login'", execName()),
+			}}
+		}
+		return []doctorResult{{
+			name:   "Coolify API",
+			status: doctorFail,
+			detail: fmt.Sprintf("%s unreachable", cfg.CoolifyURL),
+			fix:    "Check that the URL is right and Coolify is running: " + err.Error(),
+		}}
+	}
+	results := []doctorResult{{name: "Coolify API", status: doctorOK, detail: cfg.CoolifyURL}}
+
+	var missing []string
+	for _, ability := range []string{api.AbilityRead, api.AbilityWrite, api.AbilityDeploy} {
+		if !abilities[ability] {
+			missing = append(missing, ability)
+		}
+	}
+	if len(missing) > 0 {
+		results = append(results, doctorResult{
+			name:   "Coolify token",
+			status: doctorFail,
+			detail: "Missing " + strings.Join(missing, ", "),
+			fix:    fmt.Sprintf("Create a token with read, write and deploy abilities (or root) in Coolify under Keys & Tokens, then run '%s login'", execName()),
+		})
+	} else {
+		results = append(results, doctorResult{name: "Coolify token", status: doctorOK, detail: "read, write, deploy"})
+	}
+
+	v, err := client.GetVersion()
+	switch {
+	case err != nil:
+		results = append(results, doctorResult{
+			name:   "Coolify version",
+			status: doctorWarn,
+			detail: "Unknown",
+			fix:    fmt.Sprintf("Make sure Coolify is %s or newer", api.MinCoolifyVersion),
+		})
+	case !api.IsSupportedVersion(v):
+		results = append(results, doctorResult{
+			name:   "Coolify version",
+			status: doctorFail,
+			detail: v + " is too old",
+			fix:    fmt.Sprintf("Upgrade Coolify to %s or newer from its Settings page", api.MinCoolifyVersion),
+		})
+	default:
+		results = append(results, doctorResult{name: "Coolify version", status: doctorOK, detail: v})
+	}
+	return results
+}
+
+// checkGitHubToken checks that the GitHub token can create and push to
+// repositories. Only git deploys need it.
+func checkGitHubToken(cfg *config.GlobalConfig, projectCfg *config.ProjectConfig) []doctorResult {
+	needed := projectCfg == nil || projectCfg.DeployMethod == config.DeployMethodGit
+	if cfg.GitHubToken == "" {
+		r := doctorResult{name: "GitHub token", status: doctorOK, detail: "Not configured"}
+		if needed {
+			r.status = doctorWarn
+			r.detail = "Not configured (needed for git deploys)"
+			r.fix = fmt.Sprintf("Run '%s login' and add a GitHub token with the repo scope", execName())
+		}
+		return []doctorResult{r}
+	}
+
+	scopes, reported, err := git.NewGitHubClient(cfg.GitHubToken).TokenScopes()
+	if err != nil {
+		return []doctorResult{{
+			name:   "GitHub token",
+			status: doctorFail,
+			detail: "Rejected by GitHub",
+			fix:    fmt.Sprintf("Create a new token at https://github.com/settings/tokens with the repo scope, then run '%s login'", execName()),
+		}}
+	}
+	if !reported {
+		return []doctorResult{{name: "GitHub token", status: doctorOK, detail: "Fine-grained token (scopes not reported)"}}
+	}
+	for _, scope := range scopes {
+		if scope == "repo" {
+			return []doctorResult{{name: "GitHub token", status: doctorOK, detail: strings.Join(scopes, ", ")}}
+		}
+	}
+	detail := "No scopes"
+	if len(scopes) > 0 {
+		detail = "Scopes " + strings.Join(scopes, ", ") + " lack repo"
+	}
+	return []doctorResult{{
+		name:   "GitHub token",
+		status: doctorFail,
+		detail: detail,
+		fix:    fmt.Sprintf("Add the repo scope at https://github.com/settings/tokens, or create a new token and run '%s login'", execName()),
+	}}
+}
+
+// checkDockerTools checks the Docker daemon and buildx, which are required
+// for Docker deploys and multi-platform builds respectively
+func checkDockerTools(projectCfg *config.ProjectConfig) []doctorResult {
+	dockerDeploy := projectCfg != nil && projectCfg.DeployMethod == config.DeployMethodDocker
+	problem := doctorWarn
+	if dockerDeploy {
+		problem = doctorFail
+	}
+
+	if !docker.IsDockerAvailable() {
+		return []doctorResult{{
+			name:   "Docker",
+			status: problem,
+			detail: "Daemon not running (needed for Docker deploys)",
+			fix:    "Start Docker Desktop or the docker service, or install Docker from https://docs.docker.com/get-docker/",
+		}}
+	}
+	results := []doctorResult{{name: "Docker", status: doctorOK, detail: "Daemon running"}}
+
+	multiPlatform := projectCfg != nil && (docker.IsMultiPlatform(projectCfg.Platform) || len(projectCfg.Platforms) > 0)
+	if docker.IsBuildxAvailable() {
+		results = append(results, doctorResult{name: "Docker buildx", status: doctorOK, detail: "Installed"})
+	} else {
+		status := doctorWarn
+		if dockerDeploy && multiPlatform {
+			status = doctorFail
+		}
+		results = append(results, doctorResult{
+			name:   "Docker buildx",
+			status: status,
+			detail: "Not installed (needed for multi-platform builds)",
+			fix:    "Install the buildx plugin: https://docs.docker.com/build/install-buildx/",
+		})
+	}
+
+	if projectCfg != nil && projectCfg.LocalBuilder == config.LocalBuilderNixpacks && !docker.IsNixpacksAvailable() {
+		results = append(results, doctorResult{
+			name:   "nixpacks",
+			status: doctorWarn,
+			detail: "Not installed; builds fall back to a Dockerfile",
+			fix:    "Install nixpacks: https://nixpacks.com/docs/install",
+		})
+	}
+	return results
+}
+
+// checkGit checks that git is installed
+func checkGit() []doctorResult {
+	out, err := exec.Command("git", "--version").Output()
+	if err != nil {
+		return []doctorResult{{
+			name:   "git",
+			status: doctorFail,
+			detail: "Not installed",
+			fix:    "Install git from https://git-scm.com/downloads",
+		}}
+	}
+	return []doctorResult{{
+		name:   "git",
+		status: doctorOK,
+		detail: strings.TrimPrefix(strings.TrimSpace(string(out)), "git version "),
+	}}
+}
+
+// checkProjectConfig validates the project config in the current
+// directory, if there is one
+func checkProjectConfig() []doctorResult {
+	name := filepath.Base(config.ProjectConfigPath("."))
+	if !config.ProjectExists() {
+		return []doctorResult{{name: name, status: doctorOK, detail: "No project in this directory"}}
+	}
+
+	problems, err := config.ValidateProjectFrom(".")
+	if err != nil {
+		return []doctorResult{{
+			name:   name,
+			status: doctorFail,
+			detail: "Can't be parsed: " + err.Error(),
+			fix:    fmt.Sprintf("Fix the syntax of %s, or delete it and run '%s init'", name, execName()),
+		}}
+	}
+
+	var results []doctorResult
+	if len(problems) > 0 {
+		results = append(results, doctorResult{
+			name:   name,
+			status: doctorFail,
+			detail: strings.Join(problems, "; "),
+			fix:    fmt.Sprintf("Edit %s to correct these fields", name),
+		})
+	} else {
+		results = append(results, doctorResult{name: name, status: doctorOK, detail: "Valid"})
+	}
+
+	projectCfg, err := config.LoadProject()
+	if err == nil && config.IsNewerThanCLI(projectCfg) {
+		results = append(results, doctorResult{
+			name:   name,
+			status: doctorWarn,
+			detail: fmt.Sprintf("Written by cdp %s, newer than this one", projectCfg.CDPVersion),
+			fix:    fmt.Sprintf("Run '%s upgrade'", execName()),
+		})
+	}
+	return results
+}
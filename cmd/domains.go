@@ -0,0 +1,342 @@
+package cmd
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+
+	"github.com/dropalltables/cdp/internal/api"
+	"github.com/dropalltables/cdp/internal/config"
+	"github.com/dropalltables/cdp/internal/deploy"
+	"github.com/dropalltables/cdp/internal/ui"
+	"github.com/spf13/cobra"
+)
+
+var (
+	// Flags for domains command
+	domainsForceFlag bool
+)
+
+var domainsCmd = &cobra.Command{
+	Use:   "domains",
+	Short: "Manage application domains",
+	Long:  "List, add, and remove the domains served by your Coolify application.",
+}
+
+var domainsLsCmd = &cobra.Command{
+	Use:   "ls",
+	Short: "List domains",
+	RunE:  runDomainsLs,
+}
+
+var domainsAddCmd = &cobra.Command{
+	Use:   "add DOMAIN...",
+	Short: "Add one or more domains",
+	Args:  cobra.MinimumNArgs(1),
+	RunE:  runDomainsAdd,
+}
+
+var domainsRmCmd = &cobra.Command{
+	Use:   "rm DOMAIN...",
+	Short: "Remove one or more domains",
+	Args:  cobra.MinimumNArgs(1),
+	RunE:  runDomainsRm,
+}
+
+func init() {
+	rootCmd.AddCommand(domainsCmd)
+	domainsCmd.AddCommand(domainsLsCmd)
+	domainsCmd.AddCommand(domainsAddCmd)
+	domainsCmd.AddCommand(domainsRmCmd)
+
+	domainsAddCmd.Flags().BoolVar(&domainsForceFlag, "force", false, "Skip DNS resolution check")
+}
+
+func runDomainsLs(cmd *cobra.Command, args []string) error {
+	appUUID, client, err := getAppUUID()
+	if err != nil {
+		return err
+	}
+
+	app, err := fetchApplication(client, appUUID)
+	if err != nil {
+		return err
+	}
+
+	domains := parseDomains(app.FQDN)
+	if len(domains) == 0 {
+		ui.Warning("No domains configured")
+		ui.NextSteps([]string{
+			fmt.Sprintf("Run '%s domains add app.example.com' to add one", execName()),
+		})
+		return nil
+	}
+
+	rows := [][]string{}
+	for _, d := range domains {
+		kind := "custom"
+		if api.IsGeneratedDomain(d) {
+			kind = "auto-generated"
+		}
+		rows = append(rows, []string{domainHost(d), d, kind})
+	}
+
+	ui.Spacer()
+	ui.Table([]string{"Host", "URL", "Type"}, rows)
+
+	return nil
+}
+
+func runDomainsAdd(cmd *cobra.Command, args []string) error {
+	appUUID, client, err := getAppUUID()
+	if err != nil {
+		return err
+	}
+
+	var toAdd []string
+	for _, arg := range args {
+		d, err := normalizeDomain(arg)
+		if err != nil {
+			ui.Error(fmt.Sprintf("Invalid domain: %s", arg))
+			return err
+		}
+		toAdd = append(toAdd, d)
+	}
+
+	// Validate DNS before touching the application
+	if !domainsForceFlag {
+		var checks []deploy.DNSCheck
+		var serverIPs []string
+		err = ui.RunTasks([]ui.Task{
+			{
+				Name:         "check-dns",
+				ActiveName:   "Checking DNS...",
+				CompleteName: "Checked DNS",
+				Action: func() error {
+					hosts := make([]string, len(toAdd))
+					for i, d := range toAdd {
+						hosts[i] = domainHost(d)
+					}
+					checks = deploy.CheckDNS(hosts)
+					if projectCfg, err := config.LoadProject(); err == nil && projectCfg != nil {
+						serverIPs = deploy.ServerAddresses(client, projectCfg.ServerUUID)
+					}
+					return nil
+				},
+			},
+		})
+		if err != nil {
+			return err
+		}
+		var unresolved []string
+		for _, c := range checks {
+			if !c.Resolves() {
+				unresolved = append(unresolved, c.Host)
+			}
+		}
+		if len(unresolved) > 0 {
+			ui.Error("Some domains do not resolve")
+			ui.List(unresolved)
+			ui.Spacer()
+			ui.NextSteps([]string{
+				"Point the DNS records at your Coolify server",
+				fmt.Sprintf("Or run '%s domains add --force' to skip this check", execName()),
+			})
+			return fmt.Errorf("DNS resolution failed for %s", strings.Join(unresolved, ", "))
+		}
+		// Adding the domain still works; only its certificate would fail
+		if !deploy.WarnDNS(checks, serverIPs) {
+			ui.Spacer()
+		}
+	}
+
+	app, err := fetchApplication(client, appUUID)
+	if err != nil {
+		return err
+	}
+
+	domains := parseDomains(app.FQDN)
+	added := 0
+	for _, d := range toAdd {
+		if containsDomain(domains, d) {
+			ui.Dim(fmt.Sprintf("%s is already configured", domainHost(d)))
+			continue
+		}
+		domains = append(domains, d)
+		added++
+	}
+
+	if added == 0 {
+		return nil
+	}
+
+	if err := applyDomains(client, appUUID, domains); err != nil {
+		return err
+	}
+
+	ui.Spacer()
+	ui.NextSteps([]string{
+		fmt.Sprintf("Run '%s' to redeploy with the new domains", execName()),
+	})
+	return nil
+}
+
+func runDomainsRm(cmd *cobra.Command, args []string) error {
+	appUUID, client, err := getAppUUID()
+	if err != nil {
+		return err
+	}
+
+	app, err := fetchApplication(client, appUUID)
+	if err != nil {
+		return err
+	}
+
+	domains := parseDomains(app.FQDN)
+
+	var remaining []string
+	var removed []string
+	for _, d := range domains {
+		matched := false
+		for _, arg := range args {
+			if strings.EqualFold(domainHost(d), domainHost(arg)) {
+				matched = true
+				break
+			}
+		}
+		if matched {
+			removed = append(removed, domainHost(d))
+		} else {
+			remaining = append(remaining, d)
+		}
+	}
+
+	if len(removed) == 0 {
+		ui.Error("No matching domains found")
+		return fmt.Errorf("domain not found: %s", strings.Join(args, ", "))
+	}
+
+	ui.Warning(fmt.Sprintf("This will remove %d domain(s)", len(removed)))
+	ui.List(removed)
+	ui.Spacer()
+
+	confirmed, err := ui.Confirm("Are you sure?")
+	if err != nil {
+		return err
+	}
+	if !confirmed {
+		return nil
+	}
+
+	return applyDomains(client, appUUID, remaining)
+}
+
+// fetchApplication loads the application with spinner feedback
+func fetchApplication(client *api.Client, appUUID string) (*api.Application, error) {
+	var app *api.Application
+	err := ui.RunTasks([]ui.Task{
+		{
+			Name:         "fetch-app",
+			ActiveName:   "Fetching application info...",
+			CompleteName: "Fetched application info",
+			Action: func() error {
+				var err error
+				app, err = client.GetApplication(appUUID)
+				return err
+			},
+		},
+	})
+	if err != nil {
+		ui.Error("Failed to fetch application info")
+		return nil, fmt.Errorf("failed to fetch application: %w", err)
+	}
+	return app, nil
+}
+
+// applyDomains updates the application's domains and keeps cdp.json in sync
+func applyDomains(client *api.Client, appUUID string, domains []string) error {
+	joined := strings.Join(domains, ",")
+
+	err := ui.RunTasks([]ui.Task{
+		{
+			Name:         "update-domains",
+			ActiveName:   "Updating domains...",
+			CompleteName: "Updated domains",
+			Action: func() error {
+				// Coolify accepts the fqdn list under the "domains" key
+				return client.UpdateApplication(appUUID, map[string]interface{}{
+					"domains": joined,
+				})
+			},
+		},
+	})
+	if err != nil {
+		ui.Error("Failed to update domains")
+		return fmt.Errorf("failed to update domains: %w", err)
+	}
+
+	projectCfg, err := config.LoadProject()
+	if err == nil && projectCfg != nil {
+		projectCfg.Domain = joined
+		if err := config.SaveProject(projectCfg); err != nil {
+			ui.Warning("Failed to update cdp.json")
+		}
+	}
+
+	return nil
+}
+
+// domainLabel returns display, marked as auto-generated when domain is one
+// Coolify generated
+func domainLabel(domain, display string) string {
+	if api.IsGeneratedDomain(domain) {
+		return display + ui.DimStyle.Render(" (auto-generated)")
+	}
+	return display
+}
+
+// parseDomains splits Coolify's comma-separated fqdn field
+func parseDomains(fqdn string) []string {
+	var domains []string
+	for _, d := range strings.Split(fqdn, ",") {
+		d = strings.TrimSpace(d)
+		if d != "" {
+			domains = append(domains, d)
+		}
+	}
+	return domains
+}
+
+// normalizeDomain ensures a domain has a scheme, defaulting to https
+func normalizeDomain(domain string) (string, error) {
+	domain = strings.TrimSpace(domain)
+	if !strings.Contains(domain, "://") {
+		domain = "https://" + domain
+	}
+	u, err := url.Parse(domain)
+	if err != nil || u.Hostname() == "" {
+		return "", fmt.Errorf("invalid domain %q", domain)
+	}
+	return strings.TrimSuffix(domain, "/"), nil
+}
+
+// domainHost returns the hostname portion of a domain or URL
+func domainHost(domain string) string {
+	if !strings.Contains(domain, "://") {
+		domain = "https://" + domain
+	}
+	u, err := url.Parse(domain)
+	if err != nil {
+		return domain
+	}
+	return u.Hostname()
+}
+
+func containsDomain(domains []string, domain string) bool {
+	for _, d := range domains {
+		if strings.EqualFold(domainHost(d), domainHost(domain)) {
+			return true
+		}
+	}
+	return false
+}
@@ -2,20 +2,44 @@ package cmd
 
 import (
 	"bufio"
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
 	"fmt"
 	"os"
+	"path/filepath"
+	"sort"
 	"strings"
 
 	"github.com/dropalltables/cdp/internal/api"
 	"github.com/dropalltables/cdp/internal/config"
 	"github.com/dropalltables/cdp/internal/ui"
 	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v3"
+)
+
+var (
+	// Flags for env push command
+	envPruneFlag bool
+
+	// Flag for env add/push to mark variables as available at build time
+	envBuildFlag bool
+
+	// Flags for env pull/export commands
+	envOutputFlag       string
+	envExportOutputFlag string
+	envMergeFlag        bool
+	envFormatFlag       string
 )
 
 var envCmd = &cobra.Command{
 	Use:   "env",
 	Short: "Manage environment variables",
-	Long:  "Manage environment variables for your Coolify application.",
+	Long: `Manage environment variables for your Coolify application.
+
+Commands act on preview variables by default. Use --env production for
+production, or --env NAME for the same app in another Coolify environment
+of the project (e.g. staging).`,
 }
 
 var envLsCmd = &cobra.Command{
@@ -27,8 +51,10 @@ var envLsCmd = &cobra.Command{
 var envAddCmd = &cobra.Command{
 	Use:   "add KEY=value",
 	Short: "Add an environment variable",
-	Args:  cobra.ExactArgs(1),
-	RunE:  runEnvAdd,
+	Example: `  cdp env add DATABASE_URL=postgres://...
+  cdp env add NEXT_PUBLIC_API_URL=https://api.example.com --build`,
+	Args: cobra.ExactArgs(1),
+	RunE: runEnvAdd,
 }
 
 var envRmCmd = &cobra.Command{
@@ -41,19 +67,56 @@ var envRmCmd = &cobra.Command{
 var envPullCmd = &cobra.Command{
 	Use:   "pull",
 	Short: "Pull environment variables to local .env file",
-	RunE:  runEnvPull,
+	Example: `  cdp env pull
+  cdp env pull --env production --output .env.production
+  cdp env pull --merge`,
+	RunE: runEnvPull,
 }
 
 var envPushCmd = &cobra.Command{
 	Use:   "push",
 	Short: "Push local .env file to Coolify",
-	RunE:  runEnvPush,
+	Long: `Push the local .env file to Coolify.
+
+New keys are created and keys whose value changed are updated. Keys that
+exist in Coolify but not in .env are kept unless --prune is given.`,
+	RunE: runEnvPush,
+}
+
+var envDiffCmd = &cobra.Command{
+	Use:   "diff",
+	Short: "Compare local .env with Coolify",
+	Long: `Show which keys differ between the local .env file and the remote
+environment variables (preview by default, use --env production for production).
+
+Values are never printed.`,
+	RunE: runEnvDiff,
+}
+
+var envExportCmd = &cobra.Command{
+	Use:   "export",
+	Short: "Export environment variables as JSON, YAML, or dotenv",
+	Example: `  cdp env export --format json > env.json
+  cdp env export --env production --format yaml --output env.production.yaml`,
+	RunE: runEnvExport,
+}
+
+var envImportCmd = &cobra.Command{
+	Use:   "import FILE",
+	Short: "Import environment variables from a JSON, YAML, or dotenv file",
+	Long: `Import environment variables from a file, detecting its format.
+
+Accepted formats are dotenv (KEY=value), a JSON object, a YAML mapping,
+and Kubernetes Secret manifests (data or stringData). Existing keys are
+updated; use --prune to delete keys missing from the file.`,
+	Args: cobra.ExactArgs(1),
+	RunE: runEnvImport,
 }
 
 var envResetCmd = &cobra.Command{
 	Use:   "reset",
 	Short: "Delete all environment variables",
-	Long:  "Delete all environment variables for the specified deployment (preview by default, use --prod for production).",
+	Long:  "Delete all environment variables for the specified deployment (preview by default, use --env production for production).",
 	RunE:  runEnvReset,
 }
 
@@ -64,10 +127,23 @@ func init() {
 	envCmd.AddCommand(envRmCmd)
 	envCmd.AddCommand(envPullCmd)
 	envCmd.AddCommand(envPushCmd)
+	envCmd.AddCommand(envDiffCmd)
+	envCmd.AddCommand(envExportCmd)
+	envCmd.AddCommand(envImportCmd)
 	envCmd.AddCommand(envResetCmd)
 
-	// Add --prod flag for env commands to target production deployments
-	envCmd.PersistentFlags().BoolVar(&prodFlag, "prod", false, "Target production environment (default is preview)")
+	// env commands act on preview variables unless told otherwise
+	addEnvFlag(envCmd, true, envPreview)
+
+	envPushCmd.Flags().BoolVar(&envPruneFlag, "prune", false, "Delete remote variables missing from .env")
+	envAddCmd.Flags().BoolVar(&envBuildFlag, "build", false, "Make the variable available at build time")
+	envPushCmd.Flags().BoolVar(&envBuildFlag, "build", false, "Make the variables available at build time")
+	envPullCmd.Flags().StringVarP(&envOutputFlag, "output", "o", ".env", "File to write variables to")
+	envExportCmd.Flags().StringVarP(&envExportOutputFlag, "output", "o", "", "File to write to (default stdout)")
+	envExportCmd.Flags().StringVar(&envFormatFlag, "format", envFormatDotenv, "Output format: json, yaml, or dotenv")
+	envImportCmd.Flags().BoolVar(&envPruneFlag, "prune", false, "Delete remote variables missing from the file")
+	envImportCmd.Flags().BoolVar(&envBuildFlag, "build", false, "Make the variables available at build time")
+	envPullCmd.Flags().BoolVar(&envMergeFlag, "merge", false, "Update the file in place, keeping comments and order")
 }
 
 func getAppUUID() (string, *api.Client, error) {
@@ -98,7 +174,11 @@ func getAppUUID() (string, *api.Client, error) {
 }
 
 func runEnvLs(cmd *cobra.Command, args []string) error {
-	appUUID, client, err := getAppUUID()
+	target, err := resolveDeployEnv(cmd)
+	if err != nil {
+		return err
+	}
+	appUUID, client, err := deployEnvApp(target)
 	if err != nil {
 		return err
 	}
@@ -127,7 +207,7 @@ func runEnvLs(cmd *cobra.Command, args []string) error {
 	}
 
 	// Build table with environment label
-	headers := []string{"Environment", "Key", "Value"}
+	headers := []string{"Environment", "Key", "Value", "Build"}
 	rows := [][]string{}
 
 	for _, env := range allEnvVars {
@@ -147,7 +227,12 @@ func runEnvLs(cmd *cobra.Command, args []string) error {
 			envLabel = "Preview"
 		}
 
-		rows = append(rows, []string{envLabel, env.Key, value})
+		buildTime := ""
+		if env.IsBuildTime {
+			buildTime = "yes"
+		}
+
+		rows = append(rows, []string{envLabel, env.Key, value, buildTime})
 	}
 
 	ui.Spacer()
@@ -168,13 +253,17 @@ func runEnvAdd(cmd *cobra.Command, args []string) error {
 	}
 	key, value := parts[0], parts[1]
 
-	appUUID, client, err := getAppUUID()
+	target, err := resolveDeployEnv(cmd)
+	if err != nil {
+		return err
+	}
+	appUUID, client, err := deployEnvApp(target)
 	if err != nil {
 		return err
 	}
 
-	// Set is_preview based on flag (default is preview, --prod targets production)
-	isPreview := !prodFlag
+	// Set is_preview based on flag (default is preview, --env selects another)
+	isPreview := target.Preview()
 
 	err = ui.RunTasks([]ui.Task{
 		{
@@ -182,7 +271,7 @@ func runEnvAdd(cmd *cobra.Command, args []string) error {
 			ActiveName:   fmt.Sprintf("Adding %s...", key),
 			CompleteName: fmt.Sprintf("Added %s", key),
 			Action: func() error {
-				_, err := client.CreateApplicationEnvVar(appUUID, key, value, false, isPreview)
+				_, err := client.CreateApplicationEnvVar(appUUID, key, value, envBuildFlag, isPreview)
 				return err
 			},
 		},
@@ -198,13 +287,17 @@ func runEnvAdd(cmd *cobra.Command, args []string) error {
 func runEnvRm(cmd *cobra.Command, args []string) error {
 	key := args[0]
 
-	appUUID, client, err := getAppUUID()
+	target, err := resolveDeployEnv(cmd)
+	if err != nil {
+		return err
+	}
+	appUUID, client, err := deployEnvApp(target)
 	if err != nil {
 		return err
 	}
 
-	// Find the env var by key, matching the deployment type (default is preview, --prod targets production)
-	isPreview := !prodFlag
+	// Find the env var by key, matching the deployment type (default is preview, --env selects another)
+	isPreview := target.Preview()
 	envVars, err := client.GetApplicationEnvVars(appUUID)
 	if err != nil {
 		ui.Error("Failed to fetch environment variables")
@@ -220,10 +313,7 @@ func runEnvRm(cmd *cobra.Command, args []string) error {
 	}
 
 	if targetEnv == nil {
-		deploymentType := "preview"
-		if prodFlag {
-			deploymentType = "production"
-		}
+		deploymentType := target.Name
 		ui.Error(fmt.Sprintf("Variable '%s' not found in %s", key, deploymentType))
 		return fmt.Errorf("environment variable '%s' not found in %s", key, deploymentType)
 	}
@@ -285,7 +375,11 @@ func runEnvRm(cmd *cobra.Command, args []string) error {
 }
 
 func runEnvPull(cmd *cobra.Command, args []string) error {
-	appUUID, client, err := getAppUUID()
+	target, err := resolveDeployEnv(cmd)
+	if err != nil {
+		return err
+	}
+	appUUID, client, err := deployEnvApp(target)
 	if err != nil {
 		return err
 	}
@@ -308,8 +402,8 @@ func runEnvPull(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("failed to fetch environment variables: %w", err)
 	}
 
-	// Filter by deployment type (default is preview, --prod targets production)
-	isPreview := !prodFlag
+	// Filter by deployment type (default is preview, --env selects another)
+	isPreview := target.Preview()
 	var envVars []api.EnvVar
 	for _, env := range allEnvVars {
 		if env.IsPreview == isPreview {
@@ -318,17 +412,15 @@ func runEnvPull(cmd *cobra.Command, args []string) error {
 	}
 
 	if len(envVars) == 0 {
-		deploymentType := "preview"
-		if prodFlag {
-			deploymentType = "production"
-		}
+		deploymentType := target.Name
 		ui.Warning(fmt.Sprintf("No %s environment variables to pull", deploymentType))
 		return nil
 	}
 
-	// Check if .env already exists
-	if _, err := os.Stat(".env"); err == nil {
-		ui.Warning(".env file already exists")
+	// Check if the output file already exists (merging updates it in place)
+	if _, err := os.Stat(envOutputFlag); err == nil && !envMergeFlag {
+		ui.Warning(fmt.Sprintf("%s file already exists", envOutputFlag))
+		ui.Dim("Use --merge to update it while keeping comments and order")
 		overwrite, err := ui.Confirm("Overwrite?")
 		if err != nil {
 			return err
@@ -371,9 +463,13 @@ func runEnvPull(cmd *cobra.Command, args []string) error {
 		{
 			Name:         "pull-env-vars",
 			ActiveName:   "Pulling environment variables...",
-			CompleteName: fmt.Sprintf("Pulled %d variables to .env", len(envVars)),
+			CompleteName: fmt.Sprintf("Pulled %d variables to %s", len(envVars), envOutputFlag),
 			Action: func() error {
-				file, err := os.Create(".env")
+				if envMergeFlag {
+					return mergeDotEnv(envOutputFlag, envVars)
+				}
+
+				file, err := os.Create(envOutputFlag)
 				if err != nil {
 					return err
 				}
@@ -399,7 +495,7 @@ func runEnvPull(cmd *cobra.Command, args []string) error {
 
 func runEnvPush(cmd *cobra.Command, args []string) error {
 	// Read .env file
-	file, err := os.Open(".env")
+	envVars, err := readDotEnv(".env")
 	if err != nil {
 		ui.Error("Could not open .env file")
 		ui.NextSteps([]string{
@@ -408,75 +504,113 @@ func runEnvPush(cmd *cobra.Command, args []string) error {
 		})
 		return fmt.Errorf("failed to open .env file: %w", err)
 	}
-	defer file.Close()
 
-	appUUID, client, err := getAppUUID()
+	if len(envVars) == 0 {
+		ui.Warning("No valid environment variables found in .env")
+		return nil
+	}
+
+	target, err := resolveDeployEnv(cmd)
 	if err != nil {
 		return err
 	}
+	return syncEnvVars(target, withBuildFlag(envVars), cmd.Flags().Changed("build"))
+}
 
-	var envVars []struct {
-		Key   string
-		Value string
+// syncEnvVars upserts envVars into the selected environment, showing the
+// planned changes and asking for confirmation first. With --prune, remote
+// variables that are not in envVars are deleted. Unless setBuild is true,
+// existing variables keep whether they are available at build time.
+func syncEnvVars(target deployEnv, envVars []dotEnvVar, setBuild bool) error {
+	appUUID, client, err := deployEnvApp(target)
+	if err != nil {
+		return err
 	}
 
-	scanner := bufio.NewScanner(file)
-	lineNum := 0
-	for scanner.Scan() {
-		lineNum++
-		line := strings.TrimSpace(scanner.Text())
-		if line == "" || strings.HasPrefix(line, "#") {
-			continue
+	var allEnvVars []api.EnvVar
+	err = ui.RunTasks([]ui.Task{
+		{
+			Name:         "fetch-env-vars",
+			ActiveName:   "Fetching environment variables...",
+			CompleteName: "Fetched environment variables",
+			Action: func() error {
+				var err error
+				allEnvVars, err = client.GetApplicationEnvVars(appUUID)
+				return err
+			},
+		},
+	})
+	if err != nil {
+		ui.Error("Failed to fetch environment variables")
+		return fmt.Errorf("failed to fetch environment variables: %w", err)
+	}
+
+	// Set is_preview based on flag (default is preview, --env selects another)
+	isPreview := target.Preview()
+	remote := map[string]api.EnvVar{}
+	for _, env := range allEnvVars {
+		if env.IsPreview == isPreview {
+			remote[env.Key] = env
 		}
-		parts := strings.SplitN(line, "=", 2)
-		if len(parts) != 2 {
-			ui.Warning(fmt.Sprintf("Skipping invalid line %d: %s", lineNum, line))
-			continue
+	}
+
+	// Work out what needs to change
+	var creates, updates []dotEnvVar
+	var deletes []api.EnvVar
+	local := map[string]bool{}
+	unchanged := 0
+	for _, env := range envVars {
+		local[env.Key] = true
+		existing, ok := remote[env.Key]
+		switch {
+		case !ok:
+			creates = append(creates, env)
+		case existing.Value != env.Value || (setBuild && existing.IsBuildTime != env.BuildTime):
+			updates = append(updates, env)
+		default:
+			unchanged++
+		}
+	}
+	if envPruneFlag {
+		for _, env := range allEnvVars {
+			if env.IsPreview == isPreview && !local[env.Key] {
+				deletes = append(deletes, env)
+			}
 		}
-		envVars = append(envVars, struct {
-			Key   string
-			Value string
-		}{Key: parts[0], Value: parts[1]})
 	}
 
-	if len(envVars) == 0 {
-		ui.Warning("No valid environment variables found in .env")
+	if len(creates)+len(updates)+len(deletes) == 0 {
+		ui.Success("Environment variables are up to date")
 		return nil
 	}
 
-	// Display variables to be pushed
-	ui.Warning(fmt.Sprintf("This will push %d environment variables", len(envVars)))
-	ui.Spacer()
+	// Display changes to be pushed
+	deploymentType := target.Label()
 
-	// Determine deployment type for display
-	deploymentType := "Preview"
-	if prodFlag {
-		deploymentType = "Production"
-	}
-
-	headers := []string{"Environment", "Key", "Value"}
+	headers := []string{"Environment", "Action", "Key", "Value"}
 	rows := [][]string{}
-
-	for _, env := range envVars {
-		value := env.Value
-		// Mask sensitive values
-		if len(value) > 50 {
-			value = value[:20] + "..." + value[len(value)-10:]
-		}
-		if strings.Contains(strings.ToLower(env.Key), "secret") ||
-			strings.Contains(strings.ToLower(env.Key), "password") ||
-			strings.Contains(strings.ToLower(env.Key), "token") {
-			value = "••••••••"
-		}
-
-		rows = append(rows, []string{deploymentType, env.Key, value})
+	for _, env := range creates {
+		rows = append(rows, []string{deploymentType, "create", env.Key, maskEnvValue(env.Key, env.Value)})
+	}
+	for _, env := range updates {
+		rows = append(rows, []string{deploymentType, "update", env.Key, maskEnvValue(env.Key, env.Value)})
+	}
+	for _, env := range deletes {
+		rows = append(rows, []string{deploymentType, "delete", env.Key, maskEnvValue(env.Key, env.Value)})
 	}
 
+	ui.Spacer()
 	ui.Table(headers, rows)
+	if unchanged > 0 {
+		ui.Dim(fmt.Sprintf("%d unchanged", unchanged))
+	}
+	if !envPruneFlag {
+		ui.Dim("Remote variables missing locally are kept (use --prune to delete them)")
+	}
 	ui.Spacer()
 
 	// Confirm push
-	confirmed, err := ui.Confirm("Are you sure?")
+	confirmed, err := ui.ConfirmWithOptions("Are you sure?", ui.ConfirmOptions{Danger: len(deletes) > 0})
 	if err != nil {
 		return err
 	}
@@ -485,25 +619,26 @@ func runEnvPush(cmd *cobra.Command, args []string) error {
 	}
 
 	// Push variables
-	pushed := 0
-	failed := 0
-
-	// Set is_preview based on flag (default is preview, --prod targets production)
-	isPreview := !prodFlag
-
+	var results ui.Results
 	err = ui.RunTasks([]ui.Task{
 		{
 			Name:         "push-env-vars",
 			ActiveName:   "Pushing environment variables...",
-			CompleteName: fmt.Sprintf("Pushed %d variables", len(envVars)),
+			CompleteName: fmt.Sprintf("Pushed %d changes", len(rows)),
 			Action: func() error {
-				for _, env := range envVars {
-					_, err := client.CreateApplicationEnvVar(appUUID, env.Key, env.Value, false, isPreview)
-					if err != nil {
-						failed++
-					} else {
-						pushed++
+				for _, env := range creates {
+					_, err := client.CreateApplicationEnvVar(appUUID, env.Key, env.Value, env.BuildTime, isPreview)
+					results.Add(env.Key, "create", err)
+				}
+				for _, env := range updates {
+					buildTime := env.BuildTime
+					if !setBuild {
+						buildTime = remote[env.Key].IsBuildTime
 					}
+					results.Add(env.Key, "update", client.UpdateApplicationEnvVar(appUUID, env.Key, env.Value, buildTime, isPreview))
+				}
+				for _, env := range deletes {
+					results.Add(env.Key, "delete", client.DeleteApplicationEnvVar(appUUID, env.UUID))
 				}
 				return nil
 			},
@@ -514,24 +649,22 @@ func runEnvPush(cmd *cobra.Command, args []string) error {
 		return err
 	}
 
-	if failed > 0 {
-		ui.Warning(fmt.Sprintf("%d failed", failed))
-	}
-
-	return nil
+	results.Render()
+	return results.Err()
 }
 
 func runEnvReset(cmd *cobra.Command, args []string) error {
-	appUUID, client, err := getAppUUID()
+	target, err := resolveDeployEnv(cmd)
+	if err != nil {
+		return err
+	}
+	appUUID, client, err := deployEnvApp(target)
 	if err != nil {
 		return err
 	}
 
 	// Determine deployment type
-	deploymentType := "preview"
-	if prodFlag {
-		deploymentType = "production"
-	}
+	deploymentType := target.Name
 
 	// Fetch all env vars
 	envVars, err := client.GetApplicationEnvVars(appUUID)
@@ -541,7 +674,7 @@ func runEnvReset(cmd *cobra.Command, args []string) error {
 	}
 
 	// Filter by deployment type
-	isPreview := !prodFlag
+	isPreview := target.Preview()
 	var varsToDelete []api.EnvVar
 	for _, env := range envVars {
 		if env.IsPreview == isPreview {
@@ -585,7 +718,7 @@ func runEnvReset(cmd *cobra.Command, args []string) error {
 	ui.Spacer()
 	
 	// Confirm deletion
-	confirmed, err := ui.Confirm("Are you sure?")
+	confirmed, err := ui.ConfirmDanger(fmt.Sprintf("Delete all %s environment variables?", deploymentType), deploymentType)
 	if err != nil {
 		return err
 	}
@@ -594,8 +727,7 @@ func runEnvReset(cmd *cobra.Command, args []string) error {
 	}
 
 	// Delete all variables
-	deleted := 0
-	failed := 0
+	var results ui.Results
 
 	err = ui.RunTasks([]ui.Task{
 		{
@@ -604,12 +736,7 @@ func runEnvReset(cmd *cobra.Command, args []string) error {
 			CompleteName: fmt.Sprintf("Deleted %d variables", len(varsToDelete)),
 			Action: func() error {
 				for _, env := range varsToDelete {
-					err := client.DeleteApplicationEnvVar(appUUID, env.UUID)
-					if err != nil {
-						failed++
-					} else {
-						deleted++
-					}
+					results.Add(env.Key, "delete", client.DeleteApplicationEnvVar(appUUID, env.UUID))
 				}
 				return nil
 			},
@@ -620,9 +747,369 @@ func runEnvReset(cmd *cobra.Command, args []string) error {
 		return err
 	}
 
-	if failed > 0 {
-		ui.Warning(fmt.Sprintf("%d failed", failed))
+	results.Render()
+	return results.Err()
+}
+
+func runEnvDiff(cmd *cobra.Command, args []string) error {
+	local, err := readDotEnv(".env")
+	if err != nil {
+		ui.Error("Could not open .env file")
+		return fmt.Errorf("failed to open .env file: %w", err)
+	}
+
+	target, err := resolveDeployEnv(cmd)
+	if err != nil {
+		return err
+	}
+	appUUID, client, err := deployEnvApp(target)
+	if err != nil {
+		return err
+	}
+
+	var allEnvVars []api.EnvVar
+	err = ui.RunTasks([]ui.Task{
+		{
+			Name:         "fetch-env-vars",
+			ActiveName:   "Fetching environment variables...",
+			CompleteName: "Fetched environment variables",
+			Action: func() error {
+				var err error
+				allEnvVars, err = client.GetApplicationEnvVars(appUUID)
+				return err
+			},
+		},
+	})
+	if err != nil {
+		ui.Error("Failed to fetch environment variables")
+		return fmt.Errorf("failed to fetch environment variables: %w", err)
+	}
+
+	isPreview := target.Preview()
+	remote := map[string]string{}
+	for _, env := range allEnvVars {
+		if env.IsPreview == isPreview {
+			remote[env.Key] = env.Value
+		}
+	}
+	localValues := map[string]string{}
+	for _, env := range local {
+		localValues[env.Key] = env.Value
+	}
+
+	var added, removed, changed []string
+	for key, value := range localValues {
+		remoteValue, ok := remote[key]
+		if !ok {
+			added = append(added, key)
+		} else if remoteValue != value {
+			changed = append(changed, key)
+		}
+	}
+	for key := range remote {
+		if _, ok := localValues[key]; !ok {
+			removed = append(removed, key)
+		}
+	}
+	sort.Strings(added)
+	sort.Strings(removed)
+	sort.Strings(changed)
+
+	deploymentType := target.Name
+
+	ui.Spacer()
+	if len(added)+len(removed)+len(changed) == 0 {
+		ui.Success(fmt.Sprintf(".env matches %s environment variables", deploymentType))
+		return nil
+	}
+
+	// No value is printed, as any of them may hold a credential (e.g. a
+	// DATABASE_URL); changed ones are labelled instead
+	maskedRemote, maskedLocal := map[string]string{}, map[string]string{}
+	for key := range remote {
+		maskedRemote[key] = maskedEnvValue
+	}
+	for key := range localValues {
+		maskedLocal[key] = maskedEnvValue
+	}
+	for _, key := range changed {
+		maskedRemote[key] += " (Coolify value)"
+		maskedLocal[key] += " (.env value)"
+	}
+
+	ui.Dim(fmt.Sprintf("Coolify %s variables (-) vs local .env (+):", deploymentType))
+	ui.DiffKeyValues(maskedRemote, maskedLocal)
+	ui.Spacer()
+	ui.Info(fmt.Sprintf("%d added, %d removed, %d changed", len(added), len(removed), len(changed)))
+
+	return nil
+}
+
+// withBuildFlag applies --build to envVars
+func withBuildFlag(envVars []dotEnvVar) []dotEnvVar {
+	for i := range envVars {
+		envVars[i].BuildTime = envBuildFlag
+	}
+	return envVars
+}
+
+// dotEnvVar is a key/value pair read from a .env file
+type dotEnvVar struct {
+	Key       string
+	Value     string
+	BuildTime bool // applied by syncEnvVars only when setBuild is true
+}
+
+// readDotEnv parses KEY=value lines from a .env file, preserving order.
+// Blank lines and comments are skipped; malformed lines produce a warning.
+func readDotEnv(path string) ([]dotEnvVar, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	var vars []dotEnvVar
+	scanner := bufio.NewScanner(file)
+	lineNum := 0
+	for scanner.Scan() {
+		lineNum++
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		parts := strings.SplitN(line, "=", 2)
+		if len(parts) != 2 {
+			ui.Warning(fmt.Sprintf("Skipping invalid line %d: %s", lineNum, line))
+			continue
+		}
+		vars = append(vars, dotEnvVar{Key: parts[0], Value: parts[1]})
+	}
+	return vars, scanner.Err()
+}
+
+// mergeDotEnv updates an env file in place: existing keys get the remote
+// value where they stand, comments and unknown keys are kept, and new keys
+// are appended at the end
+func mergeDotEnv(path string, envVars []api.EnvVar) error {
+	remote := map[string]string{}
+	for _, env := range envVars {
+		remote[env.Key] = env.Value
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	var lines []string
+	if content := strings.TrimRight(string(data), "\n"); content != "" {
+		lines = strings.Split(content, "\n")
+	}
+
+	written := map[string]bool{}
+	for i, line := range lines {
+		trimmed := strings.TrimSpace(line)
+		if trimmed == "" || strings.HasPrefix(trimmed, "#") {
+			continue
+		}
+		key := strings.TrimSpace(strings.SplitN(trimmed, "=", 2)[0])
+		if value, ok := remote[key]; ok {
+			lines[i] = fmt.Sprintf("%s=%s", key, value)
+			written[key] = true
+		}
+	}
+	for _, env := range envVars {
+		if !written[env.Key] {
+			lines = append(lines, fmt.Sprintf("%s=%s", env.Key, env.Value))
+			written[env.Key] = true
+		}
 	}
 
+	return os.WriteFile(path, []byte(strings.Join(lines, "\n")+"\n"), 0644)
+}
+
+// maskedEnvValue stands in for a value that isn't shown
+const maskedEnvValue = "••••••••"
+
+// maskEnvValue shortens long values and hides ones that look sensitive
+func maskEnvValue(key, value string) string {
+	if len(value) > 50 {
+		value = value[:20] + "..." + value[len(value)-10:]
+	}
+	lower := strings.ToLower(key)
+	if strings.Contains(lower, "secret") ||
+		strings.Contains(lower, "password") ||
+		strings.Contains(lower, "token") {
+		value = maskedEnvValue
+	}
+	return value
+}
+
+// Formats accepted by 'env export --format'
+const (
+	envFormatJSON   = "json"
+	envFormatYAML   = "yaml"
+	envFormatDotenv = "dotenv"
+)
+
+func runEnvExport(cmd *cobra.Command, args []string) error {
+	format := strings.ToLower(envFormatFlag)
+	if format != envFormatJSON && format != envFormatYAML && format != envFormatDotenv {
+		return fmt.Errorf("unknown format %q (use json, yaml, or dotenv)", envFormatFlag)
+	}
+
+	target, err := resolveDeployEnv(cmd)
+	if err != nil {
+		return err
+	}
+	appUUID, client, err := deployEnvApp(target)
+	if err != nil {
+		return err
+	}
+
+	// Keep stdout clean when it carries the export
+	allEnvVars, err := client.GetApplicationEnvVars(appUUID)
+	if err != nil {
+		ui.Error("Failed to fetch environment variables")
+		return fmt.Errorf("failed to fetch environment variables: %w", err)
+	}
+
+	isPreview := target.Preview()
+	var envVars []api.EnvVar
+	for _, env := range allEnvVars {
+		if env.IsPreview == isPreview {
+			envVars = append(envVars, env)
+		}
+	}
+	sort.SliceStable(envVars, func(i, j int) bool { return envVars[i].Key < envVars[j].Key })
+
+	data, err := encodeEnvVars(envVars, format)
+	if err != nil {
+		return err
+	}
+
+	if envExportOutputFlag == "" {
+		_, err = os.Stdout.Write(data)
+		return err
+	}
+	if err := os.WriteFile(envExportOutputFlag, data, 0600); err != nil {
+		return fmt.Errorf("failed to write %s: %w", envExportOutputFlag, err)
+	}
+	ui.Success(fmt.Sprintf("Exported %d variables to %s", len(envVars), envExportOutputFlag))
 	return nil
 }
+
+func runEnvImport(cmd *cobra.Command, args []string) error {
+	path := args[0]
+	data, err := os.ReadFile(path)
+	if err != nil {
+		ui.Error(fmt.Sprintf("Could not open %s", path))
+		return fmt.Errorf("failed to read %s: %w", path, err)
+	}
+
+	envVars, err := decodeEnvFile(path, data)
+	if err != nil {
+		ui.Error(fmt.Sprintf("Could not parse %s", path))
+		return err
+	}
+	if len(envVars) == 0 {
+		ui.Warning(fmt.Sprintf("No environment variables found in %s", path))
+		return nil
+	}
+
+	target, err := resolveDeployEnv(cmd)
+	if err != nil {
+		return err
+	}
+	return syncEnvVars(target, withBuildFlag(envVars), cmd.Flags().Changed("build"))
+}
+
+// encodeEnvVars renders variables in the given export format
+func encodeEnvVars(envVars []api.EnvVar, format string) ([]byte, error) {
+	switch format {
+	case envFormatJSON:
+		values := map[string]string{}
+		for _, env := range envVars {
+			values[env.Key] = env.Value
+		}
+		data, err := json.MarshalIndent(values, "", "  ")
+		if err != nil {
+			return nil, err
+		}
+		return append(data, '\n'), nil
+	case envFormatYAML:
+		// Build the mapping by hand so keys keep their sorted order
+		mapping := &yaml.Node{Kind: yaml.MappingNode}
+		for _, env := range envVars {
+			mapping.Content = append(mapping.Content,
+				&yaml.Node{Kind: yaml.ScalarNode, Value: env.Key},
+				&yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: env.Value},
+			)
+		}
+		return yaml.Marshal(mapping)
+	default:
+		var buf bytes.Buffer
+		for _, env := range envVars {
+			fmt.Fprintf(&buf, "%s=%s\n", env.Key, env.Value)
+		}
+		return buf.Bytes(), nil
+	}
+}
+
+// decodeEnvFile parses variables from a file, detecting its format
+// from the extension and falling back to sniffing the content
+func decodeEnvFile(path string, data []byte) ([]dotEnvVar, error) {
+	trimmed := bytes.TrimSpace(data)
+	switch ext := strings.ToLower(filepath.Ext(path)); {
+	case ext == ".json" || bytes.HasPrefix(trimmed, []byte("{")):
+		var values map[string]interface{}
+		if err := json.Unmarshal(data, &values); err != nil {
+			return nil, fmt.Errorf("invalid JSON: %w", err)
+		}
+		return envVarsFromMap(values), nil
+	case ext == ".yaml" || ext == ".yml":
+		var values map[string]interface{}
+		if err := yaml.Unmarshal(data, &values); err != nil {
+			return nil, fmt.Errorf("invalid YAML: %w", err)
+		}
+		return envVarsFromYAML(values)
+	default:
+		return readDotEnv(path)
+	}
+}
+
+// envVarsFromYAML accepts a plain mapping or a Kubernetes Secret manifest
+func envVarsFromYAML(values map[string]interface{}) ([]dotEnvVar, error) {
+	if values["kind"] != "Secret" {
+		return envVarsFromMap(values), nil
+	}
+
+	var envVars []dotEnvVar
+	if data, ok := values["data"].(map[string]interface{}); ok {
+		for key, v := range data {
+			decoded, err := base64.StdEncoding.DecodeString(fmt.Sprint(v))
+			if err != nil {
+				return nil, fmt.Errorf("invalid base64 value for %s", key)
+			}
+			envVars = append(envVars, dotEnvVar{Key: key, Value: string(decoded)})
+		}
+	}
+	if stringData, ok := values["stringData"].(map[string]interface{}); ok {
+		envVars = append(envVars, envVarsFromMap(stringData)...)
+	}
+	sort.SliceStable(envVars, func(i, j int) bool { return envVars[i].Key < envVars[j].Key })
+	return envVars, nil
+}
+
+func envVarsFromMap(values map[string]interface{}) []dotEnvVar {
+	envVars := make([]dotEnvVar, 0, len(values))
+	for key, v := range values {
+		value := ""
+		if v != nil {
+			value = fmt.Sprint(v)
+		}
+		envVars = append(envVars, dotEnvVar{Key: key, Value: value})
+	}
+	sort.Slice(envVars, func(i, j int) bool { return envVars[i].Key < envVars[j].Key })
+	return envVars
+}
@@ -0,0 +1,250 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/dropalltables/cdp/internal/api"
+	"github.com/dropalltables/cdp/internal/config"
+	"github.com/dropalltables/cdp/internal/deploy"
+	"github.com/dropalltables/cdp/internal/ui"
+	"github.com/spf13/cobra"
+)
+
+// Deployment environments understood by --env; any other value names a
+// Coolify environment of the linked project, e.g. "staging"
+const (
+	envProduction = "production"
+	envPreview    = "preview"
+)
+
+// deployEnv is the environment a command acts on
+type deployEnv struct {
+	Name string
+}
+
+// Production reports whether the command targets the production app
+func (e deployEnv) Production() bool {
+	return e.Name == envProduction
+}
+
+// Preview reports whether the command targets preview deployments
+func (e deployEnv) Preview() bool {
+	return e.Name == envPreview
+}
+
+// Label is the environment name for display, e.g. "Production"
+func (e deployEnv) Label() string {
+	return strings.ToUpper(e.Name[:1]) + e.Name[1:]
+}
+
+// Named reports whether the environment is a Coolify environment other
+// than the linked app's own
+func (e deployEnv) Named() bool {
+	return e.Name != envProduction && e.Name != envPreview
+}
+
+// addEnvFlag registers --env with the given default, plus --prod as a
+// deprecated alias for --env production. Commands default differently, so
+// the values are read back from the command rather than a shared variable.
+func addEnvFlag(cmd *cobra.Command, persistent bool, def string) {
+	flags := cmd.Flags()
+	if persistent {
+		flags = cmd.PersistentFlags()
+	}
+	flags.String("env", def, "Target environment: production, preview, or a Coolify environment name")
+	flags.Bool("prod", false, "Target production environment")
+	deprecateFlag(flags, "prod", "env", envProduction)
+}
+
+// resolveDeployEnv returns the environment selected by --env; a deprecated
+// --prod has already been mapped onto it
+func resolveDeployEnv(cmd *cobra.Command) (deployEnv, error) {
+	if prod := cmd.Flags().Lookup("prod"); prod != nil && prod.Changed && prod.Value.String() == "false" {
+		ui.Error(fmt.Sprintf("--prod=false is not supported; use --env %s", envPreview))
+		return deployEnv{}, fmt.Errorf("--prod=false is not supported")
+	}
+	flag, _ := cmd.Flags().GetString("env")
+	name := strings.ToLower(strings.TrimSpace(flag))
+	if name == "" {
+		return deployEnv{}, fmt.Errorf("--env must not be empty")
+	}
+	return deployEnv{Name: name}, nil
+}
+
+// Errors of findEnvApp when the environment has no counterpart of the
+// linked app, which 'deploy --env' offers to create
+var (
+	errNoEnvironment = errors.New("project has no environment")
+	errNoEnvApp      = errors.New("no application")
+)
+
+// deployEnvApp returns the application serving env: the linked app, or for
+// a named environment the app recorded for it in cdp.json, else the app of
+// the same name in that Coolify environment, which is then recorded
+func deployEnvApp(env deployEnv) (string, *api.Client, error) {
+	appUUID, client, err := getAppUUID()
+	if err != nil || !env.Named() {
+		return appUUID, client, err
+	}
+
+	projectCfg, err := config.LoadProject()
+	if err != nil {
+		return "", nil, fmt.Errorf("failed to load project config: %w", err)
+	}
+	if app, ok := projectCfg.EnvironmentApp(env.Name); ok {
+		return app.AppUUID, client, nil
+	}
+	if projectCfg.ProjectUUID == "" {
+		ui.Error("The linked app's Coolify project is unknown")
+		ui.Dim(fmt.Sprintf("Run '%s link' to look it up", execName()))
+		return "", nil, fmt.Errorf("project UUID missing from cdp.json")
+	}
+
+	// No spinner: env export may be writing to stdout
+	app, err := findEnvApp(client, projectCfg.ProjectUUID, appUUID, env.Name)
+	if err != nil {
+		ui.Error(err.Error())
+		if errors.Is(err, errNoEnvironment) || errors.Is(err, errNoEnvApp) {
+			ui.Dim(fmt.Sprintf("Run '%s deploy --env %s' to create it", execName(), env.Name))
+		}
+		return "", nil, err
+	}
+	// Remembering the app is only a shortcut for next time
+	projectCfg.SetEnvironmentApp(env.Name, app)
+	_ = config.SaveProject(projectCfg)
+	return app.AppUUID, client, nil
+}
+
+// findEnvApp looks for the counterpart of the linked app in the named
+// environment: the app with the same name, or the environment's only app
+func findEnvApp(client *api.Client, projectUUID, appUUID, envName string) (config.EnvironmentApp, error) {
+	project, err := client.GetProject(projectUUID)
+	if err != nil {
+		return config.EnvironmentApp{}, fmt.Errorf("failed to load project: %w", err)
+	}
+	var env *api.Environment
+	for i, e := range project.Environments {
+		if strings.EqualFold(e.Name, envName) {
+			env = &project.Environments[i]
+			break
+		}
+	}
+	if env == nil {
+		return config.EnvironmentApp{}, fmt.Errorf("%w %q", errNoEnvironment, envName)
+	}
+
+	apps, err := client.ListApplications()
+	if err != nil {
+		return config.EnvironmentApp{}, fmt.Errorf("failed to list applications: %w", err)
+	}
+	var linkedName string
+	var candidates []api.Application
+	for _, app := range apps {
+		if app.UUID == appUUID {
+			linkedName = app.Name
+		}
+		if app.EnvironmentID == env.ID {
+			candidates = append(candidates, app)
+		}
+	}
+	for _, app := range candidates {
+		if app.Name == linkedName {
+			return config.EnvironmentApp{EnvironmentUUID: env.UUID, AppUUID: app.UUID}, nil
+		}
+	}
+	if len(candidates) == 1 {
+		return config.EnvironmentApp{EnvironmentUUID: env.UUID, AppUUID: candidates[0].UUID}, nil
+	}
+	return config.EnvironmentApp{}, fmt.Errorf("%w named %q in environment %q", errNoEnvApp, linkedName, envName)
+}
+
+// environmentUUID returns the UUID of the project's environment called name
+func environmentUUID(client *api.Client, projectUUID, name string) (string, error) {
+	project, err := client.GetProject(projectUUID)
+	if err != nil {
+		return "", fmt.Errorf("failed to load project: %w", err)
+	}
+	for _, e := range project.Environments {
+		if strings.EqualFold(e.Name, name) {
+			return e.UUID, nil
+		}
+	}
+	return "", fmt.Errorf("%w %q", errNoEnvironment, name)
+}
+
+// envDeployConfig returns the config for deploying to the named environment
+// env. When the environment has no app yet, it offers to create one as a
+// copy of the linked app; it returns nil when the user declines.
+func envDeployConfig(client *api.Client, projectCfg *config.ProjectConfig, env deployEnv) (*config.ProjectConfig, error) {
+	if app, ok := projectCfg.EnvironmentApp(env.Name); ok && app.EnvironmentUUID != "" {
+		_, err := client.GetApplication(app.AppUUID)
+		if err == nil {
+			return projectCfg.ForEnvironment(env.Name), nil
+		}
+		// An app deleted in Coolify is looked up again below
+		if !api.IsNotFound(err) {
+			ui.Error(fmt.Sprintf("Failed to load the %s app", env.Name))
+			return nil, err
+		}
+	}
+	if projectCfg.ProjectUUID == "" {
+		ui.Error("The linked app's Coolify project is unknown")
+		ui.Dim(fmt.Sprintf("Run '%s link' to look it up", execName()))
+		return nil, fmt.Errorf("project UUID missing from cdp.json")
+	}
+
+	var app config.EnvironmentApp
+	err := ui.RunTasks([]ui.Task{
+		{
+			Name:         "find-env-app",
+			ActiveName:   fmt.Sprintf("Looking up the %s app...", env.Name),
+			CompleteName: fmt.Sprintf("Looked up the %s app", env.Name),
+			Action: func() error {
+				var err error
+				app, err = findEnvApp(client, projectCfg.ProjectUUID, projectCfg.AppUUID, env.Name)
+				if errors.Is(err, errNoEnvironment) || errors.Is(err, errNoEnvApp) {
+					return nil
+				}
+				return err
+			},
+		},
+	})
+	if err != nil {
+		ui.Error(err.Error())
+		return nil, err
+	}
+
+	if app.AppUUID == "" {
+		ui.Spacer()
+		create, err := ui.ConfirmWithOptions(fmt.Sprintf("%s has no %s app yet. Create one from production?", projectCfg.Name, env.Name), ui.ConfirmOptions{Default: true})
+		if err != nil {
+			return nil, err
+		}
+		if !create {
+			return nil, nil
+		}
+		ui.Spacer()
+		uuid, err := deploy.CloneApp(client, projectCfg, deploy.CloneOptions{
+			ProjectUUID: projectCfg.ProjectUUID,
+			Environment: env.Name,
+			Name:        projectCfg.Name,
+		})
+		if err != nil {
+			return nil, err
+		}
+		app.AppUUID = uuid
+		if app.EnvironmentUUID, err = environmentUUID(client, projectCfg.ProjectUUID, env.Name); err != nil {
+			ui.Error(err.Error())
+			return nil, err
+		}
+		ui.Dim(fmt.Sprintf("  It has no domain or environment variables yet; add them with '%s env add --env %s'", execName(), env.Name))
+	}
+
+	projectCfg.SetEnvironmentApp(env.Name, app)
+	if err := config.SaveProject(projectCfg); err != nil {
+		ui.Warning("Failed to record the app in cdp.json")
+	}
+	return projectCfg.ForEnvironment(env.Name), nil
+}
@@ -0,0 +1,155 @@
+package cmd
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/dropalltables/cdp/internal/api"
+	"github.com/dropalltables/cdp/internal/config"
+	"github.com/dropalltables/cdp/internal/ui"
+	"github.com/spf13/cobra"
+)
+
+// environmentNamePattern keeps environment names usable as --env values
+var environmentNamePattern = regexp.MustCompile(`^[a-z0-9][a-z0-9_-]*$`)
+
+var envsCmd = &cobra.Command{
+	Use:     "envs",
+	Aliases: []string{"environments"},
+	Short:   "Manage the Coolify environments of the linked project",
+	Long: `List and create environments (production, staging, ...) in the linked
+project's Coolify project. Environment variables are managed with 'cdp env'.`,
+}
+
+var envsLsCmd = &cobra.Command{
+	Use:     "ls",
+	Aliases: []string{"list"},
+	Short:   "List the project's environments",
+	Args:    cobra.NoArgs,
+	RunE:    runEnvsLs,
+}
+
+var envsCreateCmd = &cobra.Command{
+	Use:     "create NAME",
+	Short:   "Create an environment in the project",
+	Example: `  cdp envs create staging`,
+	Args:    cobra.ExactArgs(1),
+	RunE:    runEnvsCreate,
+}
+
+func init() {
+	rootCmd.AddCommand(envsCmd)
+	envsCmd.AddCommand(envsLsCmd)
+	envsCmd.AddCommand(envsCreateCmd)
+}
+
+// linkedProjectClient returns the linked project config, which must know
+// its Coolify project, and an API client
+func linkedProjectClient() (*config.ProjectConfig, *api.Client, error) {
+	if err := checkLogin(); err != nil {
+		return nil, nil, err
+	}
+	projectCfg, err := loadLinkedProject()
+	if err != nil {
+		return nil, nil, err
+	}
+	if projectCfg.ProjectUUID == "" {
+		ui.Error("The linked app's Coolify project is unknown")
+		ui.Dim(fmt.Sprintf("Run '%s link' to look it up", execName()))
+		return nil, nil, fmt.Errorf("project UUID missing from cdp.json")
+	}
+	globalCfg, err := config.LoadGlobal()
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to load configuration: %w", err)
+	}
+	return projectCfg, api.NewClient(globalCfg.CoolifyURL, globalCfg.CoolifyToken), nil
+}
+
+func runEnvsLs(cmd *cobra.Command, args []string) error {
+	projectCfg, client, err := linkedProjectClient()
+	if err != nil {
+		return err
+	}
+
+	var envs []api.Environment
+	err = ui.RunTasks([]ui.Task{
+		{
+			Name:         "fetch-environments",
+			ActiveName:   "Fetching environments...",
+			CompleteName: "Fetched environments",
+			Action: func() error {
+				var err error
+				envs, err = client.ListEnvironments(projectCfg.ProjectUUID)
+				return err
+			},
+		},
+	})
+	if err != nil {
+		ui.Error("Failed to fetch environments")
+		return err
+	}
+	if len(envs) == 0 {
+		ui.Warning("The project has no environments")
+		return nil
+	}
+
+	rows := make([][]string, len(envs))
+	for i, e := range envs {
+		app := "-"
+		if e.UUID != "" && e.UUID == projectCfg.EnvironmentUUID {
+			app = "linked app"
+		} else if envApp, ok := projectCfg.EnvironmentApp(e.Name); ok {
+			app = envApp.AppUUID
+		}
+		rows[i] = []string{e.Name, orDash(e.UUID), app}
+	}
+	ui.Spacer()
+	ui.Table([]string{"Environment", "UUID", "cdp app"}, rows)
+	return nil
+}
+
+func runEnvsCreate(cmd *cobra.Command, args []string) error {
+	name := strings.ToLower(strings.TrimSpace(args[0]))
+	if !environmentNamePattern.MatchString(name) {
+		ui.Error(fmt.Sprintf("Invalid environment name %q", args[0]))
+		ui.Dim("  Use lowercase letters, digits, - and _")
+		return fmt.Errorf("invalid environment name %q", args[0])
+	}
+	if name == envPreview {
+		ui.Error("--env preview means pull request deployments, so preview can't name an environment")
+		return fmt.Errorf("reserved environment name %q", name)
+	}
+
+	projectCfg, client, err := linkedProjectClient()
+	if err != nil {
+		return err
+	}
+
+	err = ui.RunTasks([]ui.Task{
+		{
+			Name:         "create-environment",
+			ActiveName:   fmt.Sprintf("Creating %s environment...", name),
+			CompleteName: fmt.Sprintf("Created %s environment", name),
+			Action: func() error {
+				_, err := client.CreateEnvironment(projectCfg.ProjectUUID, name)
+				return err
+			},
+		},
+	})
+	if err != nil {
+		if api.IsConflict(err) {
+			ui.Error(fmt.Sprintf("The project already has a %s environment", name))
+		} else {
+			ui.Error("Failed to create environment")
+			ui.Dim("  " + err.Error())
+		}
+		return err
+	}
+
+	ui.Spacer()
+	ui.NextSteps([]string{
+		fmt.Sprintf("Run '%s deploy --env %s' to deploy a copy of the app to it", execName(), name),
+	})
+	return nil
+}
@@ -0,0 +1,542 @@
+package cmd
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"net"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/dropalltables/cdp/internal/api"
+	"github.com/dropalltables/cdp/internal/config"
+	"github.com/dropalltables/cdp/internal/deploy"
+	"github.com/dropalltables/cdp/internal/ui"
+	"github.com/spf13/cobra"
+)
+
+var fixCmd = &cobra.Command{
+	Use:   "fix",
+	Short: "Troubleshoot the last failed command",
+	Long: `Walk through diagnostics for the last cdp command that failed, picked by
+what went wrong:
+
+  Deployment failed     the build log's errors, likely causes, and a
+                        redeploy without the build cache
+  Token rejected        the token's abilities, and logging in again
+  Coolify unreachable   DNS, TCP and HTTP checks of the instance
+  Certificate error     the certificate's expiry, hostname and issuer
+
+Fixes are only applied after you confirm them. With no recorded failure,
+all of 'cdp doctor' runs instead.`,
+	Args: cobra.NoArgs,
+	RunE: runFix,
+}
+
+func init() {
+	rootCmd.AddCommand(fixCmd)
+}
+
+func runFix(cmd *cobra.Command, args []string) error {
+	f := config.LastFailure()
+	if f == nil {
+		ui.Success("No failed command recorded")
+		ui.Dim("Running all checks instead")
+		ui.Spacer()
+		return runDoctor(cmd, args)
+	}
+
+	cfg, err := config.LoadGlobal()
+	if err != nil {
+		return fmt.Errorf("failed to load configuration: %w", err)
+	}
+
+	ui.KeyValue("Command", f.Command)
+	ui.KeyValue("Failed", fmt.Sprintf("%s ago", time.Since(f.At).Round(time.Minute)))
+	if cwd, err := os.Getwd(); err == nil && f.Dir != "" && f.Dir != cwd {
+		ui.KeyValue("Directory", f.Dir)
+	}
+	ui.KeyValue("Error", f.Error)
+	ui.Spacer()
+
+	target := f.URL
+	if target == "" {
+		target = cfg.CoolifyURL
+	}
+
+	switch f.Kind {
+	case config.FailureDeploy:
+		return fixDeployment(cmd, cfg, f)
+	case config.FailureUnauthorized:
+		return fixUnauthorized(cmd, cfg)
+	case config.FailureUnreachable:
+		return fixUnreachable(cmd, cfg, target)
+	case config.FailureCertificate:
+		return fixCertificate(target)
+	default:
+		ui.Dim("No guided flow for this error; running all checks")
+		return runDoctor(cmd, args)
+	}
+}
+
+// fixDeployment shows what went wrong in the failed deployment's build log
+// and offers to redeploy without the build cache
+func fixDeployment(cmd *cobra.Command, cfg *config.GlobalConfig, f *config.Failure) error {
+	appUUID := f.AppUUID
+	if appUUID == "" {
+		if projectCfg, err := config.LoadProject(); err == nil && projectCfg != nil {
+			appUUID = projectCfg.AppUUID
+		}
+	}
+	if appUUID == "" {
+		ui.Error("The failed deployment's app is unknown")
+		ui.Dim(fmt.Sprintf("Run '%s fix' from the project's directory", execName()))
+		return fmt.Errorf("no app to troubleshoot")
+	}
+	client := api.NewClient(cfg.CoolifyURL, cfg.CoolifyToken)
+
+	var logs string
+	err := ui.RunTasks([]ui.Task{
+		{
+			Name:         "fetch-deployment",
+			ActiveName:   "Fetching the failed deployment...",
+			CompleteName: "Fetched the failed deployment",
+			Action: func() error {
+				deployments, err := client.ListDeploymentHistory(appUUID)
+				if err != nil {
+					return err
+				}
+				if len(deployments) == 0 {
+					return fmt.Errorf("the app has no deployments")
+				}
+				latest := deployments[0]
+				for _, d := range deployments {
+					if strings.EqualFold(d.Status, "failed") {
+						latest = d
+						break
+					}
+				}
+				detail, err := client.GetDeployment(latest.DeploymentUUID)
+				if err != nil {
+					return err
+				}
+				logs = api.ParseLogs(detail.Logs)
+				return nil
+			},
+		},
+	})
+	if err != nil {
+		ui.Error("Failed to fetch the deployment: " + err.Error())
+		return err
+	}
+
+	if excerpt := failureExcerpt(logs, 15); len(excerpt) > 0 {
+		ui.Spacer()
+		ui.Bold("Build log")
+		for _, line := range excerpt {
+			ui.Dim("  " + line)
+		}
+	}
+
+	causes := diagnoseDeployLog(logs)
+	ui.Spacer()
+	if len(causes) == 0 {
+		ui.Warning("No known cause found in the build log")
+	}
+	for _, c := range causes {
+		ui.Error(c.problem)
+		ui.Dim("  " + c.fix)
+	}
+	ui.Spacer()
+
+	redeploy, err := ui.ConfirmWithOptions("Redeploy without the build cache?", ui.ConfirmOptions{Default: len(causes) == 0})
+	if err != nil {
+		return err
+	}
+	if redeploy {
+		if _, err := client.Deploy(appUUID, true, 0); err != nil {
+			ui.Error("Failed to trigger deployment")
+			return err
+		}
+		watch := ui.StartGroup("Watching deployment", IsVerbose())
+		if !deploy.WatchDeployment(client, appUUID) {
+			watch.End(deploy.ErrDeploymentFailed)
+			ui.Error("The redeploy failed too")
+			return deploy.ErrDeploymentFailed
+		}
+		watch.End(nil)
+		_ = config.ClearLastFailure()
+		ui.Success("Deployed")
+		return nil
+	}
+
+	ui.NextSteps([]string{
+		fmt.Sprintf("Run '%s logs' to view the app's runtime logs", execName()),
+		fmt.Sprintf("Run '%s deployments ls' to compare with earlier deployments", execName()),
+		fmt.Sprintf("Run '%s --verbose' to deploy with the full build output", execName()),
+	})
+	return nil
+}
+
+// deployCause is a known reason for a failed build, with its fix
+type deployCause struct {
+	problem string
+	fix     string
+}
+
+// deployCausePatterns map build log text to known causes
+var deployCausePatterns = []struct {
+	patterns []string
+	cause    deployCause
+}{
+	{
+		[]string{"no space left on device"},
+		deployCause{"The server ran out of disk space", "Free space on the server, e.g. run 'docker system prune -af' over SSH"},
+	},
+	{
+		[]string{"heap out of memory", "exit code: 137", "out of memory", "oomkilled"},
+		deployCause{"The build ran out of memory", "Raise the limit with 'cdp scale --memory 2g', or lower NODE_OPTIONS=--max-old-space-size"},
+	},
+	{
+		[]string{"missing script: build", "missing script: \"build\""},
+		deployCause{"package.json has no build script", "Add a build script, or set build_command in cdp.json"},
+	},
+	{
+		[]string{"npm ci can only install", "frozen-lockfile", "lockfile is out of date", "lockfile needs to be updated"},
+		deployCause{"The lockfile doesn't match package.json", "Run the install locally and commit the updated lockfile"},
+	},
+	{
+		[]string{"failed to read dockerfile", "dockerfile: no such file"},
+		deployCause{"No Dockerfile was found", "Commit the Dockerfile, or check base_directory in cdp.json"},
+	},
+	{
+		[]string{"could not read from remote repository", "permission denied (publickey)", "repository not found"},
+		deployCause{"Coolify couldn't clone the repository", "Check that Coolify's GitHub App or deploy key can access the repository"},
+	},
+	{
+		[]string{"unhealthy", "healthcheck failed", "health check failed"},
+		deployCause{"The container failed its health check", "Check that port in cdp.json matches the port the app listens on"},
+	},
+}
+
+// diagnoseDeployLog returns the known causes found in a build log
+func diagnoseDeployLog(logs string) []deployCause {
+	lower := strings.ToLower(logs)
+	var causes []deployCause
+	for _, p := range deployCausePatterns {
+		for _, pattern := range p.patterns {
+			if strings.Contains(lower, pattern) {
+				causes = append(causes, p.cause)
+				break
+			}
+		}
+	}
+	return causes
+}
+
+// failureExcerpt returns up to max lines of a build log that look like
+// errors, or its last lines when none do
+func failureExcerpt(logs string, max int) []string {
+	var lines, errorLines []string
+	for _, line := range strings.Split(logs, "\n") {
+		line = strings.TrimRight(line, " \r")
+		if strings.TrimSpace(line) == "" {
+			continue
+		}
+		lines = append(lines, line)
+		lower := strings.ToLower(line)
+		if strings.Contains(lower, "error") || strings.Contains(lower, "failed") || strings.Contains(lower, "err!") {
+			errorLines = append(errorLines, line)
+		}
+	}
+	if len(errorLines) > 0 {
+		lines = errorLines
+	}
+	if len(lines) > max {
+		lines = lines[len(lines)-max:]
+	}
+	return lines
+}
+
+// fixUnauthorized checks the token again and offers to log in with a new one
+func fixUnauthorized(cmd *cobra.Command, cfg *config.GlobalConfig) error {
+	var results []doctorResult
+	if err := ui.RunTasks([]ui.Task{
+		doctorCheck(&results, "fix-coolify", "Checking Coolify token...", func() []doctorResult { return checkCoolify(cfg) }),
+	}); err != nil {
+		return err
+	}
+	failed, _ := printDoctorResults(results)
+	ui.Spacer()
+	if failed == 0 {
+		_ = config.ClearLastFailure()
+		ui.Success("The token works now")
+		return nil
+	}
+
+	login, err := ui.Confirm("Log in again with a new token?")
+	if err != nil || !login {
+		return err
+	}
+	ui.Spacer()
+	if err := runLogin(cmd, nil); err != nil {
+		return err
+	}
+	_ = config.ClearLastFailure()
+	return nil
+}
+
+// fixUnreachable narrows down why an instance can't be reached: DNS, a
+// closed port, or an HTTP error
+func fixUnreachable(cmd *cobra.Command, cfg *config.GlobalConfig, target string) error {
+	host, port, err := hostPort(target)
+	if err != nil {
+		ui.Error(fmt.Sprintf("%q isn't a valid URL", target))
+		ui.Dim(fmt.Sprintf("  Run '%s login' to set the Coolify URL", execName()))
+		return err
+	}
+
+	var results []doctorResult
+	err = ui.RunTasks([]ui.Task{
+		doctorCheck(&results, "fix-dns", "Checking DNS...", func() []doctorResult {
+			addrs, err := net.LookupHost(host)
+			if err != nil {
+				return []doctorResult{{
+					name:   "DNS",
+					status: doctorFail,
+					detail: fmt.Sprintf("%s doesn't resolve", host),
+					fix:    "Check the URL for typos, and connect to the VPN if the instance is private",
+				}}
+			}
+			return []doctorResult{{name: "DNS", status: doctorOK, detail: strings.Join(addrs, ", ")}}
+		}),
+		doctorCheck(&results, "fix-tcp", "Checking connection...", func() []doctorResult {
+			conn, err := net.DialTimeout("tcp", net.JoinHostPort(host, port), 5*time.Second)
+			if err != nil {
+				return []doctorResult{{
+					name:   "Connection",
+					status: doctorFail,
+					detail: fmt.Sprintf("Port %s is closed or filtered", port),
+					fix:    "Check that the server is up and a firewall allows the port",
+				}}
+			}
+			conn.Close()
+			return []doctorResult{{name: "Connection", status: doctorOK, detail: "Port " + port + " open"}}
+		}),
+	})
+	if err != nil {
+		return err
+	}
+	if sameInstance(target, cfg.CoolifyURL) {
+		_ = ui.RunTasks([]ui.Task{
+			doctorCheck(&results, "fix-http", "Checking Coolify API...", func() []doctorResult { return checkCoolify(cfg) }),
+		})
+	}
+
+	failed, _ := printDoctorResults(results)
+	ui.Spacer()
+	if failed == 0 {
+		_ = config.ClearLastFailure()
+		ui.Success(fmt.Sprintf("%s is reachable now", host))
+		return nil
+	}
+	if !sameInstance(target, cfg.CoolifyURL) {
+		return nil
+	}
+
+	change, err := ui.Confirm("Log in again with a different Coolify URL?")
+	if err != nil || !change {
+		return err
+	}
+	ui.Spacer()
+	return runLogin(cmd, nil)
+}
+
+// fixCertificate inspects the certificate an instance presents to explain
+// why it isn't trusted
+func fixCertificate(target string) error {
+	host, port, err := hostPort(target)
+	if err != nil {
+		ui.Error(fmt.Sprintf("%q isn't a valid URL", target))
+		return err
+	}
+
+	var results []doctorResult
+	err = ui.RunTasks([]ui.Task{
+		doctorCheck(&results, "fix-certificate", "Checking certificate...", func() []doctorResult {
+			return inspectCertificate(host, port)
+		}),
+	})
+	if err != nil {
+		return err
+	}
+	failed, _ := printDoctorResults(results)
+	ui.Spacer()
+	if failed == 0 {
+		_ = config.ClearLastFailure()
+		ui.Success("The certificate is trusted now")
+	}
+	return nil
+}
+
+// inspectCertificate fetches the certificate of host without verifying it,
+// then checks its expiry, hostname and chain separately
+func inspectCertificate(host, port string) []doctorResult {
+	dialer := &net.Dialer{Timeout: 5 * time.Second}
+	// Verification is what failed; skip it to look at the certificate
+	conn, err := tls.DialWithDialer(dialer, "tcp", net.JoinHostPort(host, port), &tls.Config{
+		ServerName:         host,
+		InsecureSkipVerify: true,
+	})
+	if err != nil {
+		return []doctorResult{{
+			name:   "Certificate",
+			status: doctorFail,
+			detail: "No TLS connection: " + err.Error(),
+			fix:    "Check that the URL uses the right scheme and port",
+		}}
+	}
+	defer conn.Close()
+	certs := conn.ConnectionState().PeerCertificates
+	if len(certs) == 0 {
+		return []doctorResult{{name: "Certificate", status: doctorFail, detail: "None presented"}}
+	}
+	leaf := certs[0]
+
+	var results []doctorResult
+	now := time.Now()
+	switch {
+	case now.After(leaf.NotAfter):
+		results = append(results, doctorResult{
+			name:   "Expiry",
+			status: doctorFail,
+			detail: "Expired " + leaf.NotAfter.Format("2006-01-02"),
+			fix:    "Coolify renews certificates through Let's Encrypt; check that ports 80 and 443 reach the server and restart the proxy",
+		})
+	case now.Before(leaf.NotBefore):
+		results = append(results, doctorResult{
+			name:   "Expiry",
+			status: doctorFail,
+			detail: "Not valid until " + leaf.NotBefore.Format("2006-01-02"),
+			fix:    "Check this machine's clock",
+		})
+	default:
+		results = append(results, doctorResult{name: "Expiry", status: doctorOK, detail: "Valid until " + leaf.NotAfter.Format("2006-01-02")})
+	}
+
+	if err := leaf.VerifyHostname(host); err != nil {
+		results = append(results, doctorResult{
+			name:   "Hostname",
+			status: doctorFail,
+			detail: fmt.Sprintf("Issued for %s, not %s", strings.Join(leaf.DNSNames, ", "), host),
+			fix:    "Use the domain the certificate was issued for, or set the instance's domain in Coolify's settings",
+		})
+	} else {
+		results = append(results, doctorResult{name: "Hostname", status: doctorOK, detail: host})
+	}
+
+	intermediates := x509.NewCertPool()
+	for _, c := range certs[1:] {
+		intermediates.AddCert(c)
+	}
+	// Expiry is reported above, so check the chain as of issuance
+	_, err = leaf.Verify(x509.VerifyOptions{Intermediates: intermediates, CurrentTime: leaf.NotBefore.Add(time.Second)})
+	switch {
+	case err == nil:
+		results = append(results, doctorResult{name: "Issuer", status: doctorOK, detail: leaf.Issuer.CommonName})
+	case leaf.Issuer.String() == leaf.Subject.String():
+		results = append(results, doctorResult{
+			name:   "Issuer",
+			status: doctorFail,
+			detail: "Self-signed",
+			fix:    "Give the instance a public domain so Coolify can get a Let's Encrypt certificate, or add the certificate to this machine's trust store",
+		})
+	default:
+		results = append(results, doctorResult{
+			name:   "Issuer",
+			status: doctorFail,
+			detail: fmt.Sprintf("%s isn't trusted", leaf.Issuer.CommonName),
+			fix:    "Add the issuing CA to this machine's trust store, or serve the full certificate chain",
+		})
+	}
+	return results
+}
+
+// hostPort returns the host and port a URL connects to
+func hostPort(rawURL string) (string, string, error) {
+	u, err := url.Parse(rawURL)
+	if err != nil || u.Hostname() == "" {
+		return "", "", fmt.Errorf("invalid URL %q", rawURL)
+	}
+	port := u.Port()
+	if port == "" {
+		port = "443"
+		if u.Scheme == "http" {
+			port = "80"
+		}
+	}
+	return u.Hostname(), port, nil
+}
+
+// recordFailure saves a failed command for 'cdp fix'
+func recordFailure(cmd *cobra.Command, err error) {
+	if cmd == nil || cmd == fixCmd || isCompletionCmd(cmd) {
+		return
+	}
+	f := &config.Failure{
+		Kind:    classifyFailure(err),
+		Command: cmd.CommandPath(),
+		Error:   err.Error(),
+		At:      time.Now(),
+	}
+	var urlErr *url.Error
+	if errors.As(err, &urlErr) {
+		if u, err := url.Parse(urlErr.URL); err == nil {
+			f.URL = u.Scheme + "://" + u.Host
+		}
+	}
+	if dir, err := os.Getwd(); err == nil {
+		f.Dir = dir
+	}
+	if projectCfg, err := config.LoadProject(); err == nil && projectCfg != nil {
+		f.AppUUID = projectCfg.AppUUID
+	}
+	_ = config.SaveLastFailure(f)
+}
+
+// clearResolvedFailure forgets the last failure once the same command
+// succeeds in the same directory
+func clearResolvedFailure(cmd *cobra.Command) {
+	f := config.LastFailure()
+	if f == nil || cmd == nil || f.Command != cmd.CommandPath() {
+		return
+	}
+	if dir, err := os.Getwd(); err == nil && dir == f.Dir {
+		_ = config.ClearLastFailure()
+	}
+}
+
+// classifyFailure picks the troubleshooting flow for an error
+func classifyFailure(err error) string {
+	var apiErr *api.APIError
+	var certErr *tls.CertificateVerificationError
+	var authorityErr x509.UnknownAuthorityError
+	var hostnameErr x509.HostnameError
+	var invalidErr x509.CertificateInvalidError
+	var netErr net.Error
+
+	switch {
+	case errors.Is(err, deploy.ErrDeploymentFailed):
+		return config.FailureDeploy
+	case errors.As(err, &apiErr) && apiErr.StatusCode == 401:
+		return config.FailureUnauthorized
+	case errors.As(err, &certErr), errors.As(err, &authorityErr), errors.As(err, &hostnameErr), errors.As(err, &invalidErr):
+		return config.FailureCertificate
+	case errors.As(err, &netErr):
+		return config.FailureUnreachable
+	}
+	return config.FailureOther
+}
@@ -5,6 +5,7 @@ import (
 
 	"github.com/dropalltables/cdp/internal/api"
 	"github.com/dropalltables/cdp/internal/config"
+	"github.com/dropalltables/cdp/internal/deploy"
 	"github.com/dropalltables/cdp/internal/docker"
 	"github.com/dropalltables/cdp/internal/git"
 	"github.com/dropalltables/cdp/internal/ui"
@@ -162,11 +163,10 @@ func runHealth(cmd *cobra.Command, args []string) error {
 				})
 				return nil
 			}
-			err := docker.VerifyLogin(
-				cfg.DockerRegistry.URL,
-				cfg.DockerRegistry.Username,
-				cfg.DockerRegistry.Password,
-			)
+			err := deploy.MigrateRegistryPassword(cfg)
+			if err == nil {
+				err = docker.VerifyLogin(cfg.DockerRegistry.URL, cfg.DockerRegistry.Username, "")
+			}
 			if err != nil {
 				results = append(results, checkResult{
 					name:   "Docker Registry",
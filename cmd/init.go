@@ -0,0 +1,80 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/dropalltables/cdp/internal/api"
+	"github.com/dropalltables/cdp/internal/config"
+	"github.com/dropalltables/cdp/internal/deploy"
+	"github.com/dropalltables/cdp/internal/ui"
+	"github.com/spf13/cobra"
+)
+
+var initCmd = &cobra.Command{
+	Use:   "init",
+	Short: "Configure the project without deploying",
+	Long: `Write cdp.json by detecting the framework and choosing the deploy method,
+server, project and advanced options, as the first deploy would. Nothing is
+created on Coolify or GitHub until the first deploy.
+
+Commit cdp.json and deploy later, e.g. from CI with 'cdp --yes'.`,
+	Args: cobra.NoArgs,
+	RunE: runInit,
+}
+
+func init() {
+	rootCmd.AddCommand(initCmd)
+}
+
+func runInit(cmd *cobra.Command, args []string) error {
+	if err := checkLogin(); err != nil {
+		return err
+	}
+
+	globalCfg, err := config.LoadGlobal()
+	if err != nil {
+		return fmt.Errorf("failed to load configuration: %w", err)
+	}
+
+	seed, err := config.LoadProject()
+	if err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("failed to load project configuration: %w", err)
+	}
+
+	// A config with only build settings (e.g. from a template) seeds
+	// setup; one that names a server has been through it already
+	if seed != nil && (seed.ServerUUID != "" || seed.AppUUID != "") {
+		ui.Warning("This project is already configured")
+		ui.NextSteps([]string{
+			fmt.Sprintf("Run '%s' to deploy it", execName()),
+			fmt.Sprintf("Delete %s to configure it from scratch", config.ProjectConfigPath(".")),
+		})
+		return nil
+	}
+
+	client := api.NewClient(globalCfg.CoolifyURL, globalCfg.CoolifyToken)
+	projectCfg, err := deploy.FirstTimeSetup(client, globalCfg, seed)
+	if err != nil {
+		// Exit silently on interrupt
+		if strings.Contains(err.Error(), "interrupted") {
+			return nil
+		}
+		return err
+	}
+
+	// The point of init is a config the team commits
+	commit := true
+	projectCfg.CommitConfig = &commit
+	if err := config.SaveProject(projectCfg); err != nil {
+		return fmt.Errorf("failed to save configuration: %w", err)
+	}
+
+	ui.Spacer()
+	ui.NextSteps([]string{
+		fmt.Sprintf("Commit %s", config.ProjectConfigPath(".")),
+		fmt.Sprintf("Run '%s' to deploy, or '%s --yes' from CI", execName(), execName()),
+	})
+	return nil
+}
@@ -0,0 +1,192 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/dropalltables/cdp/internal/api"
+	"github.com/dropalltables/cdp/internal/config"
+	"github.com/dropalltables/cdp/internal/deploy"
+	"github.com/dropalltables/cdp/internal/ui"
+	"github.com/spf13/cobra"
+)
+
+var (
+	// Flag for start, stop and restart commands
+	lifecycleAppFlag string
+)
+
+var startCmd = &cobra.Command{
+	Use:   "start",
+	Short: "Start the app's containers",
+	Long: `Start the linked app, or the app given with --app, and wait until it's
+running. Coolify builds the app first if it has no image yet.`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return runLifecycle(lifecycleStart)
+	},
+}
+
+var stopCmd = &cobra.Command{
+	Use:   "stop",
+	Short: "Stop the app's containers",
+	Long:  `Stop the linked app, or the app given with --app, and wait until it's stopped.`,
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return runLifecycle(lifecycleStop)
+	},
+}
+
+var restartCmd = &cobra.Command{
+	Use:   "restart",
+	Short: "Restart the app's containers without rebuilding",
+	Long: `Restart the linked app, or the app given with --app, and wait until it's
+running again. Unlike a deploy, nothing is rebuilt.`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return runLifecycle(lifecycleRestart)
+	},
+}
+
+func init() {
+	for _, c := range []*cobra.Command{startCmd, stopCmd, restartCmd} {
+		rootCmd.AddCommand(c)
+		c.Flags().StringVar(&lifecycleAppFlag, "app", "", "UUID of the app to act on instead of the linked one")
+	}
+}
+
+// lifecycleAction is one of start, stop and restart
+type lifecycleAction struct {
+	verb    string // e.g. "start"
+	active  string // e.g. "Starting"
+	done    string // e.g. "started"
+	running bool   // whether the app should end up running
+}
+
+var (
+	lifecycleStart   = lifecycleAction{verb: "start", active: "Starting", done: "started", running: true}
+	lifecycleStop    = lifecycleAction{verb: "stop", active: "Stopping", done: "stopped", running: false}
+	lifecycleRestart = lifecycleAction{verb: "restart", active: "Restarting", done: "restarted", running: true}
+)
+
+func runLifecycle(action lifecycleAction) error {
+	appUUID, client, err := lifecycleApp()
+	if err != nil {
+		return err
+	}
+
+	var app *api.Application
+	err = ui.RunTasks([]ui.Task{
+		{
+			Name:         "fetch-app",
+			ActiveName:   "Fetching application info...",
+			CompleteName: "Fetched application info",
+			Action: func() error {
+				var err error
+				app, err = client.GetApplication(appUUID)
+				return err
+			},
+		},
+	})
+	if err != nil {
+		ui.Error("Failed to fetch application info")
+		return fmt.Errorf("failed to fetch application: %w", err)
+	}
+
+	switch {
+	case action == lifecycleStart && deploy.IsRunning(app.Status):
+		ui.Success(fmt.Sprintf("%s is already running", app.Name))
+		return nil
+	case action == lifecycleStop && deploy.IsStopped(app.Status):
+		ui.Success(fmt.Sprintf("%s is already stopped", app.Name))
+		return nil
+	case action == lifecycleStop:
+		confirmed, err := ui.ConfirmWithOptions(fmt.Sprintf("Stop %s? It will be offline until started again", app.Name), ui.ConfirmOptions{Danger: true})
+		if err != nil {
+			return err
+		}
+		if !confirmed {
+			ui.Dim("Cancelled")
+			return nil
+		}
+	}
+
+	var status string
+	err = ui.RunTasks([]ui.Task{
+		{
+			Name:         action.verb,
+			ActiveName:   fmt.Sprintf("%s %s...", action.active, app.Name),
+			CompleteName: fmt.Sprintf("Asked Coolify to %s %s", action.verb, app.Name),
+			Action: func() error {
+				deploymentUUID, err := requestLifecycle(client, appUUID, action)
+				if err != nil {
+					return err
+				}
+				// Starts and restarts run as deployments, which must finish
+				// before the containers come up
+				if deploymentUUID != "" {
+					if _, err := deploy.WaitForDeployment(client, deploymentUUID); err != nil {
+						return err
+					}
+				}
+				return nil
+			},
+		},
+		{
+			Name:         "wait-" + action.verb,
+			ActiveName:   fmt.Sprintf("Waiting for %s to be %s...", app.Name, lifecycleState(action)),
+			CompleteName: fmt.Sprintf("%s is %s", app.Name, lifecycleState(action)),
+			Action: func() error {
+				var err error
+				status, err = deploy.WaitForAppState(client, appUUID, action.running)
+				return err
+			},
+		},
+	})
+	if err != nil {
+		ui.Error(fmt.Sprintf("Failed to %s %s", action.verb, app.Name))
+		ui.Dim("  " + err.Error())
+		if action.running {
+			ui.NextSteps([]string{fmt.Sprintf("Run '%s logs' to see why", execName())})
+		}
+		return err
+	}
+
+	ui.Success(fmt.Sprintf("%s %s (%s)", app.Name, action.done, status))
+	return nil
+}
+
+// requestLifecycle asks Coolify to carry out action, returning the UUID of
+// the deployment it queued, if any
+func requestLifecycle(client *api.Client, appUUID string, action lifecycleAction) (string, error) {
+	switch action {
+	case lifecycleStart:
+		return client.StartApplication(appUUID)
+	case lifecycleRestart:
+		return client.RestartApplication(appUUID)
+	default:
+		return "", client.StopApplication(appUUID)
+	}
+}
+
+// lifecycleState is the state an action leaves the app in
+func lifecycleState(action lifecycleAction) string {
+	if action.running {
+		return "running"
+	}
+	return "stopped"
+}
+
+// lifecycleApp returns the app given with --app, or the linked app
+func lifecycleApp() (string, *api.Client, error) {
+	if lifecycleAppFlag == "" {
+		return getAppUUID()
+	}
+	if err := checkLogin(); err != nil {
+		return "", nil, err
+	}
+	globalCfg, err := config.LoadGlobal()
+	if err != nil {
+		return "", nil, fmt.Errorf("failed to load configuration: %w", err)
+	}
+	return lifecycleAppFlag, api.NewClient(globalCfg.CoolifyURL, globalCfg.CoolifyToken), nil
+}
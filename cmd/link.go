@@ -2,9 +2,12 @@ package cmd
 
 import (
 	"fmt"
+	"sync"
+	"time"
 
 	"github.com/dropalltables/cdp/internal/api"
 	"github.com/dropalltables/cdp/internal/config"
+	"github.com/dropalltables/cdp/internal/git"
 	"github.com/dropalltables/cdp/internal/ui"
 	"github.com/spf13/cobra"
 )
@@ -14,12 +17,35 @@ var linkCmd = &cobra.Command{
 	Short: "Link this directory to an existing Coolify application",
 	Long: `Link the current directory to an existing Coolify application.
 
-This allows you to deploy to an app that was created in the Coolify dashboard.`,
+This allows you to deploy to an app that was created in the Coolify dashboard.
+With --from-remote the app is found by matching the 'origin' git remote,
+which regenerates an ignored cdp.json after a fresh clone. With --domain the
+app is found by one of its domains, e.g. --domain app.example.com. With
+--from the directory is linked from a teammate's 'cdp share' link.
+
+Finding the app's project fetches every project on the instance, which can
+be slow on large instances; --no-project-lookup skips it.`,
 	RunE: runLink,
 }
 
+var (
+	// Flags for link command
+	linkFromRemoteFlag      bool
+	linkDomainFlag          string
+	linkNoProjectLookupFlag bool
+	linkFromFlag            string
+)
+
+// projectLookupWorkers bounds the concurrent GetProject calls in cdp link
+const projectLookupWorkers = 8
+
 func init() {
 	rootCmd.AddCommand(linkCmd)
+
+	linkCmd.Flags().BoolVar(&linkFromRemoteFlag, "from-remote", false, "Find the application by this repository's git remote")
+	linkCmd.Flags().StringVar(&linkDomainFlag, "domain", "", "Find the application serving this domain")
+	linkCmd.Flags().BoolVar(&linkNoProjectLookupFlag, "no-project-lookup", false, "Skip looking up the app's Coolify project")
+	linkCmd.Flags().StringVar(&linkFromFlag, "from", "", "Link from a share link made with 'cdp share'")
 }
 
 func runLink(cmd *cobra.Command, args []string) error {
@@ -47,6 +73,10 @@ func runLink(cmd *cobra.Command, args []string) error {
 
 	client := api.NewClient(globalCfg.CoolifyURL, globalCfg.CoolifyToken)
 
+	if linkFromFlag != "" {
+		return linkFromShare(client, globalCfg, linkFromFlag)
+	}
+
 	// List applications
 	var apps []api.Application
 	err = ui.RunTasks([]ui.Task{
@@ -66,6 +96,12 @@ func runLink(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("failed to list applications: %w", err)
 	}
 
+	// Resolve projects in the background while the user picks an app
+	var lookup *projectLookup
+	if !linkNoProjectLookupFlag {
+		lookup = startProjectLookup(client)
+	}
+
 	if len(apps) == 0 {
 		ui.Spacer()
 		ui.Warning("No applications found in Coolify")
@@ -76,6 +112,45 @@ func runLink(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("no applications found")
 	}
 
+	if linkFromRemoteFlag {
+		remoteURL, err := git.GetRemoteURL(".", "origin")
+		if err != nil {
+			ui.Error("No 'origin' git remote found")
+			return fmt.Errorf("failed to read git remote: %w", err)
+		}
+		slug := git.RepoSlug(remoteURL)
+
+		var matches []api.Application
+		for _, app := range apps {
+			if app.GitRepository != "" && git.RepoSlug(app.GitRepository) == slug {
+				matches = append(matches, app)
+			}
+		}
+		if len(matches) == 0 {
+			ui.Error(fmt.Sprintf("No application deploys %s", slug))
+			ui.Dim(fmt.Sprintf("Run '%s link' to pick one manually", execName()))
+			return fmt.Errorf("no application found for %s", slug)
+		}
+		apps = matches
+	}
+
+	if linkDomainFlag != "" {
+		host := domainHost(linkDomainFlag)
+
+		var matches []api.Application
+		for _, app := range apps {
+			if containsDomain(parseDomains(app.FQDN), host) {
+				matches = append(matches, app)
+			}
+		}
+		if len(matches) == 0 {
+			ui.Error(fmt.Sprintf("No application serves %s", host))
+			ui.Dim(fmt.Sprintf("Run '%s link' to pick one manually", execName()))
+			return fmt.Errorf("no application found for %s", host)
+		}
+		apps = matches
+	}
+
 	// Select application
 	appOptions := make(map[string]string)
 	appMap := make(map[string]api.Application)
@@ -88,9 +163,15 @@ func runLink(cmd *cobra.Command, args []string) error {
 		appMap[app.UUID] = app
 	}
 
-	appUUID, err := ui.SelectWithKeys("Select application:", appOptions)
-	if err != nil {
-		return err
+	var appUUID string
+	if len(apps) == 1 && (linkFromRemoteFlag || linkDomainFlag != "") {
+		appUUID = apps[0].UUID
+		ui.LogChoice("Application", appOptions[appUUID])
+	} else {
+		appUUID, err = ui.SelectWithKeys("Select application:", appOptions)
+		if err != nil {
+			return err
+		}
 	}
 
 	app := appMap[appUUID]
@@ -101,36 +182,10 @@ func runLink(cmd *cobra.Command, args []string) error {
 		deployMethod = config.DeployMethodDocker
 	}
 
-	// Find the project UUID for this app by checking all projects
+	// Find the project UUID for this app; it is optional, so failures are ignored
 	var projectUUID string
-	err = ui.RunTasks([]ui.Task{
-		{
-			Name:         "lookup-project",
-			ActiveName:   "Looking up project information...",
-			CompleteName: "Found project information",
-			Action: func() error {
-				projects, err := client.ListProjects()
-				if err != nil {
-					return nil // Non-fatal - project UUID is optional
-				}
-				for _, proj := range projects {
-					// Check if this project has an environment that matches our app's environment
-					projDetail, err := client.GetProject(proj.UUID)
-					if err == nil && projDetail != nil {
-						for _, env := range projDetail.Environments {
-							if env.ID == app.EnvironmentID {
-								projectUUID = proj.UUID
-								return nil
-							}
-						}
-					}
-				}
-				return nil
-			},
-		},
-	})
-	if err != nil {
-		return err
+	if lookup != nil {
+		projectUUID = lookup.wait(app.EnvironmentID)
 	}
 
 	// Create project config
@@ -153,6 +208,11 @@ func runLink(cmd *cobra.Command, args []string) error {
 	if app.GitRepository != "" {
 		projectCfg.GitHubRepo = app.GitRepository
 	}
+	if linkFromRemoteFlag {
+		// The config was regenerated because it is not in the repo; keep it that way
+		commit := false
+		projectCfg.CommitConfig = &commit
+	}
 
 	err = ui.RunTasks([]ui.Task{
 		{
@@ -174,3 +234,84 @@ func runLink(cmd *cobra.Command, args []string) error {
 
 	return nil
 }
+
+// projectLookup maps environment IDs to their project UUIDs by fetching
+// every project's details with a bounded pool of workers
+type projectLookup struct {
+	mu      sync.Mutex
+	byEnv   map[int]string
+	checked int
+	total   int
+	done    bool
+}
+
+func startProjectLookup(client *api.Client) *projectLookup {
+	l := &projectLookup{byEnv: make(map[int]string)}
+	go func() {
+		defer func() {
+			l.mu.Lock()
+			l.done = true
+			l.mu.Unlock()
+		}()
+
+		projects, err := client.ListProjects()
+		if err != nil {
+			return
+		}
+		l.mu.Lock()
+		l.total = len(projects)
+		l.mu.Unlock()
+
+		queue := make(chan string)
+		var wg sync.WaitGroup
+		for i := 0; i < projectLookupWorkers; i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				for uuid := range queue {
+					detail, err := client.GetProject(uuid)
+					l.mu.Lock()
+					if err == nil && detail != nil {
+						for _, env := range detail.Environments {
+							l.byEnv[env.ID] = uuid
+						}
+					}
+					l.checked++
+					l.mu.Unlock()
+				}
+			}()
+		}
+		for _, proj := range projects {
+			queue <- proj.UUID
+		}
+		close(queue)
+		wg.Wait()
+	}()
+	return l
+}
+
+// wait blocks until the project owning envID is known or every project has
+// been checked, showing progress, and returns its UUID or ""
+func (l *projectLookup) wait(envID int) string {
+	spinner := ui.NewSpinner("Looking up project information...")
+	spinner.Start()
+	for {
+		l.mu.Lock()
+		uuid, checked, total, done := l.byEnv[envID], l.checked, l.total, l.done
+		l.mu.Unlock()
+
+		if uuid != "" {
+			spinner.StopWithSuccess("Found project information")
+			return uuid
+		}
+		if done {
+			spinner.Stop()
+			ui.Dim("No matching project found")
+			return ""
+		}
+		if total > 0 {
+			spinner.SetMessage(fmt.Sprintf("Looking up project information... (%d/%d)", checked, total))
+		}
+		time.Sleep(100 * time.Millisecond)
+	}
+}
@@ -1,17 +1,29 @@
 package cmd
 
 import (
+	"context"
 	"fmt"
 	"strings"
+	"time"
 
 	"github.com/dropalltables/cdp/internal/api"
 	"github.com/dropalltables/cdp/internal/config"
+	"github.com/dropalltables/cdp/internal/discover"
 	"github.com/dropalltables/cdp/internal/docker"
 	"github.com/dropalltables/cdp/internal/git"
 	"github.com/dropalltables/cdp/internal/ui"
 	"github.com/spf13/cobra"
 )
 
+var (
+	// Flags for login command
+	loginOrgFlag      string
+	loginDiscoverFlag bool
+)
+
+// discoverTimeout bounds the local network scan of 'login --discover'
+const discoverTimeout = 20 * time.Second
+
 var loginCmd = &cobra.Command{
 	Use:   "login",
 	Short: "Authenticate with Coolify",
@@ -24,25 +36,88 @@ Required:
 
 Optional:
   • GitHub personal access token (for git-based deployments)
-  • Docker registry credentials (for container-based deployments)`,
+  • Docker registry credentials (for container-based deployments)
+
+Organizations can pin defaults (Coolify URL, default server, webhooks,
+policies) in /etc/cdp/config.json or serve them from a URL passed with
+--org; they are merged beneath your own config.
+
+On a home network, --discover looks for Coolify on the machines around you
+(mDNS and the local subnet) so you can pick it instead of typing its address.`,
 	RunE: runLogin,
 }
 
 func init() {
 	rootCmd.AddCommand(loginCmd)
+
+	loginCmd.Flags().StringVar(&loginOrgFlag, "org", "", "URL of your organization's cdp config")
+	loginCmd.Flags().BoolVar(&loginDiscoverFlag, "discover", false, "Search the local network for Coolify instances")
 }
 
 func runLogin(cmd *cobra.Command, args []string) error {
+	if err := login(); err != nil {
+		return err
+	}
+	ui.NextSteps([]string{
+		fmt.Sprintf("Run '%s' in a project directory to deploy", execName()),
+		fmt.Sprintf("Run '%s health' to verify all connections", execName()),
+	})
+	return nil
+}
+
+// login asks for and saves the Coolify, GitHub and Docker credentials
+func login() error {
+	if overrides := config.EnvOverrides(); len(overrides) > 0 {
+		ui.Warning(fmt.Sprintf("%s in the environment override what you enter here", strings.Join(overrides, ", ")))
+		ui.Spacer()
+	}
+
 	// Load existing config if any
 	cfg, err := config.LoadGlobal()
 	if err != nil {
 		cfg = &config.GlobalConfig{}
 	}
 
+	// Step 0: Organization defaults
+	orgURL := loginOrgFlag
+	if orgURL == "" {
+		orgURL = cfg.OrgConfigURL
+	}
+	if orgURL != "" {
+		err = ui.RunTasks([]ui.Task{
+			{
+				Name:         "fetch-org-config",
+				ActiveName:   "Fetching organization config...",
+				CompleteName: "Fetched organization config",
+				Action: func() error {
+					return config.FetchOrgConfig(orgURL)
+				},
+			},
+		})
+		if err != nil {
+			ui.Warning("Could not fetch organization config: " + err.Error())
+		} else if loginOrgFlag != "" && loginOrgFlag != config.ManagedOrgConfigURL() {
+			cfg.OrgConfigURL = loginOrgFlag
+		}
+	}
+
 	// Step 1: Coolify credentials
-	coolifyURL, err := ui.Input("Coolify URL", "https://coolify.example.com")
-	if err != nil {
-		return err
+	coolifyURL := config.ManagedCoolifyURL()
+	if coolifyURL != "" {
+		ui.KeyValue("Coolify URL", coolifyURL+" (managed by your organization)")
+	} else {
+		if loginDiscoverFlag {
+			coolifyURL, err = discoverCoolifyURL()
+			if err != nil {
+				return err
+			}
+		}
+		if coolifyURL == "" {
+			coolifyURL, err = ui.Input("Coolify URL", "https://coolify.example.com")
+			if err != nil {
+				return err
+			}
+		}
 	}
 	coolifyURL = strings.TrimSuffix(coolifyURL, "/")
 	if coolifyURL == "" {
@@ -51,30 +126,62 @@ func runLogin(cmd *cobra.Command, args []string) error {
 
 	ui.Spacer()
 	ui.Dim("→ Get your API token from Settings → API Tokens in Coolify")
-	token, err := ui.Password("API Token")
-	if err != nil {
+	if open, err := ui.ConfirmWithOptions("Open the API token page in your browser?", ui.ConfirmOptions{Default: true}); err != nil {
 		return err
-	}
-	if token == "" {
-		return fmt.Errorf("API token is required")
+	} else if open {
+		if err := ui.OpenBrowser(coolifyURL + "/security/api-tokens"); err != nil {
+			ui.Dim("  Could not open a browser; open " + coolifyURL + "/security/api-tokens")
+		}
 	}
 
-	// Validate credentials
-	ui.Spacer()
-	client := api.NewClient(coolifyURL, token)
-	err = ui.RunTasks([]ui.Task{
-		{
-			Name:         "validate-coolify",
-			ActiveName:   "Connecting to Coolify...",
-			CompleteName: "Connected to Coolify",
-			Action: func() error {
-				return client.HealthCheck()
+	// Ask until the token works and can do what cdp needs
+	var token string
+	for {
+		token, err = ui.Password("API Token")
+		if err != nil {
+			return err
+		}
+		token = strings.TrimSpace(token)
+		if token == "" {
+			return fmt.Errorf("API token is required")
+		}
+
+		ui.Spacer()
+		client := api.NewClient(coolifyURL, token)
+		var missing []string
+		err = ui.RunTasks([]ui.Task{
+			{
+				Name:         "validate-coolify",
+				ActiveName:   "Checking the token with Coolify...",
+				CompleteName: "Connected to Coolify",
+				Action: func() error {
+					var err error
+					missing, err = missingTokenAbilities(client)
+					return err
+				},
 			},
-		},
-	})
-	if err != nil {
-		ui.Error("Connection failed")
-		return fmt.Errorf("failed to connect: %w", err)
+		})
+		if api.IsUnauthorized(err) {
+			ui.Error("Coolify rejected the token; check it was copied in full")
+			continue
+		}
+		if err != nil {
+			ui.Error("Connection failed")
+			return fmt.Errorf("failed to connect: %w", err)
+		}
+		if len(missing) == 0 {
+			break
+		}
+
+		ui.Warning(fmt.Sprintf("The token lacks the %s ability", strings.Join(missing, ", ")))
+		ui.Dim("  cdp needs read, write and deploy; create a token with all three")
+		useAnyway, err := ui.ConfirmWithOptions("Use this token anyway?", ui.ConfirmOptions{})
+		if err != nil {
+			return err
+		}
+		if useAnyway {
+			break
+		}
 	}
 
 	// Save base credentials
@@ -149,16 +256,29 @@ func runLogin(cmd *cobra.Command, args []string) error {
 			if err != nil {
 				return err
 			}
-			username, err := ui.Input("Username", "")
-			if err != nil {
-				return err
+
+			// Prefer the login Docker already keeps in its credential store
+			username, password := "", ""
+			useStored := false
+			if storedUser, ok := docker.StoredLogin(registryURL); ok {
+				useStored, err = ui.Confirm(fmt.Sprintf("Use Docker's saved login for %s (%s)?", registryURL, storedUser))
+				if err != nil {
+					return err
+				}
+				username = storedUser
 			}
-			password, err := ui.Password("Password/Token")
-			if err != nil {
-				return err
+			if !useStored {
+				username, err = ui.Input("Username", "")
+				if err != nil {
+					return err
+				}
+				password, err = ui.Password("Password/Token")
+				if err != nil {
+					return err
+				}
 			}
 
-			if registryURL != "" && username != "" && password != "" {
+			if registryURL != "" && username != "" && (useStored || password != "") {
 				ui.Spacer()
 				err = ui.RunTasks([]ui.Task{
 					{
@@ -166,6 +286,8 @@ func runLogin(cmd *cobra.Command, args []string) error {
 						ActiveName:   "Verifying registry credentials...",
 						CompleteName: "Registry credentials verified",
 						Action: func() error {
+							// Logging in saves the password in Docker's
+							// credential store, so cdp never keeps it
 							return docker.VerifyLogin(registryURL, username, password)
 						},
 					},
@@ -176,7 +298,6 @@ func runLogin(cmd *cobra.Command, args []string) error {
 					cfg.DockerRegistry = &config.DockerRegistry{
 						URL:      registryURL,
 						Username: username,
-						Password: password,
 					}
 
 					ui.Spacer()
@@ -184,7 +305,11 @@ func runLogin(cmd *cobra.Command, args []string) error {
 					ui.Spacer()
 					ui.Print("To enable Coolify to pull from your registry, run this on your Coolify server:")
 					ui.Spacer()
-					ui.Code(fmt.Sprintf("echo '%s' | docker login %s -u %s --password-stdin", password, registryURL, username))
+					if password != "" {
+						ui.Code(fmt.Sprintf("echo '%s' | docker login %s -u %s --password-stdin", password, registryURL, username))
+					} else {
+						ui.Code(fmt.Sprintf("docker login %s -u %s", registryURL, username))
+					}
 					ui.Spacer()
 				}
 			}
@@ -217,10 +342,66 @@ func runLogin(cmd *cobra.Command, args []string) error {
 		ui.KeyValue("Docker registry", cfg.DockerRegistry.URL)
 	}
 
-	ui.NextSteps([]string{
-		fmt.Sprintf("Run '%s' in a project directory to deploy", execName()),
-		fmt.Sprintf("Run '%s health' to verify all connections", execName()),
+	return nil
+}
+
+// discoverCoolifyURL scans the local network and lets the user pick one of
+// the Coolify instances found. It returns "" when none was found or the
+// user would rather type the URL.
+func discoverCoolifyURL() (string, error) {
+	var found []discover.Instance
+	err := ui.RunTasks([]ui.Task{
+		{
+			Name:         "discover",
+			ActiveName:   "Searching the local network for Coolify...",
+			CompleteName: "Searched the local network",
+			Action: func() error {
+				ctx, cancel := context.WithTimeout(context.Background(), discoverTimeout)
+				defer cancel()
+				var err error
+				found, err = discover.Find(ctx)
+				return err
+			},
+		},
 	})
+	if err != nil {
+		ui.Warning("Could not search the local network: " + err.Error())
+		return "", nil
+	}
+	if len(found) == 0 {
+		ui.Warning("No Coolify instance found on the local network")
+		ui.Dim("  It may be on another subnet, or only reachable through its domain")
+		return "", nil
+	}
 
-	return nil
+	const manual = "Enter a URL instead"
+	options := make([]string, 0, len(found)+1)
+	byLabel := make(map[string]string, len(found))
+	for _, instance := range found {
+		options = append(options, instance.Label())
+		byLabel[instance.Label()] = instance.URL
+	}
+	options = append(options, manual)
+
+	choice, err := ui.Select("Coolify instance", options)
+	if err != nil {
+		return "", err
+	}
+	return byLabel[choice], nil
+}
+
+// missingTokenAbilities returns the abilities cdp needs that the client's
+// token lacks
+func missingTokenAbilities(client *api.Client) ([]string, error) {
+	abilities, err := client.TokenAbilities()
+	if err != nil {
+		return nil, err
+	}
+	var missing []string
+	for _, ability := range []string{api.AbilityRead, api.AbilityWrite, api.AbilityDeploy} {
+		if !abilities[ability] {
+			missing = append(missing, ability)
+		}
+	}
+	return missing, nil
 }
@@ -1,6 +1,8 @@
 package cmd
 
 import (
+	"strings"
+
 	"github.com/dropalltables/cdp/internal/config"
 	"github.com/dropalltables/cdp/internal/ui"
 	"github.com/spf13/cobra"
@@ -44,6 +46,9 @@ func runLogout(cmd *cobra.Command, args []string) error {
 	}
 
 	ui.Spacer()
+	if overrides := config.EnvOverrides(); len(overrides) > 0 {
+		ui.Warning("Still authenticated through " + strings.Join(overrides, ", ") + "; unset them to log out fully")
+	}
 	ui.Dim("Run 'cdp login' to authenticate again")
 	return nil
 }
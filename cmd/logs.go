@@ -1,24 +1,100 @@
 package cmd
 
 import (
+	"encoding/json"
 	"fmt"
+	"sort"
+	"strconv"
 	"strings"
+	"time"
 
 	"github.com/dropalltables/cdp/internal/api"
 	"github.com/dropalltables/cdp/internal/config"
+	"github.com/dropalltables/cdp/internal/deploy"
 	"github.com/dropalltables/cdp/internal/ui"
 	"github.com/spf13/cobra"
 )
 
+// logsPollInterval is how often --follow fetches new log lines
+const logsPollInterval = 2 * time.Second
+
+var (
+	// Flags for logs command
+	logsFollowFlag bool
+	logsLinesFlag  int
+	logsRawFlag    bool
+	logsSinceFlag  string
+	logsUntilFlag  string
+	logsDeployFlag string
+)
+
+// logSourceStyles color-code the prefix of each application's lines
+var logSourceStyles = []func(...string) string{
+	ui.CyanStyle.Render,
+	ui.MagentaStyle.Render,
+	ui.YellowStyle.Render,
+	ui.GreenStyle.Render,
+	ui.BlueStyle.Render,
+}
+
 var logsCmd = &cobra.Command{
-	Use:   "logs",
-	Short: "View deployment logs",
-	Long:  "Display logs from the most recent deployment.",
-	RunE:  runLogs,
+	Use:   "logs [APP...]",
+	Short: "View application logs",
+	Long: `Display runtime logs of the linked application.
+
+Pass application names or UUIDs to view several at once (e.g. a web app
+and its worker). Their lines are merged in chronological order and prefixed
+with the color-coded application name.
+
+Structured JSON log lines (level, msg, timestamp) are rendered as readable,
+colored text; use --raw to print them unchanged.
+
+--since and --until take a duration ago (30s, 10m, 1h, 2d) or an absolute
+time (2006-01-02T15:04:05Z07:00, 2006-01-02 15:04, 15:04 for today). The
+window is applied to the fetched lines, so raise --lines to reach further
+back.
+
+With trace_deploys enabled in cdp.json, each deploy sets CDP_DEPLOY_ID on
+the app. If the app logs that ID at startup, --grep-deploy shows only lines
+from that marker on (the latest deploy by default, or a given trace ID).
+
+--env NAME shows the logs of the same app in another Coolify environment
+of the project, e.g. staging.`,
+	Example: `  cdp logs -f
+  cdp logs web worker -f
+  cdp logs --since 1h --until 10m -n 5000
+  cdp logs --grep-deploy -f
+  cdp logs --grep-deploy dpl-3f9a1c2b7e4d`,
+	RunE: runLogs,
 }
 
 func init() {
 	rootCmd.AddCommand(logsCmd)
+
+	logsCmd.Flags().BoolVarP(&logsFollowFlag, "follow", "f", false, "Keep streaming new log lines")
+	logsCmd.Flags().IntVarP(&logsLinesFlag, "lines", "n", 100, "Number of lines to fetch per application")
+	logsCmd.Flags().BoolVar(&logsRawFlag, "raw", false, "Print JSON log lines without formatting")
+	logsCmd.Flags().StringVar(&logsSinceFlag, "since", "", "Only show lines after this time (e.g. 1h, 2006-01-02 15:04)")
+	logsCmd.Flags().StringVar(&logsUntilFlag, "until", "", "Only show lines before this time (e.g. 10m, 15:04)")
+	logsCmd.Flags().StringVar(&logsDeployFlag, "grep-deploy", "", "Only show lines after a deploy's trace ID was logged")
+	logsCmd.Flags().Lookup("grep-deploy").NoOptDefVal = "latest"
+	addEnvFlag(logsCmd, false, envProduction)
+}
+
+// logSource is one application whose logs are being shown
+type logSource struct {
+	name   string
+	uuid   string
+	render func(...string) string
+	last   []string // lines returned by the previous fetch
+	marked bool     // the --grep-deploy marker has been seen
+}
+
+// logLine is a single line tagged with its source and timestamp
+type logLine struct {
+	source *logSource
+	time   time.Time
+	text   string
 }
 
 func runLogs(cmd *cobra.Command, args []string) error {
@@ -26,26 +102,54 @@ func runLogs(cmd *cobra.Command, args []string) error {
 		return err
 	}
 
-	projectCfg, err := config.LoadProject()
-	if err != nil || projectCfg == nil {
-		ui.Error("No project configuration found")
-		return fmt.Errorf("not linked to a project")
+	globalCfg, err := config.LoadGlobal()
+	if err != nil {
+		return fmt.Errorf("failed to load configuration: %w", err)
 	}
 
-	appUUID := projectCfg.AppUUID
-	if appUUID == "" {
-		ui.Error("No application found")
-		return fmt.Errorf("no application found")
+	client := api.NewClient(globalCfg.CoolifyURL, globalCfg.CoolifyToken)
+
+	now := time.Now()
+	var since, until time.Time
+	if logsSinceFlag != "" {
+		if since, err = parseTimeArg(logsSinceFlag, now); err != nil {
+			ui.Error(fmt.Sprintf("Invalid --since: %s", logsSinceFlag))
+			return err
+		}
+	}
+	if logsUntilFlag != "" {
+		if until, err = parseTimeArg(logsUntilFlag, now); err != nil {
+			ui.Error(fmt.Sprintf("Invalid --until: %s", logsUntilFlag))
+			return err
+		}
 	}
 
-	globalCfg, err := config.LoadGlobal()
+	// A bare --grep-deploy takes no value, so "--grep-deploy ID" leaves
+	// the ID among the application names
+	traceID := logsDeployFlag
+	if traceID == "latest" && len(args) > 0 && deploy.IsTraceID(args[0]) {
+		traceID, args = args[0], args[1:]
+	}
+
+	target, err := resolveDeployEnv(cmd)
 	if err != nil {
-		return fmt.Errorf("failed to load configuration: %w", err)
+		return err
+	}
+	sources, err := resolveLogSources(client, args, target)
+	if err != nil {
+		return err
 	}
 
-	client := api.NewClient(globalCfg.CoolifyURL, globalCfg.CoolifyToken)
+	if traceID == "latest" {
+		traceID = config.LastTraceID(sources[0].uuid)
+		if traceID == "" {
+			ui.Error("No traced deploy recorded")
+			ui.Dim("Set \"trace_deploys\": true in cdp.json and deploy again")
+			return fmt.Errorf("no trace ID recorded")
+		}
+	}
 
-	var logs string
+	var lines []logLine
 	err = ui.RunTasks([]ui.Task{
 		{
 			Name:         "fetch-logs",
@@ -53,7 +157,7 @@ func runLogs(cmd *cobra.Command, args []string) error {
 			CompleteName: "Fetched logs",
 			Action: func() error {
 				var err error
-				logs, err = client.GetDeploymentLogs(appUUID)
+				lines, err = fetchLogLines(client, sources)
 				return err
 			},
 		},
@@ -63,7 +167,31 @@ func runLogs(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("failed to fetch logs: %w", err)
 	}
 
-	if logs == "" {
+	if !since.IsZero() || !until.IsZero() {
+		timed := false
+		for _, l := range lines {
+			if !l.time.IsZero() {
+				timed = true
+				break
+			}
+		}
+		if !timed && len(lines) > 0 {
+			ui.Warning("Log lines carry no timestamps; showing them unfiltered")
+		}
+		lines = filterLogWindow(lines, since, until)
+	}
+
+	if traceID != "" {
+		lines = filterDeployMarker(lines, traceID)
+		if len(lines) == 0 {
+			ui.Warning(fmt.Sprintf("Deploy marker %s not found in the last %d lines", traceID, logsLinesFlag))
+			if !logsFollowFlag {
+				return nil
+			}
+		}
+	}
+
+	if len(lines) == 0 && !logsFollowFlag {
 		ui.Dim("No logs available yet")
 		ui.Spacer()
 		ui.NextSteps([]string{
@@ -76,14 +204,368 @@ func runLogs(cmd *cobra.Command, args []string) error {
 	// Display logs
 	ui.Spacer()
 	logStream := ui.NewLogStream()
+	width := 0
+	for _, s := range sources {
+		if len(s.name) > width {
+			width = len(s.name)
+		}
+	}
+	show := func(l logLine) {
+		text, pretty := l.text, false
+		if !logsRawFlag {
+			text, pretty = prettyLogLine(l.text)
+		}
+		if len(sources) == 1 {
+			if pretty {
+				fmt.Println("  " + text)
+			} else {
+				logStream.Write(text)
+			}
+			return
+		}
+		prefix := l.source.render(fmt.Sprintf("%-*s |", width, l.source.name))
+		fmt.Println(prefix + " " + text)
+	}
+
+	for _, l := range lines {
+		show(l)
+	}
+
+	if !logsFollowFlag {
+		return nil
+	}
+
+	for {
+		time.Sleep(logsPollInterval)
+
+		lines, err := fetchLogLines(client, sources)
+		if err != nil {
+			continue // Transient API errors should not end the stream
+		}
+		if !until.IsZero() {
+			lines = filterLogWindow(lines, since, until)
+		}
+		if traceID != "" {
+			lines = filterDeployMarker(lines, traceID)
+		}
+		for _, l := range lines {
+			show(l)
+		}
+	}
+}
+
+// resolveLogSources maps application names or UUIDs to log sources,
+// defaulting to the linked application
+func resolveLogSources(client *api.Client, args []string, target deployEnv) ([]*logSource, error) {
+	if target.Preview() {
+		ui.Error("Preview logs are not available through the Coolify API")
+		return nil, fmt.Errorf("unsupported environment %q", target.Name)
+	}
+	if len(args) > 0 && target.Named() {
+		ui.Error("--env can't be combined with application names")
+		return nil, fmt.Errorf("--env given with applications")
+	}
+
+	if len(args) == 0 {
+		projectCfg, err := config.LoadProject()
+		if err != nil || projectCfg == nil {
+			ui.Error("No project configuration found")
+			return nil, fmt.Errorf("not linked to a project")
+		}
+		if projectCfg.AppUUID == "" {
+			ui.Error("No application found")
+			return nil, fmt.Errorf("no application found")
+		}
+		if target.Named() {
+			uuid, _, err := deployEnvApp(target)
+			if err != nil {
+				return nil, err
+			}
+			return []*logSource{{name: projectCfg.Name + " (" + target.Name + ")", uuid: uuid, render: logSourceStyles[0]}}, nil
+		}
+		return []*logSource{{name: projectCfg.Name, uuid: projectCfg.AppUUID, render: logSourceStyles[0]}}, nil
+	}
 
-	// Process and display logs line by line
-	lines := strings.Split(logs, "\n")
-	for _, line := range lines {
-		if line != "" {
-			logStream.Write(line)
+	apps, err := client.ListApplications()
+	if err != nil {
+		ui.Error("Failed to list applications")
+		return nil, fmt.Errorf("failed to list applications: %w", err)
+	}
+
+	var sources []*logSource
+	for i, arg := range args {
+		var match *api.Application
+		for j := range apps {
+			if apps[j].UUID == arg || apps[j].Name == arg {
+				match = &apps[j]
+				break
+			}
+		}
+		if match == nil {
+			ui.Error(fmt.Sprintf("Application '%s' not found", arg))
+			return nil, fmt.Errorf("application %q not found", arg)
 		}
+		sources = append(sources, &logSource{
+			name:   match.Name,
+			uuid:   match.UUID,
+			render: logSourceStyles[i%len(logSourceStyles)],
+		})
 	}
+	return sources, nil
+}
 
-	return nil
+// fetchLogLines returns the lines each source has logged since the previous
+// call, merged in chronological order
+func fetchLogLines(client *api.Client, sources []*logSource) ([]logLine, error) {
+	var merged []logLine
+	for _, s := range sources {
+		logs, err := client.GetApplicationLogs(s.uuid, logsLinesFlag)
+		if err != nil {
+			return nil, err
+		}
+
+		var current []string
+		for _, text := range strings.Split(logs, "\n") {
+			if strings.TrimSpace(text) != "" {
+				current = append(current, text)
+			}
+		}
+		fresh := newLogLines(s.last, current)
+		s.last = current
+
+		// Lines without a timestamp inherit the previous one so they stay
+		// next to the line they continue
+		var ts time.Time
+		for _, text := range fresh {
+			if t, ok := parseLogTime(text); ok {
+				ts = t
+			}
+			merged = append(merged, logLine{source: s, time: ts, text: text})
+		}
+	}
+
+	sort.SliceStable(merged, func(i, j int) bool {
+		return merged[i].time.Before(merged[j].time)
+	})
+	return merged, nil
+}
+
+// newLogLines returns the lines of cur that follow the end of prev. The
+// logs endpoint returns a sliding window, so the previous window's last
+// lines are located in the new one.
+func newLogLines(prev, cur []string) []string {
+	if len(prev) == 0 {
+		return cur
+	}
+	for start := len(cur) - 1; start >= 0; start-- {
+		// Try cur[start] as the last line seen before, checking the lines above it
+		n := 0
+		for n <= start && n < len(prev) && cur[start-n] == prev[len(prev)-1-n] {
+			n++
+		}
+		if n == len(prev) || n == start+1 {
+			return cur[start+1:]
+		}
+	}
+	return cur
+}
+
+// parseLogTime extracts the leading timestamp docker adds to log lines
+func parseLogTime(line string) (time.Time, bool) {
+	field, _, _ := strings.Cut(line, " ")
+	t, err := time.Parse(time.RFC3339Nano, field)
+	if err != nil {
+		return time.Time{}, false
+	}
+	return t, true
+}
+
+// filterLogWindow keeps the lines logged between since and until (either
+// may be zero). Lines without any timestamp can't be placed and are kept.
+func filterLogWindow(lines []logLine, since, until time.Time) []logLine {
+	var kept []logLine
+	for _, l := range lines {
+		if l.time.IsZero() {
+			kept = append(kept, l)
+			continue
+		}
+		if !since.IsZero() && l.time.Before(since) {
+			continue
+		}
+		if !until.IsZero() && l.time.After(until) {
+			continue
+		}
+		kept = append(kept, l)
+	}
+	return kept
+}
+
+// filterDeployMarker drops each source's lines until one containing
+// traceID, keeping everything from that marker on
+func filterDeployMarker(lines []logLine, traceID string) []logLine {
+	var kept []logLine
+	for _, l := range lines {
+		if !l.source.marked {
+			if !strings.Contains(l.text, traceID) {
+				continue
+			}
+			l.source.marked = true
+		}
+		kept = append(kept, l)
+	}
+	return kept
+}
+
+// parseTimeArg parses a time given as a duration before now (with "d" for
+// days) or as an absolute local or RFC 3339 time
+func parseTimeArg(arg string, now time.Time) (time.Time, error) {
+	if days, ok := strings.CutSuffix(arg, "d"); ok {
+		if n, err := strconv.Atoi(days); err == nil && n >= 0 {
+			return now.AddDate(0, 0, -n), nil
+		}
+	}
+	if d, err := time.ParseDuration(arg); err == nil {
+		return now.Add(-d), nil
+	}
+	if t, err := time.Parse(time.RFC3339, arg); err == nil {
+		return t, nil
+	}
+	for _, layout := range []string{"2006-01-02T15:04:05", "2006-01-02 15:04:05", "2006-01-02T15:04", "2006-01-02 15:04", "2006-01-02"} {
+		if t, err := time.ParseInLocation(layout, arg, now.Location()); err == nil {
+			return t, nil
+		}
+	}
+	for _, layout := range []string{"15:04:05", "15:04"} {
+		if t, err := time.ParseInLocation(layout, arg, now.Location()); err == nil {
+			y, m, d := now.Date()
+			return time.Date(y, m, d, t.Hour(), t.Minute(), t.Second(), 0, now.Location()), nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("invalid time %q", arg)
+}
+
+// Keys structured loggers commonly use for the level, message and time
+var (
+	logLevelKeys   = []string{"level", "lvl", "severity", "log.level"}
+	logMessageKeys = []string{"msg", "message", "@message"}
+	logTimeKeys    = []string{"time", "timestamp", "ts", "@timestamp"}
+)
+
+// prettyLogLine renders a JSON log line as "15:04:05 LEVEL message key=value".
+// It reports false and returns the line unchanged when it isn't a
+// structured log entry.
+func prettyLogLine(line string) (string, bool) {
+	body := line
+	if _, ok := parseLogTime(line); ok {
+		_, body, _ = strings.Cut(line, " ")
+	}
+	body = strings.TrimSpace(body)
+	if !strings.HasPrefix(body, "{") {
+		return line, false
+	}
+
+	var fields map[string]interface{}
+	if err := json.Unmarshal([]byte(body), &fields); err != nil {
+		return line, false
+	}
+
+	msg, ok := takeLogField(fields, logMessageKeys)
+	if !ok {
+		return line, false
+	}
+	level, _ := takeLogField(fields, logLevelKeys)
+	ts, _ := takeLogField(fields, logTimeKeys)
+
+	var parts []string
+	if t, ok := logFieldTime(ts); ok {
+		parts = append(parts, ui.DimStyle.Render(t.Local().Format("15:04:05")))
+	}
+	if name := logLevelName(level); name != "" {
+		parts = append(parts, logLevelStyle(name)(fmt.Sprintf("%-5s", strings.ToUpper(name))))
+	}
+	parts = append(parts, fmt.Sprint(msg))
+
+	keys := make([]string, 0, len(fields))
+	for k := range fields {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		v := fields[k]
+		if s, ok := v.(string); !ok {
+			b, _ := json.Marshal(v)
+			v = string(b)
+		} else if strings.ContainsAny(s, " \t") {
+			v = strconv.Quote(s)
+		}
+		parts = append(parts, ui.DimStyle.Render(fmt.Sprintf("%s=%v", k, v)))
+	}
+
+	return strings.Join(parts, " "), true
+}
+
+// takeLogField removes and returns the first of keys present in fields
+func takeLogField(fields map[string]interface{}, keys []string) (interface{}, bool) {
+	for _, k := range keys {
+		if v, ok := fields[k]; ok {
+			delete(fields, k)
+			return v, true
+		}
+	}
+	return nil, false
+}
+
+// logFieldTime parses RFC 3339 strings and Unix timestamps in seconds or
+// milliseconds
+func logFieldTime(v interface{}) (time.Time, bool) {
+	switch t := v.(type) {
+	case string:
+		if parsed, err := time.Parse(time.RFC3339Nano, t); err == nil {
+			return parsed, true
+		}
+	case float64:
+		if t > 1e12 {
+			return time.UnixMilli(int64(t)), true
+		}
+		sec := int64(t)
+		return time.Unix(sec, int64((t-float64(sec))*1e9)), true
+	}
+	return time.Time{}, false
+}
+
+// logLevelName normalizes a level, including pino's numeric levels
+func logLevelName(v interface{}) string {
+	switch l := v.(type) {
+	case string:
+		return strings.ToLower(l)
+	case float64:
+		switch {
+		case l >= 60:
+			return "fatal"
+		case l >= 50:
+			return "error"
+		case l >= 40:
+			return "warn"
+		case l >= 30:
+			return "info"
+		case l >= 20:
+			return "debug"
+		default:
+			return "trace"
+		}
+	}
+	return ""
+}
+
+func logLevelStyle(level string) func(...string) string {
+	switch level {
+	case "fatal", "panic", "error", "err", "critical":
+		return ui.RedStyle.Render
+	case "warn", "warning":
+		return ui.YellowStyle.Render
+	case "info", "notice":
+		return ui.CyanStyle.Render
+	default:
+		return ui.DimStyle.Render
+	}
 }
@@ -6,6 +6,7 @@ import (
 
 	"github.com/dropalltables/cdp/internal/api"
 	"github.com/dropalltables/cdp/internal/config"
+	"github.com/dropalltables/cdp/internal/deploy"
 	"github.com/dropalltables/cdp/internal/ui"
 	"github.com/spf13/cobra"
 )
@@ -14,12 +15,14 @@ var lsCmd = &cobra.Command{
 	Use:     "ls",
 	Aliases: []string{"list", "status"},
 	Short:   "List project deployments",
-	Long:    "Display all environments and their deployment status for this project.",
-	RunE:    runLs,
+	Long: `Display the deployment status of this project's app, or with --env of its
+app in another Coolify environment, e.g. --env staging.`,
+	RunE: runLs,
 }
 
 func init() {
 	rootCmd.AddCommand(lsCmd)
+	addEnvFlag(lsCmd, false, envProduction)
 }
 
 func runLs(cmd *cobra.Command, args []string) error {
@@ -44,6 +47,15 @@ func runLs(cmd *cobra.Command, args []string) error {
 
 	client := api.NewClient(globalCfg.CoolifyURL, globalCfg.CoolifyToken)
 
+	target, err := resolveDeployEnv(cmd)
+	if err != nil {
+		return err
+	}
+	if target.Preview() {
+		ui.Error(fmt.Sprintf("Preview deployments are listed with '%s preview ls'", execName()))
+		return fmt.Errorf("ls doesn't support the preview environment")
+	}
+
 	appUUID := projectCfg.AppUUID
 	if appUUID == "" {
 		ui.Warning("No application found")
@@ -52,9 +64,16 @@ func runLs(cmd *cobra.Command, args []string) error {
 		})
 		return nil
 	}
+	if target.Named() {
+		if appUUID, _, err = deployEnvApp(target); err != nil {
+			return err
+		}
+		ui.KeyValue("Environment", target.Name)
+	}
 
 	// Fetch application info
 	var app *api.Application
+	var settings map[string]interface{}
 	err = ui.RunTasks([]ui.Task{
 		{
 			Name:         "fetch-app",
@@ -63,7 +82,15 @@ func runLs(cmd *cobra.Command, args []string) error {
 			Action: func() error {
 				var err error
 				app, err = client.GetApplication(appUUID)
-				return err
+				if err != nil {
+					return err
+				}
+				// cdp.json describes the production app, so other
+				// environments aren't checked for drift. Best-effort.
+				if !target.Named() {
+					settings, _ = client.GetApplicationSettings(appUUID)
+				}
+				return nil
 			},
 		},
 	})
@@ -95,9 +122,17 @@ func runLs(cmd *cobra.Command, args []string) error {
 
 	ui.KeyValue("Status", statusDisplay)
 
-	url := app.FQDN
-	if url != "" {
-		ui.KeyValue("Production URL", ui.InfoStyle.Render(url))
+	domains := parseDomains(app.FQDN)
+	if preferred := api.PreferredDomain(app.FQDN); preferred != "" {
+		ui.KeyValue("Production URL", domainLabel(preferred, ui.InfoStyle.Render(preferred)))
+		for _, d := range domains {
+			if d != preferred {
+				ui.KeyValue("Also served at", domainLabel(d, d))
+			}
+		}
+		if api.IsGeneratedDomain(preferred) {
+			ui.Dim(fmt.Sprintf("  → Attach a real domain with '%s domains add app.example.com'", execName()))
+		}
 	}
 
 	if app.PreviewURLTemplate != "" {
@@ -113,5 +148,24 @@ func runLs(cmd *cobra.Command, args []string) error {
 	ui.KeyValue("Deploy method", projectCfg.DeployMethod)
 	ui.KeyValue("Framework", projectCfg.Framework)
 
+	if settings != nil {
+		printDrift(deploy.Drift(projectCfg, settings))
+	}
+
 	return nil
 }
+
+// printDrift flags settings that were changed in Coolify (or in cdp.json)
+// since they were last in sync, before they surprise the next deploy
+func printDrift(drift []deploy.SettingChange) {
+	if len(drift) == 0 {
+		return
+	}
+	ui.Spacer()
+	for _, c := range drift {
+		name := strings.ReplaceAll(c.Setting, "_", " ")
+		ui.Warning(fmt.Sprintf("%s%s differs from cdp.json", strings.ToUpper(name[:1]), name[1:]))
+		ui.Dim(fmt.Sprintf("  Coolify: %s, cdp.json: %s", orDash(c.Remote), orDash(c.Local)))
+	}
+	ui.Dim(fmt.Sprintf("  → Run '%s apply' to update the app, or '%s pull-config' to update cdp.json", execName(), execName()))
+}
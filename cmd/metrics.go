@@ -0,0 +1,118 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/dropalltables/cdp/internal/api"
+	"github.com/dropalltables/cdp/internal/config"
+	"github.com/dropalltables/cdp/internal/ui"
+	"github.com/spf13/cobra"
+)
+
+var (
+	// Flags for metrics command
+	metricsHistoryFlag bool
+)
+
+var metricsCmd = &cobra.Command{
+	Use:   "metrics",
+	Short: "Show resource usage",
+	Long:  "Show CPU, memory, and disk usage for the linked application and its server.",
+	RunE:  runMetrics,
+}
+
+func init() {
+	rootCmd.AddCommand(metricsCmd)
+
+	metricsCmd.Flags().BoolVar(&metricsHistoryFlag, "history", false, "Show a short usage history")
+}
+
+func runMetrics(cmd *cobra.Command, args []string) error {
+	appUUID, client, err := getAppUUID()
+	if err != nil {
+		return err
+	}
+
+	projectCfg, err := config.LoadProject()
+	if err != nil {
+		return fmt.Errorf("failed to load project config: %w", err)
+	}
+	serverUUID := projectCfg.ServerUUID
+
+	var appMetrics, serverMetrics *api.ResourceMetrics
+	tasks := []ui.Task{
+		{
+			Name:         "app-metrics",
+			ActiveName:   "Fetching application metrics...",
+			CompleteName: "Fetched application metrics",
+			Action: func() error {
+				var err error
+				appMetrics, err = client.GetApplicationMetrics(appUUID)
+				return err
+			},
+		},
+	}
+	if serverUUID != "" {
+		tasks = append(tasks, ui.Task{
+			Name:         "server-metrics",
+			ActiveName:   "Fetching server metrics...",
+			CompleteName: "Fetched server metrics",
+			Action: func() error {
+				var err error
+				serverMetrics, err = client.GetServerMetrics(serverUUID)
+				return err
+			},
+		})
+	}
+
+	if err := ui.RunTasks(tasks); err != nil {
+		ui.Error("Failed to fetch metrics")
+		ui.Dim("Metrics require Sentinel to be enabled on the server in Coolify")
+		return fmt.Errorf("failed to fetch metrics: %w", err)
+	}
+
+	ui.Spacer()
+	ui.Bold("Application")
+	showMetrics(appMetrics)
+
+	if serverMetrics != nil {
+		ui.Spacer()
+		ui.Bold("Server")
+		showMetrics(serverMetrics)
+	} else {
+		ui.Spacer()
+		ui.Dim("Server metrics unavailable (no server recorded in cdp.json)")
+	}
+
+	return nil
+}
+
+func showMetrics(m *api.ResourceMetrics) {
+	ui.KeyValue("CPU", fmt.Sprintf("%.1f%%", m.CPUPercent))
+	memory := fmt.Sprintf("%.1f%%", m.MemoryPercent)
+	if m.MemoryTotalMB > 0 {
+		memory = fmt.Sprintf("%s (%.0f / %.0f MB)", memory, m.MemoryUsedMB, m.MemoryTotalMB)
+	}
+	ui.KeyValue("Memory", memory)
+	if m.DiskPercent > 0 {
+		ui.KeyValue("Disk", fmt.Sprintf("%.1f%%", m.DiskPercent))
+	}
+
+	if !metricsHistoryFlag {
+		return
+	}
+	if len(m.CPUHistory) > 0 {
+		ui.KeyValue("CPU history", ui.Sparkline(metricValues(m.CPUHistory)))
+	}
+	if len(m.MemoryHistory) > 0 {
+		ui.KeyValue("Memory history", ui.Sparkline(metricValues(m.MemoryHistory)))
+	}
+}
+
+func metricValues(points []api.MetricPoint) []float64 {
+	values := make([]float64, len(points))
+	for i, p := range points {
+		values[i] = p.Value
+	}
+	return values
+}
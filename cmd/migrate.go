@@ -0,0 +1,293 @@
+package cmd
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/dropalltables/cdp/internal/api"
+	"github.com/dropalltables/cdp/internal/config"
+	"github.com/dropalltables/cdp/internal/migrate"
+	"github.com/dropalltables/cdp/internal/ui"
+	"github.com/spf13/cobra"
+)
+
+var (
+	// Flags for migrate command
+	migrateDryRunFlag bool
+)
+
+var migrateCmd = &cobra.Command{
+	Use:       "migrate [vercel|netlify|heroku]",
+	Short:     "Migrate a project from Vercel, Netlify or Heroku",
+	ValidArgs: migrate.Sources,
+	Long: `Read vercel.json, netlify.toml, or a Procfile and app.json, and translate
+build settings, replicas, release commands and environment variables into
+cdp.json and the linked Coolify app.
+
+Settings with no Coolify equivalent (redirects, regions, add-ons, ...) are
+listed at the end so you can recreate them by hand. Without an argument,
+the platform is detected from the files present.
+
+If the directory isn't deployed yet, only cdp.json is written; run
+'cdp migrate' again after the first deploy to apply the Coolify settings.`,
+	Example: `  cdp migrate
+  cdp migrate heroku --dry-run`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: runMigrate,
+}
+
+func init() {
+	rootCmd.AddCommand(migrateCmd)
+
+	migrateCmd.Flags().BoolVar(&migrateDryRunFlag, "dry-run", false, "Show what would be migrated without changing anything")
+}
+
+func runMigrate(cmd *cobra.Command, args []string) error {
+	source, err := migrateSource(args)
+	if err != nil {
+		return err
+	}
+
+	plan, err := migrate.Load(".", source)
+	if err != nil {
+		ui.Error(fmt.Sprintf("Failed to read %s config", source))
+		return err
+	}
+
+	showMigratePlan(plan)
+
+	if migrateDryRunFlag {
+		showUntranslated(plan)
+		return nil
+	}
+
+	ui.Spacer()
+	confirmed, err := ui.Confirm("Apply these settings?")
+	if err != nil {
+		return err
+	}
+	if !confirmed {
+		return nil
+	}
+
+	projectCfg, err := config.LoadProject()
+	if err != nil {
+		return fmt.Errorf("failed to load project config: %w", err)
+	}
+	if projectCfg == nil {
+		projectCfg = &config.ProjectConfig{Name: plan.Name}
+		if projectCfg.Name == "" {
+			projectCfg.Name = getWorkingDirName()
+		}
+	}
+
+	applyMigratePlan(projectCfg, plan)
+	if err := config.SaveProject(projectCfg); err != nil {
+		ui.Error("Failed to save project config")
+		return fmt.Errorf("failed to save project config: %w", err)
+	}
+	ui.Success(fmt.Sprintf("Updated %s", config.ProjectConfigPath(".")))
+
+	if projectCfg.AppUUID == "" {
+		ui.Spacer()
+		ui.Dim("The app doesn't exist in Coolify yet")
+		ui.NextSteps([]string{
+			fmt.Sprintf("Run '%s' to deploy with the migrated settings", execName()),
+			fmt.Sprintf("Then run '%s migrate' again to apply the release command and environment variables", execName()),
+		})
+		showUntranslated(plan)
+		return nil
+	}
+
+	if err := updateMigratedApp(projectCfg, plan); err != nil {
+		return err
+	}
+
+	var envVars []dotEnvVar
+	for _, env := range plan.Env {
+		if env.Set {
+			envVars = append(envVars, dotEnvVar{Key: env.Name, Value: env.Value, BuildTime: env.BuildTime})
+		}
+	}
+	if len(envVars) > 0 {
+		// Platform configs hold production values, and say which the build needs
+		if err := syncEnvVars(deployEnv{Name: envProduction}, envVars, true); err != nil {
+			return err
+		}
+	}
+
+	showUntranslated(plan)
+
+	ui.NextSteps([]string{
+		fmt.Sprintf("Run '%s' to redeploy with the migrated settings", execName()),
+	})
+	return nil
+}
+
+// migrateSource returns the platform named in args, or the one whose config
+// is found in the current directory, asking when there are several
+func migrateSource(args []string) (string, error) {
+	if len(args) == 1 {
+		source := strings.ToLower(args[0])
+		for _, s := range migrate.Sources {
+			if s == source {
+				return source, nil
+			}
+		}
+		ui.Error(fmt.Sprintf("Unknown platform '%s'", args[0]))
+		ui.Dim("Use one of: " + strings.Join(migrate.Sources, ", "))
+		return "", fmt.Errorf("unknown platform %q", args[0])
+	}
+
+	found := migrate.Detect(".")
+	switch len(found) {
+	case 0:
+		ui.Error("No Vercel, Netlify or Heroku config found")
+		ui.Dim("Looked for vercel.json, netlify.toml, Procfile and app.json")
+		return "", fmt.Errorf("nothing to migrate")
+	case 1:
+		return found[0], nil
+	}
+	return ui.Select("Migrate from", found)
+}
+
+func showMigratePlan(plan *migrate.Plan) {
+	ui.Spacer()
+	ui.KeyValue("Source", fmt.Sprintf("%s (%s)", plan.Source, strings.Join(plan.Files, ", ")))
+	ui.Spacer()
+
+	rows := [][]string{}
+	add := func(setting, value string) {
+		if value != "" {
+			rows = append(rows, []string{setting, value})
+		}
+	}
+	add("Install command", plan.InstallCommand)
+	add("Build command", plan.BuildCommand)
+	add("Start command", plan.StartCommand)
+	add("Publish directory", plan.PublishDir)
+	add("Base directory", plan.BaseDirectory)
+	if plan.Replicas > 0 {
+		add("Replicas", strconv.Itoa(plan.Replicas))
+	}
+	add("Release command", plan.PreDeployCommand)
+	ui.Table([]string{"Setting", "Value"}, rows)
+
+	if len(plan.Env) > 0 {
+		ui.Spacer()
+		envRows := [][]string{}
+		for _, env := range plan.Env {
+			value := "(set it yourself)"
+			if env.Set {
+				value = maskEnvValue(env.Name, env.Value)
+			}
+			buildTime := ""
+			if env.BuildTime {
+				buildTime = "yes"
+			}
+			envRows = append(envRows, []string{env.Name, value, buildTime})
+		}
+		ui.Table([]string{"Environment variable", "Value", "Build"}, envRows)
+	}
+}
+
+// applyMigratePlan copies the build settings of plan into projectCfg
+func applyMigratePlan(projectCfg *config.ProjectConfig, plan *migrate.Plan) {
+	if plan.InstallCommand != "" {
+		projectCfg.InstallCommand = plan.InstallCommand
+	}
+	if plan.BuildCommand != "" {
+		projectCfg.BuildCommand = plan.BuildCommand
+	}
+	if plan.StartCommand != "" {
+		projectCfg.StartCommand = plan.StartCommand
+	}
+	if plan.PublishDir != "" {
+		projectCfg.PublishDir = plan.PublishDir
+	}
+	if plan.BaseDirectory != "" {
+		projectCfg.BaseDirectory = plan.BaseDirectory
+	}
+	if plan.Replicas > 0 {
+		projectCfg.Replicas = plan.Replicas
+	}
+}
+
+// updateMigratedApp applies the plan's settings to the linked Coolify app
+func updateMigratedApp(projectCfg *config.ProjectConfig, plan *migrate.Plan) error {
+	updates := map[string]interface{}{}
+	if plan.InstallCommand != "" {
+		updates["install_command"] = plan.InstallCommand
+	}
+	if plan.BuildCommand != "" {
+		updates["build_command"] = plan.BuildCommand
+	}
+	if plan.StartCommand != "" {
+		updates["start_command"] = plan.StartCommand
+	}
+	if plan.PublishDir != "" {
+		updates["publish_directory"] = plan.PublishDir
+	}
+	if plan.BaseDirectory != "" {
+		updates["base_directory"] = plan.BaseDirectory
+	}
+	if plan.Replicas > 0 {
+		updates["swarm_replicas"] = plan.Replicas
+	}
+	if plan.PreDeployCommand != "" {
+		updates["pre_deployment_command"] = plan.PreDeployCommand
+	}
+	if len(updates) == 0 {
+		return nil
+	}
+
+	if err := checkLogin(); err != nil {
+		return err
+	}
+	globalCfg, err := config.LoadGlobal()
+	if err != nil {
+		return fmt.Errorf("failed to load config: %w", err)
+	}
+	client := api.NewClient(globalCfg.CoolifyURL, globalCfg.CoolifyToken)
+
+	err = ui.RunTasks([]ui.Task{
+		{
+			Name:         "update-app",
+			ActiveName:   "Updating application settings...",
+			CompleteName: "Updated application settings",
+			Action: func() error {
+				return client.UpdateApplication(projectCfg.AppUUID, updates)
+			},
+		},
+	})
+	if err != nil {
+		ui.Error("Failed to update application settings")
+		return fmt.Errorf("failed to update application: %w", err)
+	}
+	return nil
+}
+
+// showUntranslated lists what the user has to recreate by hand
+func showUntranslated(plan *migrate.Plan) {
+	var missing []string
+	for _, env := range plan.Env {
+		if !env.Set {
+			missing = append(missing, env.Name)
+		}
+	}
+
+	if len(plan.Untranslated) == 0 && len(missing) == 0 {
+		return
+	}
+
+	ui.Spacer()
+	ui.Warning("Not migrated")
+	for _, item := range plan.Untranslated {
+		ui.Dim("  • " + item)
+	}
+	if len(missing) > 0 {
+		ui.Dim(fmt.Sprintf("  • values for %s: copy them from %s and add them with '%s env add KEY=value --env production'",
+			strings.Join(missing, ", "), plan.Source, execName()))
+	}
+}
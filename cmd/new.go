@@ -0,0 +1,127 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/dropalltables/cdp/internal/git"
+	"github.com/dropalltables/cdp/internal/ui"
+	"github.com/spf13/cobra"
+)
+
+// projectTemplate is a starter repository that 'cdp new' can scaffold from
+type projectTemplate struct {
+	Repo        string // GitHub owner/name
+	Description string
+}
+
+// projectTemplates are the official starters; each ships a Dockerfile and a
+// cdp.json holding build settings only
+var projectTemplates = map[string]projectTemplate{
+	"nextjs": {Repo: "dropalltables/cdp-template-nextjs", Description: "Next.js app with standalone output"},
+	"go":     {Repo: "dropalltables/cdp-template-go", Description: "Go HTTP server"},
+	"hugo":   {Repo: "dropalltables/cdp-template-hugo", Description: "Hugo static site"},
+}
+
+var newCmd = &cobra.Command{
+	Use:   "new [TEMPLATE] [DIR]",
+	Short: "Create and deploy a new project from a template",
+	Long: `Scaffold a new project from a starter template, create its GitHub
+repository, and run the first deploy.
+
+TEMPLATE is one of the official templates or any GitHub repository given
+as owner/name. DIR defaults to the template name.`,
+	Example: `  cdp new nextjs my-app
+  cdp new acme/starter-api api`,
+	Args: cobra.MaximumNArgs(2),
+	RunE: runNew,
+}
+
+func init() {
+	rootCmd.AddCommand(newCmd)
+}
+
+func runNew(cmd *cobra.Command, args []string) error {
+	if err := checkLogin(); err != nil {
+		return err
+	}
+
+	name := ""
+	if len(args) > 0 {
+		name = args[0]
+	} else {
+		names := make([]string, 0, len(projectTemplates))
+		for n := range projectTemplates {
+			names = append(names, n)
+		}
+		sort.Strings(names)
+
+		options := make([]struct{ Key, Display string }, 0, len(names))
+		for _, n := range names {
+			options = append(options, struct{ Key, Display string }{n, fmt.Sprintf("%s - %s", n, projectTemplates[n].Description)})
+		}
+		var err error
+		name, err = ui.SelectWithKeysOrdered("Template", options)
+		if err != nil {
+			return err
+		}
+	}
+
+	repo := ""
+	if t, ok := projectTemplates[name]; ok {
+		repo = t.Repo
+	} else if strings.Count(name, "/") == 1 {
+		repo = name
+	} else {
+		ui.Error(fmt.Sprintf("Unknown template '%s'", name))
+		ui.Dim(fmt.Sprintf("Run '%s new' to choose from the available templates", execName()))
+		return fmt.Errorf("unknown template %q", name)
+	}
+
+	dir := filepath.Base(repo)
+	if _, ok := projectTemplates[name]; ok {
+		dir = name
+	}
+	if len(args) > 1 {
+		dir = args[1]
+	}
+	if _, err := os.Stat(dir); err == nil {
+		ui.Error(fmt.Sprintf("%s already exists", dir))
+		return fmt.Errorf("directory %s already exists", dir)
+	}
+
+	err := ui.RunTasks([]ui.Task{
+		{
+			Name:         "clone-template",
+			ActiveName:   fmt.Sprintf("Downloading %s...", repo),
+			CompleteName: fmt.Sprintf("Created %s from %s", dir, repo),
+			Action: func() error {
+				url := fmt.Sprintf("https://github.com/%s.git", repo)
+				out, err := exec.Command("git", "clone", "--depth", "1", url, dir).CombinedOutput()
+				if err != nil {
+					return fmt.Errorf("%w: %s", err, strings.TrimSpace(string(out)))
+				}
+				// Start with fresh history so the new repo doesn't carry the template's
+				if err := os.RemoveAll(filepath.Join(dir, ".git")); err != nil {
+					return err
+				}
+				return git.Init(dir)
+			},
+		},
+	})
+	if err != nil {
+		ui.Error("Failed to download template")
+		return fmt.Errorf("failed to clone template: %w", err)
+	}
+
+	if err := os.Chdir(dir); err != nil {
+		return err
+	}
+
+	ui.Spacer()
+	return runDeploy(deployEnv{Name: envProduction})
+}
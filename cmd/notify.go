@@ -0,0 +1,284 @@
+package cmd
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+
+	"github.com/dropalltables/cdp/internal/api"
+	"github.com/dropalltables/cdp/internal/config"
+	"github.com/dropalltables/cdp/internal/ui"
+	"github.com/spf13/cobra"
+)
+
+var (
+	// Flags for notify add command
+	notifyWebhookFlag string
+	notifyTokenFlag   string
+	notifyChatIDFlag  string
+	notifyToFlag      string
+)
+
+var notifyCmd = &cobra.Command{
+	Use:   "notify",
+	Short: "Manage Coolify notification channels",
+	Long: `Manage the notification channels (email, Discord, Telegram, Slack) of
+the Coolify team your API token belongs to.
+
+Coolify sends deployment, backup and server alerts through every enabled
+channel.`,
+}
+
+var notifyLsCmd = &cobra.Command{
+	Use:   "ls",
+	Short: "List notification channels",
+	RunE:  runNotifyLs,
+}
+
+var notifyAddCmd = &cobra.Command{
+	Use:   "add CHANNEL",
+	Short: "Enable a notification channel",
+	Long: `Enable a notification channel: email, discord, telegram or slack.
+
+Missing settings are prompted for. Email only sets the recipients; SMTP or
+Resend must already be configured in Coolify.`,
+	Example: `  cdp notify add discord --webhook https://discord.com/api/webhooks/...
+  cdp notify add telegram --token 123:abc --chat-id -100123
+  cdp notify add email --to ops@example.com`,
+	Args: cobra.ExactArgs(1),
+	RunE: runNotifyAdd,
+}
+
+var notifyTestCmd = &cobra.Command{
+	Use:   "test CHANNEL",
+	Short: "Send a test notification",
+	Args:  cobra.ExactArgs(1),
+	RunE:  runNotifyTest,
+}
+
+func init() {
+	rootCmd.AddCommand(notifyCmd)
+	notifyCmd.AddCommand(notifyLsCmd)
+	notifyCmd.AddCommand(notifyAddCmd)
+	notifyCmd.AddCommand(notifyTestCmd)
+
+	notifyAddCmd.Flags().StringVar(&notifyWebhookFlag, "webhook", "", "Webhook URL (discord, slack)")
+	notifyAddCmd.Flags().StringVar(&notifyTokenFlag, "token", "", "Bot token (telegram)")
+	notifyAddCmd.Flags().StringVar(&notifyChatIDFlag, "chat-id", "", "Chat ID (telegram)")
+	notifyAddCmd.Flags().StringVar(&notifyToFlag, "to", "", "Comma-separated recipients (email)")
+}
+
+// notifyTargetKeys is the setting that identifies where each channel delivers
+var notifyTargetKeys = map[string]string{
+	"email":    "smtp_recipients",
+	"discord":  "discord_webhook_url",
+	"telegram": "telegram_chat_id",
+	"slack":    "slack_webhook_url",
+}
+
+func runNotifyLs(cmd *cobra.Command, args []string) error {
+	client, err := notifyClient()
+	if err != nil {
+		return err
+	}
+
+	var team *api.Team
+	settings := map[string]map[string]interface{}{}
+	err = ui.RunTasks([]ui.Task{
+		{
+			Name:         "load-notifications",
+			ActiveName:   "Loading notification channels...",
+			CompleteName: "Loaded notification channels",
+			Action: func() error {
+				var err error
+				team, err = client.GetCurrentTeam()
+				if err != nil {
+					return err
+				}
+				for _, ch := range api.NotificationChannels {
+					s, err := client.GetNotificationSettings(ch)
+					if err != nil {
+						return fmt.Errorf("%s: %w", ch, err)
+					}
+					settings[ch] = s
+				}
+				return nil
+			},
+		},
+	})
+	if err != nil {
+		ui.Error("Failed to load notification channels")
+		return fmt.Errorf("failed to fetch notification settings: %w", err)
+	}
+
+	rows := [][]string{}
+	for _, ch := range api.NotificationChannels {
+		s := settings[ch]
+		status := "disabled"
+		if notifyEnabled(ch, s) {
+			status = "enabled"
+		}
+		target := notifyTarget(fmt.Sprint(s[notifyTargetKeys[ch]]))
+		rows = append(rows, []string{ch, status, target})
+	}
+
+	ui.Spacer()
+	ui.KeyValue("Team", team.Name)
+	ui.Spacer()
+	ui.Table([]string{"Channel", "Status", "Target"}, rows)
+
+	return nil
+}
+
+func runNotifyAdd(cmd *cobra.Command, args []string) error {
+	channel, err := parseNotifyChannel(args[0])
+	if err != nil {
+		return err
+	}
+
+	settings := map[string]interface{}{}
+	switch channel {
+	case "discord", "slack":
+		webhook, err := flagOrPrompt(notifyWebhookFlag, "Webhook URL", true)
+		if err != nil {
+			return err
+		}
+		settings[channel+"_enabled"] = true
+		settings[channel+"_webhook_url"] = webhook
+	case "telegram":
+		token, err := flagOrPrompt(notifyTokenFlag, "Bot token", true)
+		if err != nil {
+			return err
+		}
+		chatID, err := flagOrPrompt(notifyChatIDFlag, "Chat ID", false)
+		if err != nil {
+			return err
+		}
+		settings["telegram_enabled"] = true
+		settings["telegram_token"] = token
+		settings["telegram_chat_id"] = chatID
+	case "email":
+		to, err := flagOrPrompt(notifyToFlag, "Recipients (comma-separated)", false)
+		if err != nil {
+			return err
+		}
+		settings["smtp_enabled"] = true
+		settings["smtp_recipients"] = to
+	}
+
+	client, err := notifyClient()
+	if err != nil {
+		return err
+	}
+
+	err = ui.RunTasks([]ui.Task{
+		{
+			Name:         "enable-notification",
+			ActiveName:   fmt.Sprintf("Enabling %s notifications...", channel),
+			CompleteName: fmt.Sprintf("Enabled %s notifications", channel),
+			Action: func() error {
+				return client.UpdateNotificationSettings(channel, settings)
+			},
+		},
+	})
+	if err != nil {
+		ui.Error(fmt.Sprintf("Failed to enable %s notifications", channel))
+		return fmt.Errorf("failed to update notification settings: %w", err)
+	}
+
+	ui.NextSteps([]string{
+		fmt.Sprintf("Run '%s notify test %s' to send a test message", execName(), channel),
+	})
+	return nil
+}
+
+func runNotifyTest(cmd *cobra.Command, args []string) error {
+	channel, err := parseNotifyChannel(args[0])
+	if err != nil {
+		return err
+	}
+
+	client, err := notifyClient()
+	if err != nil {
+		return err
+	}
+
+	err = ui.RunTasks([]ui.Task{
+		{
+			Name:         "test-notification",
+			ActiveName:   fmt.Sprintf("Sending test notification via %s...", channel),
+			CompleteName: fmt.Sprintf("Sent test notification via %s", channel),
+			Action: func() error {
+				return client.SendTestNotification(channel)
+			},
+		},
+	})
+	if err != nil {
+		ui.Error(fmt.Sprintf("Failed to send test notification via %s", channel))
+		ui.Dim(fmt.Sprintf("Check the channel is enabled with '%s notify ls'", execName()))
+		return fmt.Errorf("failed to send test notification: %w", err)
+	}
+
+	return nil
+}
+
+func notifyClient() (*api.Client, error) {
+	if err := checkLogin(); err != nil {
+		return nil, err
+	}
+	globalCfg, err := config.LoadGlobal()
+	if err != nil {
+		return nil, fmt.Errorf("failed to load config: %w", err)
+	}
+	return api.NewClient(globalCfg.CoolifyURL, globalCfg.CoolifyToken), nil
+}
+
+func parseNotifyChannel(arg string) (string, error) {
+	channel := strings.ToLower(arg)
+	for _, ch := range api.NotificationChannels {
+		if ch == channel {
+			return channel, nil
+		}
+	}
+	ui.Error(fmt.Sprintf("Unknown channel '%s'", arg))
+	ui.Dim("Use one of: " + strings.Join(api.NotificationChannels, ", "))
+	return "", fmt.Errorf("unknown notification channel %q", arg)
+}
+
+// flagOrPrompt returns value, prompting for it when the flag was not given
+func flagOrPrompt(value, prompt string, secret bool) (string, error) {
+	if value != "" {
+		return value, nil
+	}
+	var err error
+	if secret {
+		value, err = ui.Password(prompt)
+	} else {
+		value, err = ui.Input(prompt, "")
+	}
+	if err != nil {
+		return "", err
+	}
+	if value == "" {
+		return "", fmt.Errorf("%s is required", strings.ToLower(prompt))
+	}
+	return value, nil
+}
+
+func notifyEnabled(channel string, settings map[string]interface{}) bool {
+	if channel == "email" {
+		return settings["smtp_enabled"] == true || settings["resend_enabled"] == true
+	}
+	return settings[channel+"_enabled"] == true
+}
+
+// notifyTarget shortens webhook URLs, whose paths are secrets, to their host
+func notifyTarget(target string) string {
+	if target == "" || target == "<nil>" {
+		return "-"
+	}
+	if u, err := url.Parse(target); err == nil && u.Host != "" {
+		return u.Host + "/..."
+	}
+	return target
+}
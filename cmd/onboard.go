@@ -0,0 +1,133 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/dropalltables/cdp/internal/config"
+	"github.com/dropalltables/cdp/internal/ui"
+)
+
+// onboardingSteps are the stages of a first run, in order
+var onboardingSteps = []string{"Log in to Coolify", "Set up this project", "Deploy"}
+
+// needsOnboarding reports whether bare 'cdp' should walk the user through
+// a first run: when there are neither credentials nor a cdp.json, or to
+// resume one that was interrupted in this directory
+func needsOnboarding() bool {
+	dir, err := os.Getwd()
+	if err != nil {
+		return false
+	}
+	if o := config.LoadOnboarding(); o != nil && o.Dir == dir {
+		return true
+	}
+	return !config.IsLoggedIn() && !config.ProjectExistsIn(".")
+}
+
+// onboardingStep returns the index in onboardingSteps of the first step
+// not done yet, or len(onboardingSteps) once the app has been deployed.
+// Each step saves its result, so progress is read back from the configs.
+func onboardingStep() int {
+	if !config.IsLoggedIn() {
+		return 0
+	}
+	projectCfg, err := config.LoadProject()
+	if err != nil || projectCfg == nil || (projectCfg.ServerUUID == "" && projectCfg.AppUUID == "") {
+		return 1
+	}
+	if projectCfg.AppUUID == "" {
+		return 2
+	}
+	return len(onboardingSteps)
+}
+
+// runOnboarding chains login, setup and the first deploy into one flow.
+// An interrupted run is recorded so that the next 'cdp' resumes it.
+func runOnboarding(target deployEnv) error {
+	dir, err := os.Getwd()
+	if err != nil {
+		return err
+	}
+	state := config.LoadOnboarding()
+	resuming := state != nil && state.Dir == dir
+	if !resuming {
+		state = &config.Onboarding{Dir: dir, StartedAt: time.Now()}
+	}
+	if err := config.SaveOnboarding(state); err != nil {
+		ui.Warning(fmt.Sprintf("Could not save onboarding progress: %v", err))
+	}
+
+	step := onboardingStep()
+	if step == len(onboardingSteps) {
+		// Deployed since, e.g. with 'cdp' from another shell
+		_ = config.ClearOnboarding()
+		return runDeploy(target)
+	}
+	if resuming {
+		ui.Bold("Welcome back to cdp")
+		ui.Dim("Picking up where you left off")
+	} else {
+		ui.Bold("Welcome to cdp")
+		ui.Dim("Let's connect to Coolify and get this project deployed")
+	}
+	ui.Spacer()
+	printOnboardingProgress(step)
+
+	if step == 0 {
+		ui.Spacer()
+		ui.Bold(onboardingSteps[0])
+		if err := login(); err != nil {
+			return onboardingStopped(err)
+		}
+		step = onboardingStep()
+	}
+
+	ui.Spacer()
+	ui.Bold(onboardingSteps[step])
+	if err := runDeploy(target); err != nil {
+		return onboardingStopped(err)
+	}
+	// Setup returns without an error when interrupted
+	if onboardingStep() < len(onboardingSteps) {
+		return onboardingStopped(nil)
+	}
+
+	_ = config.ClearOnboarding()
+	ui.Spacer()
+	ui.Success("You're all set")
+	ui.NextSteps([]string{
+		fmt.Sprintf("Run '%s' again to deploy your changes", execName()),
+		fmt.Sprintf("Run '%s ls' to see the app's status and URL", execName()),
+		fmt.Sprintf("Run '%s logs' to see its logs", execName()),
+	})
+	return nil
+}
+
+// printOnboardingProgress lists the onboarding steps, marking the done
+// ones and the current one
+func printOnboardingProgress(current int) {
+	for i, name := range onboardingSteps {
+		switch {
+		case i < current:
+			ui.Print("  " + ui.SuccessStyle.Render(ui.IconSuccess) + " " + name)
+		case i == current:
+			ui.Print("  " + ui.InfoStyle.Render(ui.IconArrow) + " " + ui.BoldStyle.Render(name))
+		default:
+			ui.Print("  " + ui.DimStyle.Render(ui.IconDot+" "+name))
+		}
+	}
+}
+
+// onboardingStopped tells the user how to resume and passes err on,
+// dropping interrupts as other commands do
+func onboardingStopped(err error) error {
+	ui.Spacer()
+	ui.Dim(fmt.Sprintf("Run '%s' again to pick up where you left off", execName()))
+	if err != nil && strings.Contains(err.Error(), "interrupted") {
+		return nil
+	}
+	return err
+}
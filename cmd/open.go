@@ -0,0 +1,115 @@
+package cmd
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/dropalltables/cdp/internal/api"
+	"github.com/dropalltables/cdp/internal/config"
+	"github.com/dropalltables/cdp/internal/ui"
+	"github.com/spf13/cobra"
+)
+
+var (
+	// Flags for open command
+	openDashboardFlag bool
+)
+
+var openCmd = &cobra.Command{
+	Use:   "open",
+	Short: "Open the deployed app or its Coolify page in the browser",
+	Long: `Open the linked app's URL in your default browser, preferring a custom
+domain over one Coolify generated. With --dashboard, open the app's page in
+the Coolify dashboard instead.`,
+	Example: `  cdp open
+  cdp open --env staging
+  cdp open --dashboard`,
+	Args: cobra.NoArgs,
+	RunE: runOpen,
+}
+
+func init() {
+	rootCmd.AddCommand(openCmd)
+	openCmd.Flags().BoolVar(&openDashboardFlag, "dashboard", false, "Open the app in the Coolify dashboard")
+	addEnvFlag(openCmd, false, envProduction)
+}
+
+func runOpen(cmd *cobra.Command, args []string) error {
+	target, err := resolveDeployEnv(cmd)
+	if err != nil {
+		return err
+	}
+	if target.Preview() {
+		ui.Error(fmt.Sprintf("Previews are opened with '%s preview open PR'", execName()))
+		return fmt.Errorf("open doesn't support the preview environment")
+	}
+	appUUID, client, err := deployEnvApp(target)
+	if err != nil {
+		return err
+	}
+
+	var url string
+	err = ui.RunTasks([]ui.Task{
+		{
+			Name:         "fetch-app",
+			ActiveName:   "Fetching application info...",
+			CompleteName: "Fetched application info",
+			Action: func() error {
+				app, err := client.GetApplication(appUUID)
+				if err != nil {
+					return err
+				}
+				if !openDashboardFlag {
+					url = api.PreferredDomain(app.FQDN)
+					return nil
+				}
+				url, err = dashboardURL(client, app)
+				return err
+			},
+		},
+	})
+	if err != nil {
+		ui.Error("Failed to fetch application info")
+		ui.Dim("  " + err.Error())
+		return err
+	}
+	if url == "" {
+		ui.Error("The app has no domain")
+		ui.NextSteps([]string{
+			fmt.Sprintf("Run '%s domains add app.example.com' to add one", execName()),
+			fmt.Sprintf("Run '%s open --dashboard' to open it in Coolify", execName()),
+		})
+		return fmt.Errorf("no domain to open")
+	}
+
+	ui.Info(fmt.Sprintf("Opening %s", url))
+	if err := ui.OpenBrowser(url); err != nil {
+		ui.Warning("Could not open a browser")
+		ui.Dim(url)
+	}
+	return nil
+}
+
+// dashboardURL returns the app's page in the Coolify dashboard, which is
+// addressed by the project and environment UUIDs as well as the app's
+func dashboardURL(client *api.Client, app *api.Application) (string, error) {
+	projectCfg, err := config.LoadProject()
+	if err != nil || projectCfg == nil || projectCfg.ProjectUUID == "" {
+		return "", fmt.Errorf("the app's Coolify project is unknown; run '%s link' to look it up", execName())
+	}
+	project, err := client.GetProject(projectCfg.ProjectUUID)
+	if err != nil {
+		return "", fmt.Errorf("failed to load project: %w", err)
+	}
+	for _, e := range project.Environments {
+		if e.ID == app.EnvironmentID {
+			globalCfg, err := config.LoadGlobal()
+			if err != nil {
+				return "", fmt.Errorf("failed to load configuration: %w", err)
+			}
+			return fmt.Sprintf("%s/project/%s/environment/%s/application/%s",
+				strings.TrimSuffix(globalCfg.CoolifyURL, "/"), project.UUID, e.UUID, app.UUID), nil
+		}
+	}
+	return "", fmt.Errorf("the app's environment isn't in project %s", project.Name)
+}
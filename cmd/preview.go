@@ -0,0 +1,295 @@
+package cmd
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/dropalltables/cdp/internal/api"
+	"github.com/dropalltables/cdp/internal/deploy"
+	"github.com/dropalltables/cdp/internal/ui"
+	"github.com/spf13/cobra"
+)
+
+var (
+	// Flags for preview deploy command
+	previewForceFlag bool
+)
+
+var previewCmd = &cobra.Command{
+	Use:   "preview",
+	Short: "Manage preview deployments",
+	Long: `Manage preview deployments of the linked application.
+
+Previews are created by Coolify from GitHub pull requests; these commands
+list them, open them, redeploy them, and clean up stale ones.`,
+}
+
+var previewLsCmd = &cobra.Command{
+	Use:   "ls",
+	Short: "List preview deployments",
+	RunE:  runPreviewLs,
+}
+
+var previewOpenCmd = &cobra.Command{
+	Use:   "open PR",
+	Short: "Open a preview deployment in the browser",
+	Args:  cobra.ExactArgs(1),
+	RunE:  runPreviewOpen,
+}
+
+var previewDeployCmd = &cobra.Command{
+	Use:   "deploy PR",
+	Short: "Redeploy the preview for a pull request",
+	Args:  cobra.ExactArgs(1),
+	RunE:  runPreviewDeploy,
+}
+
+var previewRmCmd = &cobra.Command{
+	Use:   "rm PR",
+	Short: "Delete the preview for a pull request",
+	Args:  cobra.ExactArgs(1),
+	RunE:  runPreviewRm,
+}
+
+func init() {
+	rootCmd.AddCommand(previewCmd)
+	previewCmd.AddCommand(previewLsCmd)
+	previewCmd.AddCommand(previewOpenCmd)
+	previewCmd.AddCommand(previewDeployCmd)
+	previewCmd.AddCommand(previewRmCmd)
+
+	previewDeployCmd.Flags().BoolVar(&previewForceFlag, "force", false, "Force rebuild without cache")
+}
+
+func runPreviewLs(cmd *cobra.Command, args []string) error {
+	appUUID, client, err := getAppUUID()
+	if err != nil {
+		return err
+	}
+
+	app, previews, err := fetchPreviews(client, appUUID)
+	if err != nil {
+		return err
+	}
+
+	if len(previews) == 0 {
+		ui.Warning("No preview deployments found")
+		if !app.IsPreviewDeploymentsEnabled {
+			ui.Dim("Preview deployments are disabled for this application")
+		}
+		return nil
+	}
+
+	rows := [][]string{}
+	for _, p := range previews {
+		url := previewURL(app, p)
+		if url == "" {
+			url = "-"
+		}
+		status := p.Status
+		if status == "" {
+			status = "-"
+		}
+		rows = append(rows, []string{fmt.Sprintf("#%d", p.PullRequestID), url, status, formatTimestamp(p.UpdatedAt)})
+	}
+
+	ui.Spacer()
+	ui.Table([]string{"PR", "URL", "Status", "Updated"}, rows)
+
+	return nil
+}
+
+func runPreviewOpen(cmd *cobra.Command, args []string) error {
+	pr, err := parsePRNumber(args[0])
+	if err != nil {
+		return err
+	}
+
+	appUUID, client, err := getAppUUID()
+	if err != nil {
+		return err
+	}
+
+	app, previews, err := fetchPreviews(client, appUUID)
+	if err != nil {
+		return err
+	}
+
+	preview := findPreview(previews, pr)
+	if preview == nil {
+		ui.Error(fmt.Sprintf("No preview found for PR #%d", pr))
+		return fmt.Errorf("preview for PR #%d not found", pr)
+	}
+
+	url := previewURL(app, *preview)
+	if url == "" {
+		ui.Error(fmt.Sprintf("Preview for PR #%d has no URL", pr))
+		return fmt.Errorf("preview for PR #%d has no URL", pr)
+	}
+
+	ui.Info(fmt.Sprintf("Opening %s", url))
+	if err := ui.OpenBrowser(url); err != nil {
+		ui.Warning("Could not open a browser")
+		ui.Dim(url)
+	}
+	return nil
+}
+
+func runPreviewDeploy(cmd *cobra.Command, args []string) error {
+	pr, err := parsePRNumber(args[0])
+	if err != nil {
+		return err
+	}
+
+	appUUID, client, err := getAppUUID()
+	if err != nil {
+		return err
+	}
+
+	err = ui.RunTasks([]ui.Task{
+		{
+			Name:         "deploy-preview",
+			ActiveName:   fmt.Sprintf("Triggering preview deployment for PR #%d...", pr),
+			CompleteName: fmt.Sprintf("Triggered preview deployment for PR #%d", pr),
+			Action: func() error {
+				_, err := client.Deploy(appUUID, previewForceFlag, pr)
+				return err
+			},
+		},
+	})
+	if err != nil {
+		ui.Error("Failed to trigger preview deployment")
+		return fmt.Errorf("failed to deploy preview: %w", err)
+	}
+
+	ui.Info("Watching deployment...")
+	if !deploy.WatchDeployment(client, appUUID) {
+		ui.Error("Preview deployment failed")
+		return fmt.Errorf("preview deployment failed")
+	}
+
+	ui.Success(fmt.Sprintf("Deployed preview for PR #%d", pr))
+
+	app, previews, err := fetchPreviews(client, appUUID)
+	if err == nil {
+		if p := findPreview(previews, pr); p != nil {
+			if url := previewURL(app, *p); url != "" {
+				fmt.Println(ui.DimStyle.Render("  URL: " + url))
+			}
+		}
+	}
+
+	return nil
+}
+
+func runPreviewRm(cmd *cobra.Command, args []string) error {
+	pr, err := parsePRNumber(args[0])
+	if err != nil {
+		return err
+	}
+
+	appUUID, client, err := getAppUUID()
+	if err != nil {
+		return err
+	}
+
+	_, previews, err := fetchPreviews(client, appUUID)
+	if err != nil {
+		return err
+	}
+	if findPreview(previews, pr) == nil {
+		ui.Error(fmt.Sprintf("No preview found for PR #%d", pr))
+		return fmt.Errorf("preview for PR #%d not found", pr)
+	}
+
+	confirmed, err := ui.Confirm(fmt.Sprintf("Delete preview for PR #%d?", pr))
+	if err != nil {
+		return err
+	}
+	if !confirmed {
+		return nil
+	}
+
+	err = ui.RunTasks([]ui.Task{
+		{
+			Name:         "delete-preview",
+			ActiveName:   fmt.Sprintf("Deleting preview for PR #%d...", pr),
+			CompleteName: fmt.Sprintf("Deleted preview for PR #%d", pr),
+			Action: func() error {
+				return client.DeletePreview(appUUID, pr)
+			},
+		},
+	})
+	if err != nil {
+		ui.Error("Failed to delete preview")
+		return fmt.Errorf("failed to delete preview: %w", err)
+	}
+
+	return nil
+}
+
+// fetchPreviews loads the application and its preview deployments
+func fetchPreviews(client *api.Client, appUUID string) (*api.Application, []api.ApplicationPreview, error) {
+	var app *api.Application
+	var previews []api.ApplicationPreview
+	err := ui.RunTasks([]ui.Task{
+		{
+			Name:         "load-previews",
+			ActiveName:   "Loading preview deployments...",
+			CompleteName: "Loaded preview deployments",
+			Action: func() error {
+				var err error
+				app, err = client.GetApplication(appUUID)
+				if err != nil {
+					return err
+				}
+				previews, err = client.ListPreviews(appUUID)
+				return err
+			},
+		},
+	})
+	if err != nil {
+		ui.Error("Failed to load preview deployments")
+		return nil, nil, fmt.Errorf("failed to fetch previews: %w", err)
+	}
+	return app, previews, nil
+}
+
+func findPreview(previews []api.ApplicationPreview, pr int) *api.ApplicationPreview {
+	for i := range previews {
+		if previews[i].PullRequestID == pr {
+			return &previews[i]
+		}
+	}
+	return nil
+}
+
+// previewURL returns the URL of a preview, falling back to the app's
+// preview URL template when Coolify has not recorded one
+func previewURL(app *api.Application, p api.ApplicationPreview) string {
+	if ds := parseDomains(p.FQDN); len(ds) > 0 {
+		return ds[0]
+	}
+	domains := parseDomains(app.FQDN)
+	if app.PreviewURLTemplate == "" || len(domains) == 0 {
+		return ""
+	}
+
+	scheme := "https://"
+	if strings.HasPrefix(domains[0], "http://") {
+		scheme = "http://"
+	}
+	host := app.PreviewURLTemplate
+	host = strings.ReplaceAll(host, "{{pr_id}}", strconv.Itoa(p.PullRequestID))
+	host = strings.ReplaceAll(host, "{{domain}}", domainHost(domains[0]))
+	return scheme + host
+}
+
+func parsePRNumber(arg string) (int, error) {
+	pr, err := strconv.Atoi(strings.TrimPrefix(arg, "#"))
+	if err != nil || pr <= 0 {
+		return 0, fmt.Errorf("invalid pull request number %q", arg)
+	}
+	return pr, nil
+}
@@ -0,0 +1,202 @@
+package cmd
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/dropalltables/cdp/internal/api"
+	"github.com/dropalltables/cdp/internal/config"
+	"github.com/dropalltables/cdp/internal/deploy"
+	"github.com/dropalltables/cdp/internal/ui"
+	"github.com/spf13/cobra"
+)
+
+var (
+	// Flags for project redeploy command
+	projectRedeployEnvFlag         string
+	projectRedeployRestartFlag     bool
+	projectRedeployForceFlag       bool
+	projectRedeployConcurrencyFlag int
+)
+
+var projectCmd = &cobra.Command{
+	Use:   "project",
+	Short: "Act on every app of a Coolify project",
+}
+
+var projectRedeployCmd = &cobra.Command{
+	Use:   "redeploy [PROJECT]",
+	Short: "Redeploy or restart every application in a project",
+	Long: `Redeploy every application in a Coolify project, e.g. after a shared base
+image or environment variable changed, and wait for the deployments to finish.
+At most --concurrency deployments run at once.
+
+PROJECT is a project name or UUID; it defaults to the linked project.`,
+	Example: `  cdp project redeploy
+  cdp project redeploy backend --env staging --concurrency 5
+  cdp project redeploy --restart`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: runProjectRedeploy,
+}
+
+func init() {
+	rootCmd.AddCommand(projectCmd)
+	projectCmd.AddCommand(projectRedeployCmd)
+
+	projectRedeployCmd.Flags().StringVar(&projectRedeployEnvFlag, "env", "", "Only apps in this environment")
+	projectRedeployCmd.Flags().BoolVar(&projectRedeployRestartFlag, "restart", false, "Restart containers instead of rebuilding")
+	projectRedeployCmd.Flags().BoolVar(&projectRedeployForceFlag, "force", false, "Rebuild without the build cache")
+	projectRedeployCmd.Flags().IntVar(&projectRedeployConcurrencyFlag, "concurrency", 3, "Deployments to run at once")
+	projectRedeployCmd.Flags().StringVar(&overridePolicyFlag, "override-policy", "", "Deploy despite org policy violations, giving a reason")
+}
+
+func runProjectRedeploy(cmd *cobra.Command, args []string) error {
+	if err := checkLogin(); err != nil {
+		return err
+	}
+	if projectRedeployRestartFlag && projectRedeployForceFlag {
+		ui.Error("--restart doesn't rebuild, so it can't be combined with --force")
+		return fmt.Errorf("--restart and --force are mutually exclusive")
+	}
+	if projectRedeployConcurrencyFlag < 1 {
+		ui.Error("--concurrency must be at least 1")
+		return fmt.Errorf("invalid concurrency %d", projectRedeployConcurrencyFlag)
+	}
+
+	globalCfg, err := config.LoadGlobal()
+	if err != nil {
+		return fmt.Errorf("failed to load configuration: %w", err)
+	}
+	client := api.NewClient(globalCfg.CoolifyURL, globalCfg.CoolifyToken)
+
+	projectRef := ""
+	if len(args) > 0 {
+		projectRef = args[0]
+	} else if projectCfg, err := config.LoadProject(); err == nil {
+		projectRef = projectCfg.ProjectUUID
+	}
+	if projectRef == "" {
+		ui.Error("No project given and none linked")
+		ui.Dim(fmt.Sprintf("Run '%s project redeploy PROJECT'", execName()))
+		return fmt.Errorf("no project")
+	}
+
+	var project *api.Project
+	var apps []api.Application
+	err = ui.RunTasks([]ui.Task{
+		{
+			Name:         "load-apps",
+			ActiveName:   "Loading applications...",
+			CompleteName: "Loaded applications",
+			Action: func() error {
+				uuid, err := findProjectUUID(client, projectRef)
+				if err != nil {
+					return err
+				}
+				project, err = client.GetProject(uuid)
+				if err != nil {
+					return fmt.Errorf("failed to load project: %w", err)
+				}
+				apps, err = projectApps(client, project, projectRedeployEnvFlag)
+				return err
+			},
+		},
+	})
+	if err != nil {
+		ui.Error(err.Error())
+		return err
+	}
+	if len(apps) == 0 {
+		ui.Warning("No applications to redeploy")
+		return nil
+	}
+
+	action, running, finished := "Redeploy", "Redeploying", "Redeployed"
+	if projectRedeployRestartFlag {
+		action, running, finished = "Restart", "Restarting", "Restarted"
+	}
+	ui.Spacer()
+	ui.KeyValue("Project", project.Name)
+	if projectRedeployEnvFlag != "" {
+		ui.KeyValue("Environment", projectRedeployEnvFlag)
+	}
+	names := make([]string, len(apps))
+	for i, app := range apps {
+		names[i] = app.Name
+	}
+	ui.KeyValue("Applications", strings.Join(names, ", "))
+	ui.Spacer()
+
+	// Without --env, production apps are among those redeployed
+	production := projectRedeployEnvFlag == "" || strings.EqualFold(projectRedeployEnvFlag, envProduction)
+	if err := deploy.EnforcePolicies(globalCfg, &config.ProjectConfig{Name: project.Name}, production, overridePolicyFlag); err != nil {
+		return err
+	}
+
+	confirmed, err := ui.ConfirmWithOptions(fmt.Sprintf("%s %d applications?", action, len(apps)), ui.ConfirmOptions{Default: true})
+	if err != nil {
+		return err
+	}
+	if !confirmed {
+		return nil
+	}
+
+	spinner := ui.NewSpinner(fmt.Sprintf("%s applications... (0/%d)", running, len(apps)))
+	spinner.Start()
+	outcomes := deploy.RedeployAll(client, apps, deploy.BulkOptions{
+		Restart:     projectRedeployRestartFlag,
+		Force:       projectRedeployForceFlag,
+		Concurrency: projectRedeployConcurrencyFlag,
+	}, func(done int) {
+		spinner.SetMessage(fmt.Sprintf("%s applications... (%d/%d)", running, done, len(apps)))
+	})
+
+	var results ui.Results
+	rows := make([][]string, len(outcomes))
+	for i, r := range outcomes {
+		results.Add(r.App.Name, strings.ToLower(action), r.Err)
+		status := r.Status
+		if r.Err != nil {
+			status = "failed"
+		}
+		rows[i] = []string{r.App.Name, status, r.Duration.Round(time.Second).String()}
+	}
+	if failed := len(results.Failed()); failed > 0 {
+		spinner.StopWithError(fmt.Sprintf("%d of %d applications failed", failed, len(apps)))
+	} else {
+		spinner.StopWithSuccess(fmt.Sprintf("%s %d applications", finished, len(apps)))
+	}
+
+	ui.Spacer()
+	ui.Table([]string{"Application", "Result", "Duration"}, rows)
+
+	results.Render()
+	return results.Err()
+}
+
+// projectApps returns the applications in a project, limited to the named
+// environment when envName is set
+func projectApps(client *api.Client, project *api.Project, envName string) ([]api.Application, error) {
+	envIDs := map[int]bool{}
+	for _, e := range project.Environments {
+		if envName == "" || strings.EqualFold(e.Name, envName) {
+			envIDs[e.ID] = true
+		}
+	}
+	if len(envIDs) == 0 {
+		return nil, fmt.Errorf("project has no environment %q", envName)
+	}
+
+	all, err := client.ListApplications()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list applications: %w", err)
+	}
+	var apps []api.Application
+	for _, app := range all {
+		if envIDs[app.EnvironmentID] {
+			apps = append(apps, app)
+		}
+	}
+	return apps, nil
+}
@@ -0,0 +1,595 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/dropalltables/cdp/internal/api"
+	"github.com/dropalltables/cdp/internal/config"
+	"github.com/dropalltables/cdp/internal/git"
+	"github.com/dropalltables/cdp/internal/ui"
+	"github.com/spf13/cobra"
+)
+
+var (
+	// Flags for projects status command
+	projectsRootFlag  []string
+	projectsDepthFlag int
+
+	// Flags for projects ls, create and rm commands
+	projectsJSONFlag        bool
+	projectsDescriptionFlag string
+	projectsEnvironmentFlag []string
+)
+
+var projectsCmd = &cobra.Command{
+	Use:   "projects",
+	Short: "Manage Coolify projects and your local cdp projects",
+}
+
+var projectsLsCmd = &cobra.Command{
+	Use:     "ls",
+	Aliases: []string{"list"},
+	Short:   "List Coolify projects and their environments",
+	Args:    cobra.NoArgs,
+	RunE:    runProjectsLs,
+}
+
+var projectsCreateCmd = &cobra.Command{
+	Use:   "create NAME",
+	Short: "Create a Coolify project",
+	Long: `Create a Coolify project, e.g. to prepare one for a team before anyone
+deploys to it. Coolify gives every new project a production environment;
+add more with --environment.`,
+	Example: `  cdp projects create shop
+  cdp projects create shop --environment staging --environment qa`,
+	Args: cobra.ExactArgs(1),
+	RunE: runProjectsCreate,
+}
+
+var projectsRmCmd = &cobra.Command{
+	Use:     "rm PROJECT",
+	Aliases: []string{"remove", "delete"},
+	Short:   "Delete an empty Coolify project",
+	Long: `Delete a Coolify project by name or UUID. Coolify only deletes projects
+without resources, so remove their applications first.`,
+	Args: cobra.ExactArgs(1),
+	RunE: runProjectsRm,
+}
+
+var projectsStatusCmd = &cobra.Command{
+	Use:   "status",
+	Short: "Show the status of every project in your workspace roots",
+	Long: `Scan the workspace roots for project configs (cdp.json or cdp.yaml) and
+show each linked app's status, last deploy, and drift between the local
+config and the app in Coolify.
+
+Workspace roots are read from "workspace_roots" in the global config, or
+given with --root. Without either, the current directory is scanned.`,
+	Example: `  cdp projects status
+  cdp projects status --root ~/code --root ~/work`,
+	RunE: runProjectsStatus,
+}
+
+func init() {
+	rootCmd.AddCommand(projectsCmd)
+	projectsCmd.AddCommand(projectsStatusCmd)
+	projectsCmd.AddCommand(projectsLsCmd)
+	projectsCmd.AddCommand(projectsCreateCmd)
+	projectsCmd.AddCommand(projectsRmCmd)
+
+	projectsStatusCmd.Flags().StringArrayVar(&projectsRootFlag, "root", nil, "Directory to scan (repeatable)")
+	projectsStatusCmd.Flags().IntVar(&projectsDepthFlag, "depth", 3, "How many directory levels below each root to scan")
+
+	projectsLsCmd.Flags().BoolVar(&projectsJSONFlag, "json", false, "Output as JSON")
+	projectsCreateCmd.Flags().BoolVar(&projectsJSONFlag, "json", false, "Output as JSON")
+	projectsCreateCmd.Flags().StringVar(&projectsDescriptionFlag, "description", "", "Project description")
+	projectsCreateCmd.Flags().StringArrayVar(&projectsEnvironmentFlag, "environment", nil, "Also create this environment (repeatable)")
+	projectsRmCmd.ValidArgsFunction = firstArgOnly(completeProjectNames)
+}
+
+// projectsSkipDirs are never scanned for project configs
+var projectsSkipDirs = map[string]bool{
+	"node_modules": true,
+	"vendor":       true,
+	"target":       true,
+	"dist":         true,
+	"build":        true,
+}
+
+// projectStatus is what 'projects status' reports for one project directory
+type projectStatus struct {
+	Dir        string
+	Config     *config.ProjectConfig
+	Status     string
+	LastDeploy string
+	Drift      []string
+}
+
+func runProjectsStatus(cmd *cobra.Command, args []string) error {
+	if err := checkLogin(); err != nil {
+		return err
+	}
+
+	globalCfg, err := config.LoadGlobal()
+	if err != nil {
+		return fmt.Errorf("failed to load configuration: %w", err)
+	}
+
+	roots := projectsRootFlag
+	if len(roots) == 0 {
+		roots = globalCfg.WorkspaceRoots
+	}
+	if len(roots) == 0 {
+		roots = []string{"."}
+		ui.Dim("No workspace roots configured; scanning the current directory")
+	}
+
+	var dirs []string
+	for _, root := range roots {
+		found, err := findProjectDirs(expandHome(root), projectsDepthFlag)
+		if err != nil {
+			ui.Warning(fmt.Sprintf("Skipping %s: %v", root, err))
+			continue
+		}
+		dirs = append(dirs, found...)
+	}
+
+	if len(dirs) == 0 {
+		ui.Warning("No projects found")
+		ui.Dim("Add directories to \"workspace_roots\" in the global config, or pass --root")
+		return nil
+	}
+
+	client := api.NewClient(globalCfg.CoolifyURL, globalCfg.CoolifyToken)
+	statuses := make([]*projectStatus, len(dirs))
+
+	err = ui.RunTasks([]ui.Task{
+		{
+			Name:         "check-projects",
+			ActiveName:   fmt.Sprintf("Checking %d projects...", len(dirs)),
+			CompleteName: fmt.Sprintf("Checked %d projects", len(dirs)),
+			Action: func() error {
+				var wg sync.WaitGroup
+				for i, dir := range dirs {
+					wg.Add(1)
+					go func(i int, dir string) {
+						defer wg.Done()
+						statuses[i] = checkProject(client, dir)
+					}(i, dir)
+				}
+				wg.Wait()
+				return nil
+			},
+		},
+	})
+	if err != nil {
+		return err
+	}
+
+	rows := [][]string{}
+	drifted := 0
+	for _, s := range statuses {
+		name := "-"
+		if s.Config != nil && s.Config.Name != "" {
+			name = s.Config.Name
+		}
+		drift := "-"
+		if len(s.Drift) > 0 {
+			drift = fmt.Sprintf("%d warning(s)", len(s.Drift))
+			drifted++
+		}
+		rows = append(rows, []string{name, displayPath(s.Dir), s.Status, s.LastDeploy, drift})
+	}
+
+	ui.Spacer()
+	ui.Table([]string{"Project", "Path", "Status", "Last deploy", "Drift"}, rows)
+
+	if drifted > 0 {
+		ui.Spacer()
+		for _, s := range statuses {
+			for _, d := range s.Drift {
+				ui.Warning(fmt.Sprintf("%s: %s", displayPath(s.Dir), d))
+			}
+		}
+	}
+
+	return nil
+}
+
+// projectRecord is the JSON shape emitted by 'projects ls' and 'projects create'
+type projectRecord struct {
+	UUID         string   `json:"uuid"`
+	Name         string   `json:"name"`
+	Description  string   `json:"description,omitempty"`
+	Environments []string `json:"environments"`
+}
+
+func newProjectRecord(p api.Project) projectRecord {
+	r := projectRecord{UUID: p.UUID, Name: p.Name, Description: p.Description, Environments: []string{}}
+	for _, e := range p.Environments {
+		r.Environments = append(r.Environments, e.Name)
+	}
+	return r
+}
+
+func printProjectsJSON(v interface{}) error {
+	enc := json.NewEncoder(os.Stdout)
+	enc.SetIndent("", "  ")
+	return enc.Encode(v)
+}
+
+func runProjectsLs(cmd *cobra.Command, args []string) error {
+	if err := checkLogin(); err != nil {
+		return err
+	}
+	globalCfg, err := config.LoadGlobal()
+	if err != nil {
+		return fmt.Errorf("failed to load configuration: %w", err)
+	}
+	client := api.NewClient(globalCfg.CoolifyURL, globalCfg.CoolifyToken)
+
+	var projects []api.Project
+	fetch := func() error {
+		var err error
+		projects, err = projectDetails(client)
+		return err
+	}
+
+	// Keep stdout clean for machine-readable output
+	if projectsJSONFlag {
+		err = fetch()
+	} else {
+		err = ui.RunTasks([]ui.Task{
+			{
+				Name:         "fetch-projects",
+				ActiveName:   "Fetching projects...",
+				CompleteName: "Fetched projects",
+				Action:       fetch,
+			},
+		})
+	}
+	if err != nil {
+		ui.Error("Failed to fetch projects")
+		return fmt.Errorf("failed to list projects: %w", err)
+	}
+
+	sort.Slice(projects, func(i, j int) bool {
+		return strings.ToLower(projects[i].Name) < strings.ToLower(projects[j].Name)
+	})
+
+	if projectsJSONFlag {
+		records := make([]projectRecord, len(projects))
+		for i, p := range projects {
+			records[i] = newProjectRecord(p)
+		}
+		return printProjectsJSON(records)
+	}
+
+	if len(projects) == 0 {
+		ui.Warning("No projects found")
+		return nil
+	}
+
+	linked := ""
+	if projectCfg, err := config.LoadProject(); err == nil && projectCfg != nil {
+		linked = projectCfg.ProjectUUID
+	}
+	rows := make([][]string, len(projects))
+	for i, p := range projects {
+		name := p.Name
+		if p.UUID == linked {
+			name += " (linked)"
+		}
+		rows[i] = []string{name, p.UUID, strings.Join(newProjectRecord(p).Environments, ", "), orDash(p.Description)}
+	}
+	ui.Spacer()
+	ui.Table([]string{"Project", "UUID", "Environments", "Description"}, rows)
+	return nil
+}
+
+func runProjectsCreate(cmd *cobra.Command, args []string) error {
+	if err := checkLogin(); err != nil {
+		return err
+	}
+	globalCfg, err := config.LoadGlobal()
+	if err != nil {
+		return fmt.Errorf("failed to load configuration: %w", err)
+	}
+	client := api.NewClient(globalCfg.CoolifyURL, globalCfg.CoolifyToken)
+	name := strings.TrimSpace(args[0])
+
+	var project *api.Project
+	tasks := []ui.Task{
+		{
+			Name:         "create-project",
+			ActiveName:   fmt.Sprintf("Creating project %s...", name),
+			CompleteName: fmt.Sprintf("Created project %s", name),
+			Action: func() error {
+				created, err := client.CreateProject(name, projectsDescriptionFlag)
+				if err != nil {
+					return err
+				}
+				// The create response only carries the UUID
+				project, err = client.GetProject(created.UUID)
+				return err
+			},
+		},
+	}
+	for _, envName := range projectsEnvironmentFlag {
+		envName := strings.TrimSpace(envName)
+		tasks = append(tasks, ui.Task{
+			Name:         "create-environment",
+			ActiveName:   fmt.Sprintf("Creating environment %s...", envName),
+			CompleteName: fmt.Sprintf("Created environment %s", envName),
+			Action: func() error {
+				for _, e := range project.Environments {
+					if strings.EqualFold(e.Name, envName) {
+						return nil
+					}
+				}
+				env, err := client.CreateEnvironment(project.UUID, envName)
+				if err != nil {
+					return fmt.Errorf("failed to create environment %q: %w", envName, err)
+				}
+				if env.Name == "" {
+					env.Name = envName
+				}
+				project.Environments = append(project.Environments, *env)
+				return nil
+			},
+		})
+	}
+
+	if projectsJSONFlag {
+		for _, task := range tasks {
+			if err = task.Action(); err != nil {
+				break
+			}
+		}
+	} else {
+		err = ui.RunTasks(tasks)
+	}
+	if err != nil {
+		ui.Error("Failed to create project")
+		return err
+	}
+
+	if projectsJSONFlag {
+		return printProjectsJSON(newProjectRecord(*project))
+	}
+	ui.Spacer()
+	ui.KeyValue("UUID", project.UUID)
+	ui.KeyValue("Environments", strings.Join(newProjectRecord(*project).Environments, ", "))
+	ui.NextSteps([]string{
+		fmt.Sprintf("Run '%s' in a checkout and pick %s to deploy into it", execName(), project.Name),
+	})
+	return nil
+}
+
+func runProjectsRm(cmd *cobra.Command, args []string) error {
+	if err := checkLogin(); err != nil {
+		return err
+	}
+	globalCfg, err := config.LoadGlobal()
+	if err != nil {
+		return fmt.Errorf("failed to load configuration: %w", err)
+	}
+	client := api.NewClient(globalCfg.CoolifyURL, globalCfg.CoolifyToken)
+
+	var project *api.Project
+	var apps []api.Application
+	err = ui.RunTasks([]ui.Task{
+		{
+			Name:         "load-project",
+			ActiveName:   "Loading project...",
+			CompleteName: "Loaded project",
+			Action: func() error {
+				uuid, err := findProjectUUID(client, args[0])
+				if err != nil {
+					return err
+				}
+				project, err = client.GetProject(uuid)
+				if err != nil {
+					return fmt.Errorf("failed to load project: %w", err)
+				}
+				apps, err = projectApps(client, project, "")
+				return err
+			},
+		},
+	})
+	if err != nil {
+		ui.Error(err.Error())
+		return err
+	}
+
+	if len(apps) > 0 {
+		ui.Error(fmt.Sprintf("%s still has %d applications", project.Name, len(apps)))
+		ui.Dim("  Coolify only deletes empty projects; remove its applications first")
+		return fmt.Errorf("project is not empty")
+	}
+
+	confirmed, err := ui.ConfirmDanger(fmt.Sprintf("Delete project %s?", project.Name), project.Name)
+	if err != nil {
+		return err
+	}
+	if !confirmed {
+		return nil
+	}
+
+	err = ui.RunTasks([]ui.Task{
+		{
+			Name:         "delete-project",
+			ActiveName:   "Deleting project...",
+			CompleteName: fmt.Sprintf("Deleted project %s", project.Name),
+			Action: func() error {
+				return client.DeleteProject(project.UUID)
+			},
+		},
+	})
+	if err != nil {
+		ui.Error("Failed to delete project")
+		ui.Dim("  If it still holds services or databases, remove them in Coolify first")
+		return err
+	}
+	return nil
+}
+
+// findProjectDirs returns directories under root, at most depth levels
+// down, that contain a project config
+func findProjectDirs(root string, depth int) ([]string, error) {
+	root, err := filepath.Abs(root)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := os.Stat(root); err != nil {
+		return nil, err
+	}
+
+	var dirs []string
+	err = filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+		if err != nil || !d.IsDir() {
+			return nil
+		}
+		if path != root {
+			name := d.Name()
+			if strings.HasPrefix(name, ".") || projectsSkipDirs[name] {
+				return filepath.SkipDir
+			}
+			rel, _ := filepath.Rel(root, path)
+			if strings.Count(rel, string(filepath.Separator)) >= depth {
+				return filepath.SkipDir
+			}
+		}
+		if _, err := os.Stat(config.ProjectConfigPath(path)); err == nil {
+			dirs = append(dirs, path)
+		}
+		return nil
+	})
+	return dirs, err
+}
+
+// checkProject loads the project config in dir and compares it with the
+// linked app in Coolify
+func checkProject(client *api.Client, dir string) *projectStatus {
+	s := &projectStatus{Dir: dir, Status: "-", LastDeploy: "-"}
+
+	cfg, err := config.LoadProjectFrom(dir)
+	if err != nil || cfg == nil {
+		s.Status = "invalid config"
+		return s
+	}
+	s.Config = cfg
+
+	if cfg.AppUUID == "" {
+		s.Status = "not deployed"
+		return s
+	}
+
+	app, err := client.GetApplication(cfg.AppUUID)
+	if err != nil {
+		if api.IsNotFound(err) {
+			s.Status = "missing"
+			s.Drift = append(s.Drift, "linked app no longer exists in Coolify")
+		} else {
+			s.Status = "unreachable"
+		}
+		return s
+	}
+
+	s.Status = app.Status
+	if s.Status == "" {
+		s.Status = "unknown"
+	}
+
+	var deployedCommit string
+	if deployments, err := client.ListDeploymentHistory(cfg.AppUUID); err == nil && len(deployments) > 0 {
+		latest := deployments[0]
+		s.LastDeploy = fmt.Sprintf("%s (%s)", formatTimestamp(latest.CreatedAt), latest.Status)
+		deployedCommit = deploymentCommit(latest)
+	}
+
+	s.Drift = append(s.Drift, projectDrift(cfg, app)...)
+
+	if cfg.DeployMethod == config.DeployMethodGit && deployedCommit != "" {
+		if head, err := git.GetLatestCommitHash(dir); err == nil && head != "" &&
+			!strings.HasPrefix(deployedCommit, head) && !strings.HasPrefix(head, deployedCommit) {
+			s.Drift = append(s.Drift, fmt.Sprintf("local HEAD %s is not the deployed commit", head))
+		}
+	}
+
+	return s
+}
+
+// projectDrift lists settings that differ between the project config and
+// the app in Coolify, e.g. after edits in the Coolify dashboard
+func projectDrift(cfg *config.ProjectConfig, app *api.Application) []string {
+	var drift []string
+	differs := func(setting, local, remote string) {
+		if local != "" && local != remote {
+			drift = append(drift, fmt.Sprintf("%s is '%s' locally but '%s' in Coolify", setting, local, remote))
+		}
+	}
+
+	if cfg.DeployMethod == config.DeployMethodGit {
+		differs("branch", cfg.Branch, app.GitBranch)
+		differs("build pack", cfg.BuildPack, app.BuildPack)
+	}
+	differs("port", cfg.Port, app.PortsExposes)
+	differs("CPU limit", cfg.CPULimit, app.LimitsCPUs)
+	differs("memory limit", cfg.MemoryLimit, app.LimitsMemory)
+	if cfg.Replicas > 0 && cfg.Replicas != app.SwarmReplicas {
+		drift = append(drift, fmt.Sprintf("replicas is %d locally but %d in Coolify", cfg.Replicas, app.SwarmReplicas))
+	}
+
+	if cfg.Domain != "" {
+		found := false
+		for _, d := range parseDomains(app.FQDN) {
+			if strings.TrimSuffix(hostOf(d), "/") == strings.TrimSuffix(hostOf(cfg.Domain), "/") {
+				found = true
+				break
+			}
+		}
+		if !found {
+			drift = append(drift, fmt.Sprintf("domain %s is not set on the app", cfg.Domain))
+		}
+	}
+
+	return drift
+}
+
+// hostOf strips the scheme from a domain
+func hostOf(domain string) string {
+	if _, rest, ok := strings.Cut(domain, "://"); ok {
+		return rest
+	}
+	return domain
+}
+
+// expandHome expands a leading ~ to the user's home directory
+func expandHome(path string) string {
+	if path == "~" || strings.HasPrefix(path, "~/") {
+		if home, err := os.UserHomeDir(); err == nil {
+			return filepath.Join(home, strings.TrimPrefix(path, "~"))
+		}
+	}
+	return path
+}
+
+// displayPath shortens paths under the home directory to ~/...
+func displayPath(path string) string {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return path
+	}
+	if rel, err := filepath.Rel(home, path); err == nil && !strings.HasPrefix(rel, "..") {
+		return filepath.Join("~", rel)
+	}
+	return path
+}
@@ -0,0 +1,155 @@
+package cmd
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/dropalltables/cdp/internal/api"
+	"github.com/dropalltables/cdp/internal/config"
+	"github.com/dropalltables/cdp/internal/deploy"
+	"github.com/dropalltables/cdp/internal/ui"
+	"github.com/spf13/cobra"
+)
+
+var promoteCmd = &cobra.Command{
+	Use:   "promote [PR]",
+	Short: "Promote a preview deployment to production",
+	Long: `Redeploy the commit of a successful preview deployment to production.
+
+Without an argument the most recent successful preview is promoted;
+pass a pull request number to promote that PR's latest preview.`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: runPromote,
+}
+
+func init() {
+	rootCmd.AddCommand(promoteCmd)
+	promoteCmd.Flags().StringVar(&overridePolicyFlag, "override-policy", "", "Deploy despite org policy violations, giving a reason")
+}
+
+func runPromote(cmd *cobra.Command, args []string) error {
+	pr := 0
+	if len(args) > 0 {
+		var err error
+		pr, err = parsePRNumber(args[0])
+		if err != nil {
+			return err
+		}
+	}
+
+	appUUID, client, err := getAppUUID()
+	if err != nil {
+		return err
+	}
+
+	projectCfg, err := config.LoadProject()
+	if err != nil {
+		return fmt.Errorf("failed to load project config: %w", err)
+	}
+	if projectCfg.DeployMethod == config.DeployMethodDocker {
+		ui.Error("Promote is not supported for Docker-based deployments")
+		ui.Dim("Previews are only created for Git-based deployments")
+		return fmt.Errorf("promote doesn't support Docker-based deployments")
+	}
+
+	var deployments []api.Deployment
+	err = ui.RunTasks([]ui.Task{
+		{
+			Name:         "fetch-history",
+			ActiveName:   "Fetching deployment history...",
+			CompleteName: "Fetched deployment history",
+			Action: func() error {
+				var err error
+				deployments, err = client.ListDeploymentHistory(appUUID)
+				return err
+			},
+		},
+	})
+	if err != nil {
+		ui.Error("Failed to fetch deployment history")
+		return fmt.Errorf("failed to fetch deployment history: %w", err)
+	}
+
+	// History is newest first: take the latest successful preview and production deploys
+	var preview, production *api.Deployment
+	for i := range deployments {
+		d := &deployments[i]
+		if strings.ToLower(d.Status) != "finished" {
+			continue
+		}
+		dpr := deploymentPR(*d)
+		if dpr == 0 && production == nil {
+			production = d
+		}
+		if dpr > 0 && preview == nil && (pr == 0 || dpr == pr) {
+			preview = d
+		}
+	}
+
+	if preview == nil {
+		if pr > 0 {
+			ui.Warning(fmt.Sprintf("No successful preview deployment found for PR #%d", pr))
+		} else {
+			ui.Warning("No successful preview deployments found")
+		}
+		return nil
+	}
+
+	fullCommit := deploymentCommit(*preview)
+	if fullCommit == "" {
+		ui.Error("Preview deployment has no recorded commit")
+		return fmt.Errorf("preview deployment %s has no commit", preview.DeploymentUUID)
+	}
+	commit := fullCommit
+	if len(commit) > 7 {
+		commit = commit[:7]
+	}
+
+	ui.Spacer()
+	ui.KeyValue("Preview", fmt.Sprintf("PR #%d", deploymentPR(*preview)))
+	ui.KeyValue("Commit", commit)
+	if msg := firstLine(preview.CommitMessage); msg != "" {
+		ui.KeyValue("Message", msg)
+	}
+
+	if production != nil {
+		ui.Spacer()
+		ui.Diff(
+			[]string{"commit: " + deploymentCommit(*production), "message: " + production.CommitMessage},
+			[]string{"commit: " + fullCommit, "message: " + preview.CommitMessage},
+		)
+	}
+	ui.Spacer()
+
+	globalCfg, err := config.LoadGlobal()
+	if err != nil {
+		return fmt.Errorf("failed to load configuration: %w", err)
+	}
+	if err := deploy.EnforcePolicies(globalCfg, projectCfg, true, overridePolicyFlag); err != nil {
+		return err
+	}
+
+	confirmed, err := ui.ConfirmWithOptions(fmt.Sprintf("Promote %s to production?", commit), ui.ConfirmOptions{Default: true})
+	if err != nil {
+		return err
+	}
+	if !confirmed {
+		ui.Dim("Cancelled")
+		return nil
+	}
+
+	ui.Info("Promoting to production...")
+	if err := redeployCommit(client, appUUID, fullCommit); err != nil {
+		ui.Error("Promotion failed")
+		return fmt.Errorf("promote failed: %w", err)
+	}
+
+	ui.Success(fmt.Sprintf("Promoted %s to production", commit))
+
+	app, err := client.GetApplication(appUUID)
+	if err == nil && app.FQDN != "" {
+		fmt.Println(ui.DimStyle.Render("  URL: " + app.FQDN))
+	}
+
+	return nil
+}
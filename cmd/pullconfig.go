@@ -0,0 +1,91 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/dropalltables/cdp/internal/config"
+	"github.com/dropalltables/cdp/internal/deploy"
+	"github.com/dropalltables/cdp/internal/ui"
+	"github.com/spf13/cobra"
+)
+
+var (
+	// Flags for pull-config command
+	pullConfigDryRunFlag bool
+)
+
+var pullConfigCmd = &cobra.Command{
+	Use:   "pull-config",
+	Short: "Update cdp.json from the app's settings in Coolify",
+	Long: `Fetch the linked application from Coolify and write its build and start
+commands, port, domains, branch, build pack, health check, and resource
+limits into cdp.json. This is the inverse of 'cdp apply', for when someone
+changed the app in the Coolify dashboard.
+
+Settings the app leaves empty keep their cdp.json value. Use --dry-run to see
+what would change without writing cdp.json.`,
+	Example: `  cdp pull-config --dry-run
+  cdp pull-config`,
+	Args: cobra.NoArgs,
+	RunE: runPullConfig,
+}
+
+func init() {
+	rootCmd.AddCommand(pullConfigCmd)
+
+	pullConfigCmd.Flags().BoolVar(&pullConfigDryRunFlag, "dry-run", false, "Show the changes without writing cdp.json")
+}
+
+func runPullConfig(cmd *cobra.Command, args []string) error {
+	appUUID, client, err := getAppUUID()
+	if err != nil {
+		return err
+	}
+
+	projectCfg, err := loadLinkedProject()
+	if err != nil {
+		return err
+	}
+
+	var app map[string]interface{}
+	err = ui.RunTasks([]ui.Task{
+		{
+			Name:         "fetch-settings",
+			ActiveName:   "Fetching application settings...",
+			CompleteName: "Fetched application settings",
+			Action: func() error {
+				var err error
+				app, err = client.GetApplicationSettings(appUUID)
+				return err
+			},
+		},
+	})
+	if err != nil {
+		ui.Error("Failed to fetch application settings")
+		return err
+	}
+
+	changes := deploy.PullAppSettings(projectCfg, app)
+	if len(changes) == 0 {
+		ui.Success("cdp.json matches Coolify")
+		return nil
+	}
+
+	remote, local := settingValues(changes)
+	ui.Spacer()
+	ui.DiffKeyValues(local, remote)
+
+	if pullConfigDryRunFlag {
+		ui.Spacer()
+		ui.Dim("Dry run; cdp.json was not changed")
+		return nil
+	}
+
+	if err := config.SaveProject(projectCfg); err != nil {
+		ui.Error("Failed to save cdp.json")
+		return err
+	}
+	ui.Spacer()
+	ui.Success(fmt.Sprintf("Updated %d settings in cdp.json", len(changes)))
+	return nil
+}
@@ -3,6 +3,7 @@ package cmd
 import (
 	"fmt"
 	"os"
+	"path/filepath"
 	"strings"
 	"time"
 
@@ -57,7 +58,8 @@ func runReset(cmd *cobra.Command, args []string) error {
 	}
 	ui.Spacer()
 
-	confirm, err := ui.Confirm("Are you sure?")
+	// Require the project name to be typed for this irreversible operation
+	confirm, err := ui.ConfirmDanger("This cannot be undone", projectCfg.Name)
 	if err != nil {
 		return err
 	}
@@ -65,15 +67,6 @@ func runReset(cmd *cobra.Command, args []string) error {
 		return nil
 	}
 
-	// Double confirm
-	confirm2, err := ui.Confirm("Really delete everything?")
-	if err != nil {
-		return err
-	}
-	if !confirm2 {
-		return nil
-	}
-
 	client := api.NewClient(globalCfg.CoolifyURL, globalCfg.CoolifyToken)
 
 	// Collect tasks for deletion
@@ -147,11 +140,11 @@ func runReset(cmd *cobra.Command, args []string) error {
 	}
 
 	// Delete local files
-	if _, err := os.Stat("cdp.json"); err == nil {
+	if configPath := config.ProjectConfigPath("."); config.ProjectExists() {
 		tasks = append(tasks, ui.Task{
 			Name:         "delete-config",
-			ActiveName:   "Removing cdp.json...",
-			CompleteName: "Removed cdp.json",
+			ActiveName:   fmt.Sprintf("Removing %s...", filepath.Base(configPath)),
+			CompleteName: fmt.Sprintf("Removed %s", filepath.Base(configPath)),
 			Action: func() error {
 				return config.DeleteProject()
 			},
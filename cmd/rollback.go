@@ -20,6 +20,7 @@ var rollbackCmd = &cobra.Command{
 
 func init() {
 	rootCmd.AddCommand(rollbackCmd)
+	rollbackCmd.Flags().StringVar(&overridePolicyFlag, "override-policy", "", "Deploy despite org policy violations, giving a reason")
 }
 
 func runRollback(cmd *cobra.Command, args []string) error {
@@ -150,12 +151,67 @@ func runRollback(cmd *cobra.Command, args []string) error {
 	}
 
 	// Confirm rollback
-	commit := selectedDeployment.GitCommitSha
-	if commit == "" {
-		commit = selectedDeployment.Commit
+	fullCommit := selectedDeployment.GitCommitSha
+	if fullCommit == "" {
+		fullCommit = selectedDeployment.Commit
 	}
-	if len(commit) > 7 {
-		commit = commit[:7]
+	message := selectedDeployment.CommitMessage
+
+	// A force-push can drop the commit from the branch, which Coolify only
+	// notices when the build fails to check it out
+	var check *deploy.CommitCheck
+	if fullCommit != "" {
+		err = ui.RunTasks([]ui.Task{
+			{
+				Name:         "check-commit",
+				ActiveName:   "Checking the commit is still on its branch...",
+				CompleteName: "Checked the commit",
+				Action: func() error {
+					app, err := client.GetApplication(appUUID)
+					if err != nil {
+						return err
+					}
+					check, err = deploy.CheckRollbackCommit(globalCfg.GitHubToken, app.GitRepository, app.GitBranch, fullCommit)
+					return err
+				},
+			},
+		})
+		if err != nil {
+			ui.Warning("Could not check the commit on GitHub")
+			ui.Dim("  " + err.Error())
+		}
+	}
+	if check != nil && !check.OK() {
+		target, targetMessage, err := chooseRollbackCommit(check, fullCommit)
+		if err != nil {
+			return err
+		}
+		if target == "" {
+			ui.Dim("Cancelled")
+			return nil
+		}
+		if target != fullCommit {
+			fullCommit, message = target, targetMessage
+		}
+	}
+
+	commit := shortSHA(fullCommit)
+
+	// Preview what changes between the current and target deployment
+	current := deployments[0]
+	currentCommit := current.GitCommitSha
+	if currentCommit == "" {
+		currentCommit = current.Commit
+	}
+	ui.Spacer()
+	ui.Diff(
+		[]string{"commit: " + currentCommit, "message: " + current.CommitMessage},
+		[]string{"commit: " + fullCommit, "message: " + message},
+	)
+	ui.Spacer()
+
+	if err := deploy.EnforcePolicies(globalCfg, projectCfg, true, overridePolicyFlag); err != nil {
+		return err
 	}
 
 	confirmed, err := ui.ConfirmAction("rollback to", commit)
@@ -167,45 +223,89 @@ func runRollback(cmd *cobra.Command, args []string) error {
 		return nil
 	}
 
-	// Trigger rollback by updating the git commit and deploying
 	ui.Info("Initiating rollback...")
-	fullCommit := selectedDeployment.GitCommitSha
-	if fullCommit == "" {
-		fullCommit = selectedDeployment.Commit
+	if err := redeployCommit(client, appUUID, fullCommit); err != nil {
+		ui.Error("Rollback failed")
+		return fmt.Errorf("rollback failed: %w", err)
 	}
+
+	ui.Success(fmt.Sprintf("Rolled back to %s", commit))
+
+	app, err := client.GetApplication(appUUID)
+	if err == nil && app.FQDN != "" {
+		fmt.Println(ui.DimStyle.Render("  URL: " + app.FQDN))
+	}
+
+	return nil
+}
+
+// chooseRollbackCommit warns that sha is no longer on its branch and asks
+// what to deploy instead: the nearest ancestor still on the branch, or sha
+// anyway. It returns an empty commit when the rollback is cancelled.
+func chooseRollbackCommit(check *deploy.CommitCheck, sha string) (commit, message string, err error) {
+	short := shortSHA(sha)
+	ui.Spacer()
+	if check.Missing {
+		ui.Warning(fmt.Sprintf("Commit %s no longer exists in %s", short, check.Repo))
+	} else {
+		ui.Warning(fmt.Sprintf("Commit %s is no longer on %s", short, check.Branch))
+	}
+	ui.Dim("  The branch was probably force-pushed, so the build would fail to check it out")
+
+	if check.Ancestor != "" {
+		ui.KeyValue("Nearest ancestor", fmt.Sprintf("%s  %s", shortSHA(check.Ancestor), check.AncestorMessage))
+		useAncestor, err := ui.ConfirmWithOptions(
+			fmt.Sprintf("Roll back to %s instead?", shortSHA(check.Ancestor)),
+			ui.ConfirmOptions{Default: true},
+		)
+		if err != nil {
+			return "", "", err
+		}
+		if useAncestor {
+			return check.Ancestor, check.AncestorMessage, nil
+		}
+	}
+
+	anyway, err := ui.ConfirmWithOptions(fmt.Sprintf("Roll back to %s anyway?", short), ui.ConfirmOptions{})
+	if err != nil || !anyway {
+		return "", "", err
+	}
+	return sha, "", nil
+}
+
+// redeployCommit pins the application to a commit, triggers a forced
+// production deployment, and watches it to completion
+func redeployCommit(client *api.Client, appUUID, fullCommit string) error {
 	if fullCommit != "" {
-		err = client.UpdateApplication(appUUID, map[string]any{
+		err := client.UpdateApplication(appUUID, map[string]any{
 			"git_commit_sha": fullCommit,
 		})
 		if err != nil {
 			ui.Error("Failed to update application")
-			return fmt.Errorf("rollback failed: %w", err)
+			return err
 		}
 	}
 
 	// Deploy with force rebuild
-	_, err = client.Deploy(appUUID, true, 0)
+	_, err := client.Deploy(appUUID, true, 0)
 	if err != nil {
 		ui.Error("Failed to trigger deployment")
-		return fmt.Errorf("rollback failed: %w", err)
+		return err
 	}
 
 	// Watch deployment
 	ui.Info("Watching deployment...")
 
-	success := deploy.WatchDeployment(client, appUUID)
-
-	if !success {
-		ui.Error("Rollback failed")
-		return fmt.Errorf("rollback failed")
+	if !deploy.WatchDeployment(client, appUUID) {
+		return fmt.Errorf("deployment did not finish successfully")
 	}
+	return nil
+}
 
-	ui.Success(fmt.Sprintf("Rolled back to %s", commit))
-
-	app, err := client.GetApplication(appUUID)
-	if err == nil && app.FQDN != "" {
-		fmt.Println(ui.DimStyle.Render("  URL: " + app.FQDN))
+// shortSHA abbreviates a commit hash to the usual seven characters
+func shortSHA(sha string) string {
+	if len(sha) > 7 {
+		return sha[:7]
 	}
-
-	return nil
+	return sha
 }
@@ -8,6 +8,7 @@ import (
 	"path/filepath"
 
 	"github.com/dropalltables/cdp/internal/config"
+	"github.com/dropalltables/cdp/internal/profile"
 	"github.com/dropalltables/cdp/internal/ui"
 	"github.com/spf13/cobra"
 )
@@ -16,11 +17,27 @@ var (
 	// Version is set at build time
 	Version = "dev"
 
-	// Flags for env command
-	prodFlag bool
-
 	// Global verbose flag
 	verboseFlag bool
+
+	// Global flag to answer yes to confirmations
+	yesFlag bool
+
+	// Global flag to print where the command spent its time
+	profileFlag bool
+
+	// Flag for deploy to bypass preflight checks
+	skipPreflightFlag bool
+
+	// Flag for deploy to override org policies, with the reason for the audit log
+	overridePolicyFlag string
+
+	// Flag for deploy to ship a static site whose output hasn't changed
+	forceFlag bool
+
+	// Flags for deploy to pass Docker build arguments and a platform matrix
+	buildArgFlags []string
+	platformsFlag string
 )
 
 var rootCmd = &cobra.Command{
@@ -32,7 +49,27 @@ Deploy your applications with a single command - no manual setup required.
 Run 'cdp' to deploy, or 'cdp --help' for more commands.`,
 	// Running 'cdp' without subcommand triggers deploy
 	RunE: func(cmd *cobra.Command, args []string) error {
-		return runDeploy()
+		target, err := resolveDeployEnv(cmd)
+		if err != nil {
+			return err
+		}
+		if target.Name == envProduction && needsOnboarding() {
+			return runOnboarding(target)
+		}
+		return runDeploy(target)
+	},
+	// Warn before any command touches a cdp.json written by a newer cdp,
+	// and about deprecated usage
+	PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
+		ui.AssumeYes = yesFlag
+		if profileFlag {
+			profile.Enable()
+		}
+		if isCompletionCmd(cmd) {
+			return nil
+		}
+		warnVersionSkew()
+		return applyDeprecations(cmd)
 	},
 	SilenceUsage:  true, // Don't show usage on errors
 	SilenceErrors: true, // We handle errors with our UI
@@ -44,6 +81,15 @@ func init() {
 
 	// Add global flags
 	rootCmd.PersistentFlags().BoolVarP(&verboseFlag, "verbose", "v", false, "Show detailed command output (disables spinners)")
+	rootCmd.PersistentFlags().BoolVarP(&yesFlag, "yes", "y", false, "Answer yes to confirmation prompts")
+	rootCmd.PersistentFlags().BoolVar(&profileFlag, "profile", false, "Show how long the command spent on API calls, git, docker and prompts")
+
+	addEnvFlag(rootCmd, false, envProduction)
+	rootCmd.Flags().BoolVar(&skipPreflightFlag, "skip-preflight", false, "Deploy even if preflight checks fail")
+	rootCmd.Flags().BoolVar(&forceFlag, "force", false, "Deploy a static site even if its output is unchanged")
+	rootCmd.Flags().StringVar(&overridePolicyFlag, "override-policy", "", "Deploy despite org policy violations, giving a reason")
+	rootCmd.Flags().StringArrayVar(&buildArgFlags, "build-arg", nil, "Docker build argument KEY=value (repeatable)")
+	rootCmd.Flags().StringVar(&platformsFlag, "platforms", "", "Build one image per platform, e.g. linux/amd64,linux/arm64")
 }
 
 // Execute runs the root command
@@ -55,7 +101,15 @@ func Execute() error {
 		}
 	}
 
-	err := rootCmd.Execute()
+	config.CLIVersion = Version
+
+	cmd, err := rootCmd.ExecuteC()
+	ui.PrintProfile()
+	if err != nil {
+		recordFailure(cmd, err)
+	} else {
+		clearResolvedFailure(cmd)
+	}
 	return err
 }
 
@@ -80,6 +134,19 @@ func checkLogin() error {
 	return nil
 }
 
+// warnVersionSkew warns on stderr when the project config was written by a
+// newer cdp version
+func warnVersionSkew() {
+	projectCfg, err := config.LoadProject()
+	if err != nil || !config.IsNewerThanCLI(projectCfg) {
+		return
+	}
+	name := filepath.Base(config.ProjectConfigPath("."))
+	ui.StderrWarning(fmt.Sprintf("%s was written by cdp %s, but you are running %s", name, projectCfg.CDPVersion, Version))
+	fmt.Fprintln(os.Stderr, ui.DimStyle.Render(fmt.Sprintf("  Unknown settings will be preserved. Run '%s upgrade' to update.", execName())))
+	fmt.Fprintln(os.Stderr)
+}
+
 // IsVerbose returns whether verbose mode is enabled
 func IsVerbose() bool {
 	return verboseFlag
@@ -0,0 +1,195 @@
+package cmd
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/dropalltables/cdp/internal/api"
+	"github.com/dropalltables/cdp/internal/config"
+	"github.com/dropalltables/cdp/internal/ui"
+	"github.com/spf13/cobra"
+)
+
+var (
+	// Flags for login rotate-token command
+	rotateTokenStdinFlag bool
+	rotateKeepOldFlag    bool
+)
+
+var loginRotateTokenCmd = &cobra.Command{
+	Use:   "rotate-token",
+	Short: "Replace the Coolify API token with a new one",
+	Long: `Create a new Coolify API token, check that it works, save it in place of
+the current one, and revoke the old token.
+
+Coolify versions that can't create tokens over the API ask for a token
+created under Keys & Tokens instead; pass it on stdin with --token-stdin to
+rotate from a scheduled job. The old token is then revoked over the API when
+possible, or listed for you to revoke by hand.`,
+	Example: `  cdp login rotate-token
+  vault read -field=token secret/coolify | cdp login rotate-token --token-stdin`,
+	Args: cobra.NoArgs,
+	RunE: runLoginRotateToken,
+}
+
+func init() {
+	loginCmd.AddCommand(loginRotateTokenCmd)
+
+	loginRotateTokenCmd.Flags().BoolVar(&rotateTokenStdinFlag, "token-stdin", false, "Read the new token from stdin instead of creating one")
+	loginRotateTokenCmd.Flags().BoolVar(&rotateKeepOldFlag, "keep-old", false, "Don't revoke the old token")
+}
+
+func runLoginRotateToken(cmd *cobra.Command, args []string) error {
+	if err := checkLogin(); err != nil {
+		return err
+	}
+	// The new token couldn't replace the one in the environment, and
+	// revoking that one would break whatever set it, e.g. CI
+	if config.CoolifyTokenFromEnv() {
+		ui.Error(fmt.Sprintf("The Coolify token comes from %s, so cdp can't rotate it", config.EnvCoolifyToken))
+		ui.Dim("  Rotate it where it's set, e.g. in your CI secrets, or unset it to rotate the saved token")
+		return fmt.Errorf("token set by %s", config.EnvCoolifyToken)
+	}
+	cfg, err := config.LoadGlobal()
+	if err != nil {
+		return fmt.Errorf("failed to load configuration: %w", err)
+	}
+	oldToken := cfg.CoolifyToken
+	oldClient := api.NewClient(cfg.CoolifyURL, oldToken)
+
+	// Get the new token: from stdin, over the API, or from a prompt
+	newToken := ""
+	createdID := ""
+	if rotateTokenStdinFlag {
+		newToken, err = readTokenStdin()
+		if err != nil {
+			ui.Error(err.Error())
+			return err
+		}
+	} else {
+		var created *api.APIToken
+		err = ui.RunTasks([]ui.Task{
+			{
+				Name:         "create-token",
+				ActiveName:   "Creating API token...",
+				CompleteName: "Created API token",
+				Action: func() error {
+					name := fmt.Sprintf("cdp-%s", time.Now().Format("2006-01-02"))
+					var err error
+					created, err = oldClient.CreateAPIToken(name, []string{api.AbilityRead, api.AbilityWrite, api.AbilityDeploy})
+					return err
+				},
+			},
+		})
+		switch {
+		case err == nil:
+			newToken = created.Token
+			createdID = fmt.Sprint(created.ID)
+		case errors.Is(err, api.ErrTokenAPIUnsupported):
+			ui.Spacer()
+			ui.Dim("→ This Coolify can't create tokens over the API")
+			ui.Dim("  Create one with read, write and deploy abilities under Keys & Tokens")
+			newToken, err = ui.Password("New API Token")
+			if err != nil {
+				return err
+			}
+		default:
+			ui.Error("Failed to create API token")
+			return err
+		}
+	}
+	newToken = strings.TrimSpace(newToken)
+	if newToken == "" {
+		ui.Error("No new token given")
+		return fmt.Errorf("API token is required")
+	}
+	if newToken == oldToken {
+		ui.Error("The new token is the one already in use")
+		return fmt.Errorf("token unchanged")
+	}
+
+	// Check the new token before anything depends on it
+	newClient := api.NewClient(cfg.CoolifyURL, newToken)
+	var missing []string
+	err = ui.RunTasks([]ui.Task{
+		{
+			Name:         "validate-token",
+			ActiveName:   "Validating new token...",
+			CompleteName: "Validated new token",
+			Action: func() error {
+				var err error
+				missing, err = missingTokenAbilities(newClient)
+				if err != nil {
+					return err
+				}
+				if len(missing) > 0 {
+					return fmt.Errorf("the new token lacks %s", strings.Join(missing, ", "))
+				}
+				return nil
+			},
+		},
+	})
+	if err != nil {
+		ui.Error("The new token doesn't work: " + err.Error())
+		if createdID != "" {
+			if err := oldClient.RevokeAPIToken(createdID); err != nil {
+				ui.Warning(fmt.Sprintf("Revoke the unused token (ID %s) in Coolify under Keys & Tokens", createdID))
+			}
+		}
+		ui.Dim("  The current token is still in use")
+		return err
+	}
+
+	cfg.CoolifyToken = newToken
+	if err := config.SaveGlobal(cfg); err != nil {
+		ui.Error("Failed to save the new token; the current token is still in use")
+		return fmt.Errorf("failed to save configuration: %w", err)
+	}
+	ui.Success("Saved the new token")
+
+	if rotateKeepOldFlag {
+		ui.Spacer()
+		ui.Dim("The old token is still valid; revoke it in Coolify when you're done with it")
+		return nil
+	}
+
+	oldID, ok := api.TokenID(oldToken)
+	if !ok {
+		ui.Spacer()
+		ui.Warning("Couldn't tell the old token's ID; revoke it in Coolify under Keys & Tokens")
+		return nil
+	}
+	err = ui.RunTasks([]ui.Task{
+		{
+			Name:         "revoke-token",
+			ActiveName:   "Revoking old token...",
+			CompleteName: "Revoked old token",
+			Action: func() error {
+				return newClient.RevokeAPIToken(oldID)
+			},
+		},
+	})
+	if err != nil {
+		ui.Spacer()
+		if errors.Is(err, api.ErrTokenAPIUnsupported) {
+			ui.Warning(fmt.Sprintf("Revoke the old token (ID %s) in Coolify under Keys & Tokens", oldID))
+			return nil
+		}
+		ui.Error(fmt.Sprintf("Failed to revoke the old token (ID %s); revoke it in Coolify under Keys & Tokens", oldID))
+		return err
+	}
+	return nil
+}
+
+// readTokenStdin reads a token from the first line of stdin
+func readTokenStdin() (string, error) {
+	line, err := bufio.NewReader(os.Stdin).ReadString('\n')
+	if err != nil && line == "" {
+		return "", fmt.Errorf("failed to read token from stdin: %w", err)
+	}
+	return strings.TrimSpace(line), nil
+}
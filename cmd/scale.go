@@ -0,0 +1,143 @@
+package cmd
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/dropalltables/cdp/internal/api"
+	"github.com/dropalltables/cdp/internal/config"
+	"github.com/dropalltables/cdp/internal/ui"
+	"github.com/spf13/cobra"
+)
+
+var (
+	// Flags for scale command
+	scaleCPUsFlag     string
+	scaleMemoryFlag   string
+	scaleReplicasFlag int
+)
+
+var memoryLimitPattern = regexp.MustCompile(`^[0-9]+[bkmgBKMG]?$`)
+
+var scaleCmd = &cobra.Command{
+	Use:   "scale",
+	Short: "View or set resource limits",
+	Long: `View or set CPU/memory limits and replica count for the linked application.
+
+Run without flags to show the current limits. Desired values are saved to
+cdp.json and applied to Coolify. Replicas only take effect on Swarm servers.`,
+	Example: `  cdp scale
+  cdp scale --cpus 1.5 --memory 1g
+  cdp scale --replicas 3`,
+	RunE: runScale,
+}
+
+func init() {
+	rootCmd.AddCommand(scaleCmd)
+
+	scaleCmd.Flags().StringVar(&scaleCPUsFlag, "cpus", "", "CPU limit (e.g. 0.5, 2)")
+	scaleCmd.Flags().StringVar(&scaleMemoryFlag, "memory", "", "Memory limit (e.g. 512m, 1g)")
+	scaleCmd.Flags().IntVar(&scaleReplicasFlag, "replicas", 0, "Number of replicas")
+}
+
+func runScale(cmd *cobra.Command, args []string) error {
+	appUUID, client, err := getAppUUID()
+	if err != nil {
+		return err
+	}
+
+	changing := cmd.Flags().Changed("cpus") || cmd.Flags().Changed("memory") || cmd.Flags().Changed("replicas")
+	if !changing {
+		app, err := fetchApplication(client, appUUID)
+		if err != nil {
+			return err
+		}
+		showLimits(app)
+		return nil
+	}
+
+	updates := map[string]interface{}{}
+
+	if cmd.Flags().Changed("cpus") {
+		cpus, err := strconv.ParseFloat(scaleCPUsFlag, 64)
+		if err != nil || cpus <= 0 {
+			ui.Error(fmt.Sprintf("Invalid CPU limit: %s", scaleCPUsFlag))
+			return fmt.Errorf("invalid CPU limit %q", scaleCPUsFlag)
+		}
+		updates["limits_cpus"] = scaleCPUsFlag
+	}
+	if cmd.Flags().Changed("memory") {
+		if !memoryLimitPattern.MatchString(scaleMemoryFlag) {
+			ui.Error(fmt.Sprintf("Invalid memory limit: %s", scaleMemoryFlag))
+			return fmt.Errorf("invalid memory limit %q", scaleMemoryFlag)
+		}
+		updates["limits_memory"] = strings.ToLower(scaleMemoryFlag)
+	}
+	if cmd.Flags().Changed("replicas") {
+		if scaleReplicasFlag < 1 {
+			ui.Error("Replicas must be at least 1")
+			return fmt.Errorf("invalid replica count %d", scaleReplicasFlag)
+		}
+		updates["swarm_replicas"] = scaleReplicasFlag
+	}
+
+	err = ui.RunTasks([]ui.Task{
+		{
+			Name:         "update-limits",
+			ActiveName:   "Updating resource limits...",
+			CompleteName: "Updated resource limits",
+			Action: func() error {
+				return client.UpdateApplication(appUUID, updates)
+			},
+		},
+	})
+	if err != nil {
+		ui.Error("Failed to update resource limits")
+		return fmt.Errorf("failed to update resource limits: %w", err)
+	}
+
+	// Persist desired values so they survive re-creation of the app
+	projectCfg, err := config.LoadProject()
+	if err == nil && projectCfg != nil {
+		if v, ok := updates["limits_cpus"].(string); ok {
+			projectCfg.CPULimit = v
+		}
+		if v, ok := updates["limits_memory"].(string); ok {
+			projectCfg.MemoryLimit = v
+		}
+		if v, ok := updates["swarm_replicas"].(int); ok {
+			projectCfg.Replicas = v
+		}
+		if err := config.SaveProject(projectCfg); err != nil {
+			ui.Warning("Failed to update cdp.json")
+		}
+	}
+
+	ui.Spacer()
+	ui.NextSteps([]string{
+		fmt.Sprintf("Run '%s' to redeploy with the new limits", execName()),
+	})
+	return nil
+}
+
+func showLimits(app *api.Application) {
+	cpus := app.LimitsCPUs
+	if cpus == "" || cpus == "0" {
+		cpus = "unlimited"
+	}
+	memory := app.LimitsMemory
+	if memory == "" || memory == "0" {
+		memory = "unlimited"
+	}
+	replicas := app.SwarmReplicas
+	if replicas == 0 {
+		replicas = 1
+	}
+
+	ui.Spacer()
+	ui.KeyValue("CPUs", cpus)
+	ui.KeyValue("Memory", memory)
+	ui.KeyValue("Replicas", strconv.Itoa(replicas))
+}
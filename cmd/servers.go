@@ -0,0 +1,763 @@
+package cmd
+
+import (
+	"fmt"
+	"net"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/dropalltables/cdp/internal/api"
+	"github.com/dropalltables/cdp/internal/config"
+	"github.com/dropalltables/cdp/internal/deploy"
+	"github.com/dropalltables/cdp/internal/ui"
+	"github.com/spf13/cobra"
+)
+
+const (
+	// Key choices offered by 'servers add'
+	serverKeyGenerate = "generate"
+	serverKeyFile     = "file"
+
+	// How long to wait for a new server to become reachable
+	serverValidateTimeout  = 2 * time.Minute
+	serverValidateInterval = 5 * time.Second
+)
+
+var (
+	// Flags for servers add command
+	serverNameFlag string
+	serverUserFlag string
+	serverPortFlag int
+	serverKeyFlag  string
+)
+
+var serversCmd = &cobra.Command{
+	Use:   "servers",
+	Short: "Manage Coolify servers",
+	Long:  "Manage the servers Coolify deploys to.",
+}
+
+var serversAddCmd = &cobra.Command{
+	Use:   "add [IP]",
+	Short: "Register a new server",
+	Long: `Register a new server with Coolify.
+
+The server is reached over SSH. Pick an existing Coolify private key,
+upload one from disk with --key, or generate a fresh key pair; the public
+key must be present in the server's authorized_keys before validation
+can succeed.`,
+	Example: `  cdp servers add 203.0.113.10
+  cdp servers add 203.0.113.10 --user deploy --port 2222 --key ~/.ssh/id_ed25519`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: runServersAdd,
+}
+
+var serversTopCmd = &cobra.Command{
+	Use:   "top",
+	Short: "Show resource usage across servers",
+	Long: `Show CPU, memory, disk, and container counts for every server.
+
+Metrics require Sentinel to be enabled on the server in Coolify. The
+server the linked project deploys to is marked, and a warning is shown
+when any server is running low on disk.`,
+	RunE: runServersTop,
+}
+
+var serversLsCmd = &cobra.Command{
+	Use:     "ls",
+	Aliases: []string{"list"},
+	Short:   "List servers and whether they can be deployed to",
+	Long: `List the servers in Coolify with their address and the result of Coolify's
+last connection check. The server the linked project deploys to is marked.`,
+	Args: cobra.NoArgs,
+	RunE: runServersLs,
+}
+
+var serversValidateCmd = &cobra.Command{
+	Use:   "validate [SERVER]",
+	Short: "Check that Coolify can connect to a server",
+	Long: `Ask Coolify to validate its SSH connection to a server and wait until it
+is reachable and usable. SERVER is a name or UUID; it defaults to the server
+the linked project deploys to.`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: runServersValidate,
+}
+
+var serversInfoCmd = &cobra.Command{
+	Use:   "info [SERVER]",
+	Short: "Show a server's details, resources, and domains",
+	Long: `Show a server's connection details and status, the applications,
+databases, and services on it, and the domains they serve. SERVER is a
+name or UUID; it defaults to the server the linked project deploys to.`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: runServersInfo,
+}
+
+func init() {
+	rootCmd.AddCommand(serversCmd)
+	serversCmd.AddCommand(serversAddCmd)
+	serversCmd.AddCommand(serversTopCmd)
+	serversCmd.AddCommand(serversLsCmd)
+	serversCmd.AddCommand(serversValidateCmd)
+	serversCmd.AddCommand(serversInfoCmd)
+
+	serversAddCmd.Flags().StringVar(&serverNameFlag, "name", "", "Server name (defaults to the IP)")
+	serversAddCmd.Flags().StringVar(&serverUserFlag, "user", "", "SSH user (default root)")
+	serversAddCmd.Flags().IntVar(&serverPortFlag, "port", 0, "SSH port (default 22)")
+	serversAddCmd.Flags().StringVar(&serverKeyFlag, "key", "", "Path to a private key to upload")
+}
+
+func runServersAdd(cmd *cobra.Command, args []string) error {
+	if err := checkLogin(); err != nil {
+		return err
+	}
+
+	globalCfg, err := config.LoadGlobal()
+	if err != nil {
+		return fmt.Errorf("failed to load config: %w", err)
+	}
+	client := api.NewClient(globalCfg.CoolifyURL, globalCfg.CoolifyToken)
+
+	// Connection details
+	ip := ""
+	if len(args) > 0 {
+		ip = args[0]
+	} else {
+		ip, err = ui.Input("Server IP or hostname:", "")
+		if err != nil {
+			return err
+		}
+	}
+	ip = strings.TrimSpace(ip)
+	if ip == "" {
+		return fmt.Errorf("server IP is required")
+	}
+
+	user := serverUserFlag
+	if user == "" {
+		user, err = ui.InputWithDefault("SSH user:", "root")
+		if err != nil {
+			return err
+		}
+	}
+
+	port := serverPortFlag
+	if port == 0 {
+		portStr, err := ui.InputWithDefault("SSH port:", "22")
+		if err != nil {
+			return err
+		}
+		port, err = strconv.Atoi(portStr)
+		if err != nil {
+			return fmt.Errorf("invalid port %q", portStr)
+		}
+	}
+	if port < 1 || port > 65535 {
+		return fmt.Errorf("invalid port %d", port)
+	}
+
+	name := serverNameFlag
+	if name == "" {
+		name, err = ui.InputWithDefault("Server name:", ip)
+		if err != nil {
+			return err
+		}
+	}
+
+	key, err := chooseServerKey(client, name)
+	if err != nil {
+		return err
+	}
+
+	if key.PublicKey != "" {
+		ui.Spacer()
+		ui.Info(fmt.Sprintf("Make sure this public key is in ~%s/.ssh/authorized_keys on the server:", user))
+		ui.Code(strings.TrimSpace(key.PublicKey))
+		ui.Spacer()
+
+		ready, err := ui.ConfirmWithOptions("Continue?", ui.ConfirmOptions{Default: true})
+		if err != nil {
+			return err
+		}
+		if !ready {
+			ui.Dim(fmt.Sprintf("Key '%s' was kept in Coolify; rerun when the server is ready", key.Name))
+			return nil
+		}
+	}
+
+	var serverUUID string
+	err = ui.RunTasks([]ui.Task{
+		{
+			Name:         "create-server",
+			ActiveName:   fmt.Sprintf("Registering %s...", name),
+			CompleteName: fmt.Sprintf("Registered %s", name),
+			Action: func() error {
+				resp, err := client.CreateServer(&api.CreateServerRequest{
+					Name:           name,
+					Description:    fmt.Sprintf("Added by %s", execName()),
+					IP:             ip,
+					Port:           port,
+					User:           user,
+					PrivateKeyUUID: key.UUID,
+				})
+				if err != nil {
+					return err
+				}
+				serverUUID = resp.UUID
+				return nil
+			},
+		},
+	})
+	if err != nil {
+		ui.Error("Failed to register server")
+		return fmt.Errorf("failed to create server: %w", err)
+	}
+
+	server, err := waitForServerValidation(client, serverUUID)
+	if err != nil {
+		ui.Error("Server validation did not complete")
+		ui.Dim("Check SSH access and the validation log in the Coolify dashboard")
+		return err
+	}
+
+	ui.Spacer()
+	ui.KeyValue("Name", server.Name)
+	ui.KeyValue("UUID", serverUUID)
+	ui.KeyValue("Address", net.JoinHostPort(server.IP, strconv.Itoa(server.Port)))
+
+	return nil
+}
+
+// chooseServerKey returns the Coolify private key to use for a new server,
+// uploading or generating one when needed
+func chooseServerKey(client *api.Client, serverName string) (*api.PrivateKey, error) {
+	if serverKeyFlag != "" {
+		return uploadServerKey(client, serverName, serverKeyFlag)
+	}
+
+	var keys []api.PrivateKey
+	err := ui.RunTasks([]ui.Task{
+		{
+			Name:         "load-keys",
+			ActiveName:   "Loading private keys...",
+			CompleteName: "Loaded private keys",
+			Action: func() error {
+				var err error
+				keys, err = client.ListPrivateKeys()
+				return err
+			},
+		},
+	})
+	if err != nil {
+		ui.Error("Failed to load private keys")
+		return nil, fmt.Errorf("failed to list private keys: %w", err)
+	}
+
+	options := []struct{ Key, Display string }{
+		{Key: serverKeyGenerate, Display: "+ Generate a new key"},
+		{Key: serverKeyFile, Display: "+ Upload a key from disk"},
+	}
+	for _, k := range keys {
+		options = append(options, struct{ Key, Display string }{k.UUID, k.Name})
+	}
+
+	choice, err := ui.SelectWithKeysOrdered("Private key:", options)
+	if err != nil {
+		return nil, err
+	}
+
+	switch choice {
+	case serverKeyGenerate:
+		return generateServerKey(client, serverName)
+	case serverKeyFile:
+		home, _ := os.UserHomeDir()
+		path, err := ui.InputWithDefault("Private key path:", filepath.Join(home, ".ssh", "id_ed25519"))
+		if err != nil {
+			return nil, err
+		}
+		return uploadServerKey(client, serverName, path)
+	}
+
+	for i := range keys {
+		if keys[i].UUID == choice {
+			return &keys[i], nil
+		}
+	}
+	return nil, fmt.Errorf("private key %s not found", choice)
+}
+
+// uploadServerKey reads a private key from disk and stores it in Coolify
+func uploadServerKey(client *api.Client, serverName, path string) (*api.PrivateKey, error) {
+	if strings.HasPrefix(path, "~/") {
+		home, _ := os.UserHomeDir()
+		path = filepath.Join(home, path[2:])
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read private key: %w", err)
+	}
+	return createServerKey(client, serverName, filepath.Base(path), string(data))
+}
+
+// generateServerKey creates an ed25519 key pair with ssh-keygen and uploads it to Coolify
+func generateServerKey(client *api.Client, serverName string) (*api.PrivateKey, error) {
+	if _, err := exec.LookPath("ssh-keygen"); err != nil {
+		ui.Error("ssh-keygen not found")
+		ui.Dim("Install OpenSSH or upload an existing key with --key")
+		return nil, fmt.Errorf("ssh-keygen not found in PATH")
+	}
+
+	dir, err := os.MkdirTemp("", "cdp-key-")
+	if err != nil {
+		return nil, err
+	}
+	defer os.RemoveAll(dir)
+
+	keyPath := filepath.Join(dir, "id_ed25519")
+	comment := fmt.Sprintf("%s-%s", execName(), serverName)
+
+	err = ui.RunTasks([]ui.Task{
+		{
+			Name:         "generate-key",
+			ActiveName:   "Generating SSH key...",
+			CompleteName: "Generated SSH key",
+			Action: func() error {
+				out, err := exec.Command("ssh-keygen", "-t", "ed25519", "-N", "", "-C", comment, "-f", keyPath).CombinedOutput()
+				if err != nil {
+					return fmt.Errorf("%w: %s", err, strings.TrimSpace(string(out)))
+				}
+				return nil
+			},
+		},
+	})
+	if err != nil {
+		ui.Error("Failed to generate SSH key")
+		return nil, fmt.Errorf("failed to generate key: %w", err)
+	}
+
+	privateKey, err := os.ReadFile(keyPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read generated key: %w", err)
+	}
+	publicKey, err := os.ReadFile(keyPath + ".pub")
+	if err != nil {
+		return nil, fmt.Errorf("failed to read generated key: %w", err)
+	}
+
+	key, err := createServerKey(client, serverName, comment, string(privateKey))
+	if err != nil {
+		return nil, err
+	}
+	// Coolify may not echo the public key back; we know it locally
+	if key.PublicKey == "" {
+		key.PublicKey = string(publicKey)
+	}
+	return key, nil
+}
+
+func createServerKey(client *api.Client, serverName, keyName, privateKey string) (*api.PrivateKey, error) {
+	var key *api.PrivateKey
+	err := ui.RunTasks([]ui.Task{
+		{
+			Name:         "upload-key",
+			ActiveName:   "Uploading private key...",
+			CompleteName: "Uploaded private key",
+			Action: func() error {
+				var err error
+				key, err = client.CreatePrivateKey(keyName, fmt.Sprintf("Key for %s", serverName), privateKey)
+				return err
+			},
+		},
+	})
+	if err != nil {
+		ui.Error("Failed to upload private key")
+		return nil, fmt.Errorf("failed to create private key: %w", err)
+	}
+	if key.Name == "" {
+		key.Name = keyName
+	}
+	return key, nil
+}
+
+// waitForServerValidation triggers validation and polls until the server is
+// usable. The flags Coolify reports are only trusted once the validation
+// has recorded a result, as until then they are left from the last check.
+func waitForServerValidation(client *api.Client, serverUUID string) (*api.Server, error) {
+	var server *api.Server
+	err := ui.RunTasks([]ui.Task{
+		{
+			Name:         "validate-server",
+			ActiveName:   "Validating server connection...",
+			CompleteName: "Server is reachable and usable",
+			Action: func() error {
+				before, err := client.GetServer(serverUUID)
+				if err != nil {
+					return fmt.Errorf("failed to fetch server: %w", err)
+				}
+				if err := client.ValidateServer(serverUUID); err != nil {
+					return fmt.Errorf("failed to start validation: %w", err)
+				}
+
+				deadline := time.Now().Add(serverValidateTimeout)
+				for {
+					server, err = client.GetServer(serverUUID)
+					if err != nil {
+						return fmt.Errorf("failed to fetch server: %w", err)
+					}
+					if validatedSince(before, server) && server.Reachable() && server.Usable() {
+						return nil
+					}
+					if time.Now().After(deadline) {
+						if !validatedSince(before, server) {
+							return fmt.Errorf("validation didn't finish within %s", serverValidateTimeout)
+						}
+						return fmt.Errorf("server not usable after %s", serverValidateTimeout)
+					}
+					time.Sleep(serverValidateInterval)
+				}
+			},
+		},
+	})
+	return server, err
+}
+
+// validatedSince reports whether a validation has recorded its result on
+// server since before was fetched: its timestamps moved on, or its flags
+// changed
+func validatedSince(before, server *api.Server) bool {
+	if server.UpdatedAt != before.UpdatedAt {
+		return true
+	}
+	if server.Settings != nil && before.Settings != nil && server.Settings.UpdatedAt != before.Settings.UpdatedAt {
+		return true
+	}
+	return server.Reachable() != before.Reachable() || server.Usable() != before.Usable()
+}
+
+// serverUsage collects the usage data shown by 'servers top'
+type serverUsage struct {
+	server     api.Server
+	metrics    *api.ResourceMetrics
+	running    int
+	containers int
+}
+
+func runServersTop(cmd *cobra.Command, args []string) error {
+	if err := checkLogin(); err != nil {
+		return err
+	}
+
+	globalCfg, err := config.LoadGlobal()
+	if err != nil {
+		return fmt.Errorf("failed to load config: %w", err)
+	}
+	client := api.NewClient(globalCfg.CoolifyURL, globalCfg.CoolifyToken)
+
+	targetUUID := ""
+	if projectCfg, err := config.LoadProject(); err == nil && projectCfg != nil {
+		targetUUID = projectCfg.ServerUUID
+	}
+
+	var usage []serverUsage
+	err = ui.RunTasks([]ui.Task{
+		{
+			Name:         "server-usage",
+			ActiveName:   "Fetching server usage...",
+			CompleteName: "Fetched server usage",
+			Action: func() error {
+				servers, err := client.ListServers()
+				if err != nil {
+					return err
+				}
+				for _, s := range servers {
+					u := serverUsage{server: s}
+					// Metrics and resources are best-effort: Sentinel may be disabled
+					if m, err := client.GetServerMetrics(s.UUID); err == nil {
+						u.metrics = m
+					}
+					if resources, err := client.ListServerResources(s.UUID); err == nil {
+						u.containers = len(resources)
+						for _, r := range resources {
+							if strings.HasPrefix(strings.ToLower(r.Status), "running") {
+								u.running++
+							}
+						}
+					}
+					usage = append(usage, u)
+				}
+				return nil
+			},
+		},
+	})
+	if err != nil {
+		ui.Error("Failed to fetch servers")
+		return fmt.Errorf("failed to list servers: %w", err)
+	}
+
+	if len(usage) == 0 {
+		ui.Warning("No servers found")
+		return nil
+	}
+
+	rows := [][]string{}
+	var lowDisk []serverUsage
+	for _, u := range usage {
+		name := u.server.Name
+		if u.server.UUID == targetUUID {
+			name += " *"
+		}
+
+		cpu, memory, disk := "-", "-", "-"
+		if u.metrics != nil {
+			cpu = fmt.Sprintf("%.1f%%", u.metrics.CPUPercent)
+			memory = fmt.Sprintf("%.1f%%", u.metrics.MemoryPercent)
+			disk = formatDiskUsage(u.metrics)
+			if u.metrics.DiskPercent >= deploy.DiskWarnPercent {
+				disk = ui.RedStyle.Render(disk)
+				lowDisk = append(lowDisk, u)
+			}
+		}
+
+		rows = append(rows, []string{
+			name,
+			u.server.IP,
+			cpu,
+			memory,
+			disk,
+			fmt.Sprintf("%d/%d", u.running, u.containers),
+		})
+	}
+
+	ui.Spacer()
+	ui.Table([]string{"Server", "IP", "CPU", "Memory", "Disk", "Running"}, rows)
+	if targetUUID != "" {
+		ui.Dim("* target server for this project")
+	}
+
+	for _, u := range lowDisk {
+		msg := fmt.Sprintf("%s is low on disk (%.0f%% used)", u.server.Name, u.metrics.DiskPercent)
+		if u.server.UUID == targetUUID {
+			msg += " - builds for this project may fail"
+		}
+		ui.Warning(msg)
+	}
+	if len(lowDisk) > 0 {
+		ui.Dim("Free space with 'docker system prune' on the server or Coolify's cleanup settings")
+	}
+
+	return nil
+}
+
+// formatDiskUsage renders disk usage, including sizes when the API reports them
+func formatDiskUsage(m *api.ResourceMetrics) string {
+	if m.DiskTotalGB > 0 {
+		return fmt.Sprintf("%.0f%% (%.0f / %.0f GB)", m.DiskPercent, m.DiskUsedGB, m.DiskTotalGB)
+	}
+	return fmt.Sprintf("%.1f%%", m.DiskPercent)
+}
+
+func runServersLs(cmd *cobra.Command, args []string) error {
+	if err := checkLogin(); err != nil {
+		return err
+	}
+
+	globalCfg, err := config.LoadGlobal()
+	if err != nil {
+		return fmt.Errorf("failed to load config: %w", err)
+	}
+	client := api.NewClient(globalCfg.CoolifyURL, globalCfg.CoolifyToken)
+
+	var servers []api.Server
+	err = ui.RunTasks([]ui.Task{
+		{
+			Name:         "list-servers",
+			ActiveName:   "Fetching servers...",
+			CompleteName: "Fetched servers",
+			Action: func() error {
+				var err error
+				servers, err = client.ListServers()
+				return err
+			},
+		},
+	})
+	if err != nil {
+		ui.Error("Failed to fetch servers")
+		return fmt.Errorf("failed to list servers: %w", err)
+	}
+	if len(servers) == 0 {
+		ui.Warning("No servers found")
+		ui.Dim(fmt.Sprintf("Run '%s servers add' to register one", execName()))
+		return nil
+	}
+
+	targetUUID := ""
+	if projectCfg, err := config.LoadProject(); err == nil && projectCfg != nil {
+		targetUUID = projectCfg.ServerUUID
+	}
+
+	rows := [][]string{}
+	unusable := 0
+	for _, s := range servers {
+		name := s.Name
+		if s.UUID == targetUUID {
+			name += " (linked)"
+		}
+		if !s.Usable() {
+			unusable++
+		}
+		rows = append(rows, []string{
+			name,
+			net.JoinHostPort(s.IP, strconv.Itoa(s.Port)),
+			yesNo(s.Reachable()),
+			yesNo(s.Usable()),
+			s.UUID,
+		})
+	}
+
+	ui.Spacer()
+	ui.Table([]string{"Server", "Address", "Reachable", "Usable", "UUID"}, rows)
+	if unusable > 0 {
+		ui.Spacer()
+		ui.Warning(fmt.Sprintf("%d server(s) can't be deployed to", unusable))
+		ui.Dim(fmt.Sprintf("Run '%s servers validate SERVER' to check the connection again", execName()))
+	}
+	return nil
+}
+
+func runServersValidate(cmd *cobra.Command, args []string) error {
+	client, server, err := resolveServerArg(args)
+	if err != nil {
+		return err
+	}
+
+	if _, err := waitForServerValidation(client, server.UUID); err != nil {
+		ui.Error(fmt.Sprintf("%s is not ready for deployments", server.Name))
+		ui.Dim("Check SSH access and the validation log in the Coolify dashboard")
+		return err
+	}
+	return nil
+}
+
+func runServersInfo(cmd *cobra.Command, args []string) error {
+	client, server, err := resolveServerArg(args)
+	if err != nil {
+		return err
+	}
+
+	var resources []api.ServerResource
+	var domains []api.ServerDomains
+	err = ui.RunTasks([]ui.Task{
+		{
+			Name:         "server-details",
+			ActiveName:   "Fetching server details...",
+			CompleteName: "Fetched server details",
+			Action: func() error {
+				detail, err := client.GetServer(server.UUID)
+				if err != nil {
+					return err
+				}
+				server = detail
+				if resources, err = client.ListServerResources(server.UUID); err != nil {
+					return err
+				}
+				// Older Coolify versions have no domains endpoint
+				domains, _ = client.GetServerDomains(server.UUID)
+				return nil
+			},
+		},
+	})
+	if err != nil {
+		ui.Error("Failed to fetch server details")
+		return fmt.Errorf("failed to fetch server: %w", err)
+	}
+
+	ui.Spacer()
+	ui.KeyValue("Name", server.Name)
+	ui.KeyValue("UUID", server.UUID)
+	ui.KeyValue("Address", net.JoinHostPort(server.IP, strconv.Itoa(server.Port)))
+	ui.KeyValue("User", server.User)
+	ui.KeyValue("Reachable", yesNo(server.Reachable()))
+	ui.KeyValue("Usable", yesNo(server.Usable()))
+	if server.Metadata != nil && server.Metadata.OS != "" {
+		ui.KeyValue("OS", fmt.Sprintf("%s (%s)", server.Metadata.OS, server.Metadata.Arch))
+	}
+	if server.Settings != nil && server.Settings.WildcardDomain != "" {
+		ui.KeyValue("Wildcard domain", server.Settings.WildcardDomain)
+	}
+
+	ui.Spacer()
+	ui.Bold("Resources")
+	rows := [][]string{}
+	for _, r := range resources {
+		rows = append(rows, []string{r.Name, r.Type, r.Status})
+	}
+	ui.Table([]string{"Name", "Type", "Status"}, rows)
+
+	if len(domains) > 0 {
+		ui.Spacer()
+		ui.Bold("Domains")
+		for _, d := range domains {
+			for _, domain := range d.Domains {
+				ui.KeyValue(domain, d.IP)
+			}
+		}
+	}
+
+	if !server.Usable() {
+		ui.Spacer()
+		ui.Warning("Coolify can't deploy to this server")
+		ui.Dim(fmt.Sprintf("Run '%s servers validate %s' to check the connection again", execName(), server.Name))
+	}
+	return nil
+}
+
+// resolveServerArg returns a client and the server named by args[0], or
+// the linked project's server when no argument is given
+func resolveServerArg(args []string) (*api.Client, *api.Server, error) {
+	if err := checkLogin(); err != nil {
+		return nil, nil, err
+	}
+
+	globalCfg, err := config.LoadGlobal()
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to load config: %w", err)
+	}
+	client := api.NewClient(globalCfg.CoolifyURL, globalCfg.CoolifyToken)
+
+	ref := ""
+	if len(args) > 0 {
+		ref = args[0]
+	} else if projectCfg, err := config.LoadProject(); err == nil && projectCfg != nil {
+		ref = projectCfg.ServerUUID
+	}
+	if ref == "" {
+		ui.Error("No server given and none linked")
+		ui.Dim(fmt.Sprintf("Run '%s servers ls' to see server names", execName()))
+		return nil, nil, fmt.Errorf("no server")
+	}
+
+	servers, err := client.ListServers()
+	if err != nil {
+		ui.Error("Failed to fetch servers")
+		return nil, nil, fmt.Errorf("failed to list servers: %w", err)
+	}
+	server := findServer(servers, ref)
+	if server == nil {
+		ui.Error(fmt.Sprintf("No server named %q", ref))
+		return nil, nil, fmt.Errorf("server not found")
+	}
+	return client, server, nil
+}
+
+// yesNo renders a boolean for tables
+func yesNo(b bool) string {
+	if b {
+		return "yes"
+	}
+	return "no"
+}
@@ -0,0 +1,205 @@
+package cmd
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/dropalltables/cdp/internal/ui"
+	"github.com/spf13/cobra"
+)
+
+// appToggle is an on/off application setting 'cdp settings' can change
+type appToggle struct {
+	key   string // name on the command line, e.g. "force_https"
+	field string // field sent to Coolify's update endpoint
+	// read is the field Coolify reports the value under, when it differs
+	// from field
+	read        string
+	description string
+}
+
+// readField returns the field the toggle's value is reported under
+func (t appToggle) readField() string {
+	if t.read != "" {
+		return t.read
+	}
+	return t.field
+}
+
+var appToggles = []appToggle{
+	{key: "auto_deploy", field: "is_auto_deploy_enabled", description: "Deploy automatically when the branch is pushed"},
+	{key: "preview_deployments", field: "is_preview_deployments_enabled", description: "Deploy a preview for each pull request"},
+	{key: "force_https", field: "is_force_https_enabled", description: "Redirect HTTP requests to HTTPS"},
+	{key: "gzip", field: "is_gzip_enabled", description: "Compress responses with gzip"},
+	{key: "build_server", field: "use_build_server", read: "is_build_server_enabled", description: "Build on a dedicated build server"},
+}
+
+var settingsCmd = &cobra.Command{
+	Use:   "settings",
+	Short: "Show or toggle application settings in Coolify",
+	Long: `Show or change on/off settings of the linked application in Coolify.
+Unlike 'cdp config', these are stored in Coolify only, not in cdp.json.
+
+Settings: ` + strings.Join(appToggleKeys(), ", "),
+	Args: cobra.NoArgs,
+	RunE: runSettingsGet,
+}
+
+var settingsGetCmd = &cobra.Command{
+	Use:       "get [KEY]",
+	Short:     "Show application settings",
+	Long:      "Print whether a setting is on or off, or every setting when no KEY is given.",
+	Args:      cobra.MaximumNArgs(1),
+	ValidArgs: appToggleKeys(),
+	RunE:      runSettingsGet,
+}
+
+var settingsSetCmd = &cobra.Command{
+	Use:   "set KEY on|off",
+	Short: "Turn an application setting on or off",
+	Long: `Turn an on/off setting of the linked application on or off in Coolify.
+
+Settings: ` + strings.Join(appToggleKeys(), ", "),
+	Example: `  cdp settings set force_https on
+  cdp settings set auto_deploy off`,
+	Args: cobra.ExactArgs(2),
+	ValidArgsFunction: func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		if len(args) == 0 {
+			return appToggleKeys(), cobra.ShellCompDirectiveNoFileComp
+		}
+		if len(args) == 1 {
+			return []string{"on", "off"}, cobra.ShellCompDirectiveNoFileComp
+		}
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	},
+	RunE: runSettingsSet,
+}
+
+func init() {
+	rootCmd.AddCommand(settingsCmd)
+	settingsCmd.AddCommand(settingsGetCmd)
+	settingsCmd.AddCommand(settingsSetCmd)
+}
+
+func appToggleKeys() []string {
+	keys := make([]string, len(appToggles))
+	for i, t := range appToggles {
+		keys[i] = t.key
+	}
+	return keys
+}
+
+// findAppToggle returns the toggle for key, printing the valid keys when
+// there is none
+func findAppToggle(key string) (*appToggle, error) {
+	for i := range appToggles {
+		if appToggles[i].key == key {
+			return &appToggles[i], nil
+		}
+	}
+	ui.Error(fmt.Sprintf("Unknown setting %q", key))
+	ui.Dim("  Settings: " + strings.Join(appToggleKeys(), ", "))
+	return nil, fmt.Errorf("unknown setting %q", key)
+}
+
+// toggleValue returns "on" or "off" for the toggle in app, or "unknown"
+// when Coolify doesn't report it. Some versions nest the application's
+// settings under "settings".
+func toggleValue(app map[string]interface{}, t appToggle) string {
+	v, ok := app[t.readField()]
+	if !ok {
+		if nested, isMap := app["settings"].(map[string]interface{}); isMap {
+			v, ok = nested[t.readField()]
+		}
+	}
+	if !ok {
+		return "unknown"
+	}
+	switch v {
+	case true, float64(1), "1", "true":
+		return "on"
+	case false, float64(0), "0", "false":
+		return "off"
+	}
+	return "unknown"
+}
+
+// parseToggle accepts the usual spellings of on and off
+func parseToggle(value string) (bool, error) {
+	switch strings.ToLower(strings.TrimSpace(value)) {
+	case "on", "true", "yes", "1", "enable", "enabled":
+		return true, nil
+	case "off", "false", "no", "0", "disable", "disabled":
+		return false, nil
+	}
+	return false, fmt.Errorf("value must be on or off, got %q", value)
+}
+
+func runSettingsGet(cmd *cobra.Command, args []string) error {
+	var toggle *appToggle
+	if len(args) == 1 {
+		var err error
+		if toggle, err = findAppToggle(args[0]); err != nil {
+			return err
+		}
+	}
+
+	appUUID, client, err := getAppUUID()
+	if err != nil {
+		return err
+	}
+	app, err := client.GetApplicationSettings(appUUID)
+	if err != nil {
+		ui.Error("Failed to fetch application settings")
+		return err
+	}
+
+	if toggle != nil {
+		fmt.Println(toggleValue(app, *toggle))
+		return nil
+	}
+
+	rows := make([][]string, len(appToggles))
+	for i, t := range appToggles {
+		rows[i] = []string{t.key, toggleValue(app, t), t.description}
+	}
+	ui.Table([]string{"Setting", "Value", "Description"}, rows)
+	return nil
+}
+
+func runSettingsSet(cmd *cobra.Command, args []string) error {
+	toggle, err := findAppToggle(args[0])
+	if err != nil {
+		return err
+	}
+	enabled, err := parseToggle(args[1])
+	if err != nil {
+		ui.Error(err.Error())
+		return err
+	}
+
+	appUUID, client, err := getAppUUID()
+	if err != nil {
+		return err
+	}
+
+	state := "off"
+	if enabled {
+		state = "on"
+	}
+	err = ui.RunTasks([]ui.Task{
+		{
+			Name:         "update-setting",
+			ActiveName:   fmt.Sprintf("Turning %s %s...", toggle.key, state),
+			CompleteName: fmt.Sprintf("Turned %s %s", toggle.key, state),
+			Action: func() error {
+				return client.UpdateApplication(appUUID, map[string]interface{}{toggle.field: enabled})
+			},
+		},
+	})
+	if err != nil {
+		ui.Error(fmt.Sprintf("Failed to update %s", toggle.key))
+		return err
+	}
+	return nil
+}
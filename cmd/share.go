@@ -0,0 +1,189 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/dropalltables/cdp/internal/api"
+	"github.com/dropalltables/cdp/internal/config"
+	"github.com/dropalltables/cdp/internal/share"
+	"github.com/dropalltables/cdp/internal/snapshot"
+	"github.com/dropalltables/cdp/internal/ui"
+	"github.com/spf13/cobra"
+)
+
+// shareKeyEnv supplies the share key to 'link --from' without a prompt
+const shareKeyEnv = "CDP_SHARE_KEY"
+
+var shareCmd = &cobra.Command{
+	Use:   "share",
+	Short: "Make an encrypted link for teammates to link this project",
+	Long: `Print an encrypted link holding this directory's linkage: the Coolify URL
+and the project, environment and app it deploys to. Tokens are never
+included; teammates use their own login.
+
+The link is encrypted with a random key printed next to it. Send the two
+through different channels. Teammates run 'cdp link --from LINK' in their
+checkout and enter the key (or set ` + shareKeyEnv + `).`,
+	Args: cobra.NoArgs,
+	RunE: runShare,
+}
+
+func init() {
+	rootCmd.AddCommand(shareCmd)
+}
+
+func runShare(cmd *cobra.Command, args []string) error {
+	projectCfg, err := config.LoadProject()
+	if err != nil {
+		return fmt.Errorf("failed to load project config: %w", err)
+	}
+	if projectCfg == nil || (projectCfg.AppUUID == "" && projectCfg.ServiceUUID == "") {
+		ui.Error("This directory isn't linked to an app")
+		ui.Dim(fmt.Sprintf("Run '%s' or '%s link' first", execName(), execName()))
+		return fmt.Errorf("not linked")
+	}
+	globalCfg, err := config.LoadGlobal()
+	if err != nil {
+		return fmt.Errorf("failed to load configuration: %w", err)
+	}
+
+	link := &share.Link{
+		CoolifyURL:      globalCfg.CoolifyURL,
+		Name:            projectCfg.Name,
+		DeployMethod:    projectCfg.DeployMethod,
+		ProjectUUID:     projectCfg.ProjectUUID,
+		ServerUUID:      projectCfg.ServerUUID,
+		EnvironmentUUID: projectCfg.EnvironmentUUID,
+		AppUUID:         projectCfg.AppUUID,
+		ServiceUUID:     projectCfg.ServiceUUID,
+		ComposeFile:     projectCfg.ComposeFile,
+		GitHubRepo:      projectCfg.GitHubRepo,
+		Branch:          projectCfg.Branch,
+		BaseDirectory:   projectCfg.BaseDirectory,
+		DockerImage:     projectCfg.DockerImage,
+	}
+
+	key, err := share.NewKey()
+	if err != nil {
+		return err
+	}
+	blob, err := share.Encode(link, key)
+	if err != nil {
+		ui.Error("Failed to encrypt the link")
+		return err
+	}
+
+	ui.KeyValue("Link", blob)
+	ui.KeyValue("Key", key)
+	ui.Spacer()
+	ui.NextSteps([]string{
+		"Send the link and the key through different channels",
+		fmt.Sprintf("Teammates run '%s link --from LINK' in their checkout", execName()),
+	})
+	return nil
+}
+
+// linkFromShare links the current directory from a 'cdp share' link
+func linkFromShare(client *api.Client, globalCfg *config.GlobalConfig, blob string) error {
+	key := os.Getenv(shareKeyEnv)
+	if key == "" {
+		var err error
+		key, err = ui.Password("Share key")
+		if err != nil {
+			return err
+		}
+	}
+
+	link, err := share.Decode(blob, key)
+	if err != nil {
+		switch {
+		case errors.Is(err, share.ErrNotShareLink):
+			ui.Error("That isn't a cdp share link")
+		case errors.Is(err, snapshot.ErrBadPassphrase):
+			ui.Error("Wrong key, or the link was cut off when copied")
+		default:
+			ui.Error("Failed to read the share link")
+		}
+		return err
+	}
+
+	if !sameInstance(link.CoolifyURL, globalCfg.CoolifyURL) {
+		ui.Error(fmt.Sprintf("The link is for %s, but you're logged in to %s", link.CoolifyURL, globalCfg.CoolifyURL))
+		ui.Dim(fmt.Sprintf("Run '%s login' with that instance first", execName()))
+		return fmt.Errorf("share link is for another Coolify instance")
+	}
+
+	projectCfg := &config.ProjectConfig{
+		Name:            link.Name,
+		DeployMethod:    link.DeployMethod,
+		ProjectUUID:     link.ProjectUUID,
+		ServerUUID:      link.ServerUUID,
+		EnvironmentUUID: link.EnvironmentUUID,
+		AppUUID:         link.AppUUID,
+		ServiceUUID:     link.ServiceUUID,
+		ComposeFile:     link.ComposeFile,
+		GitHubRepo:      link.GitHubRepo,
+		Branch:          link.Branch,
+		BaseDirectory:   link.BaseDirectory,
+		DockerImage:     link.DockerImage,
+	}
+
+	// Checks the teammate can see the app, and fills in its build settings
+	var app *api.Application
+	err = ui.RunTasks([]ui.Task{
+		{
+			Name:         "check-app",
+			ActiveName:   "Checking access to the app...",
+			CompleteName: "Checked access to the app",
+			Action: func() error {
+				if link.AppUUID == "" {
+					return nil
+				}
+				var err error
+				app, err = client.GetApplication(link.AppUUID)
+				if api.IsNotFound(err) {
+					return fmt.Errorf("the app doesn't exist or your token's team can't see it")
+				}
+				return err
+			},
+		},
+		{
+			Name:         "save-config",
+			ActiveName:   "Saving configuration...",
+			CompleteName: "Project linked successfully",
+			Action: func() error {
+				if app != nil {
+					projectCfg.Framework = app.BuildPack
+					projectCfg.InstallCommand = app.InstallCommand
+					projectCfg.BuildCommand = app.BuildCommand
+					projectCfg.StartCommand = app.StartCommand
+				}
+				return config.SaveProject(projectCfg)
+			},
+		},
+	})
+	if err != nil {
+		ui.Error(err.Error())
+		return err
+	}
+
+	ui.Spacer()
+	if app != nil {
+		ui.KeyValue("Application", app.Name)
+	} else {
+		ui.KeyValue("Project", link.Name)
+	}
+	ui.KeyValue("Deploy method", projectCfg.DeployMethod)
+	return nil
+}
+
+// sameInstance reports whether two Coolify URLs point at the same instance
+func sameInstance(a, b string) bool {
+	normalize := func(u string) string {
+		return strings.ToLower(strings.TrimSuffix(strings.TrimSuffix(strings.TrimSpace(u), "/"), "/api/v1"))
+	}
+	return normalize(a) == normalize(b)
+}
@@ -0,0 +1,267 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/dropalltables/cdp/internal/api"
+	"github.com/dropalltables/cdp/internal/config"
+	"github.com/dropalltables/cdp/internal/deploy"
+	"github.com/dropalltables/cdp/internal/snapshot"
+	"github.com/dropalltables/cdp/internal/ui"
+	"github.com/spf13/cobra"
+)
+
+// snapshotPassphraseEnv supplies the passphrase without a prompt, for scripts
+const snapshotPassphraseEnv = "CDP_SNAPSHOT_PASSPHRASE"
+
+var (
+	// Flags for snapshot command
+	snapshotOutputFlag string
+)
+
+var snapshotCmd = &cobra.Command{
+	Use:   "snapshot",
+	Short: "Export the app's definition to a disaster-recovery archive",
+	Long: `Export everything needed to recreate the linked app on another Coolify
+instance into a single archive: cdp.json, the app definition and domains,
+environment variables (encrypted with a passphrase), scheduled tasks, and
+volume definitions. Volume contents are not included.
+
+Set ` + snapshotPassphraseEnv + ` to skip the passphrase prompt.`,
+	Example: `  cdp snapshot
+  cdp snapshot -o backups/web.cdp.tar.gz`,
+	RunE: runSnapshot,
+}
+
+var restoreCmd = &cobra.Command{
+	Use:   "restore FILE",
+	Short: "Recreate an app from a snapshot on the current Coolify instance",
+	Long: `Replay a snapshot taken with 'cdp snapshot' onto the Coolify instance you
+are logged in to, and link the current directory to the new app.
+
+The app is created but not deployed; run 'cdp' afterwards.`,
+	Args: cobra.ExactArgs(1),
+	RunE: runRestore,
+}
+
+func init() {
+	rootCmd.AddCommand(snapshotCmd)
+	rootCmd.AddCommand(restoreCmd)
+
+	snapshotCmd.Flags().StringVarP(&snapshotOutputFlag, "output", "o", "", "Archive path (default <name>-<date>.cdp.tar.gz)")
+}
+
+func runSnapshot(cmd *cobra.Command, args []string) error {
+	appUUID, client, err := getAppUUID()
+	if err != nil {
+		return err
+	}
+	projectCfg, err := config.LoadProject()
+	if err != nil {
+		return fmt.Errorf("failed to load project config: %w", err)
+	}
+	globalCfg, err := config.LoadGlobal()
+	if err != nil {
+		return fmt.Errorf("failed to load config: %w", err)
+	}
+
+	passphrase, err := snapshotPassphrase(true)
+	if err != nil {
+		return err
+	}
+
+	snap := snapshot.New(projectCfg, globalCfg.CoolifyURL)
+	var envVars []api.EnvVar
+	volumesSkipped := false
+
+	err = ui.RunTasks([]ui.Task{
+		{
+			Name:         "export-app",
+			ActiveName:   "Exporting application...",
+			CompleteName: "Exported application",
+			Action: func() error {
+				settings, err := client.GetApplicationSettings(appUUID)
+				if err != nil {
+					return err
+				}
+				snap.SetApplication(settings)
+				return nil
+			},
+		},
+		{
+			Name:         "export-env",
+			ActiveName:   "Exporting environment variables...",
+			CompleteName: "Exported environment variables",
+			Action: func() error {
+				var err error
+				envVars, err = client.GetApplicationEnvVars(appUUID)
+				if err != nil {
+					return err
+				}
+				return snap.SetEnv(envVars, passphrase)
+			},
+		},
+		{
+			Name:         "export-cron",
+			ActiveName:   "Exporting scheduled tasks...",
+			CompleteName: "Exported scheduled tasks",
+			Action: func() error {
+				var err error
+				snap.ScheduledTasks, err = client.ListScheduledTasks(appUUID)
+				return err
+			},
+		},
+		{
+			Name:         "export-volumes",
+			ActiveName:   "Exporting volumes...",
+			CompleteName: "Exported volumes",
+			Action: func() error {
+				var err error
+				snap.Volumes, err = client.ListStorages(appUUID)
+				// Older Coolify versions have no storage endpoint
+				if api.IsNotFound(err) {
+					volumesSkipped = true
+					return nil
+				}
+				return err
+			},
+		},
+	})
+	if err != nil {
+		ui.Error("Failed to export application")
+		return fmt.Errorf("failed to take snapshot: %w", err)
+	}
+
+	path := snapshotOutputFlag
+	if path == "" {
+		path = fmt.Sprintf("%s-%s.cdp.tar.gz", projectCfg.Name, time.Now().Format("20060102-150405"))
+	}
+	if err := snap.Write(path); err != nil {
+		ui.Error("Failed to write snapshot")
+		return err
+	}
+
+	ui.Spacer()
+	ui.Success(fmt.Sprintf("Saved snapshot to %s", path))
+	ui.KeyValue("Environment variables", fmt.Sprintf("%d (encrypted)", len(envVars)))
+	ui.KeyValue("Scheduled tasks", fmt.Sprintf("%d", len(snap.ScheduledTasks)))
+	if volumesSkipped {
+		ui.KeyValue("Volumes", "not supported by this Coolify version")
+	} else {
+		ui.KeyValue("Volumes", fmt.Sprintf("%d (definitions only)", len(snap.Volumes)))
+	}
+	ui.Spacer()
+	ui.Dim("Keep the passphrase safe; it is needed to restore the environment variables")
+	return nil
+}
+
+func runRestore(cmd *cobra.Command, args []string) error {
+	if err := checkLogin(); err != nil {
+		return err
+	}
+
+	projectCfg, err := config.LoadProject()
+	if err != nil {
+		return fmt.Errorf("failed to load project config: %w", err)
+	}
+	if projectCfg != nil && (projectCfg.AppUUID != "" || projectCfg.ServiceUUID != "") {
+		ui.Error("This directory is already linked to an app")
+		ui.Dim("Restore into a fresh clone or directory")
+		return fmt.Errorf("already linked")
+	}
+
+	snap, err := snapshot.Read(args[0])
+	if err != nil {
+		ui.Error(fmt.Sprintf("Cannot read %s", args[0]))
+		return err
+	}
+
+	ui.Spacer()
+	ui.KeyValue("App", snap.Manifest.AppName)
+	ui.KeyValue("Taken", formatTimestamp(snap.Manifest.CreatedAt))
+	ui.KeyValue("From", snap.Manifest.CoolifyURL)
+	ui.Spacer()
+
+	if snap.Project.DeployMethod == config.DeployMethodCompose {
+		ui.Error("Compose services can't be restored from a snapshot")
+		ui.Dim(fmt.Sprintf("Run '%s compose import' instead", execName()))
+		return fmt.Errorf("unsupported deploy method %q", snap.Project.DeployMethod)
+	}
+
+	var envVars []api.EnvVar
+	for {
+		passphrase, err := snapshotPassphrase(false)
+		if err != nil {
+			return err
+		}
+		envVars, err = snap.DecryptEnv(passphrase)
+		if err == nil {
+			break
+		}
+		if !errors.Is(err, snapshot.ErrBadPassphrase) || os.Getenv(snapshotPassphraseEnv) != "" {
+			ui.Error("Failed to decrypt environment variables")
+			return err
+		}
+		ui.Warning("Wrong passphrase, try again")
+	}
+
+	globalCfg, err := config.LoadGlobal()
+	if err != nil {
+		return fmt.Errorf("failed to load config: %w", err)
+	}
+	client := api.NewClient(globalCfg.CoolifyURL, globalCfg.CoolifyToken)
+
+	_, result, err := deploy.RestoreSnapshot(client, globalCfg, snap, envVars)
+	if err != nil {
+		if strings.Contains(err.Error(), "interrupted") {
+			return nil
+		}
+		return err
+	}
+
+	result.Results.Render()
+
+	ui.Spacer()
+	ui.Success("Restored app from snapshot")
+	ui.NextSteps([]string{
+		fmt.Sprintf("Run '%s' to deploy it", execName()),
+		"Point your DNS records at the new server",
+	})
+	return result.Results.Err()
+}
+
+// snapshotPassphrase reads the passphrase from the environment or a prompt;
+// confirm asks twice, for new snapshots
+func snapshotPassphrase(confirm bool) (string, error) {
+	return readPassphrase(snapshotPassphraseEnv, "Snapshot passphrase", confirm)
+}
+
+// readPassphrase reads a passphrase from the env variable, or prompts for
+// it with label; confirm asks for it twice
+func readPassphrase(env, label string, confirm bool) (string, error) {
+	if p := os.Getenv(env); p != "" {
+		return p, nil
+	}
+	passphrase, err := ui.Password(label)
+	if err != nil {
+		return "", err
+	}
+	if passphrase == "" {
+		return "", fmt.Errorf("a passphrase is required")
+	}
+	if confirm {
+		again, err := ui.Password("Confirm passphrase")
+		if err != nil {
+			return "", err
+		}
+		if again != passphrase {
+			ui.Error("Passphrases don't match")
+			return "", fmt.Errorf("passphrases don't match")
+		}
+	}
+	return passphrase, nil
+}
@@ -0,0 +1,163 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"github.com/dropalltables/cdp/internal/git"
+	"github.com/dropalltables/cdp/internal/ui"
+	"github.com/dropalltables/cdp/internal/version"
+	"github.com/spf13/cobra"
+)
+
+const (
+	releaseOwner = "dropalltables"
+	releaseRepo  = "cdp"
+	modulePath   = "github.com/dropalltables/cdp"
+)
+
+// Install methods
+const (
+	installHomebrew = "homebrew"
+	installScoop    = "scoop"
+	installGo       = "go"
+	installManual   = "manual"
+)
+
+var upgradeCmd = &cobra.Command{
+	Use:   "upgrade",
+	Short: "Upgrade cdp to the latest version",
+	Long: `Upgrade cdp to the latest release.
+
+If cdp was installed with Homebrew or Scoop, the package manager is used
+instead of replacing the binary in place.`,
+	RunE: runUpgrade,
+}
+
+func init() {
+	rootCmd.AddCommand(upgradeCmd)
+}
+
+func runUpgrade(cmd *cobra.Command, args []string) error {
+	release, err := fetchLatestRelease()
+	if err != nil {
+		ui.Error("Failed to check for updates")
+		return err
+	}
+
+	if !version.IsNewer(release.TagName, Version) {
+		ui.Success(fmt.Sprintf("Already up to date (%s)", Version))
+		return nil
+	}
+
+	ui.KeyValue("Current", Version)
+	ui.KeyValue("Latest", release.TagName)
+	ui.Spacer()
+
+	method := detectInstallMethod()
+	var upgrade *exec.Cmd
+	switch method {
+	case installHomebrew:
+		upgrade = exec.Command("brew", "upgrade", "cdp")
+	case installScoop:
+		upgrade = exec.Command("scoop", "update", "cdp")
+	case installGo:
+		upgrade = exec.Command("go", "install", modulePath+"@"+release.TagName)
+	default:
+		ui.Warning("Unable to upgrade this installation automatically")
+		ui.NextSteps([]string{upgradeInstruction(method)})
+		return nil
+	}
+
+	// Package managers own the binary, so delegate rather than overwrite it
+	if _, err := exec.LookPath(upgrade.Path); err != nil {
+		ui.Warning(fmt.Sprintf("%s not found in PATH", filepath.Base(upgrade.Path)))
+		ui.NextSteps([]string{upgradeInstruction(method)})
+		return nil
+	}
+
+	upgrade.Stdout = os.Stdout
+	upgrade.Stderr = os.Stderr
+	ui.Dim(strings.Join(upgrade.Args, " "))
+	if err := upgrade.Run(); err != nil {
+		ui.Error("Upgrade failed")
+		return fmt.Errorf("upgrade failed: %w", err)
+	}
+
+	ui.Spacer()
+	ui.Success(fmt.Sprintf("Upgraded to %s", release.TagName))
+	return nil
+}
+
+// fetchLatestRelease returns the latest cdp release from GitHub
+func fetchLatestRelease() (*git.Release, error) {
+	var release *git.Release
+	err := ui.RunTasks([]ui.Task{
+		{
+			Name:         "check-release",
+			ActiveName:   "Checking for updates...",
+			CompleteName: "Checked for updates",
+			Action: func() error {
+				var err error
+				release, err = git.NewGitHubClient("").GetLatestRelease(releaseOwner, releaseRepo)
+				return err
+			},
+		},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch latest release: %w", err)
+	}
+	return release, nil
+}
+
+// detectInstallMethod guesses how cdp was installed from the executable path
+func detectInstallMethod() string {
+	exePath, err := os.Executable()
+	if err != nil {
+		return installManual
+	}
+	if resolved, err := filepath.EvalSymlinks(exePath); err == nil {
+		exePath = resolved
+	}
+	path := strings.ToLower(filepath.ToSlash(exePath))
+
+	switch {
+	case strings.Contains(path, "/cellar/") || strings.Contains(path, "/homebrew/") || strings.Contains(path, "/linuxbrew/"):
+		return installHomebrew
+	case strings.Contains(path, "/scoop/"):
+		return installScoop
+	case strings.Contains(path, "/go/bin/") || isInGoBin(exePath):
+		return installGo
+	default:
+		return installManual
+	}
+}
+
+// isInGoBin reports whether the executable lives in GOBIN or GOPATH/bin
+func isInGoBin(exePath string) bool {
+	dir := filepath.Dir(exePath)
+	if gobin := os.Getenv("GOBIN"); gobin != "" && dir == filepath.Clean(gobin) {
+		return true
+	}
+	if gopath := os.Getenv("GOPATH"); gopath != "" && dir == filepath.Join(gopath, "bin") {
+		return true
+	}
+	return false
+}
+
+// upgradeInstruction returns the command a user should run to upgrade
+func upgradeInstruction(method string) string {
+	switch method {
+	case installHomebrew:
+		return "Run 'brew upgrade cdp'"
+	case installScoop:
+		return "Run 'scoop update cdp'"
+	case installGo:
+		return fmt.Sprintf("Run 'go install %s@latest'", modulePath)
+	default:
+		return fmt.Sprintf("Download the latest release from https://github.com/%s/%s/releases", releaseOwner, releaseRepo)
+	}
+}
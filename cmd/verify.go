@@ -0,0 +1,285 @@
+package cmd
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"time"
+
+	"github.com/dropalltables/cdp/internal/api"
+	"github.com/dropalltables/cdp/internal/config"
+	"github.com/dropalltables/cdp/internal/deploy"
+	"github.com/dropalltables/cdp/internal/detect"
+	"github.com/dropalltables/cdp/internal/ui"
+	"github.com/spf13/cobra"
+)
+
+const (
+	// verifyURLTimeout bounds the wait for the test app to answer, which
+	// includes the proxy issuing its certificate
+	verifyURLTimeout = 3 * time.Minute
+
+	// verifyProjectPrefix names the throwaway projects, so that one left
+	// by an interrupted run is easy to spot
+	verifyProjectPrefix = "cdp-verify-"
+
+	// verifyCleanupTimeout bounds the wait for Coolify to remove the test
+	// app in the background, as a project with resources can't be deleted
+	verifyCleanupTimeout  = 2 * time.Minute
+	verifyCleanupInterval = 2 * time.Second
+)
+
+var (
+	// Flags for verify-instance command
+	verifyServerFlag        string
+	verifyRepoFlag          string
+	verifyBranchFlag        string
+	verifyBaseDirectoryFlag string
+	verifyDomainFlag        string
+	verifyKeepFlag          bool
+)
+
+var verifyInstanceCmd = &cobra.Command{
+	Use:   "verify-instance",
+	Short: "Check a Coolify instance end to end with a throwaway app",
+	Long: `Prove that a Coolify instance and your token can deploy: create a project,
+deploy a small static site from a public repository, check that its URL
+answers, and delete everything again.
+
+The app gets the domain Coolify generates for the server, so the server needs
+a wildcard domain (e.g. sslip.io), or pass --domain.`,
+	Example: `  cdp verify-instance
+  cdp verify-instance --server hetzner-1 --domain verify.example.com`,
+	Args: cobra.NoArgs,
+	RunE: runVerifyInstance,
+}
+
+func init() {
+	rootCmd.AddCommand(verifyInstanceCmd)
+
+	verifyInstanceCmd.Flags().StringVar(&verifyServerFlag, "server", "", "Server to deploy to (default: the first usable one)")
+	verifyInstanceCmd.Flags().StringVar(&verifyRepoFlag, "repo", "https://github.com/coollabsio/coolify-examples", "Public git repository to deploy")
+	verifyInstanceCmd.Flags().StringVar(&verifyBranchFlag, "branch", "v4.x", "Branch of the repository")
+	verifyInstanceCmd.Flags().StringVar(&verifyBaseDirectoryFlag, "base-directory", "/static", "Directory of the static site in the repository")
+	verifyInstanceCmd.Flags().StringVar(&verifyDomainFlag, "domain", "", "Domain for the test app instead of a generated one")
+	verifyInstanceCmd.Flags().BoolVar(&verifyKeepFlag, "keep", false, "Keep the project and app for inspection")
+	_ = verifyInstanceCmd.RegisterFlagCompletionFunc("server", completeServerNames)
+}
+
+func runVerifyInstance(cmd *cobra.Command, args []string) (err error) {
+	if err := checkLogin(); err != nil {
+		return err
+	}
+	globalCfg, err := config.LoadGlobal()
+	if err != nil {
+		return fmt.Errorf("failed to load configuration: %w", err)
+	}
+	client := api.NewClient(globalCfg.CoolifyURL, globalCfg.CoolifyToken)
+
+	domain := ""
+	if verifyDomainFlag != "" {
+		if domain, err = normalizeDomain(verifyDomainFlag); err != nil {
+			ui.Error(err.Error())
+			return err
+		}
+	}
+
+	suffix := make([]byte, 3)
+	if _, err := rand.Read(suffix); err != nil {
+		return err
+	}
+	name := verifyProjectPrefix + hex.EncodeToString(suffix)
+
+	var (
+		coolifyVersion string
+		server         *api.Server
+		project        *api.Project
+		appUUID        string
+		url            string
+		deployTime     time.Duration
+	)
+
+	// Whatever was created is removed, even when a later step fails
+	defer func() {
+		if project == nil || project.UUID == "" {
+			return
+		}
+		ui.Spacer()
+		if verifyKeepFlag {
+			ui.Dim(fmt.Sprintf("Kept project %s for inspection; delete it in Coolify when you're done", name))
+			return
+		}
+		cleanupErr := ui.RunTasks([]ui.Task{
+			{
+				Name:         "cleanup",
+				ActiveName:   "Deleting the test project...",
+				CompleteName: "Deleted the test project",
+				Action: func() error {
+					if appUUID != "" {
+						if err := client.DeleteApplication(appUUID); err != nil && !api.IsNotFound(err) {
+							return fmt.Errorf("failed to delete application: %w", err)
+						}
+					}
+					return deleteVerifyProject(client, project.UUID, appUUID)
+				},
+			},
+		})
+		if cleanupErr != nil {
+			ui.Warning(fmt.Sprintf("Could not delete project %s; remove it in Coolify", name))
+			if err == nil {
+				err = cleanupErr
+			}
+		}
+	}()
+
+	err = ui.RunTasks([]ui.Task{
+		{
+			Name:         "check-api",
+			ActiveName:   "Checking the API...",
+			CompleteName: "API is reachable",
+			Action: func() error {
+				var err error
+				coolifyVersion, err = client.GetVersion()
+				return err
+			},
+		},
+		{
+			Name:         "pick-server",
+			ActiveName:   "Picking a server...",
+			CompleteName: "Picked a server",
+			Action: func() error {
+				servers, err := client.ListServers()
+				if err != nil {
+					return err
+				}
+				if verifyServerFlag != "" {
+					if server = findServer(servers, verifyServerFlag); server == nil {
+						return fmt.Errorf("no server named %q", verifyServerFlag)
+					}
+					if !server.Usable() {
+						return fmt.Errorf("server %s is not usable; run '%s servers validate %s'", server.Name, execName(), server.Name)
+					}
+					return nil
+				}
+				for i := range servers {
+					if servers[i].Usable() {
+						server = &servers[i]
+						return nil
+					}
+				}
+				return fmt.Errorf("no usable server found; run '%s servers ls'", execName())
+			},
+		},
+		{
+			Name:         "create-project",
+			ActiveName:   "Creating a test project...",
+			CompleteName: "Created a test project",
+			Action: func() error {
+				var err error
+				project, err = client.CreateProject(name, "Created by cdp verify-instance; safe to delete")
+				return err
+			},
+		},
+		{
+			Name:         "create-app",
+			ActiveName:   "Creating a test application...",
+			CompleteName: "Created a test application",
+			Action: func() error {
+				resp, err := client.CreatePublicApp(&api.CreatePublicAppRequest{
+					ProjectUUID:     project.UUID,
+					ServerUUID:      server.UUID,
+					EnvironmentName: "production",
+					GitRepository:   verifyRepoFlag,
+					GitBranch:       verifyBranchFlag,
+					BuildPack:       detect.BuildPackStatic,
+					Name:            name,
+					Domains:         domain,
+					PortsExposes:    "80",
+					BaseDirectory:   verifyBaseDirectoryFlag,
+				})
+				if err != nil {
+					return err
+				}
+				appUUID = resp.UUID
+				return nil
+			},
+		},
+		{
+			Name:         "deploy",
+			ActiveName:   "Deploying the test application...",
+			CompleteName: "Deployed the test application",
+			Action: func() error {
+				start := time.Now()
+				resp, err := client.Deploy(appUUID, false, 0)
+				if err != nil {
+					return err
+				}
+				if len(resp.Deployments) == 0 {
+					return fmt.Errorf("no deployment was queued")
+				}
+				if _, err := deploy.WaitForDeployment(client, resp.Deployments[0].DeploymentUUID); err != nil {
+					return err
+				}
+				deployTime = time.Since(start)
+				return nil
+			},
+		},
+		{
+			Name:         "check-url",
+			ActiveName:   "Waiting for the application to answer...",
+			CompleteName: "Application answered",
+			Action: func() error {
+				app, err := client.GetApplication(appUUID)
+				if err != nil {
+					return err
+				}
+				if url = api.PreferredDomain(app.FQDN); url == "" {
+					return fmt.Errorf("the application has no domain; set a wildcard domain on the server or pass --domain")
+				}
+				return deploy.WaitForURL(url, verifyURLTimeout)
+			},
+		},
+	})
+	if err != nil {
+		ui.Error("Instance verification failed")
+		ui.Dim("  " + err.Error())
+		return err
+	}
+
+	ui.Spacer()
+	ui.Success("Coolify instance is working")
+	ui.KeyValue("Coolify", coolifyVersion)
+	ui.KeyValue("Server", server.Name)
+	ui.KeyValue("Deploy time", deployTime.Round(time.Second).String())
+	ui.KeyValue("URL", url)
+	return nil
+}
+
+// deleteVerifyProject deletes the test project once Coolify's background
+// job has removed its app, retrying while the project still has resources
+func deleteVerifyProject(client *api.Client, projectUUID, appUUID string) error {
+	deadline := time.Now().Add(verifyCleanupTimeout)
+	for appUUID != "" {
+		_, err := client.GetApplication(appUUID)
+		if api.IsNotFound(err) {
+			break
+		}
+		if time.Now().After(deadline) {
+			return fmt.Errorf("application still exists after %s", verifyCleanupTimeout)
+		}
+		time.Sleep(verifyCleanupInterval)
+	}
+
+	delay := verifyCleanupInterval
+	for {
+		err := client.DeleteProject(projectUUID)
+		if err == nil || api.IsNotFound(err) {
+			return nil
+		}
+		if time.Now().Add(delay).After(deadline) {
+			return err
+		}
+		time.Sleep(delay)
+		delay *= 2
+	}
+}
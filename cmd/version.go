@@ -3,17 +3,44 @@ package cmd
 import (
 	"fmt"
 
+	"github.com/dropalltables/cdp/internal/ui"
+	"github.com/dropalltables/cdp/internal/version"
 	"github.com/spf13/cobra"
 )
 
+var (
+	// Flags for version command
+	versionCheckFlag bool
+)
+
 var versionCmd = &cobra.Command{
 	Use:   "version",
 	Short: "Show version information",
-	Run: func(cmd *cobra.Command, args []string) {
+	RunE: func(cmd *cobra.Command, args []string) error {
 		fmt.Printf("cdp %s\n", Version)
+		if !versionCheckFlag {
+			return nil
+		}
+
+		release, err := fetchLatestRelease()
+		if err != nil {
+			ui.Warning("Could not check for updates")
+			return err
+		}
+
+		if !version.IsNewer(release.TagName, Version) {
+			ui.Success("You are running the latest version")
+			return nil
+		}
+
+		ui.Info(fmt.Sprintf("A new version is available: %s", release.TagName))
+		ui.NextSteps([]string{upgradeInstruction(detectInstallMethod())})
+		return nil
 	},
 }
 
 func init() {
 	rootCmd.AddCommand(versionCmd)
+
+	versionCmd.Flags().BoolVar(&versionCheckFlag, "check", false, "Check for a newer release")
 }
@@ -0,0 +1,136 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/dropalltables/cdp/internal/config"
+	"github.com/dropalltables/cdp/internal/ui"
+)
+
+// Outcomes of an app in 'deploy --all'
+const (
+	workspaceDeployed = "deployed"
+	workspaceFailed   = "failed"
+	workspaceSkipped  = "skipped"
+)
+
+// runDeployAll deploys every app listed in cdp.workspace.json to target,
+// each after the apps it depends on. An app whose dependency failed is
+// skipped.
+func runDeployAll(target deployEnv) error {
+	if err := checkLogin(); err != nil {
+		return err
+	}
+
+	root, err := os.Getwd()
+	if err != nil {
+		return err
+	}
+	ws, err := config.LoadWorkspace(root)
+	if err != nil {
+		ui.Error(err.Error())
+		return err
+	}
+	if ws == nil || len(ws.Apps) == 0 {
+		ui.Error(fmt.Sprintf("No apps listed in %s", config.WorkspaceConfigFile))
+		ui.Dim(`  Create one in the repository root, e.g. {"apps": [{"path": "apps/web"}, {"path": "apps/api"}]}`)
+		return fmt.Errorf("no workspace found")
+	}
+	order, err := ws.DeployOrder()
+	if err != nil {
+		ui.Error(err.Error())
+		return err
+	}
+
+	// Setup is interactive, so every app must have been deployed once
+	var missing []string
+	for _, app := range order {
+		if !config.ProjectExistsIn(filepath.Join(root, app.Path)) {
+			missing = append(missing, app.Path)
+		}
+	}
+	if len(missing) > 0 {
+		ui.Error("Some apps aren't set up yet")
+		ui.List(missing)
+		ui.NextSteps([]string{
+			fmt.Sprintf("Run '%s' in each of them once, then deploy the workspace again", execName()),
+		})
+		return fmt.Errorf("%d apps aren't set up", len(missing))
+	}
+
+	ui.Spacer()
+	ui.Print("Deploy order:")
+	for i, app := range order {
+		ui.Dim(fmt.Sprintf("  %d. %s (%s)", i+1, app.Name, app.Path))
+	}
+	ui.Spacer()
+	confirmed, err := ui.ConfirmWithOptions(fmt.Sprintf("Deploy %d apps to %s?", len(order), target.Name), ui.ConfirmOptions{Default: true})
+	if err != nil {
+		return err
+	}
+	if !confirmed {
+		return nil
+	}
+
+	// The workspace was confirmed as a whole, so each app's own
+	// confirmation is answered for it
+	assumeYes := ui.AssumeYes
+	ui.AssumeYes = true
+	defer func() { ui.AssumeYes = assumeYes }()
+
+	outcome := map[string]string{}
+	rows := make([][]string, 0, len(order))
+	failed := 0
+	for _, app := range order {
+		status, took, detail := workspaceSkipped, "-", "-"
+		for _, dep := range app.DependsOn {
+			if outcome[dep] != workspaceDeployed {
+				detail = fmt.Sprintf("%s was not deployed", dep)
+				break
+			}
+		}
+
+		if detail == "-" {
+			ui.Spacer()
+			ui.Divider()
+			ui.Bold(app.Name)
+			start := time.Now()
+			err := deployWorkspaceApp(root, app, target)
+			took = time.Since(start).Round(time.Second).String()
+			if err != nil {
+				status, detail = workspaceFailed, err.Error()
+			} else {
+				status = workspaceDeployed
+			}
+		}
+		if status != workspaceDeployed {
+			failed++
+		}
+		outcome[app.Name] = status
+		rows = append(rows, []string{app.Name, app.Path, status, took, detail})
+	}
+
+	ui.Spacer()
+	ui.Divider()
+	ui.Table([]string{"App", "Path", "Status", "Time", "Details"}, rows)
+	if failed > 0 {
+		ui.Spacer()
+		ui.Warning(fmt.Sprintf("%d of %d apps were not deployed", failed, len(order)))
+		return fmt.Errorf("%d of %d apps were not deployed", failed, len(order))
+	}
+	ui.Spacer()
+	ui.Success(fmt.Sprintf("Deployed %d apps", len(order)))
+	return nil
+}
+
+// deployWorkspaceApp runs a normal deploy from the app's directory
+func deployWorkspaceApp(root string, app config.WorkspaceApp, target deployEnv) error {
+	if err := os.Chdir(filepath.Join(root, app.Path)); err != nil {
+		return err
+	}
+	defer os.Chdir(root)
+	return runDeploy(target)
+}
@@ -35,6 +35,32 @@ func (c *Client) UpdateApplication(uuid string, updates map[string]interface{})
 	return c.Patch("/applications/"+uuid, updates, nil)
 }
 
+// RestartApplication restarts an application's containers without a
+// rebuild. Coolify queues the restart as a deployment, whose UUID is returned.
+func (c *Client) RestartApplication(uuid string) (string, error) {
+	var resp struct {
+		DeploymentUUID string `json:"deployment_uuid"`
+	}
+	err := c.Get(fmt.Sprintf("/applications/%s/restart", uuid), &resp)
+	return resp.DeploymentUUID, err
+}
+
+// StartApplication starts an application's containers, building them first
+// if they don't exist. Coolify queues the start as a deployment, whose UUID
+// is returned.
+func (c *Client) StartApplication(uuid string) (string, error) {
+	var resp struct {
+		DeploymentUUID string `json:"deployment_uuid"`
+	}
+	err := c.Get(fmt.Sprintf("/applications/%s/start", uuid), &resp)
+	return resp.DeploymentUUID, err
+}
+
+// StopApplication stops an application's containers
+func (c *Client) StopApplication(uuid string) error {
+	return c.Get(fmt.Sprintf("/applications/%s/stop", uuid), nil)
+}
+
 // DeleteApplication deletes an application
 func (c *Client) DeleteApplication(uuid string) error {
 	return c.Delete("/applications/" + uuid)
@@ -50,15 +76,27 @@ func (c *Client) GetApplicationEnvVars(uuid string) ([]EnvVar, error) {
 // CreateApplicationEnvVar creates an environment variable for an application
 func (c *Client) CreateApplicationEnvVar(uuid, key, value string, isBuildTime, isPreview bool) (*EnvVar, error) {
 	body := map[string]interface{}{
-		"key":        key,
-		"value":      value,
-		"is_preview": isPreview,
+		"key":           key,
+		"value":         value,
+		"is_preview":    isPreview,
+		"is_build_time": isBuildTime,
 	}
 	var envVar EnvVar
 	err := c.Post(fmt.Sprintf("/applications/%s/envs", uuid), body, &envVar)
 	return &envVar, err
 }
 
+// UpdateApplicationEnvVar updates the value of an existing environment variable by key
+func (c *Client) UpdateApplicationEnvVar(uuid, key, value string, isBuildTime, isPreview bool) error {
+	body := map[string]interface{}{
+		"key":           key,
+		"value":         value,
+		"is_preview":    isPreview,
+		"is_build_time": isBuildTime,
+	}
+	return c.Patch(fmt.Sprintf("/applications/%s/envs", uuid), body, nil)
+}
+
 // DeleteApplicationEnvVar deletes an environment variable
 func (c *Client) DeleteApplicationEnvVar(appUUID, envUUID string) error {
 	return c.Delete(fmt.Sprintf("/applications/%s/envs/%s", appUUID, envUUID))
@@ -77,3 +115,54 @@ func (c *Client) CreatePrivateGitHubApp(req *CreatePrivateGitHubAppRequest) (*Cr
 	err := c.Post("/applications/private-github-app", req, &resp)
 	return &resp, err
 }
+
+// ListScheduledTasks returns the scheduled tasks for an application
+func (c *Client) ListScheduledTasks(appUUID string) ([]ScheduledTask, error) {
+	var tasks []ScheduledTask
+	err := c.Get(fmt.Sprintf("/applications/%s/scheduled-tasks", appUUID), &tasks)
+	return tasks, err
+}
+
+// CreateScheduledTask creates a scheduled task for an application
+func (c *Client) CreateScheduledTask(appUUID string, req *CreateScheduledTaskRequest) (*ScheduledTask, error) {
+	var task ScheduledTask
+	err := c.Post(fmt.Sprintf("/applications/%s/scheduled-tasks", appUUID), req, &task)
+	return &task, err
+}
+
+// DeleteScheduledTask deletes a scheduled task
+func (c *Client) DeleteScheduledTask(appUUID, taskUUID string) error {
+	return c.Delete(fmt.Sprintf("/applications/%s/scheduled-tasks/%s", appUUID, taskUUID))
+}
+
+// ListPreviews returns the preview deployments of an application
+func (c *Client) ListPreviews(appUUID string) ([]ApplicationPreview, error) {
+	var previews []ApplicationPreview
+	err := c.Get(fmt.Sprintf("/applications/%s/previews", appUUID), &previews)
+	return previews, err
+}
+
+// DeletePreview removes the preview deployment for a pull request
+func (c *Client) DeletePreview(appUUID string, pr int) error {
+	return c.Delete(fmt.Sprintf("/applications/%s/previews/%d", appUUID, pr))
+}
+
+// GetApplicationSettings returns every field Coolify reports for an
+// application, including ones the Application type doesn't model
+func (c *Client) GetApplicationSettings(uuid string) (map[string]interface{}, error) {
+	var settings map[string]interface{}
+	err := c.Get("/applications/"+uuid, &settings)
+	return settings, err
+}
+
+// ListStorages returns the persistent volumes mounted into an application
+func (c *Client) ListStorages(appUUID string) ([]Storage, error) {
+	var storages []Storage
+	err := c.Get(fmt.Sprintf("/applications/%s/storages", appUUID), &storages)
+	return storages, err
+}
+
+// CreateStorage mounts a persistent volume into an application
+func (c *Client) CreateStorage(appUUID string, storage *Storage) error {
+	return c.Post(fmt.Sprintf("/applications/%s/storages", appUUID), storage, nil)
+}
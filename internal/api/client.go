@@ -10,6 +10,8 @@ import (
 	"os"
 	"strings"
 	"time"
+
+	"github.com/dropalltables/cdp/internal/profile"
 )
 
 // Client is the Coolify API client
@@ -45,6 +47,33 @@ func IsNotFound(err error) bool {
 	return false
 }
 
+// IsUnauthorized returns true if the error is a 401 Unauthorized, i.e. the
+// token is invalid or revoked
+func IsUnauthorized(err error) bool {
+	if apiErr, ok := err.(*APIError); ok {
+		return apiErr.StatusCode == 401
+	}
+	return false
+}
+
+// IsForbidden returns true if the error is a 403 Forbidden, which Coolify
+// returns when the token lacks a required ability
+func IsForbidden(err error) bool {
+	if apiErr, ok := err.(*APIError); ok {
+		return apiErr.StatusCode == 403
+	}
+	return false
+}
+
+// IsValidation returns true if the error is a 422 Unprocessable Entity,
+// which Coolify also returns for fields it doesn't accept
+func IsValidation(err error) bool {
+	if apiErr, ok := err.(*APIError); ok {
+		return apiErr.StatusCode == 422
+	}
+	return false
+}
+
 // NewClient creates a new Coolify API client
 func NewClient(baseURL, token string) *Client {
 	// Ensure baseURL doesn't have trailing slash
@@ -65,6 +94,7 @@ func NewClient(baseURL, token string) *Client {
 
 // request performs an HTTP request
 func (c *Client) request(method, path string, body interface{}, result interface{}) error {
+	defer profile.Start(profile.API)()
 	var bodyReader io.Reader
 	if body != nil {
 		jsonBody, err := json.Marshal(body)
@@ -117,6 +147,12 @@ func (c *Client) request(method, path string, body interface{}, result interface
 		}
 	}
 
+	// Plain-text endpoints like /version are read into a *[]byte
+	if raw, ok := result.(*[]byte); ok {
+		*raw = respBody
+		return nil
+	}
+
 	if result != nil && len(respBody) > 0 {
 		if err := json.Unmarshal(respBody, result); err != nil {
 			return fmt.Errorf("failed to parse response: %w", err)
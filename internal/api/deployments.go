@@ -43,6 +43,16 @@ func (c *Client) GetDeploymentLogs(appUUID string) (string, error) {
 	return resp.Logs, err
 }
 
+// GetApplicationLogs returns the last lines of an application's runtime logs
+func (c *Client) GetApplicationLogs(appUUID string, lines int) (string, error) {
+	var resp DeploymentLogsResponse
+	params := map[string]string{
+		"lines": fmt.Sprintf("%d", lines),
+	}
+	err := c.GetWithParams(fmt.Sprintf("/applications/%s/logs", appUUID), params, &resp)
+	return resp.Logs, err
+}
+
 // Deployment represents a deployment in Coolify
 // Note: Coolify API returns some IDs as strings
 type Deployment struct {
@@ -112,6 +122,11 @@ func (c *Client) ListDeployments(appUUID string) ([]Deployment, error) {
 	return deployments, nil
 }
 
+// CancelDeployment cancels a queued or in-progress deployment
+func (c *Client) CancelDeployment(deploymentUUID string) error {
+	return c.Post(fmt.Sprintf("/deployments/%s/cancel", deploymentUUID), nil, nil)
+}
+
 // DeploymentHistoryResponse wraps the deployment history API response
 type DeploymentHistoryResponse struct {
 	Count       int          `json:"count"`
@@ -0,0 +1,49 @@
+package api
+
+import (
+	"net/url"
+	"strings"
+)
+
+// generatedDomainSuffixes are the wildcard DNS services Coolify uses to give
+// a new app a working domain before a real one is attached
+var generatedDomainSuffixes = []string{".sslip.io", ".traefik.me"}
+
+// IsGeneratedDomain reports whether domain (a URL or bare hostname) is one
+// Coolify generated on a wildcard DNS service rather than one the user owns
+func IsGeneratedDomain(domain string) bool {
+	if !strings.Contains(domain, "://") {
+		domain = "https://" + domain
+	}
+	u, err := url.Parse(domain)
+	if err != nil {
+		return false
+	}
+	host := strings.ToLower(u.Hostname())
+	for _, suffix := range generatedDomainSuffixes {
+		if strings.HasSuffix(host, suffix) {
+			return true
+		}
+	}
+	return false
+}
+
+// PreferredDomain returns the first domain in Coolify's comma-separated
+// fqdn field that the user attached, falling back to the first generated
+// one, or "" when there are none
+func PreferredDomain(fqdn string) string {
+	fallback := ""
+	for _, d := range strings.Split(fqdn, ",") {
+		d = strings.TrimSpace(d)
+		if d == "" {
+			continue
+		}
+		if !IsGeneratedDomain(d) {
+			return d
+		}
+		if fallback == "" {
+			fallback = d
+		}
+	}
+	return fallback
+}
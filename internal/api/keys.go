@@ -0,0 +1,20 @@
+package api
+
+// ListPrivateKeys returns all private keys stored in Coolify
+func (c *Client) ListPrivateKeys() ([]PrivateKey, error) {
+	var keys []PrivateKey
+	err := c.Get("/security/keys", &keys)
+	return keys, err
+}
+
+// CreatePrivateKey uploads a private key to Coolify
+func (c *Client) CreatePrivateKey(name, description, privateKey string) (*PrivateKey, error) {
+	body := map[string]string{
+		"name":        name,
+		"description": description,
+		"private_key": privateKey,
+	}
+	var key PrivateKey
+	err := c.Post("/security/keys", body, &key)
+	return &key, err
+}
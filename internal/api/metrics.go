@@ -0,0 +1,17 @@
+package api
+
+import "fmt"
+
+// GetApplicationMetrics returns current resource usage for an application
+func (c *Client) GetApplicationMetrics(uuid string) (*ResourceMetrics, error) {
+	var metrics ResourceMetrics
+	err := c.Get(fmt.Sprintf("/applications/%s/metrics", uuid), &metrics)
+	return &metrics, err
+}
+
+// GetServerMetrics returns current resource usage for a server
+func (c *Client) GetServerMetrics(uuid string) (*ResourceMetrics, error) {
+	var metrics ResourceMetrics
+	err := c.Get(fmt.Sprintf("/servers/%s/metrics", uuid), &metrics)
+	return &metrics, err
+}
@@ -0,0 +1,33 @@
+package api
+
+import "fmt"
+
+// NotificationChannels are the notification integrations Coolify supports
+var NotificationChannels = []string{"email", "discord", "telegram", "slack"}
+
+// GetCurrentTeam returns the team the API token belongs to
+func (c *Client) GetCurrentTeam() (*Team, error) {
+	var team Team
+	err := c.Get("/teams/current", &team)
+	return &team, err
+}
+
+// GetNotificationSettings returns the current team's settings for a
+// notification channel. Field names follow Coolify's, e.g.
+// "discord_enabled" and "discord_webhook_url".
+func (c *Client) GetNotificationSettings(channel string) (map[string]interface{}, error) {
+	var settings map[string]interface{}
+	err := c.Get(fmt.Sprintf("/notifications/%s", channel), &settings)
+	return settings, err
+}
+
+// UpdateNotificationSettings updates the current team's settings for a
+// notification channel
+func (c *Client) UpdateNotificationSettings(channel string, settings map[string]interface{}) error {
+	return c.Patch(fmt.Sprintf("/notifications/%s", channel), settings, nil)
+}
+
+// SendTestNotification sends a test message through a notification channel
+func (c *Client) SendTestNotification(channel string) error {
+	return c.Post(fmt.Sprintf("/notifications/%s/test", channel), nil, nil)
+}
@@ -27,6 +27,21 @@ func (c *Client) CreateProject(name, description string) (*Project, error) {
 	return &project, err
 }
 
+// ListEnvironments returns the environments of a project. Coolify versions
+// without the environments endpoint list them with the project instead.
+func (c *Client) ListEnvironments(projectUUID string) ([]Environment, error) {
+	var envs []Environment
+	err := c.Get(fmt.Sprintf("/projects/%s/environments", projectUUID), &envs)
+	if IsNotFound(err) {
+		project, err := c.GetProject(projectUUID)
+		if err != nil {
+			return nil, err
+		}
+		return project.Environments, nil
+	}
+	return envs, err
+}
+
 // CreateEnvironment creates a new environment in a project
 func (c *Client) CreateEnvironment(projectUUID, name string) (*Environment, error) {
 	body := map[string]string{
@@ -1,5 +1,7 @@
 package api
 
+import "fmt"
+
 // ListServers returns all servers
 func (c *Client) ListServers() ([]Server, error) {
 	var servers []Server
@@ -13,3 +15,30 @@ func (c *Client) GetServer(uuid string) (*Server, error) {
 	err := c.Get("/servers/"+uuid, &server)
 	return &server, err
 }
+
+// CreateServer registers a new server
+func (c *Client) CreateServer(req *CreateServerRequest) (*CreateServerResponse, error) {
+	var resp CreateServerResponse
+	err := c.Post("/servers", req, &resp)
+	return &resp, err
+}
+
+// ValidateServer starts connection validation for a server
+func (c *Client) ValidateServer(uuid string) error {
+	return c.Get(fmt.Sprintf("/servers/%s/validate", uuid), nil)
+}
+
+// ListServerResources returns the resources deployed on a server
+func (c *Client) ListServerResources(uuid string) ([]ServerResource, error) {
+	var resources []ServerResource
+	err := c.Get(fmt.Sprintf("/servers/%s/resources", uuid), &resources)
+	return resources, err
+}
+
+// GetServerDomains returns the domains of the resources on a server,
+// grouped by the IP they resolve to
+func (c *Client) GetServerDomains(uuid string) ([]ServerDomains, error) {
+	var domains []ServerDomains
+	err := c.Get(fmt.Sprintf("/servers/%s/domains", uuid), &domains)
+	return domains, err
+}
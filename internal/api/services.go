@@ -0,0 +1,62 @@
+package api
+
+import "fmt"
+
+// GetService returns a compose service by UUID
+func (c *Client) GetService(uuid string) (*Service, error) {
+	var service Service
+	err := c.Get("/services/"+uuid, &service)
+	return &service, err
+}
+
+// CreateService creates a service from a Docker Compose file
+func (c *Client) CreateService(req *CreateServiceRequest) (*CreateServiceResponse, error) {
+	var resp CreateServiceResponse
+	err := c.Post("/services", req, &resp)
+	return &resp, err
+}
+
+// UpdateServiceCompose replaces the compose file of a service; composeRaw
+// is base64-encoded
+func (c *Client) UpdateServiceCompose(uuid, composeRaw string) error {
+	body := map[string]string{
+		"docker_compose_raw": composeRaw,
+	}
+	return c.Patch("/services/"+uuid, body, nil)
+}
+
+// StartService starts a service
+func (c *Client) StartService(uuid string) error {
+	return c.Get(fmt.Sprintf("/services/%s/start", uuid), nil)
+}
+
+// RestartService restarts a service, recreating containers from its compose file
+func (c *Client) RestartService(uuid string) error {
+	return c.Get(fmt.Sprintf("/services/%s/restart", uuid), nil)
+}
+
+// GetServiceEnvVars returns environment variables for a service
+func (c *Client) GetServiceEnvVars(uuid string) ([]EnvVar, error) {
+	var envVars []EnvVar
+	err := c.Get(fmt.Sprintf("/services/%s/envs", uuid), &envVars)
+	return envVars, err
+}
+
+// CreateServiceEnvVar creates an environment variable for a service
+func (c *Client) CreateServiceEnvVar(uuid, key, value string) error {
+	body := map[string]interface{}{
+		"key":   key,
+		"value": value,
+	}
+	return c.Post(fmt.Sprintf("/services/%s/envs", uuid), body, nil)
+}
+
+// UpdateServiceEnvVar updates the value of an existing service environment
+// variable by key
+func (c *Client) UpdateServiceEnvVar(uuid, key, value string) error {
+	body := map[string]interface{}{
+		"key":   key,
+		"value": value,
+	}
+	return c.Patch(fmt.Sprintf("/services/%s/envs", uuid), body, nil)
+}
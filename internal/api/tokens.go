@@ -0,0 +1,56 @@
+package api
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// ErrTokenAPIUnsupported is returned when the Coolify instance has no API
+// for managing API tokens, so they must be created and revoked in its UI
+var ErrTokenAPIUnsupported = errors.New("this Coolify version can't manage API tokens over the API")
+
+// APIToken is a newly created Coolify API token
+type APIToken struct {
+	ID    int    `json:"id"`
+	Name  string `json:"name"`
+	Token string `json:"token"` // only returned on creation
+}
+
+// CreateAPIToken creates an API token for the current team with the given
+// abilities
+func (c *Client) CreateAPIToken(name string, abilities []string) (*APIToken, error) {
+	body := map[string]interface{}{
+		"name":      name,
+		"abilities": abilities,
+	}
+	var token APIToken
+	if err := c.Post("/security/api-tokens", body, &token); err != nil {
+		return nil, tokenAPIError(err)
+	}
+	if token.Token == "" {
+		return nil, fmt.Errorf("Coolify didn't return the new token")
+	}
+	return &token, nil
+}
+
+// RevokeAPIToken revokes the API token with the given ID
+func (c *Client) RevokeAPIToken(id string) error {
+	return tokenAPIError(c.Delete("/security/api-tokens/" + id))
+}
+
+// TokenID returns the ID of a Coolify API token, which prefixes the
+// secret as "<id>|<secret>"
+func TokenID(token string) (string, bool) {
+	id, _, ok := strings.Cut(token, "|")
+	return id, ok && id != ""
+}
+
+// tokenAPIError maps the responses of instances without the token API to
+// ErrTokenAPIUnsupported
+func tokenAPIError(err error) error {
+	if apiErr, ok := err.(*APIError); ok && (apiErr.StatusCode == 404 || apiErr.StatusCode == 405) {
+		return ErrTokenAPIUnsupported
+	}
+	return err
+}
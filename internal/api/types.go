@@ -10,6 +10,29 @@ type Server struct {
 	User        string          `json:"user"`
 	Port        int             `json:"port"`
 	Settings    *ServerSettings `json:"settings"`
+	Metadata    *ServerMetadata `json:"server_metadata,omitempty"`
+	UpdatedAt   string          `json:"updated_at"`
+
+	// The server list flattens these settings onto each server
+	IsReachable bool `json:"is_reachable"`
+	IsUsable    bool `json:"is_usable"`
+}
+
+// Reachable reports whether Coolify's last check could connect to the server
+func (s *Server) Reachable() bool {
+	return s.IsReachable || (s.Settings != nil && s.Settings.IsReachable)
+}
+
+// Usable reports whether Coolify's last check found the server ready for
+// deployments
+func (s *Server) Usable() bool {
+	return s.IsUsable || (s.Settings != nil && s.Settings.IsUsable)
+}
+
+// ServerMetadata is what Coolify gathered about the server's host
+type ServerMetadata struct {
+	OS   string `json:"os"`
+	Arch string `json:"arch"` // as reported by uname -m, e.g. "x86_64" or "aarch64"
 }
 
 // ServerSettings contains server settings
@@ -17,6 +40,33 @@ type ServerSettings struct {
 	IsReachable    bool   `json:"is_reachable"`
 	IsUsable       bool   `json:"is_usable"`
 	WildcardDomain string `json:"wildcard_domain"`
+	UpdatedAt      string `json:"updated_at"` // changes when a validation records its result
+}
+
+// CreateServerRequest is the request body for registering a server
+type CreateServerRequest struct {
+	Name            string `json:"name"`
+	Description     string `json:"description,omitempty"`
+	IP              string `json:"ip"`
+	Port            int    `json:"port"`
+	User            string `json:"user"`
+	PrivateKeyUUID  string `json:"private_key_uuid"`
+	InstantValidate bool   `json:"instant_validate,omitempty"`
+}
+
+// CreateServerResponse is the response from registering a server
+type CreateServerResponse struct {
+	UUID string `json:"uuid"`
+}
+
+// PrivateKey represents an SSH private key stored in Coolify
+type PrivateKey struct {
+	ID          int    `json:"id"`
+	UUID        string `json:"uuid"`
+	Name        string `json:"name"`
+	Description string `json:"description"`
+	PublicKey   string `json:"public_key"`
+	Fingerprint string `json:"fingerprint"`
 }
 
 // Project represents a Coolify project
@@ -58,6 +108,10 @@ type Application struct {
 	DockerRegistryTag           string `json:"docker_registry_image_tag"`
 	PreviewURLTemplate          string `json:"preview_url_template"`
 	IsPreviewDeploymentsEnabled bool   `json:"is_preview_deployments_enabled"`
+	LimitsCPUs                  string `json:"limits_cpus"`
+	LimitsMemory                string `json:"limits_memory"`
+	SwarmReplicas               int    `json:"swarm_replicas"`
+	UpdatedAt                   string `json:"updated_at"`
 }
 
 // CreatePublicAppRequest is the request body for creating a public app
@@ -113,6 +167,18 @@ type DeploymentInfo struct {
 	DeploymentUUID string `json:"deployment_uuid"`
 }
 
+// ApplicationPreview represents a preview deployment created from a pull request
+type ApplicationPreview struct {
+	ID                 int    `json:"id"`
+	UUID               string `json:"uuid"`
+	PullRequestID      int    `json:"pull_request_id"`
+	PullRequestHTMLURL string `json:"pull_request_html_url"`
+	FQDN               string `json:"fqdn"`
+	Status             string `json:"status"`
+	CreatedAt          string `json:"created_at"`
+	UpdatedAt          string `json:"updated_at"`
+}
+
 // EnvVar represents an environment variable
 type EnvVar struct {
 	ID          int    `json:"id"`
@@ -169,3 +235,92 @@ type CreatePrivateGitHubAppRequest struct {
 	HealthCheckEnabled bool   `json:"health_check_enabled,omitempty"`
 	HealthCheckPath    string `json:"health_check_path,omitempty"`
 }
+
+// MetricPoint is a single sample in a metrics time series
+type MetricPoint struct {
+	Time  string  `json:"time"`
+	Value float64 `json:"value"`
+}
+
+// ResourceMetrics reports resource usage for a server or application
+type ResourceMetrics struct {
+	CPUPercent    float64       `json:"cpu_usage"`
+	MemoryPercent float64       `json:"memory_usage"`
+	MemoryUsedMB  float64       `json:"memory_used"`
+	MemoryTotalMB float64       `json:"memory_total"`
+	DiskPercent   float64       `json:"disk_usage"`
+	DiskUsedGB    float64       `json:"disk_used"`
+	DiskTotalGB   float64       `json:"disk_total"`
+	CPUHistory    []MetricPoint `json:"cpu_history"`
+	MemoryHistory []MetricPoint `json:"memory_history"`
+}
+
+// ServerResource is an application, database, or service running on a server
+type ServerResource struct {
+	ID     int    `json:"id"`
+	UUID   string `json:"uuid"`
+	Name   string `json:"name"`
+	Type   string `json:"type"`
+	Status string `json:"status"`
+}
+
+// ServerDomains lists the domains pointed at one of a server's IPs
+type ServerDomains struct {
+	IP      string   `json:"ip"`
+	Domains []string `json:"domains"`
+}
+
+// ScheduledTask represents a cron job attached to an application
+type ScheduledTask struct {
+	ID        int    `json:"id"`
+	UUID      string `json:"uuid"`
+	Name      string `json:"name"`
+	Command   string `json:"command"`
+	Frequency string `json:"frequency"`
+	Container string `json:"container"`
+	Enabled   bool   `json:"enabled"`
+}
+
+// CreateScheduledTaskRequest is the request body for creating a scheduled task
+type CreateScheduledTaskRequest struct {
+	Name      string `json:"name"`
+	Command   string `json:"command"`
+	Frequency string `json:"frequency"`
+	Container string `json:"container,omitempty"`
+	Enabled   bool   `json:"enabled"`
+}
+
+// Service is a Coolify resource defined by a Docker Compose file
+type Service struct {
+	ID               int    `json:"id"`
+	UUID             string `json:"uuid"`
+	Name             string `json:"name"`
+	Description      string `json:"description"`
+	DockerComposeRaw string `json:"docker_compose_raw"`
+	Status           string `json:"status"`
+}
+
+// CreateServiceRequest is the request body for creating a compose service
+type CreateServiceRequest struct {
+	Name             string `json:"name"`
+	Description      string `json:"description,omitempty"`
+	ProjectUUID      string `json:"project_uuid"`
+	ServerUUID       string `json:"server_uuid"`
+	EnvironmentUUID  string `json:"environment_uuid,omitempty"`
+	EnvironmentName  string `json:"environment_name,omitempty"`
+	DockerComposeRaw string `json:"docker_compose_raw"` // base64-encoded compose file
+	InstantDeploy    bool   `json:"instant_deploy,omitempty"`
+}
+
+// CreateServiceResponse is the response from creating a service
+type CreateServiceResponse struct {
+	UUID    string   `json:"uuid"`
+	Domains []string `json:"domains"`
+}
+
+// Storage is a persistent volume mounted into an application
+type Storage struct {
+	Name      string `json:"name"`
+	MountPath string `json:"mount_path"`
+	HostPath  string `json:"host_path,omitempty"`
+}
@@ -0,0 +1,79 @@
+package api
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/dropalltables/cdp/internal/version"
+)
+
+// MinCoolifyVersion is the oldest Coolify release cdp is tested against
+const MinCoolifyVersion = "4.0.0-beta.380"
+
+// Token abilities Coolify grants API tokens
+const (
+	AbilityRead   = "read"
+	AbilityWrite  = "write"
+	AbilityDeploy = "deploy"
+)
+
+// probeUUID names a resource that doesn't exist, so ability probes are
+// rejected before they can change anything
+const probeUUID = "cdp-ability-probe"
+
+// GetVersion returns the Coolify version, e.g. "4.0.0-beta.380"
+func (c *Client) GetVersion() (string, error) {
+	var body []byte
+	if err := c.Get("/version", &body); err != nil {
+		return "", err
+	}
+	return strings.Trim(strings.TrimSpace(string(body)), `"`), nil
+}
+
+// IsSupportedVersion reports whether a Coolify version is at least
+// MinCoolifyVersion. Coolify v4 is versioned as betas of 4.0.0, so the
+// beta number is compared when the release is the same.
+func IsSupportedVersion(v string) bool {
+	if c := version.Compare(v, MinCoolifyVersion); c != 0 {
+		return c > 0
+	}
+	return betaNumber(v) >= betaNumber(MinCoolifyVersion)
+}
+
+// betaNumber returns N of a "-beta.N" version, or a large number for a
+// final release, which sorts after its betas
+func betaNumber(v string) int {
+	_, pre, ok := strings.Cut(v, "-")
+	if !ok {
+		return int(^uint(0) >> 1)
+	}
+	n, _ := strconv.Atoi(strings.TrimPrefix(pre, "beta."))
+	return n
+}
+
+// TokenAbilities reports which of the read, write and deploy abilities the
+// token has. Write and deploy are probed with requests for a resource that
+// doesn't exist: Coolify checks abilities first, answering 403 without them
+// and 404 (or similar) with them.
+func (c *Client) TokenAbilities() (map[string]bool, error) {
+	abilities := map[string]bool{}
+
+	var teams []Team
+	err := c.Get("/teams", &teams)
+	switch {
+	case err == nil:
+		abilities[AbilityRead] = true
+	case IsForbidden(err):
+		abilities[AbilityRead] = false
+	default:
+		return nil, err
+	}
+
+	err = c.Patch("/applications/"+probeUUID, map[string]string{}, nil)
+	abilities[AbilityWrite] = !IsForbidden(err)
+
+	err = c.GetWithParams("/deploy", map[string]string{"uuid": probeUUID}, nil)
+	abilities[AbilityDeploy] = !IsForbidden(err)
+
+	return abilities, nil
+}
@@ -0,0 +1,62 @@
+package config
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+)
+
+// App state files live beside the global config and map an app UUID to a
+// value recorded by its last deploy. They're local to this machine, unlike
+// cdp.json, so recording them doesn't dirty the repository.
+
+// appStateValue returns the value recorded for appUUID in file, or "" if
+// there is none
+func appStateValue(file, appUUID string) string {
+	values, _ := loadAppState(file)
+	return values[appUUID]
+}
+
+// saveAppStateValue records value for appUUID in file
+func saveAppStateValue(file, appUUID, value string) error {
+	path, err := appStatePath(file)
+	if err != nil {
+		return err
+	}
+	values, _ := loadAppState(file)
+	values[appUUID] = value
+
+	data, err := json.MarshalIndent(values, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0644)
+}
+
+func loadAppState(file string) (map[string]string, error) {
+	values := map[string]string{}
+	path, err := appStatePath(file)
+	if err != nil {
+		return values, err
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return values, err
+	}
+	err = json.Unmarshal(data, &values)
+	if values == nil {
+		values = map[string]string{}
+	}
+	return values, err
+}
+
+func appStatePath(file string) (string, error) {
+	configPath, err := GetConfigPath()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(filepath.Dir(configPath), file), nil
+}
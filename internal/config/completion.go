@@ -0,0 +1,80 @@
+package config
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// completionCacheFile keeps the names fetched for shell completion, so
+// repeated tab presses don't each wait for the API
+const completionCacheFile = "completion-cache.json"
+
+// CompletionTTL is how long cached completion names are used
+const CompletionTTL = time.Minute
+
+type completionEntry struct {
+	Fetched time.Time `json:"fetched"`
+	Values  []string  `json:"values"`
+}
+
+// CachedCompletions returns the names cached under key, if fetched within
+// CompletionTTL
+func CachedCompletions(key string) ([]string, bool) {
+	cache := loadCompletionCache()
+	entry, ok := cache[key]
+	if !ok || time.Since(entry.Fetched) > CompletionTTL {
+		return nil, false
+	}
+	return entry.Values, true
+}
+
+// SaveCompletions caches the names fetched for key
+func SaveCompletions(key string, values []string) error {
+	path, err := completionCachePath()
+	if err != nil {
+		return err
+	}
+	cache := loadCompletionCache()
+	for k, entry := range cache {
+		if time.Since(entry.Fetched) > CompletionTTL {
+			delete(cache, k)
+		}
+	}
+	cache[key] = completionEntry{Fetched: time.Now(), Values: values}
+
+	data, err := json.Marshal(cache)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return err
+	}
+	// Names only, but of a private Coolify instance
+	return os.WriteFile(path, data, 0600)
+}
+
+func loadCompletionCache() map[string]completionEntry {
+	cache := map[string]completionEntry{}
+	path, err := completionCachePath()
+	if err != nil {
+		return cache
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return cache
+	}
+	if json.Unmarshal(data, &cache) != nil || cache == nil {
+		return map[string]completionEntry{}
+	}
+	return cache
+}
+
+func completionCachePath() (string, error) {
+	configPath, err := GetConfigPath()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(filepath.Dir(configPath), completionCacheFile), nil
+}
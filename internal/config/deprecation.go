@@ -0,0 +1,137 @@
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// Kinds of deprecated CLI surface
+const (
+	DeprecatedFlag    = "flag"
+	DeprecatedCommand = "command"
+	DeprecatedField   = "config field"
+)
+
+const (
+	// deprecationsFile records when each deprecation warning was last shown
+	deprecationsFile = "deprecations.json"
+	// deprecationWarnInterval throttles repeats of the same warning
+	deprecationWarnInterval = 24 * time.Hour
+)
+
+// Deprecation describes a flag, command or config field that still works
+// but is going away, and what replaces it
+type Deprecation struct {
+	Kind        string // DeprecatedFlag, DeprecatedCommand or DeprecatedField
+	Name        string // e.g. "--prod", "ls", "prod_env_uuid"
+	Replacement string // e.g. "--env production"; empty if there is none
+	RemovedIn   string // version expected to drop it, if decided
+}
+
+// ID is the key the warning throttle is recorded under
+func (d Deprecation) ID() string {
+	return d.Kind + ":" + d.Name
+}
+
+// Message is the warning shown to the user
+func (d Deprecation) Message() string {
+	msg := fmt.Sprintf("The %s %s is deprecated", d.Kind, d.Name)
+	if d.Replacement != "" {
+		msg += fmt.Sprintf("; use %s instead", d.Replacement)
+	}
+	if d.RemovedIn != "" {
+		msg += fmt.Sprintf(" (removed in %s)", d.RemovedIn)
+	}
+	return msg
+}
+
+// ShouldWarnDeprecation reports whether the warning for d is due, at most
+// once a day per user, and records it as shown
+func ShouldWarnDeprecation(d Deprecation) bool {
+	path, err := deprecationsPath()
+	if err != nil {
+		return true
+	}
+
+	shown := map[string]time.Time{}
+	if data, err := os.ReadFile(path); err == nil {
+		json.Unmarshal(data, &shown)
+	}
+	if last, ok := shown[d.ID()]; ok && time.Since(last) < deprecationWarnInterval {
+		return false
+	}
+
+	shown[d.ID()] = time.Now()
+	if data, err := json.MarshalIndent(shown, "", "  "); err == nil {
+		os.MkdirAll(filepath.Dir(path), 0755)
+		os.WriteFile(path, data, 0644)
+	}
+	return true
+}
+
+func deprecationsPath() (string, error) {
+	configPath, err := GetConfigPath()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(filepath.Dir(configPath), deprecationsFile), nil
+}
+
+// deprecatedFields maps legacy project config fields onto their
+// replacements, leaving values already set on the replacement alone
+var deprecatedFields = []struct {
+	Deprecation
+	set     func(cfg *ProjectConfig) bool
+	migrate func(cfg *ProjectConfig)
+}{
+	{
+		Deprecation: Deprecation{Kind: DeprecatedField, Name: "prod_env_uuid", Replacement: "environment_uuid"},
+		set:         func(cfg *ProjectConfig) bool { return cfg.ProdEnvUUID != "" },
+		migrate: func(cfg *ProjectConfig) {
+			if cfg.EnvironmentUUID == "" {
+				cfg.EnvironmentUUID = cfg.ProdEnvUUID
+			}
+		},
+	},
+	{
+		Deprecation: Deprecation{Kind: DeprecatedField, Name: "app_uuids", Replacement: "app_uuid"},
+		set:         func(cfg *ProjectConfig) bool { return len(cfg.AppUUIDs) > 0 },
+		migrate: func(cfg *ProjectConfig) {
+			if cfg.AppUUID == "" {
+				cfg.AppUUID = cfg.AppUUIDs["production"]
+			}
+		},
+	},
+	{
+		// Previews now come from pull requests, in the app's own environment
+		Deprecation: Deprecation{Kind: DeprecatedField, Name: "preview_env_uuid"},
+		set:         func(cfg *ProjectConfig) bool { return cfg.PreviewEnvUUID != "" },
+		migrate:     func(cfg *ProjectConfig) {},
+	},
+}
+
+// migrateDeprecatedFields applies the replacements of legacy fields
+func migrateDeprecatedFields(cfg *ProjectConfig) {
+	for _, f := range deprecatedFields {
+		if f.set(cfg) {
+			f.migrate(cfg)
+		}
+	}
+}
+
+// DeprecatedFieldsIn returns the deprecations for legacy fields set in cfg
+func DeprecatedFieldsIn(cfg *ProjectConfig) []Deprecation {
+	if cfg == nil {
+		return nil
+	}
+	var found []Deprecation
+	for _, f := range deprecatedFields {
+		if f.set(cfg) {
+			found = append(found, f.Deprecation)
+		}
+	}
+	return found
+}
@@ -0,0 +1,62 @@
+package config
+
+import (
+	"os"
+	"strings"
+)
+
+// Environment variables that override the credentials in the global
+// config, so CI can run cdp without a config file
+const (
+	EnvCoolifyURL   = "COOLIFY_URL"
+	EnvCoolifyToken = "COOLIFY_TOKEN"
+	EnvGitHubToken  = "CDP_GITHUB_TOKEN"
+)
+
+// loadEnvOverrides returns the credentials set in the environment
+func loadEnvOverrides() *GlobalConfig {
+	return &GlobalConfig{
+		CoolifyURL:   strings.TrimSuffix(strings.TrimSpace(os.Getenv(EnvCoolifyURL)), "/"),
+		CoolifyToken: strings.TrimSpace(os.Getenv(EnvCoolifyToken)),
+		GitHubToken:  strings.TrimSpace(os.Getenv(EnvGitHubToken)),
+	}
+}
+
+// EnvOverrides returns the names of the credential environment variables
+// that are set and take precedence over the config file
+func EnvOverrides() []string {
+	env := loadEnvOverrides()
+	var names []string
+	if env.CoolifyURL != "" {
+		names = append(names, EnvCoolifyURL)
+	}
+	if env.CoolifyToken != "" {
+		names = append(names, EnvCoolifyToken)
+	}
+	if env.GitHubToken != "" {
+		names = append(names, EnvGitHubToken)
+	}
+	return names
+}
+
+// CoolifyTokenFromEnv reports whether the Coolify token in use comes from
+// the environment rather than the config file
+func CoolifyTokenFromEnv() bool {
+	return loadEnvOverrides().CoolifyToken != ""
+}
+
+// stripEnv restores the config file's values for fields still holding an
+// environment override, so saving never writes CI secrets to disk
+func stripEnv(cfg, env, file *GlobalConfig) *GlobalConfig {
+	out := *cfg
+	if env.CoolifyURL != "" && out.CoolifyURL == env.CoolifyURL {
+		out.CoolifyURL = file.CoolifyURL
+	}
+	if env.CoolifyToken != "" && out.CoolifyToken == env.CoolifyToken {
+		out.CoolifyToken = file.CoolifyToken
+	}
+	if env.GitHubToken != "" && out.GitHubToken == env.GitHubToken {
+		out.GitHubToken = file.GitHubToken
+	}
+	return &out
+}
@@ -0,0 +1,60 @@
+package config
+
+import "strings"
+
+// environmentTarget records what ForEnvironment replaced, so that saving
+// the copy doesn't relink the project
+type environmentTarget struct {
+	name            string
+	appUUID         string
+	environmentUUID string
+}
+
+// EnvironmentApp returns the app recorded for the named environment
+func (c *ProjectConfig) EnvironmentApp(name string) (EnvironmentApp, bool) {
+	app, ok := c.Environments[strings.ToLower(name)]
+	return app, ok && app.AppUUID != ""
+}
+
+// SetEnvironmentApp records the app serving the named environment
+func (c *ProjectConfig) SetEnvironmentApp(name string, app EnvironmentApp) {
+	if c.Environments == nil {
+		c.Environments = map[string]EnvironmentApp{}
+	}
+	c.Environments[strings.ToLower(name)] = app
+}
+
+// ForEnvironment returns a copy of c whose app and environment are those
+// recorded for the named environment, so the deploy code can work on it
+// unchanged. Saving the copy stores its app and environment under
+// Environments and keeps the project's own app_uuid.
+func (c *ProjectConfig) ForEnvironment(name string) *ProjectConfig {
+	app, _ := c.EnvironmentApp(name)
+	env := *c
+	env.target = &environmentTarget{
+		name:            strings.ToLower(name),
+		appUUID:         c.AppUUID,
+		environmentUUID: c.EnvironmentUUID,
+	}
+	env.AppUUID = app.AppUUID
+	env.EnvironmentUUID = app.EnvironmentUUID
+	return &env
+}
+
+// forSaving undoes ForEnvironment, recording the copy's app under its
+// environment
+func (c *ProjectConfig) forSaving() *ProjectConfig {
+	if c.target == nil {
+		return c
+	}
+	out := *c
+	out.target = nil
+	out.Environments = make(map[string]EnvironmentApp, len(c.Environments)+1)
+	for name, app := range c.Environments {
+		out.Environments[name] = app
+	}
+	out.Environments[c.target.name] = EnvironmentApp{EnvironmentUUID: c.EnvironmentUUID, AppUUID: c.AppUUID}
+	out.AppUUID = c.target.appUUID
+	out.EnvironmentUUID = c.target.environmentUUID
+	return &out
+}
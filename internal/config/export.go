@@ -0,0 +1,66 @@
+package config
+
+import (
+	"encoding/json"
+	"os"
+)
+
+// LoadGlobalFile loads only what the user saved in the global config file,
+// without managed defaults or environment overrides. A missing file gives
+// an empty config.
+func LoadGlobalFile() (*GlobalConfig, error) {
+	configPath, err := GetConfigPath()
+	if err != nil {
+		return nil, err
+	}
+	data, err := os.ReadFile(configPath)
+	if os.IsNotExist(err) {
+		return &GlobalConfig{}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var cfg GlobalConfig
+	if err := json.Unmarshal(data, &cfg); err != nil {
+		return nil, err
+	}
+	return &cfg, nil
+}
+
+// SplitSecrets separates the tokens, webhooks and signing secrets of cfg
+// from the rest. Webhook URLs count as secrets, as services such as Slack
+// and Discord embed a token in them. Registry passwords are dropped: they
+// belong in Docker's credential store, not in cdp's config.
+func SplitSecrets(cfg *GlobalConfig) (public, secrets *GlobalConfig) {
+	pub := *cfg
+	pub.managed, pub.env, pub.beforeEnv = nil, nil, nil
+	secrets = &GlobalConfig{
+		CoolifyToken:  pub.CoolifyToken,
+		GitHubToken:   pub.GitHubToken,
+		Webhooks:      pub.Webhooks,
+		WebhookSecret: pub.WebhookSecret,
+	}
+	pub.CoolifyToken, pub.GitHubToken, pub.WebhookSecret = "", "", ""
+	pub.Webhooks = nil
+	if pub.DockerRegistry != nil {
+		registry := *pub.DockerRegistry
+		registry.Password = ""
+		pub.DockerRegistry = &registry
+	}
+	return &pub, secrets
+}
+
+// ImportGlobal merges the set fields of each layer, in order, into the
+// saved global config
+func ImportGlobal(layers ...*GlobalConfig) error {
+	cfg, err := LoadGlobal()
+	if err != nil {
+		return err
+	}
+	for _, layer := range layers {
+		if layer != nil {
+			mergeGlobal(cfg, layer)
+		}
+	}
+	return SaveGlobal(cfg)
+}
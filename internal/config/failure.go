@@ -0,0 +1,84 @@
+package config
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// lastFailureFile records the most recent failed command for 'cdp fix'
+const lastFailureFile = "last-failure.json"
+
+// Failure kinds, which pick the troubleshooting flow of 'cdp fix'
+const (
+	FailureDeploy       = "deploy"       // Coolify reported the deployment failed
+	FailureUnauthorized = "unauthorized" // the API rejected the token
+	FailureUnreachable  = "unreachable"  // Coolify couldn't be reached
+	FailureCertificate  = "certificate"  // Coolify's TLS certificate wasn't trusted
+	FailureOther        = "other"
+)
+
+// Failure describes the last command that failed
+type Failure struct {
+	Kind    string    `json:"kind"`
+	Command string    `json:"command"`
+	Error   string    `json:"error"`
+	URL     string    `json:"url,omitempty"`      // server a network error came from
+	Dir     string    `json:"dir,omitempty"`      // working directory of the command
+	AppUUID string    `json:"app_uuid,omitempty"` // linked app, if any
+	At      time.Time `json:"at"`
+}
+
+// LastFailure returns the last recorded failure, or nil if there is none
+func LastFailure() *Failure {
+	path, err := lastFailurePath()
+	if err != nil {
+		return nil
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil
+	}
+	var f Failure
+	if json.Unmarshal(data, &f) != nil {
+		return nil
+	}
+	return &f
+}
+
+// SaveLastFailure records f as the last failure
+func SaveLastFailure(f *Failure) error {
+	path, err := lastFailurePath()
+	if err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(f, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0600)
+}
+
+// ClearLastFailure forgets the last failure once it has been dealt with
+func ClearLastFailure() error {
+	path, err := lastFailurePath()
+	if err != nil {
+		return err
+	}
+	if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	return nil
+}
+
+func lastFailurePath() (string, error) {
+	configPath, err := GetConfigPath()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(filepath.Dir(configPath), lastFailureFile), nil
+}
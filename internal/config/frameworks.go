@@ -0,0 +1,37 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/dropalltables/cdp/internal/detect"
+	"gopkg.in/yaml.v3"
+)
+
+// frameworksFile holds the user's framework presets, next to the global config
+const frameworksFile = "frameworks.yaml"
+
+// LoadFrameworkRules returns the framework presets from
+// ~/.config/cdp/frameworks.yaml, or none if the file doesn't exist
+func LoadFrameworkRules() ([]detect.Rule, error) {
+	configPath, err := GetConfigPath()
+	if err != nil {
+		return nil, err
+	}
+	path := filepath.Join(filepath.Dir(configPath), frameworksFile)
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	var rules []detect.Rule
+	if err := yaml.Unmarshal(data, &rules); err != nil {
+		return nil, fmt.Errorf("invalid %s: %w", path, err)
+	}
+	return rules, nil
+}
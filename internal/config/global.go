@@ -20,25 +20,35 @@ func GetConfigPath() (string, error) {
 	return filepath.Join(home, configDir, configFile), nil
 }
 
-// LoadGlobal loads the global configuration
+// LoadGlobal loads the global configuration, merged over any
+// organization-managed defaults. Credentials set in the environment
+// (COOLIFY_URL, COOLIFY_TOKEN, CDP_GITHUB_TOKEN) take precedence over both.
 func LoadGlobal() (*GlobalConfig, error) {
 	configPath, err := GetConfigPath()
 	if err != nil {
 		return nil, err
 	}
 
+	managed := loadManaged()
+	cfg := *managed
+	cfg.managed = managed
+
 	data, err := os.ReadFile(configPath)
-	if err != nil {
-		if os.IsNotExist(err) {
-			return &GlobalConfig{}, nil
-		}
+	if err != nil && !os.IsNotExist(err) {
 		return nil, err
 	}
-
-	var cfg GlobalConfig
-	if err := json.Unmarshal(data, &cfg); err != nil {
-		return nil, err
+	if err == nil {
+		var user GlobalConfig
+		if err := json.Unmarshal(data, &user); err != nil {
+			return nil, err
+		}
+		mergeGlobal(&cfg, &user)
 	}
+
+	beforeEnv := cfg
+	cfg.env = loadEnvOverrides()
+	cfg.beforeEnv = &beforeEnv
+	mergeGlobal(&cfg, cfg.env)
 	return &cfg, nil
 }
 
@@ -55,6 +65,15 @@ func SaveGlobal(cfg *GlobalConfig) error {
 		return err
 	}
 
+	// Only persist what the user set: not environment overrides, nor what
+	// the managed layer provides
+	if cfg.env != nil {
+		cfg = stripEnv(cfg, cfg.env, cfg.beforeEnv)
+	}
+	if cfg.managed != nil {
+		cfg = stripManaged(cfg, cfg.managed)
+	}
+
 	data, err := json.MarshalIndent(cfg, "", "  ")
 	if err != nil {
 		return err
@@ -78,5 +97,9 @@ func Clear() error {
 	if err != nil {
 		return err
 	}
+	// The cached org config is fetched again at the next login
+	if orgPath, err := orgConfigPath(); err == nil {
+		os.Remove(orgPath)
+	}
 	return os.Remove(configPath)
 }
@@ -0,0 +1,33 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// IgnoreFile lists paths, in .gitignore syntax, that cdp neither commits
+// when auto-committing nor sends to Docker as build context
+const IgnoreFile = ".cdpignore"
+
+// LoadIgnorePatterns returns the patterns of the .cdpignore in dir, without
+// comments and blank lines, or none if the file doesn't exist
+func LoadIgnorePatterns(dir string) ([]string, error) {
+	data, err := os.ReadFile(filepath.Join(dir, IgnoreFile))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	var patterns []string
+	for _, line := range strings.Split(string(data), "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		patterns = append(patterns, line)
+	}
+	return patterns, nil
+}
@@ -0,0 +1,225 @@
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"reflect"
+	"time"
+)
+
+const (
+	// managedConfigPath is the system-wide config installed by an organization
+	managedConfigPath = "/etc/cdp/config.json"
+	// managedConfigEnv overrides managedConfigPath
+	managedConfigEnv = "CDP_MANAGED_CONFIG"
+	// orgConfigFile caches the org config fetched from OrgConfigURL at login
+	orgConfigFile = "org.json"
+)
+
+// loadManaged loads the managed config layers that sit beneath the user's
+// config: the system file first, then the cached org config on top of it.
+// Missing or unreadable layers are skipped.
+func loadManaged() *GlobalConfig {
+	managed := &GlobalConfig{}
+
+	path := managedConfigPath
+	if p := os.Getenv(managedConfigEnv); p != "" {
+		path = p
+	}
+	if layer, err := readGlobalFile(path); err == nil {
+		mergeGlobal(managed, layer)
+	}
+
+	if orgPath, err := orgConfigPath(); err == nil {
+		if layer, err := readGlobalFile(orgPath); err == nil {
+			mergeGlobal(managed, layer)
+		}
+	}
+
+	return managed
+}
+
+// ManagedCoolifyURL returns the Coolify URL pinned by the organization, if any
+func ManagedCoolifyURL() string {
+	return loadManaged().CoolifyURL
+}
+
+// ManagedOrgConfigURL returns the org config URL set by the system config
+func ManagedOrgConfigURL() string {
+	return loadManaged().OrgConfigURL
+}
+
+// FetchOrgConfig downloads the org config from url and caches it locally so
+// it applies without network access until the next login
+func FetchOrgConfig(url string) error {
+	client := &http.Client{Timeout: 15 * time.Second}
+	resp, err := client.Get(url)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("unexpected status %s", resp.Status)
+	}
+
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+
+	var layer GlobalConfig
+	if err := json.Unmarshal(data, &layer); err != nil {
+		return fmt.Errorf("invalid org config: %w", err)
+	}
+	// Credentials are personal; never accept them from a shared source
+	layer.CoolifyToken = ""
+	layer.GitHubToken = ""
+	layer.DockerRegistry = nil
+	layer.OrgConfigURL = ""
+
+	path, err := orgConfigPath()
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return err
+	}
+	out, err := json.MarshalIndent(&layer, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, out, 0600)
+}
+
+func orgConfigPath() (string, error) {
+	configPath, err := GetConfigPath()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(filepath.Dir(configPath), orgConfigFile), nil
+}
+
+func readGlobalFile(path string) (*GlobalConfig, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var cfg GlobalConfig
+	if err := json.Unmarshal(data, &cfg); err != nil {
+		return nil, err
+	}
+	return &cfg, nil
+}
+
+// mergeGlobal copies every field set in over onto base
+func mergeGlobal(base, over *GlobalConfig) {
+	if over.CoolifyURL != "" {
+		base.CoolifyURL = over.CoolifyURL
+	}
+	if over.CoolifyToken != "" {
+		base.CoolifyToken = over.CoolifyToken
+	}
+	if over.DefaultServer != "" {
+		base.DefaultServer = over.DefaultServer
+	}
+	if over.DefaultProject != "" {
+		base.DefaultProject = over.DefaultProject
+	}
+	if over.GitHubToken != "" {
+		base.GitHubToken = over.GitHubToken
+	}
+	if over.DockerRegistry != nil {
+		base.DockerRegistry = over.DockerRegistry
+	}
+	if len(over.WorkspaceRoots) > 0 {
+		base.WorkspaceRoots = over.WorkspaceRoots
+	}
+	if over.OrgConfigURL != "" {
+		base.OrgConfigURL = over.OrgConfigURL
+	}
+	if len(over.Webhooks) > 0 {
+		base.Webhooks = over.Webhooks
+	}
+	if over.WebhookSecret != "" {
+		base.WebhookSecret = over.WebhookSecret
+	}
+	if over.Policies != nil {
+		base.Policies = mergePolicies(base.Policies, over.Policies)
+	}
+}
+
+// mergePolicies combines two layers of policies so that the later layer can
+// only add restrictions: a user config can't lift org-managed guardrails
+func mergePolicies(base, over *Policies) *Policies {
+	if base == nil {
+		return over
+	}
+	out := *base
+	out.NoFridayDeploys = base.NoFridayDeploys || over.NoFridayDeploys
+	if out.RequiredBranch == "" {
+		out.RequiredBranch = over.RequiredBranch
+	}
+	if over.RequiredReviewers > out.RequiredReviewers {
+		out.RequiredReviewers = over.RequiredReviewers
+	}
+	out.DisallowedBuildPacks = append([]string(nil), base.DisallowedBuildPacks...)
+	seen := map[string]bool{}
+	for _, bp := range base.DisallowedBuildPacks {
+		seen[bp] = true
+	}
+	for _, bp := range over.DisallowedBuildPacks {
+		if !seen[bp] {
+			seen[bp] = true
+			out.DisallowedBuildPacks = append(out.DisallowedBuildPacks, bp)
+		}
+	}
+	return &out
+}
+
+// stripManaged clears fields of cfg that only hold managed values, so saving
+// the user config doesn't copy org defaults into it
+func stripManaged(cfg, managed *GlobalConfig) *GlobalConfig {
+	out := *cfg
+	if out.CoolifyURL == managed.CoolifyURL {
+		out.CoolifyURL = ""
+	}
+	if out.DefaultServer == managed.DefaultServer {
+		out.DefaultServer = ""
+	}
+	if out.DefaultProject == managed.DefaultProject {
+		out.DefaultProject = ""
+	}
+	if stringsEqual(out.WorkspaceRoots, managed.WorkspaceRoots) {
+		out.WorkspaceRoots = nil
+	}
+	if out.OrgConfigURL == managed.OrgConfigURL {
+		out.OrgConfigURL = ""
+	}
+	if stringsEqual(out.Webhooks, managed.Webhooks) {
+		out.Webhooks = nil
+	}
+	if out.WebhookSecret == managed.WebhookSecret {
+		out.WebhookSecret = ""
+	}
+	if reflect.DeepEqual(out.Policies, managed.Policies) {
+		out.Policies = nil
+	}
+	return &out
+}
+
+func stringsEqual(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,71 @@
+package config
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// onboardingFile records a first run of cdp that hasn't deployed yet, so
+// running cdp again resumes it
+const onboardingFile = "onboarding.json"
+
+// Onboarding is a first run in progress
+type Onboarding struct {
+	Dir       string    `json:"dir"` // project directory being onboarded
+	StartedAt time.Time `json:"started_at"`
+}
+
+// LoadOnboarding returns the onboarding in progress, or nil if there is none
+func LoadOnboarding() *Onboarding {
+	path, err := onboardingPath()
+	if err != nil {
+		return nil
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil
+	}
+	var o Onboarding
+	if json.Unmarshal(data, &o) != nil {
+		return nil
+	}
+	return &o
+}
+
+// SaveOnboarding records o as the onboarding in progress
+func SaveOnboarding(o *Onboarding) error {
+	path, err := onboardingPath()
+	if err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(o, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0600)
+}
+
+// ClearOnboarding forgets the onboarding once the first deploy succeeded
+func ClearOnboarding() error {
+	path, err := onboardingPath()
+	if err != nil {
+		return err
+	}
+	if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	return nil
+}
+
+func onboardingPath() (string, error) {
+	configPath, err := GetConfigPath()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(filepath.Dir(configPath), onboardingFile), nil
+}
@@ -1,13 +1,25 @@
 package config
 
 import (
+	"bytes"
 	"encoding/json"
 	"os"
 	"path/filepath"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/dropalltables/cdp/internal/version"
 )
 
 const projectConfigFile = "cdp.json"
 
+// projectConfigYAMLFiles are YAML alternatives to cdp.json, in lookup order
+var projectConfigYAMLFiles = []string{"cdp.yaml", "cdp.yml"}
+
+// CLIVersion is the version of the running cdp binary, set at startup
+var CLIVersion = "dev"
+
 // LoadProject loads the project configuration from the current directory
 func LoadProject() (*ProjectConfig, error) {
 	return LoadProjectFrom(".")
@@ -15,7 +27,7 @@ func LoadProject() (*ProjectConfig, error) {
 
 // LoadProjectFrom loads the project configuration from a specific directory
 func LoadProjectFrom(dir string) (*ProjectConfig, error) {
-	configPath := filepath.Join(dir, projectConfigFile)
+	configPath := ProjectConfigPath(dir)
 	data, err := os.ReadFile(configPath)
 	if err != nil {
 		if os.IsNotExist(err) {
@@ -24,26 +36,124 @@ func LoadProjectFrom(dir string) (*ProjectConfig, error) {
 		return nil, err
 	}
 
+	if isYAMLConfig(configPath) {
+		if data, err = yamlToJSON(data); err != nil {
+			return nil, err
+		}
+	}
+
 	var cfg ProjectConfig
 	if err := json.Unmarshal(data, &cfg); err != nil {
 		return nil, err
 	}
+	migrateDeprecatedFields(&cfg)
 	return &cfg, nil
 }
 
+// ProjectConfigPath returns the path of the project config in dir.
+// A cdp.yaml (or cdp.yml) is used when present; otherwise cdp.json.
+func ProjectConfigPath(dir string) string {
+	for _, name := range projectConfigYAMLFiles {
+		path := filepath.Join(dir, name)
+		if _, err := os.Stat(path); err == nil {
+			return path
+		}
+	}
+	return filepath.Join(dir, projectConfigFile)
+}
+
+func isYAMLConfig(path string) bool {
+	ext := filepath.Ext(path)
+	return ext == ".yaml" || ext == ".yml"
+}
+
 // SaveProject saves the project configuration to the current directory
 func SaveProject(cfg *ProjectConfig) error {
 	return SaveProjectTo(".", cfg)
 }
 
-// SaveProjectTo saves the project configuration to a specific directory
+// SaveProjectTo saves the project configuration to a specific directory,
+// in YAML if a cdp.yaml exists there and JSON otherwise.
+// Fields written by a newer cdp that this version doesn't know about are preserved.
 func SaveProjectTo(dir string, cfg *ProjectConfig) error {
-	configPath := filepath.Join(dir, projectConfigFile)
-	data, err := json.MarshalIndent(cfg, "", "  ")
+	configPath := ProjectConfigPath(dir)
+	cfg = cfg.forSaving()
+
+	// Never downgrade the recorded version, so older CLIs keep warning
+	var onDisk string
+	if existing, err := LoadProjectFrom(dir); err == nil && existing != nil {
+		onDisk = existing.CDPVersion
+	}
+	if version.IsRelease(CLIVersion) && (!version.IsRelease(onDisk) || version.Compare(CLIVersion, onDisk) >= 0) {
+		cfg.CDPVersion = CLIVersion
+	} else if onDisk != "" {
+		cfg.CDPVersion = onDisk
+	}
+
+	if isYAMLConfig(configPath) {
+		return saveProjectYAML(configPath, cfg)
+	}
+	return saveProjectJSON(configPath, cfg)
+}
+
+func saveProjectJSON(configPath string, cfg *ProjectConfig) error {
+	existing := map[string]json.RawMessage{}
+	if data, err := os.ReadFile(configPath); err == nil {
+		_ = json.Unmarshal(data, &existing)
+	}
+
+	data, err := json.Marshal(cfg)
 	if err != nil {
 		return err
 	}
-	return os.WriteFile(configPath, data, 0644)
+
+	// Append unknown keys after the known fields, keeping struct order
+	known := projectConfigKeys()
+	var extra bytes.Buffer
+	keys := make([]string, 0, len(existing))
+	for key := range existing {
+		if _, ok := known[key]; !ok {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		name, _ := json.Marshal(key)
+		extra.WriteByte(',')
+		extra.Write(name)
+		extra.WriteByte(':')
+		extra.Write(existing[key])
+	}
+	if extra.Len() > 0 {
+		data = append(data[:len(data)-1], append(extra.Bytes(), '}')...)
+	}
+
+	var out bytes.Buffer
+	if err := json.Indent(&out, data, "", "  "); err != nil {
+		return err
+	}
+	return os.WriteFile(configPath, out.Bytes(), 0644)
+}
+
+// IsNewerThanCLI reports whether the config was written by a newer cdp than the one running
+func IsNewerThanCLI(cfg *ProjectConfig) bool {
+	if cfg == nil || !version.IsRelease(cfg.CDPVersion) || !version.IsRelease(CLIVersion) {
+		return false
+	}
+	return version.Compare(cfg.CDPVersion, CLIVersion) > 0
+}
+
+// projectConfigKeys returns the JSON keys known to this version of ProjectConfig
+func projectConfigKeys() map[string]struct{} {
+	keys := map[string]struct{}{}
+	t := reflect.TypeOf(ProjectConfig{})
+	for i := 0; i < t.NumField(); i++ {
+		name := strings.Split(t.Field(i).Tag.Get("json"), ",")[0]
+		if name != "" && name != "-" {
+			keys[name] = struct{}{}
+		}
+	}
+	return keys
 }
 
 // ProjectExists checks if a project config exists in the current directory
@@ -53,7 +163,7 @@ func ProjectExists() bool {
 
 // ProjectExistsIn checks if a project config exists in a specific directory
 func ProjectExistsIn(dir string) bool {
-	configPath := filepath.Join(dir, projectConfigFile)
+	configPath := ProjectConfigPath(dir)
 	_, err := os.Stat(configPath)
 	return err == nil
 }
@@ -65,6 +175,6 @@ func DeleteProject() error {
 
 // DeleteProjectFrom deletes the project configuration from a specific directory
 func DeleteProjectFrom(dir string) error {
-	configPath := filepath.Join(dir, projectConfigFile)
+	configPath := ProjectConfigPath(dir)
 	return os.Remove(configPath)
 }
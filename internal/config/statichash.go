@@ -0,0 +1,16 @@
+package config
+
+// staticHashesFile records the source hash of each app's last successful
+// static deploy
+const staticHashesFile = "static-hashes.json"
+
+// LastStaticHash returns the source hash recorded for appUUID,
+// or "" if there is none
+func LastStaticHash(appUUID string) string {
+	return appStateValue(staticHashesFile, appUUID)
+}
+
+// SaveStaticHash records the source hash deployed to appUUID
+func SaveStaticHash(appUUID, hash string) error {
+	return saveAppStateValue(staticHashesFile, appUUID, hash)
+}
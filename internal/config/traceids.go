@@ -0,0 +1,15 @@
+package config
+
+// traceIDsFile records the trace ID of each app's latest traced deploy
+const traceIDsFile = "trace-ids.json"
+
+// LastTraceID returns the trace ID of appUUID's latest traced deploy,
+// or "" if there is none
+func LastTraceID(appUUID string) string {
+	return appStateValue(traceIDsFile, appUUID)
+}
+
+// SaveTraceID records id as the trace ID of appUUID's latest deploy
+func SaveTraceID(appUUID, id string) error {
+	return saveAppStateValue(traceIDsFile, appUUID, id)
+}
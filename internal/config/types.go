@@ -1,5 +1,11 @@
 package config
 
+import (
+	"strings"
+
+	"github.com/dropalltables/cdp/internal/detect"
+)
+
 // Environment names
 const (
 	EnvPreview    = "preview"
@@ -8,8 +14,15 @@ const (
 
 // Deployment methods
 const (
-	DeployMethodGit    = "git"
-	DeployMethodDocker = "docker"
+	DeployMethodGit     = "git"
+	DeployMethodDocker  = "docker"
+	DeployMethodCompose = "compose" // local compose file deployed as a Coolify service
+)
+
+// Local image builders for Docker deploys
+const (
+	LocalBuilderDockerfile = "dockerfile" // project's Dockerfile, or a generated one
+	LocalBuilderNixpacks   = "nixpacks"   // nixpacks CLI, matching Coolify's git builds
 )
 
 // Default values
@@ -27,19 +40,90 @@ type GlobalConfig struct {
 	DefaultProject string          `json:"default_project,omitempty"`
 	GitHubToken    string          `json:"github_token,omitempty"`
 	DockerRegistry *DockerRegistry `json:"docker_registry,omitempty"`
+	WorkspaceRoots []string        `json:"workspace_roots,omitempty"` // scanned by 'projects status'
+
+	// Org-wide settings, usually provided by the managed config layer
+	OrgConfigURL  string    `json:"org_config_url,omitempty"` // fetched at login
+	Webhooks      []string  `json:"webhooks,omitempty"`       // deploy notification URLs
+	WebhookSecret string    `json:"webhook_secret,omitempty"` // HMAC key for webhook signatures
+	Policies      *Policies `json:"policies,omitempty"`       // guardrails checked before deploy
+
+	// Managed layer this config was merged over; not persisted
+	managed *GlobalConfig
+
+	// Environment overrides applied on top, and the values they replaced;
+	// not persisted
+	env       *GlobalConfig
+	beforeEnv *GlobalConfig
+}
+
+// Policies are organizational guardrails evaluated before every deploy
+type Policies struct {
+	NoFridayDeploys      bool     `json:"no_friday_deploys,omitempty"`
+	RequiredBranch       string   `json:"required_branch,omitempty"`    // only this branch may be deployed
+	RequiredReviewers    int      `json:"required_reviewers,omitempty"` // approvals on the deployed commit's PR
+	DisallowedBuildPacks []string `json:"disallowed_build_packs,omitempty"`
 }
 
 // DockerRegistry stores Docker registry credentials
 type DockerRegistry struct {
 	URL      string `json:"url"`
 	Username string `json:"username"`
-	Password string `json:"password"`
+
+	// Only set by older versions of cdp. It's moved into Docker's
+	// credential store, and then removed, when logging in with it succeeds.
+	Password string `json:"password,omitempty"`
+}
+
+// CronJob declares a scheduled task for the application
+type CronJob struct {
+	Name      string `json:"name"`
+	Schedule  string `json:"schedule"` // cron expression, e.g. "0 * * * *"
+	Command   string `json:"command"`
+	Container string `json:"container,omitempty"`
+}
+
+// BuildCache controls how Coolify reuses work between git deploys
+type BuildCache struct {
+	Disabled    bool     `json:"disabled,omitempty"`     // build without Docker's layer cache
+	InstallDirs []string `json:"install_dirs,omitempty"` // kept between installs, e.g. "node_modules/.cache"
+	BuildDirs   []string `json:"build_dirs,omitempty"`   // kept between builds, e.g. ".next/cache"
+}
+
+// HealthCheck configures the health check Coolify runs against the app;
+// setting it enables the check
+type HealthCheck struct {
+	Path     string `json:"path,omitempty"`     // e.g. "/healthz"
+	Port     string `json:"port,omitempty"`     // defaults to the app's port
+	Interval int    `json:"interval,omitempty"` // seconds between checks
+}
+
+// EnvironmentApp is the app serving one Coolify environment of the project
+type EnvironmentApp struct {
+	EnvironmentUUID string `json:"environment_uuid,omitempty"`
+	AppUUID         string `json:"app_uuid"`
+}
+
+// PlatformTarget is one entry of a per-platform Docker build matrix
+type PlatformTarget struct {
+	Platform  string `json:"platform"`             // e.g. "linux/arm64"
+	TagSuffix string `json:"tag_suffix,omitempty"` // appended to the image tag; defaults to "-<arch>"
+}
+
+// Suffix returns the tag suffix, defaulting to the platform's architecture
+func (t PlatformTarget) Suffix() string {
+	if t.TagSuffix != "" {
+		return t.TagSuffix
+	}
+	parts := strings.Split(t.Platform, "/")
+	return "-" + strings.Join(parts[1:], "")
 }
 
 // ProjectConfig stores per-project deployment configuration
 type ProjectConfig struct {
+	CDPVersion      string `json:"cdp_version,omitempty"` // cdp version that last wrote this file
 	Name            string `json:"name"`
-	DeployMethod    string `json:"deploy_method"` // "docker" or "git"
+	DeployMethod    string `json:"deploy_method"` // "docker", "git" or "compose"
 	ProjectUUID     string `json:"project_uuid"`
 	ServerUUID      string `json:"server_uuid"`
 	EnvironmentUUID string `json:"environment_uuid"` // Single environment for the app
@@ -50,14 +134,71 @@ type ProjectConfig struct {
 	BuildCommand    string `json:"build_command,omitempty"`
 	StartCommand    string `json:"start_command,omitempty"`
 	PublishDir      string `json:"publish_dir,omitempty"`
+	BaseDirectory   string `json:"base_directory,omitempty"` // app package in a monorepo, e.g. "/apps/web"
 	Port            string `json:"port,omitempty"`
-	Platform        string `json:"platform,omitempty"` // linux/amd64, linux/arm64
+	Platform        string `json:"platform,omitempty"` // linux/amd64, linux/arm64, or both comma-separated
 	Branch          string `json:"branch,omitempty"`   // git branch to deploy
 	Domain          string `json:"domain,omitempty"`
 	DockerImage     string `json:"docker_image,omitempty"`
 	GitHubRepo      string `json:"github_repo,omitempty"`
 	GitHubPrivate   bool   `json:"github_private,omitempty"`
 	GitHubAppUUID   string `json:"github_app_uuid,omitempty"`
+	CPULimit        string `json:"cpu_limit,omitempty"`    // e.g. "0.5", "2"
+	MemoryLimit     string `json:"memory_limit,omitempty"` // e.g. "512m", "1g"
+	Replicas        int    `json:"replicas,omitempty"`
+
+	// Docker build arguments, and env vars mounted as BuildKit secrets, for
+	// local Docker builds
+	BuildArgs    map[string]string `json:"build_args,omitempty"`
+	BuildSecrets []string          `json:"build_secrets,omitempty"`
+
+	// How Docker deploys build their image locally: "dockerfile" (default)
+	// or "nixpacks"
+	LocalBuilder string `json:"local_builder,omitempty"`
+
+	// Size above which a built Docker image is reported as too large,
+	// e.g. "500MB"
+	ImageSizeBudget string `json:"image_size_budget,omitempty"`
+
+	// Separate per-platform images for Docker deploys; the app runs the one
+	// matching its server's architecture
+	Platforms []PlatformTarget `json:"platforms,omitempty"`
+
+	// Detected platform of the server, recorded so Docker deploys don't
+	// look it up (possibly over SSH) every time
+	ServerPlatform string `json:"server_platform,omitempty"`
+
+	// Build caching on the Coolify server, applied with 'cdp apply'
+	BuildCache *BuildCache `json:"build_cache,omitempty"`
+
+	// Health check on the Coolify server, applied with 'cdp apply'
+	HealthCheck *HealthCheck `json:"health_check,omitempty"`
+
+	// Scheduled tasks declared for the app
+	CronJobs []CronJob `json:"cron_jobs,omitempty"`
+
+	// Framework presets tried before built-in detection
+	Frameworks []detect.Rule `json:"frameworks,omitempty"`
+
+	// Whether the config file is committed to git; nil until the user decides
+	CommitConfig *bool `json:"commit_config,omitempty"`
+
+	// Per-deploy trace IDs exposed to the app as CDP_DEPLOY_ID
+	TraceDeploys bool `json:"trace_deploys,omitempty"`
+
+	// Coolify service created by 'compose import', and the file it's built from
+	ServiceUUID string `json:"service_uuid,omitempty"`
+	ComposeFile string `json:"compose_file,omitempty"`
+
+	// Extra URLs notified about this project's deploys, besides global ones
+	Webhooks []string `json:"webhooks,omitempty"`
+
+	// Apps of other Coolify environments, keyed by environment name, used by
+	// --env; app_uuid stays the production app
+	Environments map[string]EnvironmentApp `json:"environments,omitempty"`
+
+	// Set on copies made by ForEnvironment
+	target *environmentTarget
 
 	// Legacy fields for migration
 	PreviewEnvUUID string            `json:"preview_env_uuid,omitempty"` // Deprecated
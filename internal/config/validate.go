@@ -0,0 +1,86 @@
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+)
+
+// ValidateProjectFrom checks the project config in dir against the schema
+// this version of cdp understands and returns the problems found. The error
+// is set when the file can't be read or parsed at all.
+func ValidateProjectFrom(dir string) ([]string, error) {
+	configPath := ProjectConfigPath(dir)
+	data, err := os.ReadFile(configPath)
+	if err != nil {
+		return nil, err
+	}
+	if isYAMLConfig(configPath) {
+		if data, err = yamlToJSON(data); err != nil {
+			return nil, err
+		}
+	}
+
+	var raw map[string]json.RawMessage
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return nil, err
+	}
+
+	var problems []string
+	known := projectConfigKeys()
+	var unknown []string
+	for key := range raw {
+		if _, ok := known[key]; !ok {
+			unknown = append(unknown, key)
+		}
+	}
+	sort.Strings(unknown)
+	for _, key := range unknown {
+		problems = append(problems, fmt.Sprintf("unknown key %q", key))
+	}
+
+	var cfg ProjectConfig
+	if err := json.Unmarshal(data, &cfg); err != nil {
+		if typeErr, ok := err.(*json.UnmarshalTypeError); ok {
+			return append(problems, fmt.Sprintf("%q should be %s, not %s", typeErr.Field, typeErr.Type, typeErr.Value)), nil
+		}
+		return nil, err
+	}
+
+	switch cfg.DeployMethod {
+	case "", DeployMethodGit, DeployMethodDocker, DeployMethodCompose:
+	default:
+		problems = append(problems, fmt.Sprintf("deploy_method %q should be git, docker or compose", cfg.DeployMethod))
+	}
+	switch cfg.LocalBuilder {
+	case "", LocalBuilderDockerfile, LocalBuilderNixpacks:
+	default:
+		problems = append(problems, fmt.Sprintf("local_builder %q should be dockerfile or nixpacks", cfg.LocalBuilder))
+	}
+	if cfg.Platform != "" {
+		for _, p := range strings.Split(cfg.Platform, ",") {
+			if !strings.HasPrefix(strings.TrimSpace(p), "linux/") {
+				problems = append(problems, fmt.Sprintf("platform %q should look like linux/amd64", strings.TrimSpace(p)))
+			}
+		}
+	}
+	for i, t := range cfg.Platforms {
+		if !strings.HasPrefix(t.Platform, "linux/") {
+			problems = append(problems, fmt.Sprintf("platforms[%d].platform %q should look like linux/arm64", i, t.Platform))
+		}
+	}
+	if cfg.Replicas < 0 {
+		problems = append(problems, "replicas can't be negative")
+	}
+	for i, job := range cfg.CronJobs {
+		if job.Name == "" || job.Schedule == "" || job.Command == "" {
+			problems = append(problems, fmt.Sprintf("cron_jobs[%d] needs a name, schedule and command", i))
+		}
+	}
+	if cfg.DeployMethod == DeployMethodCompose && cfg.ComposeFile == "" {
+		problems = append(problems, "compose deploys need compose_file")
+	}
+	return problems, nil
+}
@@ -0,0 +1,100 @@
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// WorkspaceConfigFile lists the apps of a monorepo for 'deploy --all'
+const WorkspaceConfigFile = "cdp.workspace.json"
+
+// WorkspaceApp is one app directory of a workspace, with its own cdp.json
+type WorkspaceApp struct {
+	Name      string   `json:"name,omitempty"` // defaults to the directory name
+	Path      string   `json:"path"`           // relative to the workspace file
+	DependsOn []string `json:"depends_on,omitempty"`
+}
+
+// WorkspaceConfig is the contents of cdp.workspace.json
+type WorkspaceConfig struct {
+	Apps []WorkspaceApp `json:"apps"`
+}
+
+// LoadWorkspace loads cdp.workspace.json from dir, returning nil when there
+// is none. App names default to their directory and must be unique.
+func LoadWorkspace(dir string) (*WorkspaceConfig, error) {
+	data, err := os.ReadFile(filepath.Join(dir, WorkspaceConfigFile))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	var ws WorkspaceConfig
+	if err := json.Unmarshal(data, &ws); err != nil {
+		return nil, fmt.Errorf("invalid %s: %w", WorkspaceConfigFile, err)
+	}
+	seen := map[string]bool{}
+	for i := range ws.Apps {
+		app := &ws.Apps[i]
+		if strings.TrimSpace(app.Path) == "" {
+			return nil, fmt.Errorf("app %d in %s has no path", i+1, WorkspaceConfigFile)
+		}
+		if app.Name == "" {
+			app.Name = filepath.Base(filepath.Clean(app.Path))
+		}
+		if seen[app.Name] {
+			return nil, fmt.Errorf("app %q is listed twice in %s", app.Name, WorkspaceConfigFile)
+		}
+		seen[app.Name] = true
+	}
+	return &ws, nil
+}
+
+// DeployOrder returns the apps ordered so that each comes after the apps it
+// depends on, keeping the file's order otherwise
+func (w *WorkspaceConfig) DeployOrder() ([]WorkspaceApp, error) {
+	byName := make(map[string]WorkspaceApp, len(w.Apps))
+	for _, app := range w.Apps {
+		byName[app.Name] = app
+	}
+
+	const (
+		visiting = 1
+		done     = 2
+	)
+	state := map[string]int{}
+	var order []WorkspaceApp
+	var visit func(app WorkspaceApp, chain []string) error
+	visit = func(app WorkspaceApp, chain []string) error {
+		switch state[app.Name] {
+		case done:
+			return nil
+		case visiting:
+			return fmt.Errorf("apps depend on each other: %s", strings.Join(append(chain, app.Name), " → "))
+		}
+		state[app.Name] = visiting
+		for _, dep := range app.DependsOn {
+			depApp, ok := byName[dep]
+			if !ok {
+				return fmt.Errorf("app %q depends on %q, which isn't in %s", app.Name, dep, WorkspaceConfigFile)
+			}
+			if err := visit(depApp, append(chain, app.Name)); err != nil {
+				return err
+			}
+		}
+		state[app.Name] = done
+		order = append(order, app)
+		return nil
+	}
+	for _, app := range w.Apps {
+		if err := visit(app, nil); err != nil {
+			return nil, err
+		}
+	}
+	return order, nil
+}
@@ -0,0 +1,98 @@
+package config
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+
+	"gopkg.in/yaml.v3"
+)
+
+// yamlToJSON converts a YAML document to JSON so it can be decoded
+// with the same struct tags as cdp.json
+func yamlToJSON(data []byte) ([]byte, error) {
+	var v interface{}
+	if err := yaml.Unmarshal(data, &v); err != nil {
+		return nil, err
+	}
+	if v == nil {
+		return []byte("{}"), nil
+	}
+	return json.Marshal(v)
+}
+
+// saveProjectYAML writes cfg as YAML. When the file already exists its
+// comments, key order, and keys unknown to this version are preserved.
+func saveProjectYAML(configPath string, cfg *ProjectConfig) error {
+	data, err := json.Marshal(cfg)
+	if err != nil {
+		return err
+	}
+
+	// JSON is valid YAML, so this yields a mapping in struct field order
+	var updated yaml.Node
+	if err := yaml.Unmarshal(data, &updated); err != nil {
+		return err
+	}
+	values := updated.Content[0]
+	blockStyle(values)
+
+	root := values
+	if existing, err := os.ReadFile(configPath); err == nil {
+		var doc yaml.Node
+		if err := yaml.Unmarshal(existing, &doc); err == nil && len(doc.Content) > 0 && doc.Content[0].Kind == yaml.MappingNode {
+			mergeMapping(doc.Content[0], values, projectConfigKeys())
+			root = &doc
+		}
+	}
+
+	var out bytes.Buffer
+	enc := yaml.NewEncoder(&out)
+	enc.SetIndent(2)
+	if err := enc.Encode(root); err != nil {
+		return err
+	}
+	if err := enc.Close(); err != nil {
+		return err
+	}
+	return os.WriteFile(configPath, out.Bytes(), 0644)
+}
+
+// mergeMapping updates dst in place with the entries of src.
+// Existing keys keep their position and comments, new keys are appended,
+// and known keys that are absent from src (omitted zero values) are removed.
+func mergeMapping(dst, src *yaml.Node, known map[string]struct{}) {
+	srcValues := map[string]*yaml.Node{}
+	for i := 0; i+1 < len(src.Content); i += 2 {
+		srcValues[src.Content[i].Value] = src.Content[i+1]
+	}
+
+	seen := map[string]bool{}
+	var content []*yaml.Node
+	for i := 0; i+1 < len(dst.Content); i += 2 {
+		key, value := dst.Content[i], dst.Content[i+1]
+		if replacement, ok := srcValues[key.Value]; ok {
+			replacement.HeadComment = value.HeadComment
+			replacement.LineComment = value.LineComment
+			replacement.FootComment = value.FootComment
+			content = append(content, key, replacement)
+			seen[key.Value] = true
+		} else if _, isKnown := known[key.Value]; !isKnown {
+			content = append(content, key, value)
+		}
+	}
+	for i := 0; i+1 < len(src.Content); i += 2 {
+		if !seen[src.Content[i].Value] {
+			content = append(content, src.Content[i], src.Content[i+1])
+		}
+	}
+	dst.Content = content
+}
+
+// blockStyle clears the flow style inherited from JSON input
+func blockStyle(n *yaml.Node) {
+	n.Style &^= yaml.FlowStyle | yaml.DoubleQuotedStyle
+	for _, c := range n.Content {
+		blockStyle(c)
+	}
+}
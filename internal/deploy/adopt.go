@@ -0,0 +1,83 @@
+package deploy
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/dropalltables/cdp/internal/api"
+	"github.com/dropalltables/cdp/internal/config"
+	"github.com/dropalltables/cdp/internal/ui"
+)
+
+// ExistingAppError is returned when the app cdp is about to create already
+// exists in the target environment, usually left by an interrupted first
+// deploy. The caller can adopt it instead of failing.
+type ExistingAppError struct {
+	App api.Application
+}
+
+func (e *ExistingAppError) Error() string {
+	return fmt.Sprintf("an application named %q already exists in this environment", e.App.Name)
+}
+
+// findExistingApp returns the app in the project's environment with the
+// given name or git repository, or nil when there is none
+func findExistingApp(client *api.Client, projectCfg *config.ProjectConfig, name, repo string) (*api.Application, error) {
+	project, err := client.GetProject(projectCfg.ProjectUUID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load project: %w", err)
+	}
+	envID := 0
+	for _, e := range project.Environments {
+		if e.UUID == projectCfg.EnvironmentUUID {
+			envID = e.ID
+			break
+		}
+	}
+	if envID == 0 {
+		return nil, nil
+	}
+
+	apps, err := client.ListApplications()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list applications: %w", err)
+	}
+	for i, app := range apps {
+		if app.EnvironmentID != envID {
+			continue
+		}
+		if strings.EqualFold(app.Name, name) || (repo != "" && strings.EqualFold(app.GitRepository, repo)) {
+			return &apps[i], nil
+		}
+	}
+	return nil, nil
+}
+
+// adoptExistingApp offers to link projectCfg to the app in an
+// ExistingAppError and saves the choice. It reports whether the app was
+// adopted; err is returned unchanged when it isn't an ExistingAppError.
+func adoptExistingApp(projectCfg *config.ProjectConfig, err error) (bool, error) {
+	var existing *ExistingAppError
+	if !errors.As(err, &existing) {
+		return false, err
+	}
+
+	ui.Spacer()
+	ui.Warning(fmt.Sprintf("Application %q already exists in this environment", existing.App.Name))
+	ui.Dim("  It was probably created by an earlier, interrupted deploy")
+	adopt, promptErr := ui.ConfirmWithOptions("Use the existing application?", ui.ConfirmOptions{Default: true})
+	if promptErr != nil {
+		return false, promptErr
+	}
+	if !adopt {
+		return false, err
+	}
+
+	projectCfg.AppUUID = existing.App.UUID
+	if err := config.SaveProject(projectCfg); err != nil {
+		return false, fmt.Errorf("failed to save configuration: %w", err)
+	}
+	ui.Success(fmt.Sprintf("Linked to existing application %s", existing.App.Name))
+	return true, nil
+}
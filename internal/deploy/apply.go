@@ -0,0 +1,245 @@
+package deploy
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/dropalltables/cdp/internal/api"
+	"github.com/dropalltables/cdp/internal/config"
+)
+
+// SettingChange is a setting whose cdp.json value differs from the app's
+type SettingChange struct {
+	Setting string      // cdp.json key, e.g. "build_command"
+	Field   string      // application field to update, e.g. "build_command"
+	Remote  string      // current value in Coolify
+	Local   string      // value from cdp.json
+	Value   interface{} // value sent to Coolify
+}
+
+// PlanAppSettings compares the settings declared in projectCfg with the
+// app's settings and returns the ones to update. Settings that cdp.json
+// leaves out aren't managed and never show up.
+func PlanAppSettings(projectCfg *config.ProjectConfig, app map[string]interface{}) []SettingChange {
+	var changes []SettingChange
+	remote := func(field string) string { return remoteSetting(app, field) }
+	str := func(setting, field, local string) {
+		if local != "" && local != remote(field) {
+			changes = append(changes, SettingChange{Setting: setting, Field: field, Remote: remote(field), Local: local, Value: local})
+		}
+	}
+	num := func(setting, field string, local int) {
+		if local > 0 && strconv.Itoa(local) != remote(field) {
+			changes = append(changes, SettingChange{Setting: setting, Field: field, Remote: remote(field), Local: strconv.Itoa(local), Value: local})
+		}
+	}
+
+	str("install_command", "install_command", projectCfg.InstallCommand)
+	str("build_command", "build_command", projectCfg.BuildCommand)
+	str("start_command", "start_command", projectCfg.StartCommand)
+	str("publish_dir", "publish_directory", projectCfg.PublishDir)
+	str("base_directory", "base_directory", projectCfg.BaseDirectory)
+	str("port", "ports_exposes", projectCfg.Port)
+	if projectCfg.DeployMethod == config.DeployMethodGit {
+		str("branch", "git_branch", projectCfg.Branch)
+		str("build_pack", "build_pack", projectCfg.BuildPack)
+	}
+	str("cpu_limit", "limits_cpus", projectCfg.CPULimit)
+	str("memory_limit", "limits_memory", projectCfg.MemoryLimit)
+	num("replicas", "swarm_replicas", projectCfg.Replicas)
+
+	// Domains are added to the app's domains rather than replacing them
+	var missing []string
+	for _, d := range strings.Split(projectCfg.Domain, ",") {
+		if d = strings.TrimSpace(d); d != "" && !hasDomain(remote("fqdn"), d) {
+			missing = append(missing, d)
+		}
+	}
+	if len(missing) > 0 {
+		domains := strings.Join(missing, ",")
+		if fqdn := remote("fqdn"); fqdn != "" {
+			domains = fqdn + "," + domains
+		}
+		changes = append(changes, SettingChange{Setting: "domain", Field: "domains", Remote: remote("fqdn"), Local: projectCfg.Domain, Value: domains})
+	}
+
+	if hc := projectCfg.HealthCheck; hc != nil {
+		if remote("health_check_enabled") != "true" {
+			changes = append(changes, SettingChange{Setting: "health_check", Field: "health_check_enabled", Remote: "disabled", Local: "enabled", Value: true})
+		}
+		str("health_check.path", "health_check_path", hc.Path)
+		str("health_check.port", "health_check_port", hc.Port)
+		num("health_check.interval", "health_check_interval", hc.Interval)
+	}
+	return changes
+}
+
+// PullAppSettings is the inverse of PlanAppSettings: it copies the app's
+// settings into projectCfg and returns the ones that changed, with the
+// app's value as Value. Settings the app leaves empty keep their cdp.json
+// value. The caller saves projectCfg.
+func PullAppSettings(projectCfg *config.ProjectConfig, app map[string]interface{}) []SettingChange {
+	var changes []SettingChange
+	str := func(setting, field string, local *string) {
+		v := remoteSetting(app, field)
+		if v == "" || v == *local {
+			return
+		}
+		changes = append(changes, SettingChange{Setting: setting, Field: field, Remote: v, Local: *local, Value: v})
+		*local = v
+	}
+	// Coolify reports an unset limit as "0"
+	limit := func(setting, field string, local *string) {
+		if remoteSetting(app, field) != "0" {
+			str(setting, field, local)
+		}
+	}
+	num := func(setting, field string, local *int) {
+		v, err := strconv.Atoi(remoteSetting(app, field))
+		if err != nil || v == *local || (v == 1 && *local == 0) {
+			return
+		}
+		changes = append(changes, SettingChange{Setting: setting, Field: field, Remote: strconv.Itoa(v), Local: intSetting(*local), Value: v})
+		*local = v
+	}
+
+	str("install_command", "install_command", &projectCfg.InstallCommand)
+	str("build_command", "build_command", &projectCfg.BuildCommand)
+	str("start_command", "start_command", &projectCfg.StartCommand)
+	str("publish_dir", "publish_directory", &projectCfg.PublishDir)
+	str("base_directory", "base_directory", &projectCfg.BaseDirectory)
+	str("port", "ports_exposes", &projectCfg.Port)
+	if projectCfg.DeployMethod == config.DeployMethodGit {
+		str("branch", "git_branch", &projectCfg.Branch)
+		str("build_pack", "build_pack", &projectCfg.BuildPack)
+	}
+	limit("cpu_limit", "limits_cpus", &projectCfg.CPULimit)
+	limit("memory_limit", "limits_memory", &projectCfg.MemoryLimit)
+	num("replicas", "swarm_replicas", &projectCfg.Replicas)
+
+	// cdp.json keeps the app's full domain list, as 'domains add' does
+	if fqdn := remoteSetting(app, "fqdn"); fqdn != "" && !sameDomains(fqdn, projectCfg.Domain) {
+		changes = append(changes, SettingChange{Setting: "domain", Field: "fqdn", Remote: fqdn, Local: projectCfg.Domain, Value: fqdn})
+		projectCfg.Domain = fqdn
+	}
+
+	if remoteSetting(app, "health_check_enabled") == "true" {
+		if projectCfg.HealthCheck == nil {
+			changes = append(changes, SettingChange{Setting: "health_check", Field: "health_check_enabled", Remote: "enabled", Local: "disabled", Value: true})
+			projectCfg.HealthCheck = &config.HealthCheck{}
+		}
+		hc := projectCfg.HealthCheck
+		str("health_check.path", "health_check_path", &hc.Path)
+		str("health_check.port", "health_check_port", &hc.Port)
+		num("health_check.interval", "health_check_interval", &hc.Interval)
+	} else if projectCfg.HealthCheck != nil && remoteSetting(app, "health_check_enabled") == "false" {
+		changes = append(changes, SettingChange{Setting: "health_check", Field: "health_check_enabled", Remote: "disabled", Local: "enabled", Value: false})
+		projectCfg.HealthCheck = nil
+	}
+	return changes
+}
+
+// driftSettings are the settings Drift reports, the ones a dashboard edit
+// most often changes under a deploy's feet
+var driftSettings = map[string]bool{
+	"install_command": true,
+	"build_command":   true,
+	"start_command":   true,
+	"port":            true,
+	"branch":          true,
+}
+
+// Drift returns the build commands, port, branch and domains on which
+// cdp.json and the app disagree. Unlike PlanAppSettings, domains drift
+// when the app has ones cdp.json doesn't list as well.
+func Drift(projectCfg *config.ProjectConfig, app map[string]interface{}) []SettingChange {
+	var drift []SettingChange
+	for _, c := range PlanAppSettings(projectCfg, app) {
+		if driftSettings[c.Setting] {
+			drift = append(drift, c)
+		}
+	}
+	if fqdn := remoteSetting(app, "fqdn"); projectCfg.Domain != "" && !sameDomains(fqdn, projectCfg.Domain) {
+		drift = append(drift, SettingChange{Setting: "domain", Field: "domains", Remote: fqdn, Local: projectCfg.Domain})
+	}
+	return drift
+}
+
+// ApplySettingChanges updates the app with all changes at once
+func ApplySettingChanges(client *api.Client, appUUID string, changes []SettingChange) error {
+	if len(changes) == 0 {
+		return nil
+	}
+	updates := make(map[string]interface{}, len(changes))
+	for _, c := range changes {
+		updates[c.Field] = c.Value
+	}
+	if err := client.UpdateApplication(appUUID, updates); err != nil {
+		return fmt.Errorf("failed to update application settings: %w", err)
+	}
+	return nil
+}
+
+// remoteSetting returns the app's field as a string, or "" when it's unset
+func remoteSetting(app map[string]interface{}, field string) string {
+	v, ok := app[field]
+	if !ok || v == nil {
+		return ""
+	}
+	// JSON numbers decode as float64; whole numbers print without a
+	// fraction so they compare with cdp.json's strings
+	if f, ok := v.(float64); ok && f == float64(int64(f)) {
+		return strconv.FormatInt(int64(f), 10)
+	}
+	return fmt.Sprint(v)
+}
+
+// intSetting formats a numeric cdp.json setting, leaving zero (unset) empty
+func intSetting(n int) string {
+	if n == 0 {
+		return ""
+	}
+	return strconv.Itoa(n)
+}
+
+// sameDomains reports whether two comma-separated domain lists name the
+// same hosts, ignoring order
+func sameDomains(a, b string) bool {
+	count := func(list string) int {
+		n := 0
+		for _, d := range strings.Split(list, ",") {
+			if strings.TrimSpace(d) != "" {
+				n++
+			}
+		}
+		return n
+	}
+	if count(a) != count(b) {
+		return false
+	}
+	for _, d := range strings.Split(a, ",") {
+		if strings.TrimSpace(d) != "" && !hasDomain(b, d) {
+			return false
+		}
+	}
+	return true
+}
+
+// hasDomain reports whether Coolify's comma-separated fqdn includes the
+// host of domain
+func hasDomain(fqdn, domain string) bool {
+	host := func(d string) string {
+		d = strings.TrimSpace(d)
+		if i := strings.Index(d, "://"); i >= 0 {
+			d = d[i+3:]
+		}
+		return strings.ToLower(strings.TrimSuffix(d, "/"))
+	}
+	for _, d := range strings.Split(fqdn, ",") {
+		if host(d) == host(domain) {
+			return true
+		}
+	}
+	return false
+}
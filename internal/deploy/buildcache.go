@@ -0,0 +1,92 @@
+package deploy
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/dropalltables/cdp/internal/api"
+	"github.com/dropalltables/cdp/internal/config"
+	"github.com/dropalltables/cdp/internal/detect"
+)
+
+// Build-time variables nixpacks reads extra cache directories from. The
+// directories are kept in BuildKit cache mounts on the server, so they
+// survive between deploys.
+const (
+	nixpacksInstallCacheDirs = "NIXPACKS_INSTALL_CACHE_DIRS"
+	nixpacksBuildCacheDirs   = "NIXPACKS_BUILD_CACHE_DIRS"
+)
+
+// ErrBuildCacheUnsupported is returned when the Coolify server doesn't
+// accept the build cache setting
+var ErrBuildCacheUnsupported = errors.New("this Coolify version doesn't support disabling the build cache")
+
+// ApplyBuildCache pushes the build_cache settings in cdp.json to the app.
+// Cache directories only apply to apps Coolify builds with nixpacks.
+func ApplyBuildCache(client *api.Client, projectCfg *config.ProjectConfig) error {
+	cache := projectCfg.BuildCache
+	if cache == nil {
+		return nil
+	}
+
+	if err := client.UpdateApplication(projectCfg.AppUUID, map[string]interface{}{
+		"disable_build_cache": cache.Disabled,
+	}); err != nil {
+		// Without the setting the cache is on, which is fine unless the
+		// user asked to turn it off
+		if !api.IsValidation(err) {
+			return fmt.Errorf("failed to update build cache setting: %w", err)
+		}
+		if cache.Disabled {
+			return ErrBuildCacheUnsupported
+		}
+	}
+
+	if len(cache.InstallDirs) == 0 && len(cache.BuildDirs) == 0 {
+		return nil
+	}
+	if !UsesNixpacks(projectCfg) {
+		return fmt.Errorf("build cache directories need the nixpacks build pack")
+	}
+
+	envVars, err := client.GetApplicationEnvVars(projectCfg.AppUUID)
+	if err != nil {
+		return fmt.Errorf("failed to fetch environment variables: %w", err)
+	}
+	remote := map[string]api.EnvVar{}
+	for _, env := range envVars {
+		if !env.IsPreview {
+			remote[env.Key] = env
+		}
+	}
+
+	for key, dirs := range map[string][]string{
+		nixpacksInstallCacheDirs: cache.InstallDirs,
+		nixpacksBuildCacheDirs:   cache.BuildDirs,
+	} {
+		if len(dirs) == 0 {
+			continue
+		}
+		value := strings.Join(dirs, ",")
+		existing, ok := remote[key]
+		switch {
+		case !ok:
+			_, err = client.CreateApplicationEnvVar(projectCfg.AppUUID, key, value, true, false)
+		case existing.Value != value || !existing.IsBuildTime:
+			err = client.UpdateApplicationEnvVar(projectCfg.AppUUID, key, value, true, false)
+		}
+		if err != nil {
+			return fmt.Errorf("failed to set %s: %w", key, err)
+		}
+	}
+	return nil
+}
+
+// UsesNixpacks reports whether Coolify builds the app with nixpacks
+func UsesNixpacks(projectCfg *config.ProjectConfig) bool {
+	if projectCfg.DeployMethod == config.DeployMethodDocker || projectCfg.DeployMethod == config.DeployMethodCompose {
+		return false
+	}
+	return projectCfg.BuildPack == "" || projectCfg.BuildPack == detect.BuildPackNixpacks
+}
@@ -0,0 +1,127 @@
+package deploy
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/dropalltables/cdp/internal/api"
+)
+
+const (
+	// bulkPollInterval is how often bulk redeploys check on each deployment
+	bulkPollInterval = 3 * time.Second
+	// bulkTimeout bounds the wait for a single deployment to finish
+	bulkTimeout = 30 * time.Minute
+)
+
+// BulkOptions control a bulk redeploy
+type BulkOptions struct {
+	Restart     bool // restart containers instead of rebuilding
+	Force       bool // rebuild without the build cache
+	Concurrency int  // deployments in flight at once
+}
+
+// BulkResult is the outcome of redeploying one application
+type BulkResult struct {
+	App      api.Application
+	Status   string // final deployment status, e.g. "finished"
+	Duration time.Duration
+	Err      error
+}
+
+// RedeployAll redeploys or restarts apps, with at most opts.Concurrency
+// deployments running at once, and waits for each to finish. progress is
+// called after each app with the number done so far. Results are in the
+// order of apps.
+func RedeployAll(client *api.Client, apps []api.Application, opts BulkOptions, progress func(done int)) []BulkResult {
+	concurrency := opts.Concurrency
+	if concurrency < 1 {
+		concurrency = 1
+	}
+
+	results := make([]BulkResult, len(apps))
+	queue := make(chan int)
+	var mu sync.Mutex
+	done := 0
+
+	var wg sync.WaitGroup
+	for w := 0; w < concurrency && w < len(apps); w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range queue {
+				results[i] = redeployOne(client, apps[i], opts)
+
+				mu.Lock()
+				done++
+				n := done
+				mu.Unlock()
+				if progress != nil {
+					progress(n)
+				}
+			}
+		}()
+	}
+	for i := range apps {
+		queue <- i
+	}
+	close(queue)
+	wg.Wait()
+	return results
+}
+
+func redeployOne(client *api.Client, app api.Application, opts BulkOptions) (result BulkResult) {
+	result = BulkResult{App: app}
+	start := time.Now()
+	defer func() { result.Duration = time.Since(start) }()
+
+	var deploymentUUID string
+	if opts.Restart {
+		uuid, err := client.RestartApplication(app.UUID)
+		if err != nil {
+			result.Err = fmt.Errorf("failed to restart: %w", err)
+			return result
+		}
+		deploymentUUID = uuid
+	} else {
+		resp, err := client.Deploy(app.UUID, opts.Force, 0)
+		if err != nil {
+			result.Err = fmt.Errorf("failed to trigger deployment: %w", err)
+			return result
+		}
+		if len(resp.Deployments) > 0 {
+			deploymentUUID = resp.Deployments[0].DeploymentUUID
+		}
+	}
+	if deploymentUUID == "" {
+		result.Err = fmt.Errorf("no deployment was queued")
+		return result
+	}
+
+	result.Status, result.Err = WaitForDeployment(client, deploymentUUID)
+	return result
+}
+
+// WaitForDeployment polls a deployment until it finishes and returns its
+// final status, with an error if it didn't succeed
+func WaitForDeployment(client *api.Client, deploymentUUID string) (string, error) {
+	deadline := time.Now().Add(bulkTimeout)
+	for time.Now().Before(deadline) {
+		time.Sleep(bulkPollInterval)
+		detail, err := client.GetDeployment(deploymentUUID)
+		if err != nil {
+			// Transient API errors shouldn't abandon a running deployment
+			continue
+		}
+		status := strings.ToLower(strings.TrimSpace(detail.Status))
+		switch status {
+		case "finished":
+			return status, nil
+		case "failed", "error", "cancelled", "cancelled-by-user":
+			return status, fmt.Errorf("deployment %s", status)
+		}
+	}
+	return "timed out", fmt.Errorf("deployment did not finish within %s", bulkTimeout)
+}
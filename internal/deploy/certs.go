@@ -0,0 +1,96 @@
+package deploy
+
+import (
+	"crypto/tls"
+	"errors"
+	"net"
+	"strings"
+	"time"
+)
+
+const (
+	// tlsTimeout bounds the handshake with one domain
+	tlsTimeout = 10 * time.Second
+
+	// CertExpiryWarning is how close to expiry a certificate is flagged.
+	// Let's Encrypt renews 30 days ahead, so a certificate this close to
+	// expiry has failed to renew.
+	CertExpiryWarning = 14 * 24 * time.Hour
+)
+
+// traefikDefaultCert is the subject of the self-signed certificate Coolify's
+// Traefik proxy serves for a domain it has no certificate for
+const traefikDefaultCert = "TRAEFIK DEFAULT CERT"
+
+// CertCheck is the certificate a domain serves over HTTPS
+type CertCheck struct {
+	Host      string
+	Issuer    string
+	NotAfter  time.Time
+	Default   bool  // the proxy's placeholder, as no certificate was issued
+	VerifyErr error // the certificate was served but isn't trusted
+	Err       error // no TLS handshake was possible
+}
+
+// Valid reports whether the domain serves a trusted certificate
+func (c CertCheck) Valid() bool {
+	return c.Err == nil && c.VerifyErr == nil
+}
+
+// DaysLeft returns the whole days until the certificate expires
+func (c CertCheck) DaysLeft() int {
+	return int(time.Until(c.NotAfter).Hours() / 24)
+}
+
+// ExpiringSoon reports whether a valid certificate expires within
+// CertExpiryWarning
+func (c CertCheck) ExpiringSoon() bool {
+	return c.Valid() && time.Until(c.NotAfter) < CertExpiryWarning
+}
+
+// CheckCerts connects to each host on port 443 and records the certificate
+// it serves. A certificate that fails verification is still inspected, so
+// an expired or placeholder certificate can be told apart from no HTTPS.
+func CheckCerts(hosts []string) []CertCheck {
+	checks := make([]CertCheck, len(hosts))
+	for i, host := range hosts {
+		checks[i] = checkCert(host)
+	}
+	return checks
+}
+
+func checkCert(host string) CertCheck {
+	check := CertCheck{Host: host}
+	addr := net.JoinHostPort(host, "443")
+	dialer := &net.Dialer{Timeout: tlsTimeout}
+
+	conn, err := tls.DialWithDialer(dialer, "tcp", addr, &tls.Config{ServerName: host})
+	if err != nil {
+		var certErr *tls.CertificateVerificationError
+		if !errors.As(err, &certErr) {
+			check.Err = err
+			return check
+		}
+		check.VerifyErr = err
+
+		// Verification is only skipped to describe what was served
+		conn, err = tls.DialWithDialer(dialer, "tcp", addr, &tls.Config{ServerName: host, InsecureSkipVerify: true})
+		if err != nil {
+			return check
+		}
+	}
+	defer conn.Close()
+
+	certs := conn.ConnectionState().PeerCertificates
+	if len(certs) == 0 {
+		return check
+	}
+	leaf := certs[0]
+	check.NotAfter = leaf.NotAfter
+	check.Issuer = leaf.Issuer.CommonName
+	if len(leaf.Issuer.Organization) > 0 {
+		check.Issuer = leaf.Issuer.Organization[0]
+	}
+	check.Default = strings.EqualFold(leaf.Subject.CommonName, traefikDefaultCert)
+	return check
+}
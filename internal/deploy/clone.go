@@ -0,0 +1,134 @@
+package deploy
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/dropalltables/cdp/internal/api"
+	"github.com/dropalltables/cdp/internal/config"
+	"github.com/dropalltables/cdp/internal/ui"
+)
+
+// CloneOptions describe the copy made by CloneApp
+type CloneOptions struct {
+	ProjectUUID string // target project
+	Environment string // target environment name, created if missing
+	Name        string // name of the copy
+	Domain      string // domains of the copy; the source's are never reused
+
+	// Environment variables set on the copy
+	EnvVars []api.EnvVar
+}
+
+// CloneApp creates a copy of the linked app in another environment or
+// project, on the same server, from the same repository or image and with
+// the same settings. It returns the copy's UUID; nothing is deployed.
+func CloneApp(client *api.Client, projectCfg *config.ProjectConfig, opts CloneOptions) (string, error) {
+	target := *projectCfg
+	target.ProjectUUID = opts.ProjectUUID
+	target.EnvironmentUUID = ""
+	target.Name = opts.Name
+
+	var app map[string]interface{}
+	var uuid string
+	var results ui.Results
+
+	tasks := []ui.Task{
+		{
+			Name:         "load-app",
+			ActiveName:   "Loading application...",
+			CompleteName: "Loaded application",
+			Action: func() error {
+				var err error
+				app, err = client.GetApplicationSettings(projectCfg.AppUUID)
+				return err
+			},
+		},
+		{
+			Name:         "setup-env",
+			ActiveName:   fmt.Sprintf("Setting up %s environment...", opts.Environment),
+			CompleteName: fmt.Sprintf("Environment %s ready", opts.Environment),
+			Action: func() error {
+				var err error
+				target.EnvironmentUUID, err = ensureEnvironment(client, opts.ProjectUUID, opts.Environment)
+				return err
+			},
+		},
+		{
+			Name:         "create-app",
+			ActiveName:   fmt.Sprintf("Creating %s...", opts.Name),
+			CompleteName: fmt.Sprintf("Created %s", opts.Name),
+			Action: func() error {
+				source := make(map[string]interface{}, len(app))
+				for k, v := range app {
+					source[k] = v
+				}
+				source["name"] = opts.Name
+
+				var err error
+				uuid, err = createRestoredApp(client, &target, source, projectCfg.GitHubAppUUID != "")
+				return err
+			},
+		},
+		{
+			Name:         "copy-settings",
+			ActiveName:   "Copying settings...",
+			CompleteName: "Copied settings",
+			Action: func() error {
+				updates := copiedSettings(app)
+				if opts.Domain != "" {
+					updates["domains"] = opts.Domain
+				}
+				if len(updates) == 0 {
+					return nil
+				}
+				return client.UpdateApplication(uuid, updates)
+			},
+		},
+	}
+	if len(opts.EnvVars) > 0 {
+		tasks = append(tasks, ui.Task{
+			Name:         "copy-env",
+			ActiveName:   "Setting environment variables...",
+			CompleteName: fmt.Sprintf("Set %d environment variables", len(opts.EnvVars)),
+			Action: func() error {
+				for _, env := range opts.EnvVars {
+					_, err := client.CreateApplicationEnvVar(uuid, env.Key, env.Value, env.IsBuildTime, false)
+					results.Add(env.Key, "set", err)
+				}
+				return nil
+			},
+		})
+	}
+
+	if err := ui.RunTasks(tasks); err != nil {
+		ui.Error("Failed to clone application")
+		return uuid, err
+	}
+	// A copy missing variables shouldn't be deployed, so the caller stops here
+	results.Render()
+	if err := results.Err(); err != nil {
+		ui.Dim(fmt.Sprintf("  The copy was created (%s); set the missing variables before deploying it", uuid))
+		return uuid, fmt.Errorf("failed to set environment variables: %w", err)
+	}
+	return uuid, nil
+}
+
+// ensureEnvironment returns the UUID of the named environment of a
+// project, creating it if it doesn't exist
+func ensureEnvironment(client *api.Client, projectUUID, name string) (string, error) {
+	project, err := client.GetProject(projectUUID)
+	if err != nil {
+		return "", fmt.Errorf("failed to load project: %w", err)
+	}
+	for _, env := range project.Environments {
+		if strings.EqualFold(env.Name, name) {
+			return env.UUID, nil
+		}
+	}
+	env, err := client.CreateEnvironment(projectUUID, name)
+	if err != nil {
+		return "", fmt.Errorf("failed to create %s environment: %w", name, err)
+	}
+	return env.UUID, nil
+}
@@ -0,0 +1,247 @@
+package deploy
+
+import (
+	"encoding/base64"
+	"fmt"
+	"os"
+	"regexp"
+	"strings"
+
+	"github.com/dropalltables/cdp/internal/api"
+	"github.com/dropalltables/cdp/internal/config"
+	"github.com/dropalltables/cdp/internal/ui"
+)
+
+// ComposeFiles are the compose file names Docker looks for, in order
+var ComposeFiles = []string{"compose.yaml", "compose.yml", "docker-compose.yaml", "docker-compose.yml"}
+
+// ComposePlaceholder is a ${VAR} reference in a compose file
+type ComposePlaceholder struct {
+	Name       string
+	Default    string
+	HasDefault bool
+}
+
+// composeVarPattern matches ${VAR}, ${VAR:-default}, ${VAR-default},
+// ${VAR:?error} and $VAR, but not the $$ escape
+var composeVarPattern = regexp.MustCompile(`\$(\$|\{([A-Za-z_][A-Za-z0-9_]*)(?:(:?[-?])([^}]*))?\}|([A-Za-z_][A-Za-z0-9_]*))`)
+
+// ComposePlaceholders returns the variables referenced by a compose file,
+// in order of first use. Coolify's generated SERVICE_* variables are skipped.
+func ComposePlaceholders(data []byte) []ComposePlaceholder {
+	var placeholders []ComposePlaceholder
+	seen := map[string]bool{}
+	for _, m := range composeVarPattern.FindAllStringSubmatch(string(data), -1) {
+		if m[1] == "$" {
+			continue
+		}
+		name := m[2]
+		if name == "" {
+			name = m[5]
+		}
+		if seen[name] || strings.HasPrefix(name, "SERVICE_") {
+			continue
+		}
+		seen[name] = true
+
+		p := ComposePlaceholder{Name: name}
+		if strings.HasSuffix(m[3], "-") {
+			p.Default = m[4]
+			p.HasDefault = true
+		}
+		placeholders = append(placeholders, p)
+	}
+	return placeholders
+}
+
+// ImportCompose creates a Coolify service from composeFile, sets the
+// variables it references, and returns the project config linking the
+// current directory to it. Values come from dotEnv, then the placeholder's
+// default, then a prompt.
+func ImportCompose(client *api.Client, globalCfg *config.GlobalConfig, composeFile string, dotEnv map[string]string) (*config.ProjectConfig, error) {
+	data, err := os.ReadFile(composeFile)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", composeFile, err)
+	}
+
+	serverUUID, err := selectServer(client, globalCfg.DefaultServer)
+	if err != nil {
+		return nil, err
+	}
+
+	projectName, projectUUID, environmentUUID, err := selectOrCreateProject(client)
+	if err != nil {
+		return nil, err
+	}
+
+	serviceName, err := ui.InputWithDefault("Service name", getWorkingDirName())
+	if err != nil {
+		return nil, err
+	}
+
+	placeholders := ComposePlaceholders(data)
+	values := map[string]string{}
+	var unset []string
+	for _, p := range placeholders {
+		if v, ok := dotEnv[p.Name]; ok {
+			values[p.Name] = v
+			continue
+		}
+		if p.HasDefault {
+			values[p.Name] = p.Default
+			continue
+		}
+		v, err := ui.Input(p.Name, "leave empty to set later")
+		if err != nil {
+			return nil, err
+		}
+		if v == "" {
+			unset = append(unset, p.Name)
+			continue
+		}
+		values[p.Name] = v
+	}
+
+	projectCfg := &config.ProjectConfig{
+		Name:            projectName,
+		DeployMethod:    config.DeployMethodCompose,
+		ProjectUUID:     projectUUID,
+		ServerUUID:      serverUUID,
+		EnvironmentUUID: environmentUUID,
+		Framework:       "Docker Compose",
+		ComposeFile:     composeFile,
+	}
+
+	var tasks []ui.Task
+	if projectCfg.ProjectUUID == "" {
+		tasks = append(tasks, createProjectTask(client, projectCfg))
+	}
+	tasks = append(tasks, setupEnvironmentTask(client, projectCfg))
+	tasks = append(tasks,
+		ui.Task{
+			Name:         "create-service",
+			ActiveName:   "Creating service...",
+			CompleteName: "Created service",
+			Action: func() error {
+				resp, err := client.CreateService(&api.CreateServiceRequest{
+					Name:             serviceName,
+					Description:      "Imported by CDP",
+					ProjectUUID:      projectCfg.ProjectUUID,
+					ServerUUID:       projectCfg.ServerUUID,
+					EnvironmentUUID:  projectCfg.EnvironmentUUID,
+					DockerComposeRaw: base64.StdEncoding.EncodeToString(data),
+				})
+				if err != nil {
+					return fmt.Errorf("failed to create service: %w", err)
+				}
+				projectCfg.ServiceUUID = resp.UUID
+				return config.SaveProject(projectCfg)
+			},
+		},
+		ui.Task{
+			Name:         "set-env-vars",
+			ActiveName:   "Setting environment variables...",
+			CompleteName: fmt.Sprintf("Set %d environment variables", len(values)),
+			Action: func() error {
+				return setServiceEnvVars(client, projectCfg.ServiceUUID, values)
+			},
+		},
+		ui.Task{
+			Name:         "start-service",
+			ActiveName:   "Starting service...",
+			CompleteName: "Started service",
+			Action: func() error {
+				return client.StartService(projectCfg.ServiceUUID)
+			},
+		},
+	)
+
+	if err := ui.RunTasks(tasks); err != nil {
+		ui.Error("Compose import failed")
+		return nil, err
+	}
+
+	if len(unset) > 0 {
+		ui.Warning(fmt.Sprintf("Not set: %s", strings.Join(unset, ", ")))
+		ui.Dim("Set them in the Coolify dashboard, then run 'cdp' to redeploy")
+	}
+
+	return projectCfg, nil
+}
+
+// DeployCompose uploads the local compose file to the linked service and
+// restarts it
+func DeployCompose(client *api.Client, globalCfg *config.GlobalConfig, projectCfg *config.ProjectConfig, verbose bool) error {
+	data, err := os.ReadFile(projectCfg.ComposeFile)
+	if err != nil {
+		return fmt.Errorf("failed to read %s: %w", projectCfg.ComposeFile, err)
+	}
+
+	tasks := []ui.Task{
+		{
+			Name:         "upload-compose",
+			ActiveName:   fmt.Sprintf("Uploading %s...", projectCfg.ComposeFile),
+			CompleteName: fmt.Sprintf("Uploaded %s", projectCfg.ComposeFile),
+			Action: func() error {
+				return client.UpdateServiceCompose(projectCfg.ServiceUUID, base64.StdEncoding.EncodeToString(data))
+			},
+		},
+		{
+			Name:         "restart-service",
+			ActiveName:   "Restarting service...",
+			CompleteName: "Restarted service",
+			Action: func() error {
+				return client.RestartService(projectCfg.ServiceUUID)
+			},
+		},
+	}
+
+	if err := ui.RunGroup("Deploying compose service", tasks, verbose); err != nil {
+		ui.Error("Deployment failed")
+		return err
+	}
+
+	// New placeholders added since the import have no value in Coolify yet
+	if envVars, err := client.GetServiceEnvVars(projectCfg.ServiceUUID); err == nil {
+		remote := map[string]bool{}
+		for _, env := range envVars {
+			remote[env.Key] = true
+		}
+		var missing []string
+		for _, p := range ComposePlaceholders(data) {
+			if !remote[p.Name] && !p.HasDefault {
+				missing = append(missing, p.Name)
+			}
+		}
+		if len(missing) > 0 {
+			ui.Warning(fmt.Sprintf("No value in Coolify for: %s", strings.Join(missing, ", ")))
+		}
+	}
+
+	ui.Success("Deployment complete")
+	return nil
+}
+
+// setServiceEnvVars upserts values into the service's environment
+func setServiceEnvVars(client *api.Client, serviceUUID string, values map[string]string) error {
+	existing, err := client.GetServiceEnvVars(serviceUUID)
+	if err != nil {
+		return err
+	}
+	remote := map[string]bool{}
+	for _, env := range existing {
+		remote[env.Key] = true
+	}
+
+	for key, value := range values {
+		if remote[key] {
+			err = client.UpdateServiceEnvVar(serviceUUID, key, value)
+		} else {
+			err = client.CreateServiceEnvVar(serviceUUID, key, value)
+		}
+		if err != nil {
+			return fmt.Errorf("failed to set %s: %w", key, err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,120 @@
+package deploy
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"strings"
+	"time"
+
+	"github.com/dropalltables/cdp/internal/api"
+	"github.com/dropalltables/cdp/internal/ui"
+)
+
+// dnsTimeout bounds the lookups for one domain
+const dnsTimeout = 5 * time.Second
+
+// DNSCheck is what a domain's DNS records point at
+type DNSCheck struct {
+	Host  string
+	CNAME string   // canonical name, when the host is an alias
+	Addrs []string // A and AAAA records
+	Err   error    // the lookup failed, e.g. no such host
+}
+
+// Resolves reports whether the domain has any address records
+func (c DNSCheck) Resolves() bool {
+	return c.Err == nil && len(c.Addrs) > 0
+}
+
+// PointsAt reports whether any of the domain's addresses is one of ips
+func (c DNSCheck) PointsAt(ips []string) bool {
+	for _, a := range c.Addrs {
+		for _, ip := range ips {
+			if net.ParseIP(a).Equal(net.ParseIP(ip)) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// CheckDNS resolves the CNAME and A/AAAA records of each host
+func CheckDNS(hosts []string) []DNSCheck {
+	checks := make([]DNSCheck, len(hosts))
+	for i, host := range hosts {
+		ctx, cancel := context.WithTimeout(context.Background(), dnsTimeout)
+		check := DNSCheck{Host: host}
+		if cname, err := net.DefaultResolver.LookupCNAME(ctx, host); err == nil {
+			if cname = strings.TrimSuffix(cname, "."); !strings.EqualFold(cname, host) {
+				check.CNAME = cname
+			}
+		}
+		check.Addrs, check.Err = net.DefaultResolver.LookupHost(ctx, host)
+		cancel()
+		checks[i] = check
+	}
+	return checks
+}
+
+// ServerAddresses returns the public IPs of a Coolify server, resolving its
+// address when it's a hostname. It returns nil when they can't be
+// determined, or when the server only has private addresses, which a
+// public domain can't point at directly (e.g. behind NAT).
+func ServerAddresses(client *api.Client, serverUUID string) []string {
+	if serverUUID == "" {
+		return nil
+	}
+	server, err := client.GetServer(serverUUID)
+	if err != nil || server.IP == "" {
+		return nil
+	}
+	addrs := []string{server.IP}
+	if net.ParseIP(server.IP) == nil {
+		ctx, cancel := context.WithTimeout(context.Background(), dnsTimeout)
+		defer cancel()
+		if addrs, err = net.DefaultResolver.LookupHost(ctx, server.IP); err != nil {
+			return nil
+		}
+	}
+	var public []string
+	for _, a := range addrs {
+		ip := net.ParseIP(a)
+		if ip != nil && !ip.IsLoopback() && !ip.IsPrivate() && !ip.IsLinkLocalUnicast() {
+			public = append(public, a)
+		}
+	}
+	return public
+}
+
+// WarnDNS warns about each checked domain that doesn't resolve, or with
+// serverIPs known, doesn't point at the server, explaining how to fix it
+// before Let's Encrypt fails to issue a certificate. It reports whether
+// every domain looked right.
+func WarnDNS(checks []DNSCheck, serverIPs []string) bool {
+	target := "your Coolify server"
+	if len(serverIPs) > 0 {
+		target = strings.Join(serverIPs, " or ")
+	}
+
+	ok := true
+	for _, c := range checks {
+		switch {
+		case !c.Resolves():
+			ui.Warning(fmt.Sprintf("%s doesn't resolve yet", c.Host))
+			ui.Dim(fmt.Sprintf("  Add an A record for it pointing at %s", target))
+		case len(serverIPs) > 0 && !c.PointsAt(serverIPs):
+			via := ""
+			if c.CNAME != "" {
+				via = fmt.Sprintf(" (via %s)", c.CNAME)
+			}
+			ui.Warning(fmt.Sprintf("%s points at %s%s, not the Coolify server", c.Host, strings.Join(c.Addrs, ", "), via))
+			ui.Dim(fmt.Sprintf("  Point its A record at %s so Let's Encrypt can issue a certificate", target))
+			ui.Dim("  Behind a proxy such as Cloudflare this is expected")
+		default:
+			continue
+		}
+		ok = false
+	}
+	return ok
+}
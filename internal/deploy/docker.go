@@ -12,7 +12,7 @@ import (
 )
 
 // DeployDocker handles Docker-based deployments
-func DeployDocker(client *api.Client, globalCfg *config.GlobalConfig, projectCfg *config.ProjectConfig, prNumber int, verbose bool) error {
+func DeployDocker(client *api.Client, globalCfg *config.GlobalConfig, projectCfg *config.ProjectConfig, prNumber int, build BuildInputs, verbose bool) error {
 	// Generate tag based on PR number (0 = production, >0 = preview)
 	deployType := "production"
 	if prNumber > 0 {
@@ -22,57 +22,160 @@ func DeployDocker(client *api.Client, globalCfg *config.GlobalConfig, projectCfg
 
 	needsProjectCreation := projectCfg.ProjectUUID == ""
 
-	ui.KeyValue("Image", projectCfg.DockerImage)
-	ui.KeyValue("Tag", tag)
-	ui.KeyValue("Platform", projectCfg.Platform)
+	// Pushes use Docker's stored login, so a password cdp kept must move there
+	if err := MigrateRegistryPassword(globalCfg); err != nil {
+		ui.Warning(fmt.Sprintf("Failed to move the saved registry password into Docker's credential store: %v", err))
+		ui.Dim("  It stays in the cdp config until logging in with it succeeds")
+	}
 
-	// Build Docker image
-	if err := buildDockerImage(projectCfg, tag, verbose); err != nil {
-		return err
+	ui.KeyValue("Image", projectCfg.DockerImage)
+	build.Nixpacks = useNixpacks(projectCfg, build)
+	if build.Nixpacks {
+		ui.KeyValue("Builder", config.LocalBuilderNixpacks)
 	}
 
-	ui.Info("Deploying to Coolify")
+	var tasks []ui.Task
+	if len(build.Platforms) > 0 {
+		// One image per platform; the app runs the one for its server
+		deployTag, pushTags, err := buildPlatformMatrix(client, globalCfg, projectCfg, tag, build, verbose)
+		if err != nil {
+			return err
+		}
+		tasks = buildDockerDeploymentTasks(client, globalCfg, projectCfg, deployTag, pushTags, needsProjectCreation, verbose)
+	} else {
+		ui.KeyValue("Tag", tag)
+		// Without a configured platform, build for the server's
+		platform := projectCfg.Platform
+		if platform == "" {
+			platform = projectServerPlatform(client, projectCfg)
+			if platform == "" {
+				platform = config.DefaultPlatform
+			}
+		} else if !docker.IsMultiPlatform(platform) {
+			checkPlatformMatch(client, projectCfg, platform)
+		}
+
+		// Several platforms need buildx, which pushes as it builds
+		multiPlatform := docker.IsMultiPlatform(platform)
+		if multiPlatform && !docker.IsBuildxAvailable() {
+			platform = docker.Platforms(platform)[0]
+			multiPlatform = false
+			ui.Warning(fmt.Sprintf("docker buildx is not available, building for %s only", platform))
+		}
+		if multiPlatform && build.Nixpacks {
+			platform = docker.Platforms(platform)[0]
+			multiPlatform = false
+			ui.Warning(fmt.Sprintf("nixpacks can't build multi-platform images, building for %s only", platform))
+			ui.Dim("  Use \"platforms\" in cdp.json for one image per platform")
+		}
+		ui.KeyValue("Platform", platform)
+
+		// Build Docker image
+		if err := buildDockerImage(globalCfg, projectCfg, platform, tag, build, multiPlatform, verbose); err != nil {
+			return err
+		}
 
-	tasks := buildDockerDeploymentTasks(client, globalCfg, projectCfg, tag, needsProjectCreation, verbose)
+		var pushTags []string
+		if !multiPlatform {
+			pushTags = []string{tag}
+		}
+		tasks = buildDockerDeploymentTasks(client, globalCfg, projectCfg, tag, pushTags, needsProjectCreation, verbose)
+	}
 
-	if err := ui.RunTasksVerbose(tasks, verbose); err != nil {
+	if err := ui.RunGroup("Deploying to Coolify", tasks, verbose); err != nil {
 		ui.Error("Deployment setup failed")
 		return err
 	}
 
 	// Watch deployment
-	ui.Info("Watching deployment...")
+	watch := ui.StartGroup("Watching deployment", verbose)
 
 	success := WatchDeployment(client, projectCfg.AppUUID)
 
 	if !success {
+		watch.End(ErrDeploymentFailed)
 		ui.Error("Deployment failed")
 		ui.Spacer()
 		ui.NextSteps([]string{
+			"Run 'cdp fix' to find out what went wrong",
 			"Run 'cdp logs' to view deployment logs",
 			"Check the Coolify dashboard for more details",
 		})
-		return fmt.Errorf("deployment failed")
+		return ErrDeploymentFailed
 	}
 
+	watch.End(nil)
+
 	// Get app info for URL
 	ui.Success("Deployment complete")
 
-	app, err := client.GetApplication(projectCfg.AppUUID)
-	if err == nil && app.FQDN != "" {
-		fmt.Println(ui.DimStyle.Render("  URL: " + app.FQDN))
-	}
+	printAppURL(client, projectCfg.AppUUID)
 
 	return nil
 }
 
-func buildDockerImage(projectCfg *config.ProjectConfig, tag string, verbose bool) error {
+// useNixpacks reports whether the image should be built with the nixpacks
+// CLI, falling back to the Dockerfile builder when it can't be
+func useNixpacks(projectCfg *config.ProjectConfig, build BuildInputs) bool {
+	if projectCfg.LocalBuilder != config.LocalBuilderNixpacks {
+		return false
+	}
+	if !docker.IsNixpacksAvailable() {
+		ui.Warning("nixpacks is not installed, building from a Dockerfile instead")
+		ui.Dim("  Install it from https://nixpacks.com")
+		return false
+	}
+	if len(build.Secrets) > 0 {
+		ui.Warning("nixpacks doesn't support build secrets, building from a Dockerfile instead")
+		return false
+	}
+	return true
+}
+
+// buildDockerImage builds the image for platform; multiPlatform builds are
+// pushed to the registry as part of the build
+func buildDockerImage(globalCfg *config.GlobalConfig, projectCfg *config.ProjectConfig, platform, tag string, inputs BuildInputs, multiPlatform, verbose bool) error {
+	// Monorepo apps build from their package directory
+	dir := "."
+	if projectCfg.BaseDirectory != "" {
+		dir = strings.TrimPrefix(projectCfg.BaseDirectory, "/")
+	}
+
 	framework := &detect.FrameworkInfo{
 		Name:             projectCfg.Framework,
 		InstallCommand:   projectCfg.InstallCommand,
 		BuildCommand:     projectCfg.BuildCommand,
 		StartCommand:     projectCfg.StartCommand,
 		PublishDirectory: projectCfg.PublishDir,
+		PackageManager:   detect.DetectPackageManager(dir),
+	}
+
+	build := func(verbose bool) error {
+		opts := &docker.BuildOptions{
+			Dir:       dir,
+			ImageName: projectCfg.DockerImage,
+			Tag:       tag,
+			Framework: framework,
+			Platform:  platform,
+			Verbose:   verbose,
+			BuildArgs: inputs.Args,
+			Secrets:   inputs.Secrets,
+		}
+		if multiPlatform {
+			return docker.BuildxPush(opts, registryPushOptions(globalCfg, projectCfg, tag, verbose))
+		}
+		if inputs.Nixpacks {
+			return docker.NixpacksBuild(opts)
+		}
+		return docker.Build(opts)
+	}
+
+	activeName, completeName := "Building Docker image...", "Image built successfully"
+	if platform != "" {
+		activeName, completeName = fmt.Sprintf("Building Docker image for %s...", platform), fmt.Sprintf("Built image for %s", platform)
+	}
+	if multiPlatform {
+		activeName, completeName = "Building and pushing multi-platform image...", "Built and pushed multi-platform image"
 	}
 
 	// Use spinner for build unless verbose mode is enabled
@@ -80,31 +183,18 @@ func buildDockerImage(projectCfg *config.ProjectConfig, tag string, verbose bool
 	if !verbose {
 		buildTask := ui.Task{
 			Name:         "build-image",
-			ActiveName:   "Building Docker image...",
-			CompleteName: "Image built successfully",
+			ActiveName:   activeName,
+			CompleteName: completeName,
 			Action: func() error {
-				return docker.Build(&docker.BuildOptions{
-					Dir:       ".",
-					ImageName: projectCfg.DockerImage,
-					Tag:       tag,
-					Framework: framework,
-					Platform:  projectCfg.Platform,
-					Verbose:   false,
-				})
+				return build(false)
 			},
 		}
 		err = ui.RunTasks([]ui.Task{buildTask})
 	} else {
 		// In verbose mode, show build output directly
-		fmt.Println("* Building Docker image...")
-		err = docker.Build(&docker.BuildOptions{
-			Dir:       ".",
-			ImageName: projectCfg.DockerImage,
-			Tag:       tag,
-			Framework: framework,
-			Platform:  projectCfg.Platform,
-			Verbose:   true,
-		})
+		group := ui.StartGroup(strings.TrimSuffix(activeName, "..."), true)
+		err = build(true)
+		group.End(err)
 	}
 
 	if err != nil {
@@ -112,6 +202,10 @@ func buildDockerImage(projectCfg *config.ProjectConfig, tag string, verbose bool
 		return fmt.Errorf("build failed: %w", err)
 	}
 
+	// Multi-platform images only exist in the registry
+	if !multiPlatform {
+		reportImageSize(projectCfg, dir, framework, tag, verbose)
+	}
 	return nil
 }
 
@@ -120,6 +214,7 @@ func buildDockerDeploymentTasks(
 	globalCfg *config.GlobalConfig,
 	projectCfg *config.ProjectConfig,
 	tag string,
+	pushTags []string,
 	needsProjectCreation bool,
 	verbose bool,
 ) []ui.Task {
@@ -133,8 +228,10 @@ func buildDockerDeploymentTasks(
 		tasks = append(tasks, checkEnvironmentTask(client, projectCfg))
 	}
 
-	// Push image
-	tasks = append(tasks, pushImageTask(globalCfg, projectCfg, tag, verbose))
+	// Push images, unless buildx already did
+	for _, t := range pushTags {
+		tasks = append(tasks, pushImageTask(globalCfg, projectCfg, t, verbose))
+	}
 
 	// Create app if needed
 	if projectCfg.AppUUID == "" {
@@ -231,18 +328,11 @@ func checkEnvironmentTask(client *api.Client, projectCfg *config.ProjectConfig)
 
 func pushImageTask(globalCfg *config.GlobalConfig, projectCfg *config.ProjectConfig, tag string, verbose bool) ui.Task {
 	return ui.Task{
-		Name:         "push-image",
-		ActiveName:   "Pushing image to registry...",
-		CompleteName: "Pushed image to registry",
+		Name:         "push-image-" + tag,
+		ActiveName:   fmt.Sprintf("Pushing %s to registry...", tag),
+		CompleteName: fmt.Sprintf("Pushed %s to registry", tag),
 		Action: func() error {
-			err := docker.Push(&docker.PushOptions{
-				ImageName: projectCfg.DockerImage,
-				Tag:       tag,
-				Registry:  globalCfg.DockerRegistry.URL,
-				Username:  globalCfg.DockerRegistry.Username,
-				Password:  globalCfg.DockerRegistry.Password,
-				Verbose:   verbose,
-			})
+			err := docker.Push(registryPushOptions(globalCfg, projectCfg, tag, verbose))
 			if err != nil {
 				return fmt.Errorf("failed to push image %s:%s to registry: %w", projectCfg.DockerImage, tag, err)
 			}
@@ -251,6 +341,32 @@ func pushImageTask(globalCfg *config.GlobalConfig, projectCfg *config.ProjectCon
 	}
 }
 
+// MigrateRegistryPassword moves a registry password saved by an older cdp
+// into Docker's credential store by logging in with it, and removes it from
+// the config once that succeeded. On failure the password is kept, as it
+// may be the only copy.
+func MigrateRegistryPassword(globalCfg *config.GlobalConfig) error {
+	registry := globalCfg.DockerRegistry
+	if registry == nil || registry.Password == "" {
+		return nil
+	}
+	if err := docker.VerifyLogin(registry.URL, registry.Username, registry.Password); err != nil {
+		return err
+	}
+	registry.Password = ""
+	return config.SaveGlobal(globalCfg)
+}
+
+func registryPushOptions(globalCfg *config.GlobalConfig, projectCfg *config.ProjectConfig, tag string, verbose bool) *docker.PushOptions {
+	return &docker.PushOptions{
+		ImageName: projectCfg.DockerImage,
+		Tag:       tag,
+		Registry:  globalCfg.DockerRegistry.URL,
+		Username:  globalCfg.DockerRegistry.Username,
+		Verbose:   verbose,
+	}
+}
+
 func createDockerAppTask(client *api.Client, projectCfg *config.ProjectConfig, tag string) ui.Task {
 	return ui.Task{
 		Name:         "create-app",
@@ -267,6 +383,7 @@ func createDockerAppTask(client *api.Client, projectCfg *config.ProjectConfig, t
 				ServerUUID:              projectCfg.ServerUUID,
 				EnvironmentUUID:         projectCfg.EnvironmentUUID,
 				Name:                    projectCfg.Name,
+				Domains:                 projectCfg.Domain,
 				DockerRegistryImageName: projectCfg.DockerImage,
 				DockerRegistryImageTag:  tag,
 				PortsExposes:            port,
@@ -276,12 +393,40 @@ func createDockerAppTask(client *api.Client, projectCfg *config.ProjectConfig, t
 				return fmt.Errorf("failed to create Coolify application %q: %w", projectCfg.Name, err)
 			}
 			projectCfg.AppUUID = resp.UUID
+			// Saved first, so a failure below can't orphan the new app
+			if err := config.SaveProject(projectCfg); err != nil {
+				return err
+			}
 
-			return config.SaveProject(projectCfg)
+			if err := ApplyResourceLimits(client, projectCfg); err != nil {
+				ui.TaskWarning(fmt.Sprintf("Failed to apply resource limits, run 'cdp apply' to retry: %v", err))
+			}
+			return nil
 		},
 	}
 }
 
+// ApplyResourceLimits pushes limits saved in cdp.json to the app
+func ApplyResourceLimits(client *api.Client, projectCfg *config.ProjectConfig) error {
+	updates := map[string]interface{}{}
+	if projectCfg.CPULimit != "" {
+		updates["limits_cpus"] = projectCfg.CPULimit
+	}
+	if projectCfg.MemoryLimit != "" {
+		updates["limits_memory"] = projectCfg.MemoryLimit
+	}
+	if projectCfg.Replicas > 0 {
+		updates["swarm_replicas"] = projectCfg.Replicas
+	}
+	if len(updates) == 0 {
+		return nil
+	}
+	if err := client.UpdateApplication(projectCfg.AppUUID, updates); err != nil {
+		return fmt.Errorf("failed to apply resource limits: %w", err)
+	}
+	return nil
+}
+
 func triggerDeploymentTask(client *api.Client, projectCfg *config.ProjectConfig, tag string) ui.Task {
 	return ui.Task{
 		Name:         "trigger-deploy",
@@ -1,7 +1,9 @@
 package deploy
 
 import (
+	"errors"
 	"fmt"
+	"path/filepath"
 	"strings"
 
 	"github.com/dropalltables/cdp/internal/api"
@@ -15,6 +17,11 @@ import (
 func DeployGit(client *api.Client, globalCfg *config.GlobalConfig, projectCfg *config.ProjectConfig, prNumber int, verbose bool) error {
 	ghClient := git.NewGitHubClient(globalCfg.GitHubToken)
 
+	// Decide whether cdp.json goes into the commits we push
+	if err := ensureConfigPolicy(projectCfg); err != nil {
+		return err
+	}
+
 	// Get GitHub user
 	user, err := getGitHubUser(ghClient, verbose)
 	if err != nil {
@@ -40,34 +47,89 @@ func DeployGit(client *api.Client, globalCfg *config.GlobalConfig, projectCfg *c
 	// Execute deployment tasks
 	tasks := buildGitDeploymentTasks(client, ghClient, globalCfg, projectCfg, user.Login, needsRepoCreation, verbose)
 
-	if err := ui.RunTasksVerbose(tasks, verbose); err != nil {
+	err = ui.RunGroup("Preparing deployment", tasks, verbose)
+	if err != nil {
+		adopted, adoptErr := adoptExistingApp(projectCfg, err)
+		if adopted {
+			// Everything before the app was set up; push to the adopted app
+			tasks = buildGitDeploymentTasks(client, ghClient, globalCfg, projectCfg, user.Login, false, verbose)
+			err = ui.RunGroup("Preparing deployment", tasks, verbose)
+		} else {
+			err = adoptErr
+		}
+	}
+	if err != nil {
 		ui.Error("Deployment setup failed")
 		return err
 	}
 
 	// Watch deployment
-	ui.Info("Watching deployment...")
+	watch := ui.StartGroup("Watching deployment", verbose)
 
 	success := WatchDeployment(client, projectCfg.AppUUID)
 
 	if !success {
+		watch.End(ErrDeploymentFailed)
 		ui.Error("Deployment failed")
 		ui.Spacer()
 		ui.NextSteps([]string{
+			"Run 'cdp fix' to find out what went wrong",
 			"Run 'cdp logs' to view deployment logs",
 			"Check the Coolify dashboard for more details",
 		})
-		return fmt.Errorf("deployment failed")
+		return ErrDeploymentFailed
 	}
 
+	watch.End(nil)
+
 	// Get app info for URL
 	ui.Success("Deployment complete")
 
-	app, err := client.GetApplication(projectCfg.AppUUID)
-	if err == nil && app.FQDN != "" {
-		fmt.Println(ui.DimStyle.Render("  URL: " + app.FQDN))
+	printAppURL(client, projectCfg.AppUUID)
+
+	return nil
+}
+
+// ensureConfigPolicy asks once whether the project config should be committed,
+// and keeps it out of git when it should not be
+func ensureConfigPolicy(projectCfg *config.ProjectConfig) error {
+	configName := filepath.Base(config.ProjectConfigPath("."))
+
+	if projectCfg.CommitConfig == nil {
+		commit := false
+		if !ui.AssumeYes {
+			choice, err := ui.SelectWithKeysOrdered(fmt.Sprintf("Commit %s to the repository?", configName), []struct{ Key, Display string }{
+				{Key: "ignore", Display: fmt.Sprintf("No, add %s to .gitignore (recommended)", configName)},
+				{Key: "commit", Display: "Yes, commit it (exposes Coolify UUIDs to anyone who can read the repo)"},
+			})
+			if err != nil {
+				return err
+			}
+			commit = choice == "commit"
+		}
+		projectCfg.CommitConfig = &commit
+		if err := config.SaveProject(projectCfg); err != nil {
+			return fmt.Errorf("failed to save configuration: %w", err)
+		}
+	}
+
+	if *projectCfg.CommitConfig {
+		return nil
 	}
 
+	changed, err := git.EnsureIgnored(".", configName)
+	if err != nil {
+		return fmt.Errorf("failed to update .gitignore: %w", err)
+	}
+	if changed {
+		ui.Dim(fmt.Sprintf("Added %s to .gitignore", configName))
+	}
+	if git.IsTracked(".", configName) {
+		if err := git.Untrack(".", configName); err != nil {
+			return fmt.Errorf("failed to untrack %s: %w", configName, err)
+		}
+		ui.Dim(fmt.Sprintf("Stopped tracking %s; teammates can run 'cdp link --from-remote'", configName))
+	}
 	return nil
 }
 
@@ -396,17 +458,35 @@ func createGitAppTask(client *api.Client, projectCfg *config.ProjectConfig, user
 				BuildCommand:       projectCfg.BuildCommand,
 				StartCommand:       projectCfg.StartCommand,
 				PublishDirectory:   projectCfg.PublishDir,
+				BaseDirectory:      projectCfg.BaseDirectory,
 				PortsExposes:       port,
 				HealthCheckEnabled: healthCheckEnabled,
 				HealthCheckPath:    healthCheckPath,
 				InstantDeploy:      false,
 			})
+			if api.IsConflict(err) {
+				// Left behind by an interrupted first deploy; offered for adoption
+				if existing, lookupErr := findExistingApp(client, projectCfg, projectCfg.Name, fullRepoName); lookupErr == nil && existing != nil {
+					return &ExistingAppError{App: *existing}
+				}
+			}
 			if err != nil {
 				return fmt.Errorf("failed to create Coolify application %q with GitHub integration: %w", projectCfg.Name, err)
 			}
 			projectCfg.AppUUID = resp.UUID
+			// Saved first, so a failure below can't orphan the new app
+			if err := config.SaveProject(projectCfg); err != nil {
+				return err
+			}
 
-			return config.SaveProject(projectCfg)
+			if err := ApplyResourceLimits(client, projectCfg); err != nil {
+				ui.TaskWarning(fmt.Sprintf("Failed to apply resource limits, run 'cdp apply' to retry: %v", err))
+			}
+			// Older Coolify versions just build with the cache
+			if err := ApplyBuildCache(client, projectCfg); err != nil && !errors.Is(err, ErrBuildCacheUnsupported) {
+				ui.TaskWarning(fmt.Sprintf("Failed to apply build cache settings, run 'cdp apply' to retry: %v", err))
+			}
+			return nil
 		},
 	}
 }
@@ -0,0 +1,59 @@
+package deploy
+
+import (
+	"fmt"
+
+	"github.com/dropalltables/cdp/internal/config"
+	"github.com/dropalltables/cdp/internal/detect"
+	"github.com/dropalltables/cdp/internal/docker"
+	"github.com/dropalltables/cdp/internal/ui"
+)
+
+// reportedLayers is how many of the largest layers the size report lists
+const reportedLayers = 3
+
+// reportImageSize shows the size of the image just built. The largest
+// layers and hints to shrink it are shown in verbose mode, or when the
+// image exceeds the project's image_size_budget.
+func reportImageSize(projectCfg *config.ProjectConfig, dir string, framework *detect.FrameworkInfo, tag string, verbose bool) {
+	size, err := docker.InspectImageSize(projectCfg.DockerImage, tag)
+	if err != nil {
+		// The report is informational; the deploy goes on without it
+		return
+	}
+	ui.KeyValue("Image size", docker.FormatSize(size.Size))
+
+	overBudget := false
+	if projectCfg.ImageSizeBudget != "" {
+		budget, err := docker.ParseSize(projectCfg.ImageSizeBudget)
+		if err != nil {
+			ui.Warning(fmt.Sprintf("Ignoring image_size_budget: %v", err))
+		} else if size.Size > budget {
+			overBudget = true
+			ui.Warning(fmt.Sprintf("Image is %s, over the %s budget", docker.FormatSize(size.Size), docker.FormatSize(budget)))
+		}
+	}
+	if !verbose && !overBudget {
+		return
+	}
+
+	ui.Dim("  Largest layers:")
+	for _, l := range size.LargestLayers(reportedLayers) {
+		ui.Dim(fmt.Sprintf("    %9s  %s", docker.FormatSize(l.Size), truncateInstruction(l.CreatedBy)))
+	}
+	if hints := docker.SizeHints(size, dir, framework); len(hints) > 0 {
+		ui.Dim("  To reduce the image size:")
+		for _, h := range hints {
+			ui.Dim("    - " + h)
+		}
+	}
+}
+
+// truncateInstruction shortens long RUN lines for the layer list
+func truncateInstruction(s string) string {
+	const max = 60
+	if len(s) <= max {
+		return s
+	}
+	return s[:max-3] + "..."
+}
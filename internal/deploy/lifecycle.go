@@ -0,0 +1,55 @@
+package deploy
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/dropalltables/cdp/internal/api"
+)
+
+const (
+	// appStatePollInterval is how often WaitForAppState checks the app
+	appStatePollInterval = 3 * time.Second
+	// appStateTimeout bounds how long WaitForAppState waits
+	appStateTimeout = 5 * time.Minute
+)
+
+// IsRunning reports whether an app status, e.g. "running:healthy", is a
+// running state
+func IsRunning(status string) bool {
+	return strings.HasPrefix(strings.ToLower(status), "running")
+}
+
+// IsStopped reports whether an app status, e.g. "exited:unhealthy", is a
+// stopped state
+func IsStopped(status string) bool {
+	status = strings.ToLower(status)
+	return strings.HasPrefix(status, "exited") || strings.HasPrefix(status, "stopped")
+}
+
+// WaitForAppState polls the app until it's running, or with running false
+// until it's stopped, and returns the last status seen
+func WaitForAppState(client *api.Client, appUUID string, running bool) (string, error) {
+	want := IsStopped
+	if running {
+		want = IsRunning
+	}
+	status := ""
+	deadline := time.Now().Add(appStateTimeout)
+	for time.Now().Before(deadline) {
+		app, err := client.GetApplication(appUUID)
+		// Transient API errors are retried until the deadline
+		if err == nil {
+			status = app.Status
+			if want(status) {
+				return status, nil
+			}
+		}
+		time.Sleep(appStatePollInterval)
+	}
+	if status == "" {
+		status = "unknown"
+	}
+	return status, fmt.Errorf("app still %s after %s", status, appStateTimeout)
+}
@@ -0,0 +1,172 @@
+package deploy
+
+import (
+	"context"
+	"fmt"
+	"os/exec"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/dropalltables/cdp/internal/api"
+	"github.com/dropalltables/cdp/internal/config"
+	"github.com/dropalltables/cdp/internal/ui"
+)
+
+// sshArchTimeout bounds the uname -m fallback of ServerPlatform
+const sshArchTimeout = 10 * time.Second
+
+// ServerPlatform returns the Docker platform of a Coolify server, e.g.
+// "linux/arm64", or "" if it can't be determined. The architecture Coolify
+// reports is used when available; otherwise uname -m is run over SSH, which
+// only works if this machine can log in to the server without a prompt.
+func ServerPlatform(client *api.Client, serverUUID string) string {
+	if serverUUID == "" {
+		return ""
+	}
+	server, err := client.GetServer(serverUUID)
+	if err != nil {
+		return ""
+	}
+	if server.Metadata != nil {
+		if platform := PlatformForArch(server.Metadata.Arch); platform != "" {
+			return platform
+		}
+	}
+	return PlatformForArch(sshArch(server))
+}
+
+// sshArch runs uname -m on the server, returning "" on any failure
+func sshArch(server *api.Server) string {
+	if server.IP == "" {
+		return ""
+	}
+	user := server.User
+	if user == "" {
+		user = "root"
+	}
+	port := server.Port
+	if port == 0 {
+		port = 22
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), sshArchTimeout)
+	defer cancel()
+	out, err := exec.CommandContext(ctx, "ssh",
+		"-o", "BatchMode=yes",
+		"-o", "ConnectTimeout=5",
+		"-p", strconv.Itoa(port),
+		user+"@"+server.IP,
+		"uname", "-m",
+	).Output()
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSpace(string(out))
+}
+
+// PlatformForArch maps a uname -m machine name to a Docker platform
+func PlatformForArch(arch string) string {
+	switch strings.ToLower(strings.TrimSpace(arch)) {
+	case "x86_64", "amd64":
+		return "linux/amd64"
+	case "aarch64", "arm64", "armv8l":
+		return "linux/arm64"
+	case "armv7l", "armv7":
+		return "linux/arm/v7"
+	default:
+		return ""
+	}
+}
+
+// buildPlatformMatrix builds one image per platform target, tagged with the
+// target's suffix, and returns the tag the app should run (the one matching
+// its server's architecture) along with every tag to push
+func buildPlatformMatrix(client *api.Client, globalCfg *config.GlobalConfig, projectCfg *config.ProjectConfig, tag string, build BuildInputs, verbose bool) (string, []string, error) {
+	var platforms, tags []string
+	for _, t := range build.Platforms {
+		platforms = append(platforms, t.Platform)
+		tags = append(tags, tag+t.Suffix())
+	}
+	ui.KeyValue("Tags", strings.Join(tags, ", "))
+	ui.KeyValue("Platforms", strings.Join(platforms, ", "))
+
+	deployTag := ""
+	serverPlatform := projectServerPlatform(client, projectCfg)
+	for i, t := range build.Platforms {
+		if t.Platform == serverPlatform {
+			deployTag = tags[i]
+		}
+	}
+	if deployTag == "" {
+		deployTag = tags[0]
+		if serverPlatform == "" {
+			ui.Warning(fmt.Sprintf("Server architecture unknown, deploying the %s image", platforms[0]))
+		} else {
+			ui.Warning(fmt.Sprintf("No image for the server's %s platform, deploying the %s image", serverPlatform, platforms[0]))
+		}
+	}
+	ui.KeyValue("Deploying", deployTag)
+
+	for i, t := range build.Platforms {
+		if err := buildDockerImage(globalCfg, projectCfg, t.Platform, tags[i], build, false, verbose); err != nil {
+			return "", nil, err
+		}
+	}
+	return deployTag, tags, nil
+}
+
+// projectServerPlatform returns the platform of the project's server,
+// looking it up once and recording it in cdp.json, as the SSH fallback of
+// ServerPlatform is slow. It returns "" if the platform can't be determined.
+func projectServerPlatform(client *api.Client, projectCfg *config.ProjectConfig) string {
+	if projectCfg.ServerPlatform != "" {
+		return projectCfg.ServerPlatform
+	}
+	platform := ServerPlatform(client, projectCfg.ServerUUID)
+	if platform == "" {
+		return ""
+	}
+	projectCfg.ServerPlatform = platform
+	if err := config.SaveProject(projectCfg); err != nil {
+		ui.Warning("Failed to record the server platform in cdp.json")
+	}
+	return platform
+}
+
+// detectPlatformTask looks up the server's platform for a new Docker app,
+// falling back to the default when it can't be determined. The detected
+// platform is returned separately, to be recorded as the server's.
+func detectPlatformTask(client *api.Client, serverUUID string) (defaultPlatform, serverPlatform string, err error) {
+	err = ui.RunTasks([]ui.Task{
+		{
+			Name:         "detect-platform",
+			ActiveName:   "Detecting server architecture...",
+			CompleteName: "Detected server architecture",
+			Action: func() error {
+				serverPlatform = ServerPlatform(client, serverUUID)
+				return nil
+			},
+		},
+	})
+	if err != nil {
+		return "", "", err
+	}
+	if serverPlatform == "" {
+		ui.Dim(fmt.Sprintf("Server architecture unknown, defaulting to %s", config.DefaultPlatform))
+		return config.DefaultPlatform, "", nil
+	}
+	ui.KeyValue("Server platform", serverPlatform)
+	return serverPlatform, serverPlatform, nil
+}
+
+// checkPlatformMatch warns when a single-platform image won't run on the
+// server, which fails at start with "exec format error"
+func checkPlatformMatch(client *api.Client, projectCfg *config.ProjectConfig, platform string) {
+	serverPlatform := projectServerPlatform(client, projectCfg)
+	if serverPlatform == "" || serverPlatform == platform {
+		return
+	}
+	ui.Warning(fmt.Sprintf("Building for %s, but the server is %s; the app will fail to start", platform, serverPlatform))
+	ui.Dim(fmt.Sprintf("  Set \"platform\": %q in cdp.json", serverPlatform))
+}
@@ -0,0 +1,214 @@
+package deploy
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/dropalltables/cdp/internal/config"
+	"github.com/dropalltables/cdp/internal/git"
+	"github.com/dropalltables/cdp/internal/ui"
+)
+
+// auditLogFile records deploys that overrode a policy, next to the global config
+const auditLogFile = "audit.log"
+
+// PolicyViolation describes one org policy a deploy would break
+type PolicyViolation struct {
+	Policy  string `json:"policy"`
+	Message string `json:"message"`
+}
+
+// policyOverride is one line of the audit log
+type policyOverride struct {
+	Time       string            `json:"time"`
+	User       string            `json:"user"`
+	Project    string            `json:"project"`
+	AppUUID    string            `json:"app_uuid"`
+	Reason     string            `json:"reason"`
+	Violations []PolicyViolation `json:"violations"`
+}
+
+// CheckPolicies evaluates the org policies in globalCfg against a deploy of
+// projectCfg from the current directory, to production or another
+// environment
+func CheckPolicies(globalCfg *config.GlobalConfig, projectCfg *config.ProjectConfig, production bool, now time.Time) []PolicyViolation {
+	p := globalCfg.Policies
+	if p == nil {
+		return nil
+	}
+
+	var violations []PolicyViolation
+
+	if p.NoFridayDeploys && production && now.Weekday() == time.Friday {
+		violations = append(violations, PolicyViolation{
+			Policy:  "no_friday_deploys",
+			Message: "Production deploys are not allowed on Fridays",
+		})
+	}
+
+	if p.RequiredBranch != "" && projectCfg.DeployMethod == config.DeployMethodGit {
+		if branch := deployBranch(projectCfg); branch != p.RequiredBranch {
+			violations = append(violations, PolicyViolation{
+				Policy:  "required_branch",
+				Message: fmt.Sprintf("Deploys must come from '%s', not '%s'", p.RequiredBranch, branch),
+			})
+		}
+	}
+
+	for _, bp := range p.DisallowedBuildPacks {
+		if bp != "" && strings.EqualFold(bp, projectCfg.BuildPack) {
+			violations = append(violations, PolicyViolation{
+				Policy:  "disallowed_build_packs",
+				Message: fmt.Sprintf("The %s build pack is not allowed", projectCfg.BuildPack),
+			})
+		}
+	}
+
+	if p.RequiredReviewers > 0 {
+		if msg := checkReviewers(globalCfg, p.RequiredReviewers); msg != "" {
+			violations = append(violations, PolicyViolation{
+				Policy:  "required_reviewers",
+				Message: msg,
+			})
+		}
+	}
+
+	return violations
+}
+
+// EnforcePolicies refuses the deploy when a policy is violated, unless
+// overrideReason is given, in which case the override is written to the
+// audit log and the deploy proceeds
+func EnforcePolicies(globalCfg *config.GlobalConfig, projectCfg *config.ProjectConfig, production bool, overrideReason string) error {
+	violations := CheckPolicies(globalCfg, projectCfg, production, time.Now())
+	if len(violations) == 0 {
+		return nil
+	}
+
+	if overrideReason == "" {
+		ui.Spacer()
+		ui.Error("Deploy blocked by organization policy")
+		for _, v := range violations {
+			ui.Dim(fmt.Sprintf("  • %s (%s)", v.Message, v.Policy))
+		}
+		ui.Spacer()
+		ui.Dim("Rerun with --override-policy \"reason\" to deploy anyway; the override is logged")
+		return fmt.Errorf("deploy violates %d policy rule(s)", len(violations))
+	}
+
+	for _, v := range violations {
+		ui.Warning(fmt.Sprintf("Overriding policy: %s", v.Message))
+	}
+
+	record := policyOverride{
+		Time:       time.Now().UTC().Format(time.RFC3339),
+		User:       git.GetUserEmail("."),
+		Project:    projectCfg.Name,
+		AppUUID:    projectCfg.AppUUID,
+		Reason:     overrideReason,
+		Violations: violations,
+	}
+	if record.User == "" {
+		record.User = os.Getenv("USER")
+	}
+	if err := appendAuditLog(record); err != nil {
+		// An override that can't be recorded must not go through silently
+		ui.Error("Failed to record policy override")
+		return fmt.Errorf("failed to write audit log: %w", err)
+	}
+	return nil
+}
+
+// checkReviewers returns a violation message unless HEAD belongs to a pull
+// request with at least required distinct approvals
+func checkReviewers(globalCfg *config.GlobalConfig, required int) string {
+	if git.HasChanges(".") {
+		return "Uncommitted changes have not been reviewed"
+	}
+	if globalCfg.GitHubToken == "" {
+		return "Cannot verify reviewers without a GitHub token (run 'cdp login')"
+	}
+
+	remote, err := git.GetRemoteURL(".", "origin")
+	if err != nil || remote == "" {
+		return "Cannot verify reviewers: no origin remote"
+	}
+	owner, name, ok := strings.Cut(git.RepoSlug(remote), "/")
+	if !ok {
+		return "Cannot verify reviewers: origin is not a GitHub repository"
+	}
+	sha, err := git.GetLatestCommitHash(".")
+	if err != nil {
+		return "Cannot verify reviewers: no commits"
+	}
+
+	gh := git.NewGitHubClient(globalCfg.GitHubToken)
+	pulls, err := gh.ListCommitPulls(owner, name, sha)
+	if err != nil {
+		return fmt.Sprintf("Cannot verify reviewers: %v", err)
+	}
+
+	best := 0
+	for _, pr := range pulls {
+		reviews, err := gh.ListReviews(owner, name, pr.Number)
+		if err != nil {
+			continue
+		}
+		approvers := map[string]bool{}
+		for _, r := range reviews {
+			if r.State == "APPROVED" {
+				approvers[r.User.Login] = true
+			}
+		}
+		if len(approvers) > best {
+			best = len(approvers)
+		}
+	}
+
+	if best >= required {
+		return ""
+	}
+	if len(pulls) == 0 {
+		return fmt.Sprintf("Commit %s is not part of a reviewed pull request", sha)
+	}
+	return fmt.Sprintf("Commit %s has %d of %d required approvals", sha, best, required)
+}
+
+// deployBranch returns the branch a git deploy pushes
+func deployBranch(projectCfg *config.ProjectConfig) string {
+	if projectCfg.Branch != "" {
+		return projectCfg.Branch
+	}
+	if b, _ := git.GetCurrentBranch("."); b != "" {
+		return b
+	}
+	return config.DefaultBranch
+}
+
+func appendAuditLog(record policyOverride) error {
+	configPath, err := config.GetConfigPath()
+	if err != nil {
+		return err
+	}
+	path := filepath.Join(filepath.Dir(configPath), auditLogFile)
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return err
+	}
+
+	line, err := json.Marshal(record)
+	if err != nil {
+		return err
+	}
+
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0600)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	_, err = f.Write(append(line, '\n'))
+	return err
+}
@@ -0,0 +1,56 @@
+package deploy
+
+import "time"
+
+const (
+	// fastPollInterval is used while a deployment starts and right after
+	// it changes phase, when the UI should react quickly
+	fastPollInterval = 1 * time.Second
+	// slowPollInterval caps the back-off during long phases, e.g. a build
+	slowPollInterval = 10 * time.Second
+	// startFastPeriod is how long polling stays fast after it starts
+	startFastPeriod = 30 * time.Second
+	// phaseFastPeriod is how long polling stays fast after a phase change
+	phaseFastPeriod = 10 * time.Second
+)
+
+// adaptivePoll spaces out status checks to spare small Coolify servers: it
+// polls every second at first, then backs off to slowPollInterval, and
+// tightens again whenever the observed phase changes
+type adaptivePoll struct {
+	interval  time.Duration
+	fastUntil time.Time
+	phase     string
+}
+
+func newAdaptivePoll() *adaptivePoll {
+	return &adaptivePoll{
+		interval:  fastPollInterval,
+		fastUntil: time.Now().Add(startFastPeriod),
+	}
+}
+
+// Observe records the current phase, e.g. a deployment's status; a change
+// makes polling fast again
+func (p *adaptivePoll) Observe(phase string) {
+	if phase == p.phase {
+		return
+	}
+	p.phase = phase
+	p.interval = fastPollInterval
+	if until := time.Now().Add(phaseFastPeriod); until.After(p.fastUntil) {
+		p.fastUntil = until
+	}
+}
+
+// Next returns how long to wait before the next check
+func (p *adaptivePoll) Next() time.Duration {
+	if time.Now().Before(p.fastUntil) {
+		return fastPollInterval
+	}
+	p.interval *= 2
+	if p.interval > slowPollInterval {
+		p.interval = slowPollInterval
+	}
+	return p.interval
+}
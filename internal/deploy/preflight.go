@@ -0,0 +1,51 @@
+package deploy
+
+import (
+	"fmt"
+
+	"github.com/dropalltables/cdp/internal/api"
+	"github.com/dropalltables/cdp/internal/ui"
+)
+
+const (
+	// DiskWarnPercent is the disk usage at which a deploy prints a warning
+	DiskWarnPercent = 85.0
+
+	// DiskBlockPercent is the disk usage at which a deploy is refused;
+	// builds on a nearly full disk fail with unhelpful ENOSPC errors
+	DiskBlockPercent = 95.0
+)
+
+// CheckDiskSpace verifies the target server has room for a build.
+// It returns an error when usage is above DiskBlockPercent. Missing
+// metrics (e.g. Sentinel disabled) are not treated as a failure.
+func CheckDiskSpace(client *api.Client, serverUUID string, verbose bool) error {
+	if serverUUID == "" {
+		return nil
+	}
+
+	metrics, err := client.GetServerMetrics(serverUUID)
+	if err != nil {
+		if verbose {
+			ui.Dim("Skipping disk check: server metrics unavailable")
+		}
+		return nil
+	}
+
+	usage := fmt.Sprintf("%.0f%% used", metrics.DiskPercent)
+	if metrics.DiskTotalGB > 0 {
+		usage = fmt.Sprintf("%s, %.1f GB free", usage, metrics.DiskTotalGB-metrics.DiskUsedGB)
+	}
+
+	switch {
+	case metrics.DiskPercent >= DiskBlockPercent:
+		ui.Error(fmt.Sprintf("Server disk is almost full (%s)", usage))
+		ui.Dim("Free space with 'docker system prune' on the server, or rerun with --skip-preflight")
+		return fmt.Errorf("server disk usage %.0f%% exceeds %.0f%%", metrics.DiskPercent, DiskBlockPercent)
+	case metrics.DiskPercent >= DiskWarnPercent:
+		ui.Warning(fmt.Sprintf("Server is low on disk (%s); the build may fail", usage))
+	case verbose:
+		ui.Dim(fmt.Sprintf("Server disk: %s", usage))
+	}
+	return nil
+}
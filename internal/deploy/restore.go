@@ -0,0 +1,208 @@
+package deploy
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/dropalltables/cdp/internal/api"
+	"github.com/dropalltables/cdp/internal/config"
+	"github.com/dropalltables/cdp/internal/snapshot"
+	"github.com/dropalltables/cdp/internal/ui"
+)
+
+// copiedSettings returns the snapshot.AppSettings set on app, as updates
+// for another app
+func copiedSettings(app map[string]interface{}) map[string]interface{} {
+	updates := map[string]interface{}{}
+	for _, key := range snapshot.AppSettings {
+		if v, ok := app[key]; ok && v != nil && v != "" {
+			updates[key] = v
+		}
+	}
+	return updates
+}
+
+// RestoreResult reports the outcome of restoring each environment variable,
+// scheduled task and volume of a snapshot
+type RestoreResult struct {
+	Results ui.Results
+}
+
+// RestoreSnapshot recreates the app described by snap on the Coolify
+// instance behind client, with envVars as its environment. The returned
+// project config links the current directory to the new app.
+func RestoreSnapshot(client *api.Client, globalCfg *config.GlobalConfig, snap *snapshot.Snapshot, envVars []api.EnvVar) (*config.ProjectConfig, *RestoreResult, error) {
+	projectCfg := *snap.Project
+	projectCfg.AppUUID = ""
+	projectCfg.ServiceUUID = ""
+	projectCfg.GitHubAppUUID = ""
+
+	serverUUID, err := selectServer(client, globalCfg.DefaultServer)
+	if err != nil {
+		return nil, nil, err
+	}
+	projectName, projectUUID, environmentUUID, err := selectOrCreateProject(client)
+	if err != nil {
+		return nil, nil, err
+	}
+	projectCfg.Name = projectName
+	projectCfg.ServerUUID = serverUUID
+	projectCfg.ServerPlatform = ""
+	projectCfg.ProjectUUID = projectUUID
+	projectCfg.EnvironmentUUID = environmentUUID
+
+	app := snap.Application
+	private := snap.Project.GitHubAppUUID != ""
+	if projectCfg.DeployMethod == config.DeployMethodGit && private {
+		if err := handleGitHubAppSelection(client, &projectCfg, false, false); err != nil {
+			return nil, nil, err
+		}
+	}
+
+	result := &RestoreResult{}
+	var tasks []ui.Task
+	if projectCfg.ProjectUUID == "" {
+		tasks = append(tasks, createProjectTask(client, &projectCfg))
+	}
+	tasks = append(tasks, setupEnvironmentTask(client, &projectCfg))
+	tasks = append(tasks,
+		ui.Task{
+			Name:         "create-app",
+			ActiveName:   "Creating application...",
+			CompleteName: "Created application",
+			Action: func() error {
+				uuid, err := createRestoredApp(client, &projectCfg, app, private)
+				if err != nil {
+					return err
+				}
+				projectCfg.AppUUID = uuid
+				return config.SaveProject(&projectCfg)
+			},
+		},
+		ui.Task{
+			Name:         "restore-settings",
+			ActiveName:   "Restoring settings and domains...",
+			CompleteName: "Restored settings and domains",
+			Action: func() error {
+				updates := copiedSettings(app)
+				if fqdn, _ := app["fqdn"].(string); fqdn != "" {
+					updates["domains"] = fqdn
+				}
+				if len(updates) == 0 {
+					return nil
+				}
+				return client.UpdateApplication(projectCfg.AppUUID, updates)
+			},
+		},
+		ui.Task{
+			Name:         "restore-env",
+			ActiveName:   "Restoring environment variables...",
+			CompleteName: fmt.Sprintf("Restored %d environment variables", len(envVars)),
+			Action: func() error {
+				for _, env := range envVars {
+					_, err := client.CreateApplicationEnvVar(projectCfg.AppUUID, env.Key, env.Value, env.IsBuildTime, env.IsPreview)
+					result.Results.Add(env.Key, "restore env var", err)
+				}
+				return nil
+			},
+		},
+		ui.Task{
+			Name:         "restore-cron",
+			ActiveName:   "Restoring scheduled tasks...",
+			CompleteName: fmt.Sprintf("Restored %d scheduled tasks", len(snap.ScheduledTasks)),
+			Action: func() error {
+				for _, t := range snap.ScheduledTasks {
+					_, err := client.CreateScheduledTask(projectCfg.AppUUID, &api.CreateScheduledTaskRequest{
+						Name:      t.Name,
+						Command:   t.Command,
+						Frequency: t.Frequency,
+						Container: t.Container,
+						Enabled:   t.Enabled,
+					})
+					result.Results.Add(t.Name, "restore scheduled task", err)
+				}
+				return nil
+			},
+		},
+		ui.Task{
+			Name:         "restore-volumes",
+			ActiveName:   "Restoring volumes...",
+			CompleteName: fmt.Sprintf("Restored %d volumes", len(snap.Volumes)),
+			Action: func() error {
+				for i := range snap.Volumes {
+					result.Results.Add(snap.Volumes[i].MountPath, "restore volume", client.CreateStorage(projectCfg.AppUUID, &snap.Volumes[i]))
+				}
+				return nil
+			},
+		},
+	)
+
+	if err := ui.RunTasks(tasks); err != nil {
+		ui.Error("Restore failed")
+		return nil, nil, err
+	}
+
+	if err := config.SaveProject(&projectCfg); err != nil {
+		return nil, nil, fmt.Errorf("failed to save configuration: %w", err)
+	}
+	return &projectCfg, result, nil
+}
+
+// createRestoredApp creates an empty app of the snapshot's kind and returns
+// its UUID
+func createRestoredApp(client *api.Client, projectCfg *config.ProjectConfig, app map[string]interface{}, private bool) (string, error) {
+	str := func(key string) string {
+		s, _ := app[key].(string)
+		return s
+	}
+	name := str("name")
+	if name == "" {
+		name = projectCfg.Name
+	}
+
+	var resp *api.CreateAppResponse
+	var err error
+	switch {
+	case projectCfg.DeployMethod == config.DeployMethodDocker:
+		resp, err = client.CreateDockerImageApp(&api.CreateDockerImageAppRequest{
+			ProjectUUID:             projectCfg.ProjectUUID,
+			ServerUUID:              projectCfg.ServerUUID,
+			EnvironmentUUID:         projectCfg.EnvironmentUUID,
+			Name:                    name,
+			DockerRegistryImageName: str("docker_registry_image_name"),
+			DockerRegistryImageTag:  str("docker_registry_image_tag"),
+			PortsExposes:            str("ports_exposes"),
+		})
+	case private:
+		resp, err = client.CreatePrivateGitHubApp(&api.CreatePrivateGitHubAppRequest{
+			ProjectUUID:     projectCfg.ProjectUUID,
+			ServerUUID:      projectCfg.ServerUUID,
+			EnvironmentUUID: projectCfg.EnvironmentUUID,
+			GitHubAppUUID:   projectCfg.GitHubAppUUID,
+			GitRepository:   str("git_repository"),
+			GitBranch:       str("git_branch"),
+			BuildPack:       str("build_pack"),
+			Name:            name,
+			PortsExposes:    str("ports_exposes"),
+		})
+	default:
+		repo := str("git_repository")
+		if !strings.Contains(repo, "://") {
+			repo = "https://github.com/" + repo
+		}
+		resp, err = client.CreatePublicApp(&api.CreatePublicAppRequest{
+			ProjectUUID:     projectCfg.ProjectUUID,
+			ServerUUID:      projectCfg.ServerUUID,
+			EnvironmentUUID: projectCfg.EnvironmentUUID,
+			GitRepository:   repo,
+			GitBranch:       str("git_branch"),
+			BuildPack:       str("build_pack"),
+			Name:            name,
+			PortsExposes:    str("ports_exposes"),
+		})
+	}
+	if err != nil {
+		return "", fmt.Errorf("failed to create application: %w", err)
+	}
+	return resp.UUID, nil
+}
@@ -0,0 +1,78 @@
+package deploy
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/dropalltables/cdp/internal/git"
+)
+
+// CommitCheck is whether a rollback target is still in its branch's history
+type CommitCheck struct {
+	Repo   string // owner/name on GitHub
+	Branch string
+
+	// Missing is set when the commit no longer exists in the repository at
+	// all, e.g. after a force-push and garbage collection
+	Missing bool
+	// OffBranch is set when the commit exists but the branch was rewritten
+	// so that it no longer contains it
+	OffBranch bool
+
+	// Ancestor is the newest commit the target shares with the branch,
+	// when it is off the branch
+	Ancestor        string
+	AncestorMessage string
+}
+
+// OK reports whether the commit can be deployed from the branch as is
+func (c *CommitCheck) OK() bool {
+	return !c.Missing && !c.OffBranch
+}
+
+// CheckRollbackCommit asks GitHub whether sha is still reachable from the
+// app's branch, so a rollback doesn't fail later at checkout. It returns
+// nil when the repository isn't on GitHub and can't be checked.
+func CheckRollbackCommit(token, repo, branch, sha string) (*CommitCheck, error) {
+	if sha == "" || branch == "" || !isGitHubRepo(repo) {
+		return nil, nil
+	}
+	owner, name, ok := strings.Cut(git.RepoSlug(repo), "/")
+	if !ok {
+		return nil, nil
+	}
+
+	gh := git.NewGitHubClient(token)
+	check := &CommitCheck{Repo: owner + "/" + name, Branch: branch}
+	comparison, err := gh.CompareCommits(owner, name, branch, sha)
+	if git.IsNotFound(err) {
+		// A private repository looks the same as a missing commit, so
+		// only blame the commit when the repository itself is visible
+		if _, repoErr := gh.GetRepo(owner, name); repoErr != nil {
+			return nil, fmt.Errorf("cannot access %s on GitHub: %w", check.Repo, repoErr)
+		}
+		check.Missing = true
+		return check, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	// ahead or diverged: the commit has history the branch doesn't
+	switch comparison.Status {
+	case "ahead", "diverged":
+		check.OffBranch = true
+		check.Ancestor = comparison.MergeBaseCommit.SHA
+		check.AncestorMessage, _, _ = strings.Cut(comparison.MergeBaseCommit.Commit.Message, "\n")
+	}
+	return check, nil
+}
+
+// isGitHubRepo reports whether repo, as stored on a Coolify app, lives on
+// GitHub. Apps deployed through a GitHub App store a bare owner/name.
+func isGitHubRepo(repo string) bool {
+	if strings.Contains(repo, "://") || strings.Contains(repo, "@") {
+		return strings.Contains(strings.ToLower(repo), "github.com")
+	}
+	return strings.Count(strings.Trim(repo, "/"), "/") == 1
+}
@@ -0,0 +1,64 @@
+package deploy
+
+import (
+	"errors"
+
+	"github.com/dropalltables/cdp/internal/api"
+	"github.com/dropalltables/cdp/internal/config"
+	"github.com/dropalltables/cdp/internal/ui"
+)
+
+// ErrDeploymentFailed is returned when Coolify reports the deployment failed
+var ErrDeploymentFailed = errors.New("deployment failed")
+
+// BuildInputs are values for local Docker builds that are resolved per deploy
+type BuildInputs struct {
+	Args    map[string]string // build args from cdp.json and --build-arg
+	Secrets map[string]string // BuildKit secrets by id
+
+	// Per-platform images from cdp.json or --platforms; empty builds the
+	// single image described by the platform setting
+	Platforms []config.PlatformTarget
+
+	// Build with the nixpacks CLI instead of a Dockerfile; set by
+	// DeployDocker from the local_builder setting
+	Nixpacks bool
+}
+
+// Run deploys the project with its configured method and notifies the
+// configured webhooks when the deploy starts and finishes
+func Run(client *api.Client, globalCfg *config.GlobalConfig, projectCfg *config.ProjectConfig, prNumber int, build BuildInputs, verbose bool) error {
+	NotifyWebhooks(globalCfg, projectCfg, EventDeployStarted, nil)
+
+	var err error
+	switch projectCfg.DeployMethod {
+	case config.DeployMethodDocker:
+		err = DeployDocker(client, globalCfg, projectCfg, prNumber, build, verbose)
+	case config.DeployMethodCompose:
+		err = DeployCompose(client, globalCfg, projectCfg, verbose)
+	default:
+		err = DeployGit(client, globalCfg, projectCfg, prNumber, verbose)
+	}
+
+	if err != nil {
+		NotifyWebhooks(globalCfg, projectCfg, EventDeployFailed, err)
+	} else {
+		NotifyWebhooks(globalCfg, projectCfg, EventDeploySucceeded, nil)
+	}
+	return err
+}
+
+// printAppURL shows the deployed app's URL and, in an interactive terminal,
+// copies it to the clipboard; a missing clipboard just isn't mentioned
+func printAppURL(client *api.Client, appUUID string) {
+	app, err := client.GetApplication(appUUID)
+	if err != nil || app.FQDN == "" {
+		return
+	}
+	url := api.PreferredDomain(app.FQDN)
+	line := "  URL: " + app.FQDN
+	if ui.Interactive() && ui.CopyToClipboard(url) == nil {
+		line += " (copied to clipboard)"
+	}
+	ui.Dim(line)
+}
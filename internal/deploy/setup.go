@@ -15,9 +15,24 @@ import (
 )
 
 // FirstTimeSetup walks the user through initial project configuration.
-func FirstTimeSetup(client *api.Client, globalCfg *config.GlobalConfig) (*config.ProjectConfig, error) {
+// seed is an optional config without Coolify resources (e.g. shipped by a
+// template); its build settings take precedence over detected ones.
+func FirstTimeSetup(client *api.Client, globalCfg *config.GlobalConfig, seed *config.ProjectConfig) (*config.ProjectConfig, error) {
+	// In a monorepo, pick which package to deploy
+	baseDir := ""
+	if seed != nil {
+		baseDir = seed.BaseDirectory
+	}
+	if baseDir == "" {
+		var err error
+		baseDir, err = selectWorkspace()
+		if err != nil {
+			return nil, err
+		}
+	}
+
 	// Detect framework
-	framework, err := detectFramework()
+	framework, err := detectFramework(baseDir, seed)
 	if err != nil {
 		return nil, err
 	}
@@ -29,7 +44,7 @@ func FirstTimeSetup(client *api.Client, globalCfg *config.GlobalConfig) (*config
 	}
 
 	// Select server
-	serverUUID, err := selectServer(client)
+	serverUUID, err := selectServer(client, globalCfg.DefaultServer)
 	if err != nil {
 		return nil, err
 	}
@@ -40,8 +55,17 @@ func FirstTimeSetup(client *api.Client, globalCfg *config.GlobalConfig) (*config
 		return nil, err
 	}
 
+	// Docker images must match the server's architecture
+	defaultPlatform, serverPlatform := config.DefaultPlatform, ""
+	if deployMethod == config.DeployMethodDocker {
+		defaultPlatform, serverPlatform, err = detectPlatformTask(client, serverUUID)
+		if err != nil {
+			return nil, err
+		}
+	}
+
 	// Advanced options
-	advancedCfg, err := configureAdvancedOptions(deployMethod, framework)
+	advancedCfg, err := configureAdvancedOptions(deployMethod, framework, defaultPlatform)
 	if err != nil {
 		return nil, err
 	}
@@ -57,6 +81,19 @@ func FirstTimeSetup(client *api.Client, globalCfg *config.GlobalConfig) (*config
 		advancedCfg,
 		globalCfg,
 	)
+	projectCfg.BaseDirectory = baseDir
+	projectCfg.ServerPlatform = serverPlatform
+	if projectCfg.Domain == "" {
+		projectCfg.Domain = generateDomain(client, serverUUID, projectCfg.Name)
+	} else {
+		checkDomainDNS(client, serverUUID, projectCfg.Domain)
+	}
+	if seed != nil {
+		projectCfg.Frameworks = seed.Frameworks
+		if seed.Replicas > 0 {
+			projectCfg.Replicas = seed.Replicas
+		}
+	}
 
 	// Save project config
 	err = config.SaveProject(projectCfg)
@@ -66,20 +103,71 @@ func FirstTimeSetup(client *api.Client, globalCfg *config.GlobalConfig) (*config
 
 	ui.Success("Project configured successfully")
 
+	// The app only exists once deployed, so its variables are set after
+	if len(framework.EnvHints) > 0 {
+		ui.Spacer()
+		ui.NextSteps([]string{
+			fmt.Sprintf("After the first deploy, set %s with 'cdp env add KEY=value --env production'", strings.Join(framework.EnvHints, ", ")),
+			"Then run 'cdp' to redeploy with them",
+		})
+	}
+
 	return projectCfg, nil
 }
 
-func detectFramework() (*detect.FrameworkInfo, error) {
+// selectWorkspace asks which package of a monorepo to deploy. It returns
+// the base directory in Coolify's form (e.g. "/apps/web"), or "" for the
+// repository root or when the directory is not a monorepo.
+func selectWorkspace() (string, error) {
+	tool, workspaces := detect.DetectMonorepo(".")
+	if tool == "" || len(workspaces) == 0 {
+		return "", nil
+	}
+
+	ui.Dim(fmt.Sprintf("Detected %s monorepo", tool))
+	options := make([]struct{ Key, Display string }, 0, len(workspaces)+1)
+	for _, ws := range workspaces {
+		options = append(options, struct{ Key, Display string }{"/" + ws.Dir, fmt.Sprintf("%s (%s)", ws.Name, ws.Dir)})
+	}
+	options = append(options, struct{ Key, Display string }{"/", "Repository root"})
+
+	choice, err := ui.SelectWithKeysOrdered("App to deploy", options)
+	if err != nil {
+		return "", err
+	}
+	if choice == "/" {
+		return "", nil
+	}
+	return choice, nil
+}
+
+func detectFramework(baseDir string, seed *config.ProjectConfig) (*detect.FrameworkInfo, error) {
 	var framework *detect.FrameworkInfo
 
-	err := ui.RunTasks([]ui.Task{
+	dir := "."
+	if baseDir != "" {
+		dir = strings.TrimPrefix(baseDir, "/")
+	}
+
+	// Project presets take precedence over the user's global ones
+	var rules []detect.Rule
+	if seed != nil {
+		rules = append(rules, seed.Frameworks...)
+	}
+	globalRules, err := config.LoadFrameworkRules()
+	if err != nil {
+		ui.Warning(fmt.Sprintf("Ignoring framework presets: %v", err))
+	}
+	rules = append(rules, globalRules...)
+
+	err = ui.RunTasks([]ui.Task{
 		{
 			Name:         "detect-framework",
 			ActiveName:   "Analyzing project...",
 			CompleteName: "Analyzed project",
 			Action: func() error {
 				var err error
-				framework, err = detect.Detect(".")
+				framework, err = detect.DetectWithRules(dir, rules)
 				return err
 			},
 		},
@@ -87,6 +175,9 @@ func detectFramework() (*detect.FrameworkInfo, error) {
 	if err != nil {
 		return nil, fmt.Errorf("failed to detect framework: %w", err)
 	}
+	if seed != nil {
+		applySeed(framework, seed)
+	}
 
 	ui.LogChoice("Framework", framework.Name)
 
@@ -103,6 +194,9 @@ func detectFramework() (*detect.FrameworkInfo, error) {
 	if framework.PublishDirectory != "" {
 		ui.KeyValue("Output", framework.PublishDirectory)
 	}
+	if len(framework.EnvHints) > 0 {
+		ui.KeyValue("Requires", strings.Join(framework.EnvHints, ", "))
+	}
 
 	ui.Spacer()
 
@@ -136,6 +230,31 @@ func detectFramework() (*detect.FrameworkInfo, error) {
 	return framework, nil
 }
 
+// applySeed overrides detected build settings with those set in seed
+func applySeed(f *detect.FrameworkInfo, seed *config.ProjectConfig) {
+	if seed.Framework != "" {
+		f.Name = seed.Framework
+	}
+	if seed.BuildPack != "" {
+		f.BuildPack = seed.BuildPack
+	}
+	if seed.InstallCommand != "" {
+		f.InstallCommand = seed.InstallCommand
+	}
+	if seed.BuildCommand != "" {
+		f.BuildCommand = seed.BuildCommand
+	}
+	if seed.StartCommand != "" {
+		f.StartCommand = seed.StartCommand
+	}
+	if seed.PublishDir != "" {
+		f.PublishDirectory = seed.PublishDir
+	}
+	if seed.Port != "" {
+		f.Port = seed.Port
+	}
+}
+
 func editBuildSettings(f *detect.FrameworkInfo) (*detect.FrameworkInfo, error) {
 	installCmd, err := ui.InputWithDefault("Install command", f.InstallCommand)
 	if err != nil {
@@ -202,7 +321,9 @@ func chooseDeployMethod(globalCfg *config.GlobalConfig) (string, error) {
 	return optionMap[selected], nil
 }
 
-func selectServer(client *api.Client) (string, error) {
+// selectServer prompts for a server unless defaultServer (a UUID or name,
+// typically pinned by the org config) matches one
+func selectServer(client *api.Client, defaultServer string) (string, error) {
 	var servers []api.Server
 	err := ui.RunTasks([]ui.Task{
 		{
@@ -226,6 +347,16 @@ func selectServer(client *api.Client) (string, error) {
 		return "", fmt.Errorf("no servers available")
 	}
 
+	if defaultServer != "" {
+		for _, s := range servers {
+			if s.UUID == defaultServer || s.Name == defaultServer {
+				ui.KeyValue("Server", s.Name)
+				return s.UUID, nil
+			}
+		}
+		ui.Warning(fmt.Sprintf("Default server '%s' not found", defaultServer))
+	}
+
 	serverOptions := make(map[string]string)
 	for _, s := range servers {
 		displayName := s.Name
@@ -286,12 +417,54 @@ func selectOrCreateProject(client *api.Client) (projectName, projectUUID, enviro
 		project := projectMap[selectedProject]
 		projectName = selectedProject
 		projectUUID = project.UUID
-		environmentUUID = ""
+		environmentUUID, err = selectEnvironment(client, projectUUID)
+		if err != nil {
+			return "", "", "", err
+		}
 	}
 
 	return projectName, projectUUID, environmentUUID, nil
 }
 
+// selectEnvironment asks which environment of an existing project to deploy
+// to when it has more than one. An empty UUID leaves the choice to the
+// deploy, which uses (or creates) production.
+func selectEnvironment(client *api.Client, projectUUID string) (string, error) {
+	var envs []api.Environment
+	err := ui.RunTasks([]ui.Task{
+		{
+			Name:         "load-environments",
+			ActiveName:   "Loading environments...",
+			CompleteName: "Loaded environments",
+			Action: func() error {
+				var err error
+				envs, err = client.ListEnvironments(projectUUID)
+				return err
+			},
+		},
+	})
+	if err != nil {
+		return "", fmt.Errorf("failed to list environments: %w", err)
+	}
+	if len(envs) <= 1 {
+		return "", nil
+	}
+
+	// Production first, as it's what a deploy uses by default
+	options := make([]struct{ Key, Display string }, 0, len(envs))
+	for _, e := range envs {
+		if strings.EqualFold(e.Name, "production") {
+			options = append(options, struct{ Key, Display string }{Key: e.UUID, Display: e.Name})
+		}
+	}
+	for _, e := range envs {
+		if !strings.EqualFold(e.Name, "production") {
+			options = append(options, struct{ Key, Display string }{Key: e.UUID, Display: e.Name})
+		}
+	}
+	return ui.SelectWithKeysOrdered("Environment", options)
+}
+
 type advancedConfig struct {
 	Port     string
 	Platform string
@@ -299,7 +472,7 @@ type advancedConfig struct {
 	Domain   string
 }
 
-func configureAdvancedOptions(deployMethod string, framework *detect.FrameworkInfo) (*advancedConfig, error) {
+func configureAdvancedOptions(deployMethod string, framework *detect.FrameworkInfo, defaultPlatform string) (*advancedConfig, error) {
 	configureAdvanced, err := ui.Confirm("Configure advanced options")
 	if err != nil {
 		return nil, err
@@ -307,7 +480,7 @@ func configureAdvancedOptions(deployMethod string, framework *detect.FrameworkIn
 
 	cfg := &advancedConfig{
 		Port:     framework.Port,
-		Platform: config.DefaultPlatform,
+		Platform: defaultPlatform,
 		Branch:   config.DefaultBranch,
 		Domain:   "",
 	}
@@ -326,13 +499,22 @@ func configureAdvancedOptions(deployMethod string, framework *detect.FrameworkIn
 	}
 
 	if deployMethod == config.DeployMethodDocker {
-		platformOptions := []string{"linux/amd64 (Intel/AMD)", "linux/arm64 (ARM)"}
+		platformOptions := []string{"linux/amd64 (Intel/AMD)", "linux/arm64 (ARM)", "Both (multi-platform, needs docker buildx)"}
+		if cfg.Platform == "linux/arm64" {
+			// List the server's architecture first
+			platformOptions[0], platformOptions[1] = platformOptions[1], platformOptions[0]
+		}
 		platformChoice, err := ui.Select("Target platform", platformOptions)
 		if err != nil {
 			return nil, err
 		}
-		if strings.Contains(platformChoice, "arm64") {
+		switch {
+		case strings.HasPrefix(platformChoice, "Both"):
+			cfg.Platform = "linux/amd64,linux/arm64"
+		case strings.Contains(platformChoice, "arm64"):
 			cfg.Platform = "linux/arm64"
+		default:
+			cfg.Platform = "linux/amd64"
 		}
 	}
 
@@ -398,6 +580,59 @@ func buildProjectConfig(
 	return projectCfg
 }
 
+// generateDomain returns a domain under the server's wildcard domain for
+// an app set up without a custom one, or "" to leave it to Coolify
+func generateDomain(client *api.Client, serverUUID, appName string) string {
+	var domain string
+	err := ui.RunTasks([]ui.Task{
+		{
+			Name:         "generate-domain",
+			ActiveName:   "Generating domain...",
+			CompleteName: "Generated domain",
+			Action: func() error {
+				var err error
+				domain, err = WildcardDomain(client, serverUUID, appName)
+				return err
+			},
+		},
+	})
+	if err != nil {
+		ui.Warning("Could not generate a domain from the server's wildcard domain")
+		ui.Dim("  " + err.Error())
+		return ""
+	}
+	if domain != "" {
+		ui.KeyValue("Domain", domain)
+	}
+	return domain
+}
+
+// checkDomainDNS warns when a custom domain doesn't point at the server
+// yet. Setup carries on either way, as DNS can be fixed before the deploy.
+func checkDomainDNS(client *api.Client, serverUUID, domain string) {
+	var checks []DNSCheck
+	var serverIPs []string
+	_ = ui.RunTasks([]ui.Task{
+		{
+			Name:         "check-dns",
+			ActiveName:   "Checking DNS...",
+			CompleteName: "Checked DNS",
+			Action: func() error {
+				var hosts []string
+				for _, d := range strings.Split(domain, ",") {
+					if d = strings.TrimSpace(d); d != "" {
+						hosts = append(hosts, hostOf(d))
+					}
+				}
+				checks = CheckDNS(hosts)
+				serverIPs = ServerAddresses(client, serverUUID)
+				return nil
+			},
+		},
+	})
+	WarnDNS(checks, serverIPs)
+}
+
 func getWorkingDirName() string {
 	dir, err := os.Getwd()
 	if err != nil {
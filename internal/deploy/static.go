@@ -0,0 +1,37 @@
+package deploy
+
+import (
+	"path/filepath"
+	"strings"
+
+	"github.com/dropalltables/cdp/internal/config"
+	"github.com/dropalltables/cdp/internal/git"
+)
+
+// StaticOutputDir returns the local publish directory of a static site, or
+// "" if the project isn't one
+func StaticOutputDir(projectCfg *config.ProjectConfig) string {
+	if projectCfg.PublishDir == "" {
+		return ""
+	}
+	return filepath.Join(".", strings.TrimPrefix(projectCfg.BaseDirectory, "/"), projectCfg.PublishDir)
+}
+
+// StaticSourceHash hashes the sources a static site is built from, as its
+// output is built remotely or in a container and a local publish directory
+// may be stale. It returns "" if the project isn't a static site or isn't
+// in a git repository, in which case there is nothing to compare.
+func StaticSourceHash(projectCfg *config.ProjectConfig) (string, error) {
+	if StaticOutputDir(projectCfg) == "" || !git.IsRepo(".") {
+		return "", nil
+	}
+	dir := filepath.Join(".", strings.TrimPrefix(projectCfg.BaseDirectory, "/"))
+
+	// cdp.json changes with cdp's own bookkeeping, and the publish
+	// directory is built output, unless the site is published as is
+	exclude := []string{filepath.ToSlash(config.ProjectConfigPath("."))}
+	if output := StaticOutputDir(projectCfg); output != dir {
+		exclude = append(exclude, filepath.ToSlash(output))
+	}
+	return git.SourceHash(".", dir, exclude...)
+}
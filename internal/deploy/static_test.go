@@ -0,0 +1,88 @@
+package deploy
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+
+	"github.com/dropalltables/cdp/internal/config"
+)
+
+// initSiteRepo creates a git repository in a temporary directory, made the
+// working directory, with files committed
+func initSiteRepo(t *testing.T, files map[string]string) {
+	t.Helper()
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not installed")
+	}
+	dir := t.TempDir()
+	t.Chdir(dir)
+	for name, content := range files {
+		writeSiteFile(t, name, content)
+	}
+	for _, args := range [][]string{
+		{"init", "-q"},
+		{"add", "-A"},
+		{"-c", "user.name=cdp", "-c", "user.email=cdp@example.com", "commit", "-q", "-m", "init"},
+	} {
+		if out, err := exec.Command("git", args...).CombinedOutput(); err != nil {
+			t.Fatalf("git %v: %v\n%s", args, err, out)
+		}
+	}
+}
+
+func writeSiteFile(t *testing.T, name, content string) {
+	t.Helper()
+	if err := os.MkdirAll(filepath.Dir(name), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(name, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func staticHash(t *testing.T, projectCfg *config.ProjectConfig) string {
+	t.Helper()
+	hash, err := StaticSourceHash(projectCfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if hash == "" {
+		t.Fatal("no hash for a static site in a git repository")
+	}
+	return hash
+}
+
+func TestStaticSourceHashPublishedAsIs(t *testing.T) {
+	initSiteRepo(t, map[string]string{"index.html": "<h1>Hello</h1>"})
+	projectCfg := &config.ProjectConfig{PublishDir: "."}
+
+	before := staticHash(t, projectCfg)
+	writeSiteFile(t, "index.html", "<h1>Hello again</h1>")
+	if staticHash(t, projectCfg) == before {
+		t.Error("uncommitted edit to a site published from its base directory wasn't detected")
+	}
+
+	before = staticHash(t, projectCfg)
+	writeSiteFile(t, "about.html", "<h1>About</h1>")
+	if staticHash(t, projectCfg) == before {
+		t.Error("new file in a site published from its base directory wasn't detected")
+	}
+}
+
+func TestStaticSourceHashIgnoresOutput(t *testing.T) {
+	initSiteRepo(t, map[string]string{"src/index.md": "# Hello"})
+	projectCfg := &config.ProjectConfig{PublishDir: "dist"}
+
+	before := staticHash(t, projectCfg)
+	writeSiteFile(t, "dist/index.html", "<h1>Hello</h1>")
+	if staticHash(t, projectCfg) != before {
+		t.Error("built output changed the source hash")
+	}
+
+	writeSiteFile(t, "src/index.md", "# Hello again")
+	if staticHash(t, projectCfg) == before {
+		t.Error("uncommitted source edit wasn't detected")
+	}
+}
@@ -0,0 +1,51 @@
+package deploy
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"strings"
+
+	"github.com/dropalltables/cdp/internal/api"
+)
+
+// TraceEnvVar is the runtime environment variable that carries the current
+// deploy's trace ID. Apps log it at startup so 'cdp logs --grep-deploy' can
+// find where a release began.
+const TraceEnvVar = "CDP_DEPLOY_ID"
+
+const traceIDPrefix = "dpl-"
+
+// NewTraceID returns a random ID identifying a single deploy
+func NewTraceID() string {
+	b := make([]byte, 6)
+	if _, err := rand.Read(b); err != nil {
+		return ""
+	}
+	return traceIDPrefix + hex.EncodeToString(b)
+}
+
+// IsTraceID reports whether s has the form of an ID from NewTraceID
+func IsTraceID(s string) bool {
+	id, ok := strings.CutPrefix(s, traceIDPrefix)
+	if !ok || len(id) != 12 {
+		return false
+	}
+	_, err := hex.DecodeString(id)
+	return err == nil
+}
+
+// SetTraceID stores id in the application's preview or production
+// variables, whichever the deploy targets
+func SetTraceID(client *api.Client, appUUID, id string, preview bool) error {
+	envVars, err := client.GetApplicationEnvVars(appUUID)
+	if err != nil {
+		return err
+	}
+	for _, env := range envVars {
+		if env.Key == TraceEnvVar && env.IsPreview == preview {
+			return client.UpdateApplicationEnvVar(appUUID, TraceEnvVar, id, false, preview)
+		}
+	}
+	_, err = client.CreateApplicationEnvVar(appUUID, TraceEnvVar, id, false, preview)
+	return err
+}
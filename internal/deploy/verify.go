@@ -0,0 +1,38 @@
+package deploy
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+)
+
+const (
+	// urlPollInterval is how often WaitForURL retries
+	urlPollInterval = 5 * time.Second
+	// urlRequestTimeout bounds each request made by WaitForURL
+	urlRequestTimeout = 10 * time.Second
+)
+
+// WaitForURL requests url until it answers with a status below 400 or
+// timeout passes. Right after a deploy the proxy may still be picking up
+// the app or issuing its certificate, so errors are retried.
+func WaitForURL(url string, timeout time.Duration) error {
+	client := &http.Client{Timeout: urlRequestTimeout}
+	deadline := time.Now().Add(timeout)
+	var lastErr error
+	for {
+		resp, err := client.Get(url)
+		if err == nil {
+			resp.Body.Close()
+			if resp.StatusCode < 400 {
+				return nil
+			}
+			err = fmt.Errorf("%s answered %s", url, resp.Status)
+		}
+		lastErr = err
+		if time.Now().Add(urlPollInterval).After(deadline) {
+			return lastErr
+		}
+		time.Sleep(urlPollInterval)
+	}
+}
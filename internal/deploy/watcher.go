@@ -12,10 +12,9 @@ import (
 
 const (
 	// Polling configuration
-	maxPollAttempts      = 120 // 4 minutes max (2s intervals)
-	pollInterval         = 2 * time.Second
-	noDeploymentTimeout  = 15 // attempts before giving up if no deployment found
-	maxConsecutiveErrors = 5  // max API errors before giving up
+	maxWatchDuration     = 4 * time.Minute
+	noDeploymentTimeout  = 30 * time.Second // before giving up if no deployment found
+	maxConsecutiveErrors = 5                // max API errors before giving up
 )
 
 // WatchDeployment polls the deployment status and displays build logs.
@@ -32,6 +31,8 @@ func WatchDeployment(client *api.Client, appUUID string) bool {
 		debug:             debug,
 		consecutiveErrors: 0,
 		lastLogLen:        0,
+		poll:              newAdaptivePoll(),
+		started:           time.Now(),
 	}
 
 	return watcher.watch()
@@ -45,26 +46,28 @@ type deploymentWatcher struct {
 	lastLogLen         int
 	lastDeploymentUUID string
 	seenDeployment     bool
+	poll               *adaptivePoll
+	started            time.Time
 }
 
 func (w *deploymentWatcher) watch() bool {
-	for attempt := 0; attempt < maxPollAttempts; attempt++ {
+	for attempt := 0; time.Since(w.started) < maxWatchDuration; attempt++ {
 		status, done := w.checkDeploymentStatus(attempt)
 		if done {
 			return status == deploymentSuccess
 		}
 		
-		// Print progress every 30 attempts (1 minute)
+		// Print progress every 30 attempts
 		if attempt > 0 && attempt%30 == 0 && w.debug {
 			fmt.Printf("[DEBUG] Still waiting... (attempt %d)\n", attempt)
 		}
 		
-		time.Sleep(pollInterval)
+		time.Sleep(w.poll.Next())
 	}
 
 	// Timeout reached - make final check
 	if w.debug {
-		fmt.Printf("[DEBUG] Reached max poll attempts (%d), making final check\n", maxPollAttempts)
+		fmt.Printf("[DEBUG] Watched for %s, making final check\n", maxWatchDuration)
 	}
 	return w.checkFinalStatus()
 }
@@ -115,7 +118,7 @@ func (w *deploymentWatcher) handleAPIError(err error) (deploymentStatus, bool) {
 
 func (w *deploymentWatcher) handleNoDeployments(attempt int) (deploymentStatus, bool) {
 	// If we never saw a deployment after reasonable wait, give up
-	if !w.seenDeployment && attempt >= noDeploymentTimeout {
+	if !w.seenDeployment && time.Since(w.started) >= noDeploymentTimeout {
 		if w.debug {
 			fmt.Printf("[DEBUG] No deployment found after %d attempts\n", attempt)
 		}
@@ -178,6 +181,7 @@ func (w *deploymentWatcher) processDeployment(deployment api.Deployment, attempt
 	} else {
 		// Print new logs
 		w.printNewLogs(detail.Logs)
+		w.poll.Observe(deployUUID + " " + strings.ToLower(detail.Status))
 
 		// Check status from detailed info
 		if status, done := w.checkStatus(detail.Status); done {
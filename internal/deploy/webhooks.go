@@ -0,0 +1,168 @@
+package deploy
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"sync"
+	"time"
+
+	"github.com/dropalltables/cdp/internal/config"
+	"github.com/dropalltables/cdp/internal/git"
+	"github.com/dropalltables/cdp/internal/ui"
+)
+
+// Webhook events sent for each deploy
+const (
+	EventDeployStarted   = "deploy.started"
+	EventDeploySucceeded = "deploy.succeeded"
+	EventDeployFailed    = "deploy.failed"
+)
+
+// SignatureHeader carries the hex HMAC-SHA256 of the body, keyed with the
+// webhook secret, as "sha256=<hex>"
+const SignatureHeader = "X-CDP-Signature"
+
+// webhookTimeout bounds each delivery so a slow receiver can't stall a deploy
+const webhookTimeout = 5 * time.Second
+
+// WebhookPayload is the JSON body POSTed to webhook URLs
+type WebhookPayload struct {
+	Event     string `json:"event"`
+	Project   string `json:"project"`
+	AppUUID   string `json:"app_uuid,omitempty"`
+	Method    string `json:"method"`
+	Branch    string `json:"branch,omitempty"`
+	Commit    string `json:"commit,omitempty"`
+	TraceID   string `json:"trace_id,omitempty"`
+	Error     string `json:"error,omitempty"`
+	Timestamp string `json:"timestamp"`
+}
+
+// NotifyWebhooks POSTs a signed payload about a deploy event to every
+// configured webhook. Delivery failures are reported but never fail the deploy.
+func NotifyWebhooks(globalCfg *config.GlobalConfig, projectCfg *config.ProjectConfig, event string, deployErr error) {
+	urls := append(append([]string{}, globalCfg.Webhooks...), projectCfg.Webhooks...)
+	if len(urls) == 0 {
+		return
+	}
+
+	payload := WebhookPayload{
+		Event:     event,
+		Project:   projectCfg.Name,
+		AppUUID:   projectCfg.AppUUID,
+		Method:    projectCfg.DeployMethod,
+		Timestamp: time.Now().UTC().Format(time.RFC3339),
+	}
+	if projectCfg.DeployMethod == config.DeployMethodGit {
+		payload.Branch = deployBranch(projectCfg)
+		payload.Commit, _ = git.GetLatestCommitHash(".")
+	}
+	if projectCfg.TraceDeploys {
+		payload.TraceID = config.LastTraceID(projectCfg.AppUUID)
+	}
+	if deployErr != nil {
+		payload.Error = deployErr.Error()
+	}
+
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return
+	}
+
+	// Payloads are always signed, so receivers never have to trust an
+	// unsigned request
+	if globalCfg.WebhookSecret == "" {
+		if err := createWebhookSecret(globalCfg); err != nil {
+			ui.Warning(fmt.Sprintf("Webhooks not sent: failed to create a webhook secret: %v", err))
+			return
+		}
+	}
+	mac := hmac.New(sha256.New, []byte(globalCfg.WebhookSecret))
+	mac.Write(body)
+	signature := "sha256=" + hex.EncodeToString(mac.Sum(nil))
+
+	client := &http.Client{Timeout: webhookTimeout}
+	var wg sync.WaitGroup
+	errs := make([]error, len(urls))
+	for i, target := range urls {
+		wg.Add(1)
+		go func(i int, target string) {
+			defer wg.Done()
+			errs[i] = postWebhook(client, target, event, signature, body)
+		}(i, target)
+	}
+	wg.Wait()
+
+	for i, err := range errs {
+		if err != nil {
+			ui.Warning(fmt.Sprintf("Webhook %s failed: %v", webhookName(urls[i], i), err))
+		}
+	}
+}
+
+// webhookName identifies a webhook by scheme and host only, as the rest of
+// its URL often carries a token
+func webhookName(target string, i int) string {
+	u, err := url.Parse(target)
+	if err != nil || u.Host == "" {
+		return fmt.Sprintf("#%d", i+1)
+	}
+	return u.Scheme + "://" + u.Host
+}
+
+// createWebhookSecret generates the secret webhooks are signed with and
+// saves it, as receivers need the same one every time
+func createWebhookSecret(globalCfg *config.GlobalConfig) error {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return err
+	}
+	globalCfg.WebhookSecret = hex.EncodeToString(b)
+	if err := config.SaveGlobal(globalCfg); err != nil {
+		globalCfg.WebhookSecret = ""
+		return err
+	}
+
+	path, _ := config.GetConfigPath()
+	ui.Dim(fmt.Sprintf("Generated webhook_secret in %s; verify %s with it in your receivers", path, SignatureHeader))
+	return nil
+}
+
+// postWebhook delivers one event; errors never include the URL
+func postWebhook(client *http.Client, target, event, signature string, body []byte) error {
+	req, err := http.NewRequest(http.MethodPost, target, bytes.NewReader(body))
+	if err != nil {
+		return redactURLError(err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "cdp/"+config.CLIVersion)
+	req.Header.Set("X-CDP-Event", event)
+	req.Header.Set(SignatureHeader, signature)
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return redactURLError(err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf("status %d", resp.StatusCode)
+	}
+	return nil
+}
+
+// redactURLError drops the URL a *url.Error repeats in its message
+func redactURLError(err error) error {
+	var urlErr *url.Error
+	if errors.As(err, &urlErr) {
+		return fmt.Errorf("%s: %w", urlErr.Op, urlErr.Err)
+	}
+	return err
+}
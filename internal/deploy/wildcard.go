@@ -0,0 +1,96 @@
+package deploy
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+
+	"github.com/dropalltables/cdp/internal/api"
+)
+
+// maxDomainAttempts bounds the numbered suffixes tried for a free subdomain
+const maxDomainAttempts = 100
+
+// WildcardDomain returns a domain for appName under the server's wildcard
+// domain, e.g. https://blog.apps.example.com, numbering it (blog-2, ...)
+// when another resource on the server already uses it. It returns "" when
+// the server has no wildcard domain.
+func WildcardDomain(client *api.Client, serverUUID, appName string) (string, error) {
+	server, err := client.GetServer(serverUUID)
+	if err != nil {
+		return "", fmt.Errorf("failed to load server: %w", err)
+	}
+	if server.Settings == nil || strings.TrimSpace(server.Settings.WildcardDomain) == "" {
+		return "", nil
+	}
+
+	wildcard := strings.TrimSpace(server.Settings.WildcardDomain)
+	if !strings.Contains(wildcard, "://") {
+		wildcard = "https://" + wildcard
+	}
+	base, err := url.Parse(wildcard)
+	if err != nil || base.Hostname() == "" {
+		return "", fmt.Errorf("invalid wildcard domain %q", server.Settings.WildcardDomain)
+	}
+
+	// Best-effort: without the list, the first candidate is used
+	taken := map[string]bool{}
+	if groups, err := client.GetServerDomains(serverUUID); err == nil {
+		for _, g := range groups {
+			for _, d := range g.Domains {
+				taken[strings.ToLower(hostOf(d))] = true
+			}
+		}
+	}
+
+	label := subdomainLabel(appName)
+	for i := 1; i <= maxDomainAttempts; i++ {
+		candidate := label
+		if i > 1 {
+			candidate = fmt.Sprintf("%s-%d", label, i)
+		}
+		if !taken[candidate+"."+strings.ToLower(base.Hostname())] {
+			return base.Scheme + "://" + candidate + "." + base.Host, nil
+		}
+	}
+	return "", fmt.Errorf("no free subdomain for %q under %s", label, base.Hostname())
+}
+
+// subdomainLabel turns an app name into a DNS label: lowercase letters,
+// digits and dashes, at most 63 characters
+func subdomainLabel(name string) string {
+	var b strings.Builder
+	dash := false
+	for _, r := range strings.ToLower(name) {
+		if (r >= 'a' && r <= 'z') || (r >= '0' && r <= '9') {
+			b.WriteRune(r)
+			dash = false
+		} else if !dash && b.Len() > 0 {
+			b.WriteByte('-')
+			dash = true
+		}
+	}
+	// Leave room for a numbered suffix
+	label := b.String()
+	if len(label) > 59 {
+		label = label[:59]
+	}
+	label = strings.Trim(label, "-")
+	if label == "" {
+		return "app"
+	}
+	return label
+}
+
+// hostOf returns the hostname of a domain with or without a scheme
+func hostOf(domain string) string {
+	domain = strings.TrimSpace(domain)
+	if !strings.Contains(domain, "://") {
+		domain = "https://" + domain
+	}
+	u, err := url.Parse(domain)
+	if err != nil {
+		return domain
+	}
+	return u.Hostname()
+}
@@ -2,8 +2,12 @@ package detect
 
 import (
 	"encoding/json"
+	"fmt"
 	"os"
 	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
 )
 
 // Detect attempts to detect the framework in the given directory
@@ -18,6 +22,21 @@ func Detect(dir string) (*FrameworkInfo, error) {
 		return detectDockerCompose(dir)
 	}
 
+	// Check for Ruby before package.json: Rails apps often ship one for assets
+	if fileExists(filepath.Join(dir, "Gemfile")) {
+		return detectRuby(dir)
+	}
+
+	// Check for PHP before package.json: Laravel ships one for Vite
+	if fileExists(filepath.Join(dir, "composer.json")) {
+		return detectPHP(dir)
+	}
+
+	// Check for Deno before package.json, which Deno projects may also have
+	if fileExists(filepath.Join(dir, "deno.json")) || fileExists(filepath.Join(dir, "deno.jsonc")) || fileExists(filepath.Join(dir, "deno.lock")) {
+		return detectDeno(dir)
+	}
+
 	// Check for package.json (Node.js projects)
 	if fileExists(filepath.Join(dir, "package.json")) {
 		return detectNodeProject(dir)
@@ -35,6 +54,21 @@ func Detect(dir string) (*FrameworkInfo, error) {
 		return detectGo(dir)
 	}
 
+	// Check for JVM projects (Maven or Gradle)
+	if fileExists(filepath.Join(dir, "pom.xml")) || fileExists(filepath.Join(dir, "build.gradle")) || fileExists(filepath.Join(dir, "build.gradle.kts")) {
+		return detectJVM(dir)
+	}
+
+	// Check for Elixir
+	if fileExists(filepath.Join(dir, "mix.exs")) {
+		return detectElixir(dir)
+	}
+
+	// Check for Rust
+	if fileExists(filepath.Join(dir, "Cargo.toml")) {
+		return detectRust(dir)
+	}
+
 	// Check for Python
 	if fileExists(filepath.Join(dir, "requirements.txt")) || fileExists(filepath.Join(dir, "pyproject.toml")) {
 		return detectPython(dir)
@@ -62,11 +96,14 @@ func detectNodeProject(dir string) (*FrameworkInfo, error) {
 		Dependencies    map[string]string `json:"dependencies"`
 		DevDependencies map[string]string `json:"devDependencies"`
 		Scripts         map[string]string `json:"scripts"`
+		PackageManager  string            `json:"packageManager"`
 	}
 	if err := json.Unmarshal(data, &pkg); err != nil {
 		return nil, err
 	}
 
+	pm := nodeCommands(detectPackageManager(dir, pkg.PackageManager))
+
 	allDeps := make(map[string]string)
 	for k, v := range pkg.Dependencies {
 		allDeps[k] = v
@@ -80,11 +117,12 @@ func detectNodeProject(dir string) (*FrameworkInfo, error) {
 		return &FrameworkInfo{
 			Name:           "Next.js",
 			BuildPack:      BuildPackNixpacks,
-			InstallCommand: "npm install",
-			BuildCommand:   "npm run build",
-			StartCommand:   "npm start",
+			InstallCommand: pm.install,
+			BuildCommand:   pm.run("build"),
+			StartCommand:   pm.run("start"),
 			Port:           "3000",
 			IsStatic:       false,
+			PackageManager: pm.name,
 		}, nil
 	}
 
@@ -93,11 +131,12 @@ func detectNodeProject(dir string) (*FrameworkInfo, error) {
 		return &FrameworkInfo{
 			Name:             "Astro",
 			BuildPack:        BuildPackNixpacks,
-			InstallCommand:   "npm install",
-			BuildCommand:     "npm run build",
+			InstallCommand:   pm.install,
+			BuildCommand:     pm.run("build"),
 			PublishDirectory: "dist",
 			Port:             "4321",
 			IsStatic:         true,
+			PackageManager:   pm.name,
 		}, nil
 	}
 
@@ -106,11 +145,12 @@ func detectNodeProject(dir string) (*FrameworkInfo, error) {
 		return &FrameworkInfo{
 			Name:           "Nuxt",
 			BuildPack:      BuildPackNixpacks,
-			InstallCommand: "npm install",
-			BuildCommand:   "npm run build",
-			StartCommand:   "npm start",
+			InstallCommand: pm.install,
+			BuildCommand:   pm.run("build"),
+			StartCommand:   pm.run("start"),
 			Port:           "3000",
 			IsStatic:       false,
+			PackageManager: pm.name,
 		}, nil
 	}
 
@@ -119,11 +159,77 @@ func detectNodeProject(dir string) (*FrameworkInfo, error) {
 		return &FrameworkInfo{
 			Name:           "SvelteKit",
 			BuildPack:      BuildPackNixpacks,
-			InstallCommand: "npm install",
-			BuildCommand:   "npm run build",
-			StartCommand:   "npm run preview",
+			InstallCommand: pm.install,
+			BuildCommand:   pm.run("build"),
+			StartCommand:   pm.run("preview"),
 			Port:           "4173",
 			IsStatic:       false,
+			PackageManager: pm.name,
+		}, nil
+	}
+
+	// Detect Remix before Vite, which Remix v2 builds with
+	if _, ok := allDeps["@remix-run/react"]; ok {
+		return &FrameworkInfo{
+			Name:           "Remix",
+			BuildPack:      BuildPackNixpacks,
+			InstallCommand: pm.install,
+			BuildCommand:   pm.run("build"),
+			StartCommand:   pm.run("start"),
+			Port:           "3000",
+			IsStatic:       false,
+			PackageManager: pm.name,
+		}, nil
+	}
+
+	// Detect Gatsby
+	if _, ok := allDeps["gatsby"]; ok {
+		return &FrameworkInfo{
+			Name:             "Gatsby",
+			BuildPack:        BuildPackNixpacks,
+			InstallCommand:   pm.install,
+			BuildCommand:     pm.run("build"),
+			PublishDirectory: "public",
+			Port:             "9000",
+			IsStatic:         true,
+			PackageManager:   pm.name,
+		}, nil
+	}
+
+	// Detect Angular
+	if _, ok := allDeps["@angular/core"]; ok {
+		return detectAngular(dir, pm, allDeps)
+	}
+
+	// Detect Vue CLI
+	if _, ok := allDeps["@vue/cli-service"]; ok {
+		return &FrameworkInfo{
+			Name:             "Vue CLI",
+			BuildPack:        BuildPackNixpacks,
+			InstallCommand:   pm.install,
+			BuildCommand:     pm.run("build"),
+			PublishDirectory: "dist",
+			Port:             "8080",
+			IsStatic:         true,
+			PackageManager:   pm.name,
+		}, nil
+	}
+
+	// Detect Eleventy
+	if _, ok := allDeps["@11ty/eleventy"]; ok {
+		buildCmd := "npx @11ty/eleventy"
+		if _, ok := pkg.Scripts["build"]; ok {
+			buildCmd = pm.run("build")
+		}
+		return &FrameworkInfo{
+			Name:             "Eleventy",
+			BuildPack:        BuildPackNixpacks,
+			InstallCommand:   pm.install,
+			BuildCommand:     buildCmd,
+			PublishDirectory: "_site",
+			Port:             "8080",
+			IsStatic:         true,
+			PackageManager:   pm.name,
 		}, nil
 	}
 
@@ -132,11 +238,12 @@ func detectNodeProject(dir string) (*FrameworkInfo, error) {
 		return &FrameworkInfo{
 			Name:             "Vite",
 			BuildPack:        BuildPackNixpacks,
-			InstallCommand:   "npm install",
-			BuildCommand:     "npm run build",
+			InstallCommand:   pm.install,
+			BuildCommand:     pm.run("build"),
 			PublishDirectory: "dist",
 			Port:             "5173",
 			IsStatic:         true,
+			PackageManager:   pm.name,
 		}, nil
 	}
 
@@ -145,34 +252,232 @@ func detectNodeProject(dir string) (*FrameworkInfo, error) {
 		return &FrameworkInfo{
 			Name:             "Create React App",
 			BuildPack:        BuildPackNixpacks,
-			InstallCommand:   "npm install",
-			BuildCommand:     "npm run build",
+			InstallCommand:   pm.install,
+			BuildCommand:     pm.run("build"),
 			PublishDirectory: "build",
 			IsStatic:         true,
+			PackageManager:   pm.name,
 		}, nil
 	}
 
 	// Generic Node.js
 	startCmd := ""
 	if _, ok := pkg.Scripts["start"]; ok {
-		startCmd = "npm start"
+		startCmd = pm.run("start")
 	}
 	buildCmd := ""
 	if _, ok := pkg.Scripts["build"]; ok {
-		buildCmd = "npm run build"
+		buildCmd = pm.run("build")
+	}
+
+	name := "Node.js"
+	if pm.name == PackageManagerBun {
+		name = "Bun"
 	}
 
 	return &FrameworkInfo{
-		Name:           "Node.js",
+		Name:           name,
 		BuildPack:      BuildPackNixpacks,
-		InstallCommand: "npm install",
+		InstallCommand: pm.install,
 		BuildCommand:   buildCmd,
 		StartCommand:   startCmd,
 		Port:           "3000",
 		IsStatic:       false,
+		PackageManager: pm.name,
+	}, nil
+}
+
+// detectAngular reads the build output path from angular.json. Apps using
+// @angular/ssr are served by Node; others are static.
+func detectAngular(dir string, pm nodePackageManager, deps map[string]string) (*FrameworkInfo, error) {
+	outputPath, browserDir := angularOutput(dir)
+
+	if _, ok := deps["@angular/ssr"]; ok {
+		return &FrameworkInfo{
+			Name:           "Angular",
+			BuildPack:      BuildPackNixpacks,
+			InstallCommand: pm.install,
+			BuildCommand:   pm.run("build"),
+			StartCommand:   fmt.Sprintf("node %s/server/server.mjs", outputPath),
+			Port:           "4000",
+			IsStatic:       false,
+			PackageManager: pm.name,
+		}, nil
+	}
+
+	publishDir := outputPath
+	if browserDir != "" {
+		publishDir += "/" + browserDir
+	}
+
+	return &FrameworkInfo{
+		Name:             "Angular",
+		BuildPack:        BuildPackNixpacks,
+		InstallCommand:   pm.install,
+		BuildCommand:     pm.run("build"),
+		PublishDirectory: publishDir,
+		Port:             "4200",
+		IsStatic:         true,
+		PackageManager:   pm.name,
 	}, nil
 }
 
+// angularOutput returns the output path of the first application in
+// angular.json and the subdirectory holding browser files, which the
+// application builder (Angular 17+) adds and the older browser builder doesn't
+func angularOutput(dir string) (string, string) {
+	data, err := os.ReadFile(filepath.Join(dir, "angular.json"))
+	if err != nil {
+		return "dist", ""
+	}
+
+	var workspace struct {
+		Projects map[string]struct {
+			ProjectType string `json:"projectType"`
+			Architect   struct {
+				Build struct {
+					Builder string `json:"builder"`
+					Options struct {
+						OutputPath json.RawMessage `json:"outputPath"`
+					} `json:"options"`
+				} `json:"build"`
+			} `json:"architect"`
+		} `json:"projects"`
+	}
+	if json.Unmarshal(data, &workspace) != nil {
+		return "dist", ""
+	}
+
+	names := make([]string, 0, len(workspace.Projects))
+	for name := range workspace.Projects {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		project := workspace.Projects[name]
+		if project.ProjectType != "" && project.ProjectType != "application" {
+			continue
+		}
+		build := project.Architect.Build
+
+		browserDir := ""
+		if strings.HasSuffix(build.Builder, ":application") {
+			browserDir = "browser"
+		}
+
+		// outputPath is either "dist/app" or {"base": "dist/app", "browser": ""}
+		var path string
+		if json.Unmarshal(build.Options.OutputPath, &path) == nil && path != "" {
+			return path, browserDir
+		}
+		var obj struct {
+			Base    string  `json:"base"`
+			Browser *string `json:"browser"`
+		}
+		if json.Unmarshal(build.Options.OutputPath, &obj) == nil && obj.Base != "" {
+			if obj.Browser != nil {
+				browserDir = *obj.Browser
+			}
+			return obj.Base, browserDir
+		}
+		return "dist/" + name, browserDir
+	}
+	return "dist", ""
+}
+
+// nodePackageManager describes how to drive a JavaScript package manager
+type nodePackageManager struct {
+	name    string
+	install string
+	runCmd  string
+}
+
+func (pm nodePackageManager) run(script string) string {
+	return pm.runCmd + " " + script
+}
+
+func nodeCommands(name string) nodePackageManager {
+	switch name {
+	case PackageManagerPNPM:
+		return nodePackageManager{name: name, install: "pnpm install", runCmd: "pnpm run"}
+	case PackageManagerYarn:
+		return nodePackageManager{name: name, install: "yarn install", runCmd: "yarn run"}
+	case PackageManagerBun:
+		return nodePackageManager{name: name, install: "bun install", runCmd: "bun run"}
+	default:
+		return nodePackageManager{name: PackageManagerNPM, install: "npm install", runCmd: "npm run"}
+	}
+}
+
+// DetectPackageManager returns the package manager a JavaScript project in
+// dir uses, defaulting to npm
+func DetectPackageManager(dir string) string {
+	var pkg struct {
+		PackageManager string `json:"packageManager"`
+	}
+	if data, err := os.ReadFile(filepath.Join(dir, "package.json")); err == nil {
+		_ = json.Unmarshal(data, &pkg)
+	}
+	return detectPackageManager(dir, pkg.PackageManager)
+}
+
+// detectPackageManager prefers the packageManager field of package.json
+// (e.g. "pnpm@9.1.0") and falls back to whichever lockfile is present
+func detectPackageManager(dir, packageManager string) string {
+	if name, _, _ := strings.Cut(packageManager, "@"); name != "" {
+		switch name {
+		case PackageManagerPNPM, PackageManagerYarn, PackageManagerBun, PackageManagerNPM:
+			return name
+		}
+	}
+
+	switch {
+	case fileExists(filepath.Join(dir, "pnpm-lock.yaml")):
+		return PackageManagerPNPM
+	case fileExists(filepath.Join(dir, "yarn.lock")):
+		return PackageManagerYarn
+	case fileExists(filepath.Join(dir, "bun.lockb")) || fileExists(filepath.Join(dir, "bun.lock")):
+		return PackageManagerBun
+	}
+	return PackageManagerNPM
+}
+
+func detectDeno(dir string) (*FrameworkInfo, error) {
+	var cfg struct {
+		Tasks map[string]string `json:"tasks"`
+	}
+	for _, name := range []string{"deno.json", "deno.jsonc"} {
+		if data, err := os.ReadFile(filepath.Join(dir, name)); err == nil {
+			// deno.jsonc may hold comments; tasks are simply left undetected then
+			_ = json.Unmarshal(data, &cfg)
+			break
+		}
+	}
+
+	info := &FrameworkInfo{
+		Name:           "Deno",
+		BuildPack:      BuildPackNixpacks,
+		InstallCommand: "deno install",
+		Port:           "8000",
+		IsStatic:       false,
+	}
+	if _, ok := cfg.Tasks["build"]; ok {
+		info.BuildCommand = "deno task build"
+	}
+	if _, ok := cfg.Tasks["start"]; ok {
+		info.StartCommand = "deno task start"
+	} else {
+		for _, entry := range []string{"main.ts", "server.ts", "main.js", "mod.ts"} {
+			if fileExists(filepath.Join(dir, entry)) {
+				info.StartCommand = "deno run --allow-net --allow-env --allow-read " + entry
+				break
+			}
+		}
+	}
+	return info, nil
+}
+
 func detectHugo(dir string) (*FrameworkInfo, error) {
 	return &FrameworkInfo{
 		Name:             "Hugo",
@@ -207,15 +512,323 @@ func detectPython(dir string) (*FrameworkInfo, error) {
 		installCmd = "pip install ."
 	}
 
-	return &FrameworkInfo{
+	// Dependency names as declared in requirements.txt and pyproject.toml
+	var deps string
+	for _, name := range []string{"requirements.txt", "pyproject.toml"} {
+		if data, err := os.ReadFile(filepath.Join(dir, name)); err == nil {
+			deps += strings.ToLower(string(data)) + "\n"
+		}
+	}
+	hasGunicorn := hasPyDep(deps, "gunicorn")
+
+	info := &FrameworkInfo{
 		Name:           "Python",
 		BuildPack:      BuildPackNixpacks,
 		InstallCommand: installCmd,
 		Port:           "8000",
 		IsStatic:       false,
+	}
+
+	switch {
+	case fileExists(filepath.Join(dir, "manage.py")) || hasPyDep(deps, "django"):
+		info.Name = "Django"
+		info.BuildCommand = "python manage.py collectstatic --noinput"
+		info.StartCommand = "python manage.py runserver 0.0.0.0:8000"
+		if project := djangoProject(dir); project != "" && hasGunicorn {
+			info.StartCommand = fmt.Sprintf("gunicorn %s.wsgi:application --bind 0.0.0.0:8000", project)
+		}
+
+	case hasPyDep(deps, "fastapi"):
+		info.Name = "FastAPI"
+		if module, app := findPyApp(dir, "FastAPI", "main.py", "app.py", "app/main.py", "asgi.py"); module != "" {
+			info.StartCommand = fmt.Sprintf("uvicorn %s:%s --host 0.0.0.0 --port 8000", module, app)
+		}
+
+	case hasPyDep(deps, "flask"):
+		info.Name = "Flask"
+		info.Port = "5000"
+		if module, app := findPyApp(dir, "Flask", "app.py", "wsgi.py", "main.py", "app/__init__.py"); module != "" {
+			if hasGunicorn {
+				info.StartCommand = fmt.Sprintf("gunicorn %s:%s --bind 0.0.0.0:5000", module, app)
+			} else {
+				info.StartCommand = fmt.Sprintf("flask --app %s:%s run --host 0.0.0.0 --port 5000", module, app)
+			}
+		}
+
+	case fileExists(filepath.Join(dir, "asgi.py")) && hasPyDep(deps, "uvicorn"):
+		info.StartCommand = "uvicorn asgi:application --host 0.0.0.0 --port 8000"
+
+	case fileExists(filepath.Join(dir, "wsgi.py")) && hasGunicorn:
+		info.StartCommand = "gunicorn wsgi:application --bind 0.0.0.0:8000"
+	}
+
+	return info, nil
+}
+
+// hasPyDep reports whether a lowercased requirements.txt or pyproject.toml
+// declares the named package
+func hasPyDep(deps, name string) bool {
+	re := regexp.MustCompile(`(^|[^a-z0-9_.-])` + regexp.QuoteMeta(name) + `([^a-z0-9_.-]|$)`)
+	for _, line := range strings.Split(deps, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		if re.MatchString(line) {
+			return true
+		}
+	}
+	return false
+}
+
+// djangoProject returns the package holding settings.py and wsgi.py
+func djangoProject(dir string) string {
+	matches, _ := filepath.Glob(filepath.Join(dir, "*", "wsgi.py"))
+	for _, m := range matches {
+		pkg := filepath.Dir(m)
+		if fileExists(filepath.Join(pkg, "settings.py")) {
+			return filepath.Base(pkg)
+		}
+	}
+	return ""
+}
+
+// findPyApp locates the module that instantiates the given framework class
+// and returns its import path and the application variable name
+func findPyApp(dir, class string, candidates ...string) (module, app string) {
+	re := regexp.MustCompile(`(?m)^(\w+)\s*=\s*` + class + `\(`)
+	for _, c := range candidates {
+		data, err := os.ReadFile(filepath.Join(dir, c))
+		if err != nil {
+			continue
+		}
+		module = strings.TrimSuffix(c, ".py")
+		module = strings.TrimSuffix(module, "/__init__")
+		module = strings.ReplaceAll(module, "/", ".")
+		if m := re.FindSubmatch(data); m != nil {
+			return module, string(m[1])
+		}
+		if strings.Contains(string(data), class+"(") {
+			return module, "app"
+		}
+	}
+	return "", ""
+}
+
+func detectJVM(dir string) (*FrameworkInfo, error) {
+	var buildFile, buildCmd, jar string
+	switch {
+	case fileExists(filepath.Join(dir, "pom.xml")):
+		buildFile = "pom.xml"
+		buildCmd = "mvn -DskipTests package"
+		if fileExists(filepath.Join(dir, "mvnw")) {
+			buildCmd = "./mvnw -DskipTests package"
+		}
+		jar = "$(ls target/*.jar | head -n 1)"
+	default:
+		buildFile = "build.gradle"
+		if fileExists(filepath.Join(dir, "build.gradle.kts")) {
+			buildFile = "build.gradle.kts"
+		}
+		buildCmd = "gradle build -x test"
+		if fileExists(filepath.Join(dir, "gradlew")) {
+			buildCmd = "./gradlew build -x test"
+		}
+		// Skip the "-plain" jar Gradle builds alongside the runnable one
+		jar = "$(ls build/libs/*.jar | grep -v plain | head -n 1)"
+	}
+
+	data, err := os.ReadFile(filepath.Join(dir, buildFile))
+	if err != nil {
+		return nil, err
+	}
+	content := string(data)
+
+	name := "Java"
+	if strings.HasSuffix(buildFile, ".kts") || strings.Contains(content, "kotlin") {
+		name = "Kotlin"
+	}
+
+	startCmd := "java -jar " + jar
+	if strings.Contains(content, "spring-boot") || strings.Contains(content, "org.springframework.boot") {
+		name = "Spring Boot"
+		startCmd = "java -Dserver.port=8080 -jar " + jar
+	}
+
+	return &FrameworkInfo{
+		Name:         name,
+		BuildPack:    BuildPackNixpacks,
+		BuildCommand: buildCmd,
+		StartCommand: startCmd,
+		Port:         "8080",
+		IsStatic:     false,
+	}, nil
+}
+
+func detectElixir(dir string) (*FrameworkInfo, error) {
+	data, err := os.ReadFile(filepath.Join(dir, "mix.exs"))
+	if err != nil {
+		return nil, err
+	}
+	mix := string(data)
+
+	if !strings.Contains(mix, ":phoenix") {
+		return &FrameworkInfo{
+			Name:           "Elixir",
+			BuildPack:      BuildPackNixpacks,
+			InstallCommand: "mix deps.get",
+			BuildCommand:   "mix compile",
+			StartCommand:   "mix run --no-halt",
+			IsStatic:       false,
+		}, nil
+	}
+
+	buildCmd := "MIX_ENV=prod mix compile"
+	if strings.Contains(mix, "assets.deploy") {
+		buildCmd += " && MIX_ENV=prod mix assets.deploy"
+	}
+
+	// Run the release when the app name is known, otherwise the server task
+	startCmd := "MIX_ENV=prod mix phx.server"
+	if m := regexp.MustCompile(`app:\s*:(\w+)`).FindStringSubmatch(mix); m != nil {
+		buildCmd += " && MIX_ENV=prod mix release"
+		startCmd = fmt.Sprintf("PHX_SERVER=true _build/prod/rel/%s/bin/%s start", m[1], m[1])
+	}
+
+	return &FrameworkInfo{
+		Name:           "Phoenix",
+		BuildPack:      BuildPackNixpacks,
+		InstallCommand: "mix deps.get --only prod",
+		BuildCommand:   buildCmd,
+		StartCommand:   startCmd,
+		Port:           "4000",
+		IsStatic:       false,
+		EnvHints:       []string{"SECRET_KEY_BASE", "PHX_HOST"},
 	}, nil
 }
 
+func detectRuby(dir string) (*FrameworkInfo, error) {
+	gemfile, err := os.ReadFile(filepath.Join(dir, "Gemfile"))
+	if err != nil {
+		return nil, err
+	}
+
+	if fileExists(filepath.Join(dir, "config", "application.rb")) || hasGem(string(gemfile), "rails") {
+		return &FrameworkInfo{
+			Name:           "Ruby on Rails",
+			BuildPack:      BuildPackNixpacks,
+			InstallCommand: "bundle install",
+			BuildCommand:   "bundle exec rails assets:precompile",
+			StartCommand:   "bundle exec rails server -b 0.0.0.0",
+			Port:           "3000",
+			IsStatic:       false,
+			EnvHints:       []string{"RAILS_MASTER_KEY"},
+		}, nil
+	}
+
+	// Generic Rack application
+	startCmd := ""
+	if fileExists(filepath.Join(dir, "config.ru")) {
+		startCmd = "bundle exec rackup -o 0.0.0.0 -p 9292"
+	}
+
+	return &FrameworkInfo{
+		Name:           "Ruby",
+		BuildPack:      BuildPackNixpacks,
+		InstallCommand: "bundle install",
+		StartCommand:   startCmd,
+		Port:           "9292",
+		IsStatic:       false,
+	}, nil
+}
+
+func detectPHP(dir string) (*FrameworkInfo, error) {
+	if fileExists(filepath.Join(dir, "artisan")) {
+		return &FrameworkInfo{
+			Name:           "Laravel",
+			BuildPack:      BuildPackNixpacks,
+			InstallCommand: "composer install --no-dev --optimize-autoloader",
+			StartCommand:   "php artisan serve --host=0.0.0.0 --port=8000",
+			Port:           "8000",
+			IsStatic:       false,
+			EnvHints:       []string{"APP_KEY"},
+		}, nil
+	}
+
+	// Generic PHP app served by the built-in server
+	docRoot := "."
+	if dirExists(filepath.Join(dir, "public")) {
+		docRoot = "public"
+	}
+
+	return &FrameworkInfo{
+		Name:           "PHP",
+		BuildPack:      BuildPackNixpacks,
+		InstallCommand: "composer install --no-dev --optimize-autoloader",
+		StartCommand:   "php -S 0.0.0.0:8000 -t " + docRoot,
+		Port:           "8000",
+		IsStatic:       false,
+	}, nil
+}
+
+// hasGem reports whether a Gemfile declares the named gem
+func hasGem(gemfile, name string) bool {
+	for _, line := range strings.Split(gemfile, "\n") {
+		line = strings.TrimSpace(line)
+		if strings.HasPrefix(line, "gem '"+name+"'") || strings.HasPrefix(line, "gem \""+name+"\"") {
+			return true
+		}
+	}
+	return false
+}
+
+func detectRust(dir string) (*FrameworkInfo, error) {
+	data, err := os.ReadFile(filepath.Join(dir, "Cargo.toml"))
+	if err != nil {
+		return nil, err
+	}
+
+	// The binary is named after the package unless a [[bin]] overrides it
+	startCmd := ""
+	if name := cargoBinary(string(data)); name != "" {
+		startCmd = "./target/release/" + name
+	}
+
+	return &FrameworkInfo{
+		Name:         "Rust",
+		BuildPack:    BuildPackNixpacks,
+		BuildCommand: "cargo build --release",
+		StartCommand: startCmd,
+		Port:         "8080",
+		IsStatic:     false,
+	}, nil
+}
+
+// cargoBinary returns the name of the first binary declared in Cargo.toml,
+// falling back to the package name
+func cargoBinary(manifest string) string {
+	nameRe := regexp.MustCompile(`^name\s*=\s*"([^"]+)"`)
+	var section, pkgName string
+	for _, line := range strings.Split(manifest, "\n") {
+		line = strings.TrimSpace(line)
+		if strings.HasPrefix(line, "[") {
+			section = line
+			continue
+		}
+		m := nameRe.FindStringSubmatch(line)
+		if m == nil {
+			continue
+		}
+		switch section {
+		case "[[bin]]":
+			return m[1]
+		case "[package]":
+			pkgName = m[1]
+		}
+	}
+	return pkgName
+}
+
 func detectStatic(dir string) (*FrameworkInfo, error) {
 	return &FrameworkInfo{
 		Name:             "Static Site",
@@ -0,0 +1,125 @@
+package detect
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Workspace is a deployable package inside a monorepo
+type Workspace struct {
+	Name string // package name, or the directory name when unnamed
+	Dir  string // path relative to the repository root, e.g. "apps/web"
+}
+
+// defaultWorkspacePatterns are used by tools that don't list their packages
+var defaultWorkspacePatterns = []string{"apps/*", "packages/*"}
+
+// DetectMonorepo reports the monorepo tool used in dir (Turborepo, Nx,
+// pnpm or npm/yarn workspaces) and the packages it contains. It returns an
+// empty tool name when dir is not a monorepo.
+func DetectMonorepo(dir string) (string, []Workspace) {
+	patterns := workspacePatterns(dir)
+
+	tool := ""
+	switch {
+	case fileExists(filepath.Join(dir, "turbo.json")):
+		tool = "Turborepo"
+	case fileExists(filepath.Join(dir, "nx.json")):
+		tool = "Nx"
+	case fileExists(filepath.Join(dir, "pnpm-workspace.yaml")):
+		tool = "pnpm workspaces"
+	case len(patterns) > 0:
+		tool = "workspaces"
+	default:
+		return "", nil
+	}
+	if len(patterns) == 0 {
+		patterns = defaultWorkspacePatterns
+	}
+
+	seen := map[string]bool{}
+	var workspaces []Workspace
+	for _, pattern := range patterns {
+		if strings.HasPrefix(pattern, "!") {
+			continue
+		}
+		// Only one level of nesting is expanded; "apps/**" is treated as "apps/*"
+		pattern = strings.ReplaceAll(pattern, "**", "*")
+		matches, _ := filepath.Glob(filepath.Join(dir, filepath.FromSlash(pattern)))
+		for _, m := range matches {
+			rel, err := filepath.Rel(dir, m)
+			if err != nil || seen[rel] || !dirExists(m) {
+				continue
+			}
+			if !fileExists(filepath.Join(m, "package.json")) && !fileExists(filepath.Join(m, "project.json")) {
+				continue
+			}
+			seen[rel] = true
+			workspaces = append(workspaces, Workspace{
+				Name: workspaceName(m),
+				Dir:  filepath.ToSlash(rel),
+			})
+		}
+	}
+
+	sort.Slice(workspaces, func(i, j int) bool {
+		return workspaces[i].Dir < workspaces[j].Dir
+	})
+	return tool, workspaces
+}
+
+// workspacePatterns reads package globs from pnpm-workspace.yaml or the
+// "workspaces" field of package.json
+func workspacePatterns(dir string) []string {
+	if data, err := os.ReadFile(filepath.Join(dir, "pnpm-workspace.yaml")); err == nil {
+		var ws struct {
+			Packages []string `yaml:"packages"`
+		}
+		if yaml.Unmarshal(data, &ws) == nil && len(ws.Packages) > 0 {
+			return ws.Packages
+		}
+	}
+
+	data, err := os.ReadFile(filepath.Join(dir, "package.json"))
+	if err != nil {
+		return nil
+	}
+	var pkg struct {
+		Workspaces json.RawMessage `json:"workspaces"`
+	}
+	if json.Unmarshal(data, &pkg) != nil || len(pkg.Workspaces) == 0 {
+		return nil
+	}
+
+	// Either ["apps/*"] or {"packages": ["apps/*"]} (yarn classic)
+	var list []string
+	if json.Unmarshal(pkg.Workspaces, &list) == nil {
+		return list
+	}
+	var obj struct {
+		Packages []string `json:"packages"`
+	}
+	if json.Unmarshal(pkg.Workspaces, &obj) == nil {
+		return obj.Packages
+	}
+	return nil
+}
+
+func workspaceName(dir string) string {
+	var pkg struct {
+		Name string `json:"name"`
+	}
+	for _, manifest := range []string{"package.json", "project.json"} {
+		if data, err := os.ReadFile(filepath.Join(dir, manifest)); err == nil {
+			if json.Unmarshal(data, &pkg) == nil && pkg.Name != "" {
+				return pkg.Name
+			}
+		}
+	}
+	return filepath.Base(dir)
+}
@@ -0,0 +1,62 @@
+package detect
+
+import (
+	"path/filepath"
+)
+
+// Rule is a user-defined framework preset, declared under "frameworks" in
+// cdp.json or in ~/.config/cdp/frameworks.yaml. It matches when every
+// pattern in Files matches at least one path in the project directory.
+type Rule struct {
+	Name           string   `json:"name" yaml:"name"`
+	Files          []string `json:"files" yaml:"files"` // glob patterns, e.g. "acme.toml" or "src/*.acme"
+	BuildPack      string   `json:"build_pack,omitempty" yaml:"build_pack,omitempty"`
+	InstallCommand string   `json:"install_command,omitempty" yaml:"install_command,omitempty"`
+	BuildCommand   string   `json:"build_command,omitempty" yaml:"build_command,omitempty"`
+	StartCommand   string   `json:"start_command,omitempty" yaml:"start_command,omitempty"`
+	PublishDir     string   `json:"publish_dir,omitempty" yaml:"publish_dir,omitempty"`
+	Port           string   `json:"port,omitempty" yaml:"port,omitempty"`
+	Static         bool     `json:"static,omitempty" yaml:"static,omitempty"`
+}
+
+// DetectWithRules tries rules in order before falling back to the built-in
+// detection of Detect
+func DetectWithRules(dir string, rules []Rule) (*FrameworkInfo, error) {
+	for _, rule := range rules {
+		if rule.matches(dir) {
+			return rule.frameworkInfo(), nil
+		}
+	}
+	return Detect(dir)
+}
+
+func (r Rule) matches(dir string) bool {
+	// A rule without files would match everything
+	if len(r.Files) == 0 {
+		return false
+	}
+	for _, pattern := range r.Files {
+		matches, err := filepath.Glob(filepath.Join(dir, filepath.FromSlash(pattern)))
+		if err != nil || len(matches) == 0 {
+			return false
+		}
+	}
+	return true
+}
+
+func (r Rule) frameworkInfo() *FrameworkInfo {
+	buildPack := r.BuildPack
+	if buildPack == "" {
+		buildPack = BuildPackNixpacks
+	}
+	return &FrameworkInfo{
+		Name:             r.Name,
+		BuildPack:        buildPack,
+		InstallCommand:   r.InstallCommand,
+		BuildCommand:     r.BuildCommand,
+		StartCommand:     r.StartCommand,
+		PublishDirectory: r.PublishDir,
+		Port:             r.Port,
+		IsStatic:         r.Static,
+	}
+}
@@ -10,6 +10,8 @@ type FrameworkInfo struct {
 	PublishDirectory string
 	Port             string
 	IsStatic         bool
+	EnvHints         []string // Environment variables the app needs to run
+	PackageManager   string   // npm, pnpm, yarn or bun for JavaScript projects
 }
 
 // Common build packs
@@ -19,3 +21,11 @@ const (
 	BuildPackDockerfile    = "dockerfile"
 	BuildPackDockerCompose = "dockercompose"
 )
+
+// JavaScript package managers
+const (
+	PackageManagerNPM  = "npm"
+	PackageManagerPNPM = "pnpm"
+	PackageManagerYarn = "yarn"
+	PackageManagerBun  = "bun"
+)
@@ -0,0 +1,245 @@
+package discover
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// ports are probed in order; the first one that answers like Coolify wins.
+// 8000 is where the Coolify installer exposes the dashboard.
+var ports = []int{8000, 80}
+
+const (
+	// scanWorkers bounds the number of concurrent connection attempts
+	scanWorkers = 128
+
+	// dialTimeout is how long a closed or filtered port may take to fail
+	dialTimeout = 400 * time.Millisecond
+
+	// probeTimeout bounds each HTTP request to an open port
+	probeTimeout = 2 * time.Second
+
+	// mdnsWait is how long to collect mDNS responses
+	mdnsWait = 1500 * time.Millisecond
+)
+
+// virtualInterfaces are name prefixes of container and VM bridges, which
+// never hold the homelab box itself
+var virtualInterfaces = []string{"docker", "br-", "veth", "virbr", "cni", "flannel", "tailscale", "utun"}
+
+// Instance is a Coolify dashboard found on the local network
+type Instance struct {
+	URL  string // e.g. http://192.168.1.20:8000
+	Host string // the host's name, if it has one
+}
+
+// Label describes the instance for a picker
+func (i Instance) Label() string {
+	if i.Host == "" {
+		return i.URL
+	}
+	return fmt.Sprintf("%s (%s)", i.URL, i.Host)
+}
+
+// Find looks for Coolify on the hosts that answer mDNS and on every address
+// of the local IPv4 subnets, up to a /24 each. It returns the instances
+// sorted by URL; finding none is not an error.
+func Find(ctx context.Context) ([]Instance, error) {
+	hosts, err := subnetHosts()
+	if err != nil {
+		return nil, err
+	}
+	names := map[string]string{}
+	for ip, name := range mdnsHosts(ctx, mdnsWait) {
+		if _, ok := names[ip]; !ok && !contains(hosts, ip) {
+			hosts = append(hosts, ip)
+		}
+		names[ip] = name
+	}
+	if len(hosts) == 0 {
+		return nil, fmt.Errorf("no local IPv4 network found")
+	}
+
+	queue := make(chan string)
+	var mu sync.Mutex
+	var found []Instance
+	var wg sync.WaitGroup
+	for i := 0; i < scanWorkers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for host := range queue {
+				url := probeHost(ctx, host)
+				if url == "" {
+					continue
+				}
+				name := names[host]
+				if name == "" {
+					name = lookupName(ctx, host)
+				}
+				mu.Lock()
+				found = append(found, Instance{URL: url, Host: name})
+				mu.Unlock()
+			}
+		}()
+	}
+feed:
+	for _, host := range hosts {
+		select {
+		case queue <- host:
+		case <-ctx.Done():
+			break feed
+		}
+	}
+	close(queue)
+	wg.Wait()
+
+	sort.Slice(found, func(i, j int) bool { return found[i].URL < found[j].URL })
+	return found, nil
+}
+
+// subnetHosts returns every host address of the private IPv4 networks this
+// machine is on. Larger networks are narrowed to the /24 around our address
+// so that a /16 doesn't turn into 65k probes.
+func subnetHosts() ([]string, error) {
+	ifaces, err := net.Interfaces()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list network interfaces: %w", err)
+	}
+
+	var hosts []string
+	seen := map[string]bool{}
+	for _, iface := range ifaces {
+		if iface.Flags&net.FlagUp == 0 || iface.Flags&net.FlagLoopback != 0 || isVirtual(iface.Name) {
+			continue
+		}
+		addrs, err := iface.Addrs()
+		if err != nil {
+			continue
+		}
+		for _, addr := range addrs {
+			ipNet, ok := addr.(*net.IPNet)
+			if !ok {
+				continue
+			}
+			ip := ipNet.IP.To4()
+			if ip == nil || !ip.IsPrivate() {
+				continue
+			}
+			ones, _ := ipNet.Mask.Size()
+			if ones < 24 {
+				ones = 24
+			}
+			network := ip.Mask(net.CIDRMask(ones, 32))
+			size := uint32(1) << (32 - ones)
+			base := uint32(network[0])<<24 | uint32(network[1])<<16 | uint32(network[2])<<8 | uint32(network[3])
+			// Skips the network and broadcast addresses
+			for n := uint32(1); n+1 < size; n++ {
+				v := base + n
+				host := net.IPv4(byte(v>>24), byte(v>>16), byte(v>>8), byte(v)).String()
+				if !seen[host] {
+					seen[host] = true
+					hosts = append(hosts, host)
+				}
+			}
+		}
+	}
+	return hosts, nil
+}
+
+func isVirtual(name string) bool {
+	for _, prefix := range virtualInterfaces {
+		if strings.HasPrefix(name, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// probeHost returns the dashboard URL when host serves Coolify on one of
+// the probed ports, or "" when it doesn't
+func probeHost(ctx context.Context, host string) string {
+	dialer := net.Dialer{Timeout: dialTimeout}
+	for _, port := range ports {
+		addr := net.JoinHostPort(host, fmt.Sprint(port))
+		conn, err := dialer.DialContext(ctx, "tcp", addr)
+		if err != nil {
+			continue
+		}
+		conn.Close()
+
+		url := "http://" + addr
+		if port == 80 {
+			url = "http://" + host
+		}
+		if isCoolify(ctx, url) {
+			return url
+		}
+	}
+	return ""
+}
+
+// isCoolify reports whether url answers Coolify's health check and serves
+// its login page. Either alone is too common to go by.
+func isCoolify(ctx context.Context, url string) bool {
+	health, err := fetch(ctx, url+"/api/health")
+	if err != nil || strings.TrimSpace(health) != "OK" {
+		return false
+	}
+	login, err := fetch(ctx, url+"/login")
+	return err == nil && strings.Contains(strings.ToLower(login), "coolify")
+}
+
+var probeClient = &http.Client{
+	Timeout: probeTimeout,
+	// A redirect usually leads to a domain or HTTPS, which the caller
+	// can't tell apart from a different app
+	CheckRedirect: func(*http.Request, []*http.Request) error {
+		return http.ErrUseLastResponse
+	},
+}
+
+func fetch(ctx context.Context, url string) (string, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return "", err
+	}
+	resp, err := probeClient.Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("unexpected status %d", resp.StatusCode)
+	}
+	body, err := io.ReadAll(io.LimitReader(resp.Body, 64*1024))
+	return string(body), err
+}
+
+// lookupName returns the reverse DNS name of ip without its trailing dot,
+// or "" when it has none
+func lookupName(ctx context.Context, ip string) string {
+	ctx, cancel := context.WithTimeout(ctx, time.Second)
+	defer cancel()
+	names, err := net.DefaultResolver.LookupAddr(ctx, ip)
+	if err != nil || len(names) == 0 {
+		return ""
+	}
+	return strings.TrimSuffix(names[0], ".")
+}
+
+func contains(list []string, s string) bool {
+	for _, v := range list {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,167 @@
+package discover
+
+import (
+	"context"
+	"encoding/binary"
+	"errors"
+	"net"
+	"strings"
+	"time"
+)
+
+// mdnsAddr is the IPv4 multicast group mDNS responders listen on
+var mdnsAddr = &net.UDPAddr{IP: net.IPv4(224, 0, 0, 251), Port: 5353}
+
+// mdnsServices are asked for in the query. Avahi announces every Linux
+// host as a workstation; the service list gets an answer from most others.
+var mdnsServices = []string{"_workstation._tcp.local", "_services._dns-sd._udp.local"}
+
+// DNS record types and class used by the query
+const (
+	typeA   = 1
+	typePTR = 12
+	classIN = 1
+)
+
+var errMalformed = errors.New("malformed DNS message")
+
+// mdnsHosts returns the IPv4 hosts that answer an mDNS query within wait,
+// keyed by address, with their .local name when they announce one. It is
+// best-effort: a network without multicast just yields no hosts.
+func mdnsHosts(ctx context.Context, wait time.Duration) map[string]string {
+	hosts := map[string]string{}
+
+	// A one-shot query from an ephemeral port gets unicast replies, so
+	// this doesn't have to bind port 5353 next to the system's responder
+	conn, err := net.ListenUDP("udp4", &net.UDPAddr{})
+	if err != nil {
+		return hosts
+	}
+	defer conn.Close()
+
+	if _, err := conn.WriteToUDP(mdnsQuery(mdnsServices), mdnsAddr); err != nil {
+		return hosts
+	}
+
+	deadline := time.Now().Add(wait)
+	if d, ok := ctx.Deadline(); ok && d.Before(deadline) {
+		deadline = d
+	}
+	_ = conn.SetReadDeadline(deadline)
+
+	buf := make([]byte, 9000)
+	for {
+		n, from, err := conn.ReadFromUDP(buf)
+		if err != nil {
+			return hosts
+		}
+		if ip := from.IP.To4(); ip != nil {
+			if _, ok := hosts[ip.String()]; !ok {
+				hosts[ip.String()] = ""
+			}
+		}
+		records, err := addressRecords(buf[:n])
+		if err != nil {
+			continue
+		}
+		for ip, name := range records {
+			hosts[ip] = name
+		}
+	}
+}
+
+// mdnsQuery builds a DNS query asking for the PTR records of names
+func mdnsQuery(names []string) []byte {
+	msg := make([]byte, 12)
+	binary.BigEndian.PutUint16(msg[4:], uint16(len(names)))
+	for _, name := range names {
+		for _, label := range strings.Split(name, ".") {
+			msg = append(msg, byte(len(label)))
+			msg = append(msg, label...)
+		}
+		msg = append(msg, 0)
+		msg = binary.BigEndian.AppendUint16(msg, typePTR)
+		msg = binary.BigEndian.AppendUint16(msg, classIN)
+	}
+	return msg
+}
+
+// addressRecords returns the A records in a DNS response, mapping each
+// address to its name
+func addressRecords(msg []byte) (map[string]string, error) {
+	if len(msg) < 12 {
+		return nil, errMalformed
+	}
+	questions := int(binary.BigEndian.Uint16(msg[4:]))
+	records := int(binary.BigEndian.Uint16(msg[6:])) +
+		int(binary.BigEndian.Uint16(msg[8:])) +
+		int(binary.BigEndian.Uint16(msg[10:]))
+
+	off := 12
+	for i := 0; i < questions; i++ {
+		_, next, err := readName(msg, off)
+		if err != nil {
+			return nil, err
+		}
+		off = next + 4 // type and class
+	}
+
+	found := map[string]string{}
+	for i := 0; i < records; i++ {
+		name, next, err := readName(msg, off)
+		if err != nil {
+			return nil, err
+		}
+		if next+10 > len(msg) {
+			return nil, errMalformed
+		}
+		rrType := binary.BigEndian.Uint16(msg[next:])
+		length := int(binary.BigEndian.Uint16(msg[next+8:]))
+		data := next + 10
+		if data+length > len(msg) {
+			return nil, errMalformed
+		}
+		if rrType == typeA && length == 4 {
+			found[net.IP(msg[data:data+4]).String()] = name
+		}
+		off = data + length
+	}
+	return found, nil
+}
+
+// readName decodes the possibly compressed name at off, returning it and
+// the offset just past it
+func readName(msg []byte, off int) (string, int, error) {
+	var labels []string
+	end := -1
+	// Bounds the pointers followed, so a pointer loop can't spin forever
+	for jumps := 0; jumps < 32; {
+		if off >= len(msg) {
+			return "", 0, errMalformed
+		}
+		length := int(msg[off])
+		switch {
+		case length == 0:
+			if end < 0 {
+				end = off + 1
+			}
+			return strings.Join(labels, "."), end, nil
+		case length&0xC0 == 0xC0:
+			if off+1 >= len(msg) {
+				return "", 0, errMalformed
+			}
+			if end < 0 {
+				end = off + 2
+			}
+			off = int(binary.BigEndian.Uint16(msg[off:]) & 0x3FFF)
+			jumps++
+		default:
+			if off+1+length > len(msg) {
+				return "", 0, errMalformed
+			}
+			labels = append(labels, string(msg[off+1:off+1+length]))
+			off += 1 + length
+		}
+	}
+	return "", 0, errMalformed
+}
@@ -7,11 +7,13 @@ import (
 	"os"
 	"os/exec"
 	"path/filepath"
+	"sort"
 	"strings"
 	"time"
 
 	"github.com/dropalltables/cdp/internal/config"
 	"github.com/dropalltables/cdp/internal/detect"
+	"github.com/dropalltables/cdp/internal/profile"
 	"github.com/dropalltables/cdp/internal/ui"
 )
 
@@ -23,36 +25,18 @@ type BuildOptions struct {
 	Framework *detect.FrameworkInfo
 	Platform  string // e.g., "linux/amd64" or "linux/arm64"
 	Verbose   bool   // Show full output instead of hiding it
+
+	BuildArgs map[string]string // passed with --build-arg
+	Secrets   map[string]string // BuildKit secrets by id, never stored in layers
 }
 
 // Build builds a Docker image for the project
-func Build(opts *BuildOptions) (err error) {
-	// Generate Dockerfile if one doesn't exist
-	dockerfilePath := filepath.Join(opts.Dir, "Dockerfile")
-	tempDockerfile := false
-
-	if _, statErr := os.Stat(dockerfilePath); os.IsNotExist(statErr) {
-		content := GenerateDockerfile(opts.Framework)
-		tempDockerfilePath := filepath.Join(opts.Dir, "Dockerfile.cdp")
-		if writeErr := os.WriteFile(tempDockerfilePath, []byte(content), 0644); writeErr != nil {
-			return fmt.Errorf("failed to write Dockerfile: %w", writeErr)
-		}
-		dockerfilePath = tempDockerfilePath
-		tempDockerfile = true
-	}
-
-	// Ensure cleanup happens even on panic or early return
-	if tempDockerfile {
-		defer func() {
-			// Recover from panic if any, clean up, then re-panic
-			if r := recover(); r != nil {
-				os.Remove(dockerfilePath)
-				panic(r)
-			}
-			// Normal cleanup
-			os.Remove(dockerfilePath)
-		}()
+func Build(opts *BuildOptions) error {
+	dockerfilePath, cleanup, err := prepareDockerfile(opts)
+	if err != nil {
+		return err
 	}
+	defer cleanup()
 
 	platform := opts.Platform
 	if platform == "" {
@@ -60,18 +44,119 @@ func Build(opts *BuildOptions) (err error) {
 	}
 
 	imageTag := fmt.Sprintf("%s:%s", opts.ImageName, opts.Tag)
-	args := []string{"build", "--progress=plain", "--platform", platform, "-t", imageTag, "-f", dockerfilePath, opts.Dir}
+	args := []string{"build", "--progress=plain", "--platform", platform, "-t", imageTag, "-f", dockerfilePath}
+	args = append(args, buildInputArgs(opts)...)
+	args = append(args, opts.Dir)
+
+	if err := runBuild("docker", args, opts, opts.Verbose); err != nil {
+		return fmt.Errorf("docker build %w", err)
+	}
+	return nil
+}
 
-	cmd := exec.Command("docker", args...)
-	cmd.Dir = opts.Dir
+// prepareDockerfile returns the project's Dockerfile, generating a temporary
+// one if it has none, along with the ignore file for .cdpignore, and a
+// function that removes the temporary files
+func prepareDockerfile(opts *BuildOptions) (string, func(), error) {
+	dockerfilePath := filepath.Join(opts.Dir, "Dockerfile")
+	if _, err := os.Stat(dockerfilePath); !os.IsNotExist(err) {
+		cleanup, err := prepareIgnoreFile(opts.Dir, dockerfilePath)
+		if err != nil {
+			return "", nil, err
+		}
+		return dockerfilePath, cleanup, nil
+	}
+
+	content := withBuildInputs(GenerateDockerfile(opts.Framework), sortedKeys(opts.BuildArgs), sortedKeys(opts.Secrets))
+	tempDockerfilePath := filepath.Join(opts.Dir, "Dockerfile.cdp")
+	if err := os.WriteFile(tempDockerfilePath, []byte(content), 0644); err != nil {
+		return "", nil, fmt.Errorf("failed to write Dockerfile: %w", err)
+	}
+	cleanupIgnore, err := prepareIgnoreFile(opts.Dir, tempDockerfilePath)
+	if err != nil {
+		os.Remove(tempDockerfilePath)
+		return "", nil, err
+	}
+	return tempDockerfilePath, func() {
+		cleanupIgnore()
+		os.Remove(tempDockerfilePath)
+	}, nil
+}
+
+// buildInputArgs returns the --build-arg and --secret flags for opts.
+// Secret values reach docker through its environment, not the command line.
+func buildInputArgs(opts *BuildOptions) []string {
+	var args []string
+	for _, k := range sortedKeys(opts.BuildArgs) {
+		args = append(args, "--build-arg", k+"="+opts.BuildArgs[k])
+	}
+	for _, k := range sortedKeys(opts.Secrets) {
+		args = append(args, "--secret", fmt.Sprintf("id=%s,env=%s", k, k))
+	}
+	return args
+}
+
+// withBuildInputs adapts a generated Dockerfile to the build inputs: build
+// args are declared in every stage, and secrets are mounted as environment
+// variables for every RUN instruction
+func withBuildInputs(content string, buildArgs, secrets []string) string {
+	if len(buildArgs) == 0 && len(secrets) == 0 {
+		return content
+	}
+
+	var mounts string
+	for _, k := range secrets {
+		mounts += fmt.Sprintf("--mount=type=secret,id=%s,env=%s ", k, k)
+	}
+
+	var b strings.Builder
+	if len(secrets) > 0 {
+		// Secret mounts as env vars need a recent Dockerfile frontend
+		b.WriteString("# syntax=docker/dockerfile:1\n")
+	}
+	for _, line := range strings.SplitAfter(content, "\n") {
+		if strings.HasPrefix(line, "RUN ") && mounts != "" {
+			line = "RUN " + mounts + strings.TrimPrefix(line, "RUN ")
+		}
+		b.WriteString(line)
+		if strings.HasPrefix(line, "FROM ") {
+			for _, k := range buildArgs {
+				b.WriteString("ARG " + k + "\n")
+			}
+		}
+	}
+	return b.String()
+}
+
+func sortedKeys(m map[string]string) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// runBuild runs a build tool (docker or nixpacks) with args, streaming its
+// output in verbose mode. It runs from the repository root, so paths in args
+// point into opts.Dir.
+func runBuild(name string, args []string, opts *BuildOptions, verbose bool) error {
+	defer profile.Start(profile.Docker)()
+	cmd := exec.Command(name, args...)
+	if len(opts.Secrets) > 0 {
+		cmd.Env = append(os.Environ(), "DOCKER_BUILDKIT=1")
+		for _, k := range sortedKeys(opts.Secrets) {
+			cmd.Env = append(cmd.Env, k+"="+opts.Secrets[k])
+		}
+	}
 
 	// In verbose mode, stream output with dim styling like deployment logs
-	if opts.Verbose {
+	if verbose {
 		stdout, _ := cmd.StdoutPipe()
 		stderr, _ := cmd.StderrPipe()
 
 		if err := cmd.Start(); err != nil {
-			return fmt.Errorf("docker build failed to start: %w", err)
+			return fmt.Errorf("failed to start: %w", err)
 		}
 
 		done := make(chan bool, 2)
@@ -104,19 +189,19 @@ func Build(opts *BuildOptions) (err error) {
 		<-done
 
 		if err := cmd.Wait(); err != nil {
-			return fmt.Errorf("docker build failed: %w", err)
-		}
-	} else {
-		// In normal mode, capture output (only shown on error via CDP_DEBUG)
-		cmdOut := ui.NewCmdOutput()
-		cmd.Stdout = cmdOut
-		cmd.Stderr = cmdOut
-
-		if err := cmd.Run(); err != nil {
-			return fmt.Errorf("docker build failed: %w", err)
+			return fmt.Errorf("failed: %w", err)
 		}
+		return nil
 	}
 
+	// In normal mode, capture output (only shown on error via CDP_DEBUG)
+	cmdOut := ui.NewCmdOutput()
+	cmd.Stdout = cmdOut
+	cmd.Stderr = cmdOut
+
+	if err := cmd.Run(); err != nil {
+		return fmt.Errorf("failed: %w", err)
+	}
 	return nil
 }
 
@@ -130,6 +215,7 @@ func GenerateTag(env string) string {
 
 // IsDockerAvailable checks if Docker is available
 func IsDockerAvailable() bool {
+	defer profile.Start(profile.Docker)()
 	cmd := exec.Command("docker", "version")
 	return cmd.Run() == nil
 }
@@ -0,0 +1,78 @@
+package docker
+
+import (
+	"fmt"
+	"os/exec"
+	"strings"
+
+	"github.com/dropalltables/cdp/internal/profile"
+)
+
+// buildxBuilder is the builder cdp creates for multi-platform builds; the
+// default docker driver can't produce manifest lists
+const buildxBuilder = "cdp-multiarch"
+
+// IsMultiPlatform reports whether platform lists more than one target, e.g.
+// "linux/amd64,linux/arm64"
+func IsMultiPlatform(platform string) bool {
+	return len(Platforms(platform)) > 1
+}
+
+// Platforms splits a comma-separated platform list
+func Platforms(platform string) []string {
+	var platforms []string
+	for _, p := range strings.Split(platform, ",") {
+		if p = strings.TrimSpace(p); p != "" {
+			platforms = append(platforms, p)
+		}
+	}
+	return platforms
+}
+
+// IsBuildxAvailable checks if the docker buildx plugin is installed
+func IsBuildxAvailable() bool {
+	defer profile.Start(profile.Docker)()
+	cmd := exec.Command("docker", "buildx", "version")
+	return cmd.Run() == nil
+}
+
+// BuildxPush builds the image for every platform in opts.Platform and pushes
+// the resulting manifest list. Multi-platform images can't be loaded into the
+// local image store, so building and pushing happen in one step.
+func BuildxPush(opts *BuildOptions, push *PushOptions) error {
+	if err := ensureBuilder(); err != nil {
+		return err
+	}
+	dockerfilePath, cleanup, err := prepareDockerfile(opts)
+	if err != nil {
+		return err
+	}
+	defer cleanup()
+
+	imageTag := fmt.Sprintf("%s:%s", opts.ImageName, opts.Tag)
+	args := []string{
+		"buildx", "build", "--builder", buildxBuilder, "--progress=plain",
+		"--platform", strings.Join(Platforms(opts.Platform), ","),
+		"-t", imageTag, "-f", dockerfilePath, "--push",
+	}
+	args = append(args, buildInputArgs(opts)...)
+	args = append(args, opts.Dir)
+
+	if err := runBuild("docker", args, opts, opts.Verbose); err != nil {
+		return fmt.Errorf("docker buildx build %w", err)
+	}
+	return nil
+}
+
+// ensureBuilder creates cdp's docker-container builder on first use
+func ensureBuilder() error {
+	defer profile.Start(profile.Docker)()
+	if exec.Command("docker", "buildx", "inspect", buildxBuilder).Run() == nil {
+		return nil
+	}
+	out, err := exec.Command("docker", "buildx", "create", "--name", buildxBuilder, "--driver", "docker-container").CombinedOutput()
+	if err != nil {
+		return fmt.Errorf("failed to create buildx builder: %s", strings.TrimSpace(string(out)))
+	}
+	return nil
+}
@@ -0,0 +1,122 @@
+package docker
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"github.com/dropalltables/cdp/internal/profile"
+)
+
+// dockerHubKey is the key Docker stores Docker Hub credentials under
+const dockerHubKey = "https://index.docker.io/v1/"
+
+// dockerConfig is the part of ~/.docker/config.json that records logins
+type dockerConfig struct {
+	Auths map[string]struct {
+		Auth string `json:"auth"` // base64 "username:password" without a store
+	} `json:"auths"`
+	CredsStore  string            `json:"credsStore"`  // default credential helper
+	CredHelpers map[string]string `json:"credHelpers"` // helpers by registry
+}
+
+// StoredLogin returns the username Docker has credentials for on registry,
+// from its credential store or ~/.docker/config.json. The secret stays with
+// Docker; docker push and build use it directly.
+func StoredLogin(registry string) (string, bool) {
+	cfg, err := loadDockerConfig()
+	if err != nil {
+		return "", false
+	}
+	key := registryKey(registry)
+
+	helper := cfg.CredHelpers[key]
+	if helper == "" {
+		helper = cfg.CredsStore
+	}
+	if helper != "" {
+		return helperUsername(helper, key)
+	}
+
+	for _, k := range []string{key, "https://" + key} {
+		entry, ok := cfg.Auths[k]
+		if !ok || entry.Auth == "" {
+			continue
+		}
+		decoded, err := base64.StdEncoding.DecodeString(entry.Auth)
+		if err != nil {
+			continue
+		}
+		username, _, _ := strings.Cut(string(decoded), ":")
+		return username, true
+	}
+	return "", false
+}
+
+// helperUsername asks a docker-credential-* helper for the registry login
+func helperUsername(helper, key string) (string, bool) {
+	defer profile.Start(profile.Docker)()
+	cmd := exec.Command("docker-credential-"+helper, "get")
+	cmd.Stdin = strings.NewReader(key)
+	out, err := cmd.Output()
+	if err != nil {
+		return "", false
+	}
+	var creds struct {
+		Username string `json:"Username"`
+	}
+	if err := json.Unmarshal(out, &creds); err != nil {
+		return "", false
+	}
+	return creds.Username, true
+}
+
+func loadDockerConfig() (*dockerConfig, error) {
+	dir := os.Getenv("DOCKER_CONFIG")
+	if dir == "" {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return nil, err
+		}
+		dir = filepath.Join(home, ".docker")
+	}
+	data, err := os.ReadFile(filepath.Join(dir, "config.json"))
+	if err != nil {
+		return nil, err
+	}
+	var cfg dockerConfig
+	if err := json.Unmarshal(data, &cfg); err != nil {
+		return nil, err
+	}
+	return &cfg, nil
+}
+
+// registryKey normalizes a registry to the key Docker stores logins under
+func registryKey(registry string) string {
+	registry = strings.TrimSuffix(strings.TrimPrefix(strings.TrimPrefix(registry, "https://"), "http://"), "/")
+	switch registry {
+	case "", "docker.io", "index.docker.io", "registry-1.docker.io", "index.docker.io/v1":
+		return dockerHubKey
+	}
+	return registry
+}
+
+// verifyStoredLogin checks Docker's stored credentials for registry
+// against the registry. With no username or password, docker login reuses
+// the stored ones and fails instead of prompting, as stdin isn't a terminal.
+func verifyStoredLogin(registry string) error {
+	// StoredLogin times its own helper call, so only the login is timed here
+	if _, ok := StoredLogin(registry); !ok {
+		return fmt.Errorf("docker has no login for %s; run 'docker login %s'", registry, registry)
+	}
+	defer profile.Start(profile.Docker)()
+	output, err := exec.Command("docker", "login", registry).CombinedOutput()
+	if err != nil {
+		return fmt.Errorf("%s", strings.TrimSpace(string(output)))
+	}
+	return nil
+}
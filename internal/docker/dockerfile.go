@@ -2,6 +2,8 @@ package docker
 
 import (
 	"fmt"
+	"path"
+	"strings"
 
 	"github.com/dropalltables/cdp/internal/detect"
 )
@@ -17,37 +19,54 @@ func GenerateDockerfile(framework *detect.FrameworkInfo) string {
 		return generateNuxtDockerfile(framework)
 	case "SvelteKit":
 		return generateSvelteKitDockerfile(framework)
-	case "Vite", "Create React App":
+	case "Vite", "Create React App", "Gatsby", "Vue CLI", "Eleventy":
 		return generateStaticDockerfile(framework, framework.PublishDirectory)
+	case "Angular":
+		if framework.IsStatic {
+			return generateStaticDockerfile(framework, framework.PublishDirectory)
+		}
+		return generateNodeServerDockerfile(framework)
+	case "Remix":
+		return generateNodeServerDockerfile(framework)
 	case "Hugo":
 		return generateHugoDockerfile(framework)
 	case "Go":
 		return generateGoDockerfile(framework)
-	case "Python":
+	case "Python", "Django", "FastAPI", "Flask":
 		return generatePythonDockerfile(framework)
-	case "Node.js":
+	case "Rust":
+		return generateRustDockerfile(framework)
+	case "Java", "Kotlin", "Spring Boot":
+		return generateJVMDockerfile(framework)
+	case "Node.js", "Bun":
 		return generateNodeDockerfile(framework)
 	case "Static Site":
 		return generatePureStaticDockerfile(framework)
 	default:
+		// Presets marked static are served as-is, or built first when they
+		// come with a package.json
+		if framework.IsStatic {
+			if framework.PackageManager != "" {
+				return generateStaticDockerfile(framework, framework.PublishDirectory)
+			}
+			return generatePureStaticDockerfile(framework)
+		}
 		return generateGenericDockerfile(framework)
 	}
 }
 
 func generateNextJSDockerfile(f *detect.FrameworkInfo) string {
-	return `FROM node:20-alpine AS base
+	return fmt.Sprintf(`FROM node:20-alpine AS base
 
 FROM base AS deps
 RUN apk add --no-cache libc6-compat
 WORKDIR /app
-COPY package.json package-lock.json* ./
-RUN npm ci
-
+%s
 FROM base AS builder
 WORKDIR /app
-COPY --from=deps /app/node_modules ./node_modules
+%sCOPY --from=deps /app/node_modules ./node_modules
 COPY . .
-RUN npm run build
+RUN %s
 
 FROM base AS runner
 WORKDIR /app
@@ -63,16 +82,14 @@ ENV PORT 3000
 HEALTHCHECK --interval=30s --timeout=3s --start-period=10s --retries=3 \
   CMD wget -qO- http://localhost:3000/ || exit 1
 CMD ["node", "server.js"]
-`
+`, nodeInstall(f.PackageManager, false), nodeSetup(f.PackageManager), nodeRun(f.PackageManager, "build"))
 }
 
 func generateStaticDockerfile(f *detect.FrameworkInfo, outputDir string) string {
 	return fmt.Sprintf(`FROM node:20-alpine AS builder
 WORKDIR /app
-COPY package.json package-lock.json* ./
-RUN npm ci
-COPY . .
-RUN npm run build
+%sCOPY . .
+RUN %s
 
 FROM nginx:alpine
 COPY --from=builder /app/%s /usr/share/nginx/html
@@ -80,16 +97,14 @@ EXPOSE 80
 HEALTHCHECK --interval=30s --timeout=3s --start-period=5s --retries=3 \
   CMD wget -qO- http://localhost:80/ || exit 1
 CMD ["nginx", "-g", "daemon off;"]
-`, outputDir)
+`, nodeInstall(f.PackageManager, false), nodeRun(f.PackageManager, "build"), outputDir)
 }
 
 func generateNuxtDockerfile(f *detect.FrameworkInfo) string {
-	return `FROM node:20-alpine AS builder
+	return fmt.Sprintf(`FROM node:20-alpine AS builder
 WORKDIR /app
-COPY package.json package-lock.json* ./
-RUN npm ci
-COPY . .
-RUN npm run build
+%sCOPY . .
+RUN %s
 
 FROM node:20-alpine
 WORKDIR /app
@@ -98,16 +113,14 @@ EXPOSE 3000
 HEALTHCHECK --interval=30s --timeout=3s --start-period=10s --retries=3 \
   CMD wget -qO- http://localhost:3000/ || exit 1
 CMD ["node", ".output/server/index.mjs"]
-`
+`, nodeInstall(f.PackageManager, false), nodeRun(f.PackageManager, "build"))
 }
 
 func generateSvelteKitDockerfile(f *detect.FrameworkInfo) string {
-	return `FROM node:20-alpine AS builder
+	return fmt.Sprintf(`FROM node:20-alpine AS builder
 WORKDIR /app
-COPY package.json package-lock.json* ./
-RUN npm ci
-COPY . .
-RUN npm run build
+%sCOPY . .
+RUN %s
 
 FROM node:20-alpine
 WORKDIR /app
@@ -118,7 +131,7 @@ EXPOSE 3000
 HEALTHCHECK --interval=30s --timeout=3s --start-period=10s --retries=3 \
   CMD wget -qO- http://localhost:3000/ || exit 1
 CMD ["node", "build"]
-`
+`, nodeInstall(f.PackageManager, false), nodeRun(f.PackageManager, "build"))
 }
 
 func generateHugoDockerfile(f *detect.FrameworkInfo) string {
@@ -137,62 +150,178 @@ CMD ["nginx", "-g", "daemon off;"]
 }
 
 func generateGoDockerfile(f *detect.FrameworkInfo) string {
-	return `FROM golang:1.22-alpine AS builder
+	port := portOr(f, "8080")
+	return fmt.Sprintf(`FROM golang:1-alpine AS builder
 WORKDIR /app
 COPY go.mod go.sum* ./
 RUN go mod download
 COPY . .
-RUN CGO_ENABLED=0 go build -o app .
+RUN CGO_ENABLED=0 go build -trimpath -ldflags="-s -w" -o /out/app .
 
-FROM alpine:latest
+FROM alpine:3
+RUN apk add --no-cache ca-certificates tzdata
 WORKDIR /app
-COPY --from=builder /app/app .
-EXPOSE 8080
+COPY --from=builder /out/app .
+EXPOSE %s
+ENV PORT %s
 HEALTHCHECK --interval=30s --timeout=3s --start-period=10s --retries=3 \
-  CMD wget -qO- http://localhost:8080/ || exit 1
+  CMD wget -qO- http://localhost:%s/ || exit 1
 CMD ["./app"]
-`
+`, port, port, port)
 }
 
+// generatePythonDockerfile installs dependencies into a virtualenv in a
+// builder stage, so compilers needed by native wheels stay out of the image
 func generatePythonDockerfile(f *detect.FrameworkInfo) string {
-	return `FROM python:3.12-slim
+	port := portOr(f, "8000")
+	install := f.InstallCommand
+	if install == "" {
+		install = "pip install -r requirements.txt"
+	}
+	startCmd := f.StartCommand
+	if startCmd == "" {
+		startCmd = fmt.Sprintf("uvicorn main:app --host 0.0.0.0 --port %s", port)
+	}
+	build := ""
+	if f.BuildCommand != "" {
+		build = fmt.Sprintf("RUN %s\n", f.BuildCommand)
+	}
+	return fmt.Sprintf(`FROM python:3.12-slim AS builder
+WORKDIR /app
+ENV PIP_NO_CACHE_DIR=1 PIP_DISABLE_PIP_VERSION_CHECK=1
+RUN python -m venv /opt/venv
+ENV PATH="/opt/venv/bin:$PATH"
+COPY . .
+RUN %s
+
+FROM python:3.12-slim
 WORKDIR /app
-COPY requirements.txt* pyproject.toml* ./
-RUN pip install --no-cache-dir -r requirements.txt 2>/dev/null || pip install --no-cache-dir .
+ENV PATH="/opt/venv/bin:$PATH" PYTHONUNBUFFERED=1
+COPY --from=builder /opt/venv /opt/venv
 COPY . .
-EXPOSE 8000
+%sEXPOSE %s
+ENV PORT %s
 HEALTHCHECK --interval=30s --timeout=3s --start-period=10s --retries=3 \
-  CMD python -c "import urllib.request; urllib.request.urlopen('http://localhost:8000/')" || exit 1
-CMD ["python", "-m", "uvicorn", "main:app", "--host", "0.0.0.0", "--port", "8000"]
-`
+  CMD python -c "import urllib.request; urllib.request.urlopen('http://localhost:%s/')" || exit 1
+CMD %s
+`, install, build, port, port, port, startCmd)
+}
+
+// generateRustDockerfile compiles a release build and ships only the binary
+func generateRustDockerfile(f *detect.FrameworkInfo) string {
+	port := portOr(f, "8080")
+	// Without a known binary name, take the only executable cargo produced
+	binary := "$(find target/release -maxdepth 1 -type f -perm -u+x | head -n 1)"
+	if f.StartCommand != "" {
+		binary = "target/release/" + path.Base(f.StartCommand)
+	}
+	return fmt.Sprintf(`FROM rust:1-slim-bookworm AS builder
+RUN apt-get update && apt-get install -y --no-install-recommends pkg-config libssl-dev && rm -rf /var/lib/apt/lists/*
+WORKDIR /app
+COPY . .
+RUN cargo build --release && cp %s /app/server
+
+FROM debian:bookworm-slim
+RUN apt-get update && apt-get install -y --no-install-recommends ca-certificates libssl3 wget && rm -rf /var/lib/apt/lists/*
+WORKDIR /app
+COPY --from=builder /app/server .
+EXPOSE %s
+ENV PORT %s
+HEALTHCHECK --interval=30s --timeout=3s --start-period=10s --retries=3 \
+  CMD wget -qO- http://localhost:%s/ || exit 1
+CMD ["./server"]
+`, binary, port, port, port)
+}
+
+// generateJVMDockerfile builds a jar with Maven or Gradle, whichever the
+// detected build command uses, and runs it on a JRE
+func generateJVMDockerfile(f *detect.FrameworkInfo) string {
+	port := portOr(f, "8080")
+	builder := "maven:3-eclipse-temurin-21"
+	buildCmd := "mvn -B -DskipTests package"
+	jar := "$(ls target/*.jar | head -n 1)"
+	if strings.Contains(f.BuildCommand, "gradle") {
+		builder = "gradle:8-jdk21"
+		buildCmd = "gradle build -x test --no-daemon"
+		// Skip the "-plain" jar Gradle builds alongside the runnable one
+		jar = "$(ls build/libs/*.jar | grep -v plain | head -n 1)"
+	}
+	javaOpts := ""
+	if f.Name == "Spring Boot" {
+		javaOpts = fmt.Sprintf(`"-Dserver.port=%s", `, port)
+	}
+	return fmt.Sprintf(`FROM %s AS builder
+WORKDIR /app
+COPY . .
+RUN %s && cp %s /app/app.jar
+
+FROM eclipse-temurin:21-jre-alpine
+WORKDIR /app
+COPY --from=builder /app/app.jar .
+EXPOSE %s
+ENV PORT %s
+HEALTHCHECK --interval=30s --timeout=3s --start-period=30s --retries=3 \
+  CMD wget -qO- http://localhost:%s/ || exit 1
+CMD ["java", %s"-jar", "app.jar"]
+`, builder, buildCmd, jar, port, port, port, javaOpts)
 }
 
 func generateNodeDockerfile(f *detect.FrameworkInfo) string {
 	// Use shell form for CMD to allow complex start commands
 	startCmd := f.StartCommand
 	if startCmd == "" {
-		startCmd = "npm start"
+		startCmd = nodeRun(f.PackageManager, "start")
 	}
 	return fmt.Sprintf(`FROM node:20-alpine
 WORKDIR /app
-COPY package.json package-lock.json* ./
-RUN npm ci --production
-COPY . .
+%sCOPY . .
 EXPOSE 3000
 HEALTHCHECK --interval=30s --timeout=3s --start-period=10s --retries=3 \
   CMD wget -qO- http://localhost:3000/ || exit 1
 CMD %s
-`, startCmd)
+`, nodeInstall(f.PackageManager, true), startCmd)
+}
+
+// generateNodeServerDockerfile builds the app, then runs its start command
+// with the build output and dependencies in place
+func generateNodeServerDockerfile(f *detect.FrameworkInfo) string {
+	port := f.Port
+	if port == "" {
+		port = "3000"
+	}
+	startCmd := f.StartCommand
+	if startCmd == "" {
+		startCmd = nodeRun(f.PackageManager, "start")
+	}
+	return fmt.Sprintf(`FROM node:20-alpine AS builder
+WORKDIR /app
+%sCOPY . .
+RUN %s
+
+FROM node:20-alpine
+WORKDIR /app
+ENV NODE_ENV production
+COPY --from=builder /app ./
+EXPOSE %s
+ENV PORT %s
+HEALTHCHECK --interval=30s --timeout=3s --start-period=10s --retries=3 \
+  CMD wget -qO- http://localhost:%s/ || exit 1
+CMD %s
+`, nodeInstall(f.PackageManager, false), nodeRun(f.PackageManager, "build"), port, port, port, startCmd)
 }
 
 func generatePureStaticDockerfile(f *detect.FrameworkInfo) string {
-	return `FROM nginx:alpine
-COPY . /usr/share/nginx/html
+	dir := f.PublishDirectory
+	if dir == "" {
+		dir = "."
+	}
+	return fmt.Sprintf(`FROM nginx:alpine
+COPY %s /usr/share/nginx/html
 EXPOSE 80
 HEALTHCHECK --interval=30s --timeout=3s --start-period=5s --retries=3 \
   CMD wget -qO- http://localhost:80/ || exit 1
 CMD ["nginx", "-g", "daemon off;"]
-`
+`, dir)
 }
 
 func generateGenericDockerfile(f *detect.FrameworkInfo) string {
@@ -206,3 +335,74 @@ HEALTHCHECK --interval=30s --timeout=3s --start-period=10s --retries=3 \
 CMD ["npm", "start"]
 `
 }
+
+// portOr returns the detected port, or def when none was detected
+func portOr(f *detect.FrameworkInfo, def string) string {
+	if f.Port != "" {
+		return f.Port
+	}
+	return def
+}
+
+// nodeSetup returns the Dockerfile line that makes the package manager
+// available in a node image, if npm isn't the one in use
+func nodeSetup(pm string) string {
+	switch pm {
+	case detect.PackageManagerPNPM, detect.PackageManagerYarn:
+		return "RUN corepack enable\n"
+	case detect.PackageManagerBun:
+		return "RUN npm install -g bun\n"
+	}
+	return ""
+}
+
+// yarnInstall returns the install command for the yarn version corepack
+// picks: berry (v2+) dropped --production, and only installs production
+// dependencies through 'workspaces focus'
+func yarnInstall(production bool) string {
+	classic, berry := "yarn install --frozen-lockfile", "yarn install --immutable"
+	if production {
+		classic += " --production"
+		berry = "yarn workspaces focus --production"
+	}
+	return fmt.Sprintf(`case "$(yarn --version)" in 1.*) %s ;; *) %s ;; esac`, classic, berry)
+}
+
+// nodeInstall returns Dockerfile lines that copy the manifest and lockfile
+// and install dependencies with the project's package manager
+func nodeInstall(pm string, production bool) string {
+	var copyLine, install string
+	switch pm {
+	case detect.PackageManagerPNPM:
+		copyLine = "COPY package.json pnpm-lock.yaml* pnpm-workspace.yaml* ./"
+		install = "pnpm install --frozen-lockfile"
+		if production {
+			install += " --prod"
+		}
+	case detect.PackageManagerYarn:
+		copyLine = "COPY package.json yarn.lock* .yarnrc.yml* ./"
+		install = yarnInstall(production)
+	case detect.PackageManagerBun:
+		copyLine = "COPY package.json bun.lock* bun.lockb* ./"
+		install = "bun install --frozen-lockfile"
+		if production {
+			install += " --production"
+		}
+	default:
+		copyLine = "COPY package.json package-lock.json* ./"
+		install = "npm ci"
+		if production {
+			install += " --production"
+		}
+	}
+	return nodeSetup(pm) + copyLine + "\nRUN " + install + "\n"
+}
+
+// nodeRun returns the command that runs a package.json script
+func nodeRun(pm, script string) string {
+	switch pm {
+	case detect.PackageManagerPNPM, detect.PackageManagerYarn, detect.PackageManagerBun:
+		return pm + " run " + script
+	}
+	return "npm run " + script
+}
@@ -0,0 +1,239 @@
+package docker
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/dropalltables/cdp/internal/detect"
+)
+
+// dockerfileInstructions are the instructions a Dockerfile may contain
+var dockerfileInstructions = map[string]bool{
+	"ADD": true, "ARG": true, "CMD": true, "COPY": true, "ENTRYPOINT": true,
+	"ENV": true, "EXPOSE": true, "FROM": true, "HEALTHCHECK": true, "LABEL": true,
+	"ONBUILD": true, "RUN": true, "SHELL": true, "STOPSIGNAL": true, "USER": true,
+	"VOLUME": true, "WORKDIR": true,
+}
+
+// templateFrameworks covers every case of GenerateDockerfile
+var templateFrameworks = []detect.FrameworkInfo{
+	{Name: "Next.js"},
+	{Name: "Astro"},
+	{Name: "Nuxt"},
+	{Name: "SvelteKit"},
+	{Name: "Vite", PublishDirectory: "dist"},
+	{Name: "Create React App", PublishDirectory: "build"},
+	{Name: "Gatsby", PublishDirectory: "public"},
+	{Name: "Vue CLI", PublishDirectory: "dist"},
+	{Name: "Eleventy", PublishDirectory: "_site"},
+	{Name: "Angular", IsStatic: true, PublishDirectory: "dist/app/browser"},
+	{Name: "Angular"},
+	{Name: "Remix"},
+	{Name: "Hugo"},
+	{Name: "Go"},
+	{Name: "Go", Port: "9000"},
+	{Name: "Python"},
+	{Name: "Django", InstallCommand: "pip install -r requirements.txt", BuildCommand: "python manage.py collectstatic --noinput", StartCommand: "gunicorn app.wsgi --bind 0.0.0.0:8000"},
+	{Name: "FastAPI"},
+	{Name: "Flask"},
+	{Name: "Rust"},
+	{Name: "Rust", StartCommand: "./target/release/server"},
+	{Name: "Java"},
+	{Name: "Kotlin", BuildCommand: "./gradlew build"},
+	{Name: "Spring Boot"},
+	{Name: "Node.js"},
+	{Name: "Node.js", StartCommand: "node server.js"},
+	{Name: "Bun"},
+	{Name: "Static Site"},
+	{Name: "Static Site", PublishDirectory: "public"},
+	{Name: "Docusaurus", IsStatic: true, PublishDirectory: "build"},
+	{Name: "Plain HTML preset", IsStatic: true},
+	{Name: "Unknown"},
+}
+
+var templatePackageManagers = []string{
+	"",
+	detect.PackageManagerNPM,
+	detect.PackageManagerPNPM,
+	detect.PackageManagerYarn,
+	detect.PackageManagerBun,
+}
+
+// dockerfileInstruction is one instruction of a parsed Dockerfile
+type dockerfileInstruction struct {
+	Name string
+	Args string
+}
+
+// parseDockerfile splits content into instructions, joining continued
+// lines, and checks that each starts with a known instruction
+func parseDockerfile(content string) ([]dockerfileInstruction, error) {
+	var instructions []dockerfileInstruction
+	var pending string
+	for i, line := range strings.Split(content, "\n") {
+		trimmed := strings.TrimSpace(line)
+		if pending == "" && (trimmed == "" || strings.HasPrefix(trimmed, "#")) {
+			continue
+		}
+		if strings.HasSuffix(trimmed, "\\") {
+			pending += strings.TrimSuffix(trimmed, "\\") + " "
+			continue
+		}
+		full := pending + trimmed
+		pending = ""
+
+		name, args, _ := strings.Cut(full, " ")
+		if !dockerfileInstructions[name] {
+			return nil, fmt.Errorf("line %d: unknown instruction %q", i+1, name)
+		}
+		if strings.TrimSpace(args) == "" {
+			return nil, fmt.Errorf("line %d: %s has no arguments", i+1, name)
+		}
+		instructions = append(instructions, dockerfileInstruction{Name: name, Args: strings.TrimSpace(args)})
+	}
+	if pending != "" {
+		return nil, fmt.Errorf("unterminated line continuation")
+	}
+	return instructions, nil
+}
+
+// checkDockerfile checks the structure of a generated Dockerfile: it starts
+// with a stage, copies only from stages defined before, and ends in a stage
+// that runs something
+func checkDockerfile(content string) error {
+	if strings.Contains(content, "%!") {
+		return fmt.Errorf("formatting error in template")
+	}
+	instructions, err := parseDockerfile(content)
+	if err != nil {
+		return err
+	}
+	if len(instructions) == 0 || instructions[0].Name != "FROM" {
+		return fmt.Errorf("doesn't start with FROM")
+	}
+
+	stages := map[string]bool{}
+	hasCmd := false
+	for _, ins := range instructions {
+		switch ins.Name {
+		case "FROM":
+			fields := strings.Fields(ins.Args)
+			if len(fields) == 3 && strings.EqualFold(fields[1], "AS") {
+				stages[fields[2]] = true
+			} else if len(fields) != 1 {
+				return fmt.Errorf("malformed FROM %q", ins.Args)
+			}
+			hasCmd = false
+		case "COPY":
+			for _, field := range strings.Fields(ins.Args) {
+				if from, ok := strings.CutPrefix(field, "--from="); ok && !stages[from] {
+					return fmt.Errorf("COPY from undefined stage %q", from)
+				}
+			}
+		case "CMD", "ENTRYPOINT":
+			hasCmd = true
+		}
+	}
+	if !hasCmd {
+		return fmt.Errorf("final stage has no CMD or ENTRYPOINT")
+	}
+	return nil
+}
+
+func renderTemplates(t *testing.T, visit func(name, content string)) {
+	t.Helper()
+	for _, framework := range templateFrameworks {
+		for _, pm := range templatePackageManagers {
+			f := framework
+			f.PackageManager = pm
+			name := fmt.Sprintf("%s/%s", f.Name, pm)
+			if pm == "" {
+				name = f.Name + "/default"
+			}
+			visit(name, GenerateDockerfile(&f))
+		}
+	}
+}
+
+func TestGenerateDockerfileParses(t *testing.T) {
+	renderTemplates(t, func(name, content string) {
+		if err := checkDockerfile(content); err != nil {
+			t.Errorf("%s: %v\n%s", name, err, content)
+		}
+	})
+}
+
+func TestGenerateDockerfileWithBuildInputsParses(t *testing.T) {
+	renderTemplates(t, func(name, content string) {
+		content = withBuildInputs(content, []string{"API_URL"}, []string{"NPM_TOKEN"})
+		if err := checkDockerfile(content); err != nil {
+			t.Errorf("%s: %v\n%s", name, err, content)
+		}
+		if !strings.HasPrefix(content, "# syntax=docker/dockerfile:1\n") {
+			t.Errorf("%s: secrets need the dockerfile:1 syntax line", name)
+		}
+	})
+}
+
+func TestNodeInstallUsesPackageManager(t *testing.T) {
+	tests := []struct {
+		pm      string
+		install string
+	}{
+		{detect.PackageManagerNPM, "RUN npm ci"},
+		{detect.PackageManagerPNPM, "RUN pnpm install --frozen-lockfile"},
+		{detect.PackageManagerYarn, "1.*) yarn install --frozen-lockfile ;; *) yarn install --immutable ;;"},
+		{detect.PackageManagerBun, "RUN bun install --frozen-lockfile"},
+	}
+	for _, tt := range tests {
+		f := &detect.FrameworkInfo{Name: "Vite", PublishDirectory: "dist", PackageManager: tt.pm}
+		content := GenerateDockerfile(f)
+		if !strings.Contains(content, tt.install) {
+			t.Errorf("%s: expected %q in\n%s", tt.pm, tt.install, content)
+		}
+		if !strings.Contains(content, "RUN "+nodeRun(tt.pm, "build")) {
+			t.Errorf("%s: expected the build to run with %s", tt.pm, tt.pm)
+		}
+	}
+}
+
+func TestNodeInstallYarnProduction(t *testing.T) {
+	f := &detect.FrameworkInfo{Name: "Node.js", PackageManager: detect.PackageManagerYarn}
+	content := GenerateDockerfile(f)
+	if !strings.Contains(content, "1.*) yarn install --frozen-lockfile --production ;;") {
+		t.Errorf("expected yarn v1 to install with --production in\n%s", content)
+	}
+	if !strings.Contains(content, "*) yarn workspaces focus --production ;;") {
+		t.Errorf("expected yarn berry to install with workspaces focus in\n%s", content)
+	}
+}
+
+// TestGenerateDockerfileDockerCheck has Docker validate every template
+// without building it. It runs only when docker supports build checks.
+func TestGenerateDockerfileDockerCheck(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping docker check in short mode")
+	}
+	if _, err := exec.LookPath("docker"); err != nil {
+		t.Skip("docker not installed")
+	}
+	if out, err := exec.Command("docker", "build", "--help").CombinedOutput(); err != nil || !strings.Contains(string(out), "--check") {
+		t.Skip("docker build doesn't support --check")
+	}
+
+	dir := t.TempDir()
+	renderTemplates(t, func(name, content string) {
+		path := filepath.Join(dir, "Dockerfile")
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+		out, err := exec.Command("docker", "build", "--check", "-f", path, dir).CombinedOutput()
+		if err != nil {
+			t.Errorf("%s: docker build --check failed: %v\n%s", name, err, out)
+		}
+	})
+}
@@ -0,0 +1,66 @@
+package docker
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/dropalltables/cdp/internal/config"
+)
+
+// prepareIgnoreFile applies the project's .cdpignore to the build context.
+// BuildKit prefers <Dockerfile>.dockerignore over the context's .dockerignore,
+// so a temporary one is written holding the .dockerignore patterns followed
+// by the .cdpignore ones. A Dockerfile-specific ignore file the project
+// already has is left alone.
+func prepareIgnoreFile(dir, dockerfilePath string) (func(), error) {
+	patterns, err := config.LoadIgnorePatterns(dir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", config.IgnoreFile, err)
+	}
+	ignorePath := dockerfilePath + ".dockerignore"
+	if len(patterns) == 0 {
+		return func() {}, nil
+	}
+	if _, err := os.Stat(ignorePath); err == nil {
+		return func() {}, nil
+	}
+
+	var b strings.Builder
+	if data, err := os.ReadFile(filepath.Join(dir, ".dockerignore")); err == nil {
+		b.Write(data)
+		if len(data) > 0 && data[len(data)-1] != '\n' {
+			b.WriteString("\n")
+		}
+	}
+	b.WriteString("# " + config.IgnoreFile + "\n")
+	for _, p := range patterns {
+		b.WriteString(dockerIgnorePattern(p) + "\n")
+	}
+
+	if err := os.WriteFile(ignorePath, []byte(b.String()), 0644); err != nil {
+		return nil, fmt.Errorf("failed to write %s: %w", filepath.Base(ignorePath), err)
+	}
+	return func() { os.Remove(ignorePath) }, nil
+}
+
+// dockerIgnorePattern converts a .gitignore pattern to .dockerignore syntax.
+// Git matches a pattern without an inner slash at any depth, while Docker
+// anchors every pattern at the context root.
+func dockerIgnorePattern(pattern string) string {
+	negate := strings.HasPrefix(pattern, "!")
+	pattern = strings.TrimPrefix(pattern, "!")
+	pattern = strings.TrimSuffix(pattern, "/")
+
+	if strings.HasPrefix(pattern, "/") {
+		pattern = strings.TrimPrefix(pattern, "/")
+	} else if !strings.Contains(pattern, "/") {
+		pattern = "**/" + pattern
+	}
+
+	if negate {
+		return "!" + pattern
+	}
+	return pattern
+}
@@ -0,0 +1,52 @@
+package docker
+
+import (
+	"fmt"
+	"os/exec"
+
+	"github.com/dropalltables/cdp/internal/config"
+)
+
+// IsNixpacksAvailable checks if the nixpacks CLI is installed
+func IsNixpacksAvailable() bool {
+	_, err := exec.LookPath("nixpacks")
+	return err == nil
+}
+
+// NixpacksBuild builds the image with the nixpacks CLI, the builder Coolify
+// uses for git deploys, so the image matches what Coolify would produce.
+// The framework's commands override the ones nixpacks detects, as they do
+// on Coolify. Nixpacks has no secret mounts, so opts.Secrets must be empty.
+func NixpacksBuild(opts *BuildOptions) error {
+	if len(opts.Secrets) > 0 {
+		return fmt.Errorf("build secrets are not supported by nixpacks")
+	}
+
+	platform := opts.Platform
+	if platform == "" {
+		platform = config.DefaultPlatform
+	}
+
+	imageTag := fmt.Sprintf("%s:%s", opts.ImageName, opts.Tag)
+	args := []string{"build", opts.Dir, "--name", imageTag, "--platform", platform}
+	if f := opts.Framework; f != nil {
+		if f.InstallCommand != "" {
+			args = append(args, "--install-cmd", f.InstallCommand)
+		}
+		if f.BuildCommand != "" {
+			args = append(args, "--build-cmd", f.BuildCommand)
+		}
+		if f.StartCommand != "" {
+			args = append(args, "--start-cmd", f.StartCommand)
+		}
+	}
+	// Nixpacks exposes --env values to the build, like Docker build args
+	for _, k := range sortedKeys(opts.BuildArgs) {
+		args = append(args, "--env", k+"="+opts.BuildArgs[k])
+	}
+
+	if err := runBuild("nixpacks", args, opts, opts.Verbose); err != nil {
+		return fmt.Errorf("nixpacks build %w", err)
+	}
+	return nil
+}
@@ -6,6 +6,7 @@ import (
 	"os/exec"
 	"strings"
 
+	"github.com/dropalltables/cdp/internal/profile"
 	"github.com/dropalltables/cdp/internal/ui"
 )
 
@@ -15,19 +16,13 @@ type PushOptions struct {
 	Tag       string
 	Registry  string
 	Username  string
-	Password  string
 	Verbose   bool // Show full output instead of hiding it
 }
 
-// Push pushes a Docker image to a registry
+// Push pushes a Docker image to a registry with Docker's stored login
 func Push(opts *PushOptions) error {
-	if opts.Username != "" && opts.Password != "" {
-		if err := login(opts.Registry, opts.Username, opts.Password, opts.Verbose); err != nil {
-			return fmt.Errorf("failed to login to registry: %w", err)
-		}
-	}
-
 	imageTag := fmt.Sprintf("%s:%s", opts.ImageName, opts.Tag)
+	defer profile.Start(profile.Docker)()
 	cmd := exec.Command("docker", "push", imageTag)
 
 	// In verbose mode, stream output with dim styling like deployment logs
@@ -85,60 +80,14 @@ func Push(opts *PushOptions) error {
 	return nil
 }
 
-func login(registry, username, password string, verbose bool) error {
-	cmd := exec.Command("docker", "login", registry, "-u", username, "--password-stdin")
-	cmd.Stdin = strings.NewReader(password)
-
-	// In verbose mode, stream output with dim styling like deployment logs
-	if verbose {
-		stdout, _ := cmd.StdoutPipe()
-		stderr, _ := cmd.StderrPipe()
-
-		if err := cmd.Start(); err != nil {
-			return err
-		}
-
-		done := make(chan bool, 2)
-
-		go func() {
-			scanner := bufio.NewScanner(stdout)
-			for scanner.Scan() {
-				line := strings.TrimSpace(scanner.Text())
-				// Only print non-empty lines
-				if line != "" {
-					fmt.Println(ui.DimStyle.Render("  " + line))
-				}
-			}
-			done <- true
-		}()
-
-		go func() {
-			scanner := bufio.NewScanner(stderr)
-			for scanner.Scan() {
-				line := strings.TrimSpace(scanner.Text())
-				// Only print non-empty lines
-				if line != "" {
-					fmt.Println(ui.DimStyle.Render("  " + line))
-				}
-			}
-			done <- true
-		}()
-
-		<-done
-		<-done
-
-		return cmd.Wait()
-	}
-
-	// In normal mode, capture output (only shown on error via CDP_DEBUG)
-	cmdOut := ui.NewCmdOutput()
-	cmd.Stdout = cmdOut
-	cmd.Stderr = cmdOut
-	return cmd.Run()
-}
-
-// VerifyLogin verifies Docker registry credentials without printing output
+// VerifyLogin verifies Docker registry credentials without printing output.
+// Logging in saves them in Docker's credential store. With no password, the
+// credentials Docker already has for registry are verified instead.
 func VerifyLogin(registry, username, password string) error {
+	if password == "" {
+		return verifyStoredLogin(registry)
+	}
+	defer profile.Start(profile.Docker)()
 	cmd := exec.Command("docker", "login", registry, "-u", username, "--password-stdin")
 	cmd.Stdin = strings.NewReader(password)
 	output, err := cmd.CombinedOutput()
@@ -0,0 +1,188 @@
+package docker
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/dropalltables/cdp/internal/detect"
+	"github.com/dropalltables/cdp/internal/profile"
+)
+
+const (
+	// largeInstallLayer is the dependency layer size that suggests dev
+	// dependencies are being shipped
+	largeInstallLayer = 150 << 20
+	// largeCopyLayer is the source layer size that suggests the build
+	// context holds files the image doesn't need
+	largeCopyLayer = 50 << 20
+)
+
+// Layer is one layer of a built image
+type Layer struct {
+	Size      int64
+	CreatedBy string // Dockerfile instruction that created the layer
+}
+
+// ImageSize describes the size of a locally built image
+type ImageSize struct {
+	Size   int64
+	Layers []Layer // in build order
+}
+
+// InspectImageSize reads the size and layers of a local image
+func InspectImageSize(imageName, tag string) (*ImageSize, error) {
+	defer profile.Start(profile.Docker)()
+	image := fmt.Sprintf("%s:%s", imageName, tag)
+
+	out, err := exec.Command("docker", "image", "inspect", "--format", "{{.Size}}", image).Output()
+	if err != nil {
+		return nil, fmt.Errorf("failed to inspect image: %w", err)
+	}
+	size, err := strconv.ParseInt(strings.TrimSpace(string(out)), 10, 64)
+	if err != nil {
+		return nil, fmt.Errorf("unexpected image size %q", strings.TrimSpace(string(out)))
+	}
+
+	out, err = exec.Command("docker", "history", "--no-trunc", "--human=false", "--format", "{{.Size}}\t{{.CreatedBy}}", image).Output()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read image history: %w", err)
+	}
+	report := &ImageSize{Size: size}
+	for _, line := range strings.Split(strings.TrimSpace(string(out)), "\n") {
+		sizeField, createdBy, _ := strings.Cut(line, "\t")
+		layerSize, err := strconv.ParseInt(sizeField, 10, 64)
+		if err != nil || layerSize == 0 {
+			continue
+		}
+		report.Layers = append(report.Layers, Layer{Size: layerSize, CreatedBy: cleanCreatedBy(createdBy)})
+	}
+	// docker history lists the newest layer first
+	for i, j := 0, len(report.Layers)-1; i < j; i, j = i+1, j-1 {
+		report.Layers[i], report.Layers[j] = report.Layers[j], report.Layers[i]
+	}
+	return report, nil
+}
+
+// cleanCreatedBy strips the shell wrapper and buildkit comment from a
+// history entry, leaving the instruction as written in the Dockerfile
+func cleanCreatedBy(s string) string {
+	s = strings.TrimSpace(strings.TrimSuffix(s, " # buildkit"))
+	switch {
+	case strings.HasPrefix(s, "/bin/sh -c #(nop) "):
+		// Legacy builder, non-RUN instruction
+		return strings.TrimPrefix(s, "/bin/sh -c #(nop) ")
+	case strings.HasPrefix(s, "/bin/sh -c "):
+		return "RUN " + strings.TrimPrefix(s, "/bin/sh -c ")
+	default:
+		return strings.Replace(s, "RUN /bin/sh -c ", "RUN ", 1)
+	}
+}
+
+// LargestLayers returns up to n layers, largest first
+func (s *ImageSize) LargestLayers(n int) []Layer {
+	layers := append([]Layer(nil), s.Layers...)
+	sort.SliceStable(layers, func(i, j int) bool { return layers[i].Size > layers[j].Size })
+	if len(layers) > n {
+		layers = layers[:n]
+	}
+	return layers
+}
+
+var (
+	installPattern  = regexp.MustCompile(`\b(npm (ci|install)|yarn( install)?|pnpm (i|install)|bun (i|install))\b`)
+	prodFlagPattern = regexp.MustCompile(`--(production|prod|omit[= ]dev)\b`)
+)
+
+// SizeHints suggests ways to shrink the image built from dir, based on its
+// Dockerfile, layers and framework
+func SizeHints(s *ImageSize, dir string, framework *detect.FrameworkInfo) []string {
+	var hints []string
+
+	// Generated Dockerfiles are already multi-stage where it helps
+	if content, err := os.ReadFile(filepath.Join(dir, "Dockerfile")); err == nil && countStages(string(content)) == 1 {
+		hints = append(hints, "Use a multi-stage build so compilers and build dependencies stay out of the final image")
+	}
+
+	var installSize, copySize int64
+	prodInstall := false
+	for _, l := range s.Layers {
+		switch {
+		case installPattern.MatchString(l.CreatedBy):
+			installSize += l.Size
+			if prodFlagPattern.MatchString(l.CreatedBy) {
+				prodInstall = true
+			}
+		case strings.HasPrefix(l.CreatedBy, "COPY . ") || strings.HasPrefix(l.CreatedBy, "ADD . "):
+			copySize += l.Size
+		}
+	}
+	if installSize > largeInstallLayer && !prodInstall {
+		hints = append(hints, fmt.Sprintf("Dependencies take %s; prune devDependencies with 'npm ci --omit=dev' (or your package manager's production flag) in the final stage", FormatSize(installSize)))
+	}
+	if copySize > largeCopyLayer {
+		if _, err := os.Stat(filepath.Join(dir, ".dockerignore")); os.IsNotExist(err) {
+			hints = append(hints, fmt.Sprintf("The copied sources take %s; add a .dockerignore for node_modules, build output and caches", FormatSize(copySize)))
+		}
+	}
+
+	if framework != nil && framework.Name == "Next.js" && !nextStandalone(dir) {
+		hints = append(hints, "Enable standalone output with output: 'standalone' in next.config to ship only the files the server needs")
+	}
+	return hints
+}
+
+// countStages counts the FROM instructions of a Dockerfile
+func countStages(content string) int {
+	n := 0
+	for _, line := range strings.Split(content, "\n") {
+		if strings.HasPrefix(strings.ToUpper(strings.TrimSpace(line)), "FROM ") {
+			n++
+		}
+	}
+	return n
+}
+
+// nextStandalone reports whether the Next.js config enables standalone output
+func nextStandalone(dir string) bool {
+	for _, name := range []string{"next.config.js", "next.config.mjs", "next.config.ts", "next.config.cjs"} {
+		content, err := os.ReadFile(filepath.Join(dir, name))
+		if err == nil && strings.Contains(string(content), "standalone") {
+			return true
+		}
+	}
+	return false
+}
+
+var sizePattern = regexp.MustCompile(`^(\d+(?:\.\d+)?)\s*([kmgt]?)i?b?$`)
+
+// ParseSize parses a size such as "500MB", "1.5g" or "800mb" into bytes;
+// units are powers of 1024
+func ParseSize(s string) (int64, error) {
+	m := sizePattern.FindStringSubmatch(strings.ToLower(strings.TrimSpace(s)))
+	if m == nil {
+		return 0, fmt.Errorf("invalid size %q, expected e.g. 500MB or 1.5GB", s)
+	}
+	value, _ := strconv.ParseFloat(m[1], 64)
+	shift := map[string]uint{"": 0, "k": 10, "m": 20, "g": 30, "t": 40}[m[2]]
+	return int64(value * float64(int64(1)<<shift)), nil
+}
+
+// FormatSize formats bytes for display, e.g. "412.3 MB"
+func FormatSize(bytes int64) string {
+	const unit = 1024
+	if bytes < unit {
+		return fmt.Sprintf("%d B", bytes)
+	}
+	div, exp := int64(unit), 0
+	for n := bytes / unit; n >= unit; n /= unit {
+		div *= unit
+		exp++
+	}
+	return fmt.Sprintf("%.1f %cB", float64(bytes)/float64(div), "KMGT"[exp])
+}
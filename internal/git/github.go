@@ -3,12 +3,15 @@ package git
 import (
 	"bytes"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"net/http"
 	"os"
 	"strings"
 	"time"
+
+	"github.com/dropalltables/cdp/internal/profile"
 )
 
 // GitHubClient is a simple GitHub API client
@@ -17,6 +20,23 @@ type GitHubClient struct {
 	httpClient *http.Client
 }
 
+// APIError is an error response from the GitHub API
+type APIError struct {
+	StatusCode int
+	Body       string
+}
+
+func (e *APIError) Error() string {
+	return fmt.Sprintf("GitHub API error (status %d): %s", e.StatusCode, e.Body)
+}
+
+// IsNotFound returns true if err is a 404 from the GitHub API. GitHub also
+// answers 404 for private repositories the token can't see.
+func IsNotFound(err error) bool {
+	var apiErr *APIError
+	return errors.As(err, &apiErr) && apiErr.StatusCode == http.StatusNotFound
+}
+
 // NewGitHubClient creates a new GitHub client
 func NewGitHubClient(token string) *GitHubClient {
 	return &GitHubClient{
@@ -52,6 +72,37 @@ type User struct {
 	ID    int    `json:"id"`
 }
 
+// Release represents a GitHub release
+type Release struct {
+	TagName string `json:"tag_name"`
+	Name    string `json:"name"`
+	HTMLURL string `json:"html_url"`
+}
+
+// PullRequest represents a GitHub pull request
+type PullRequest struct {
+	Number int    `json:"number"`
+	Title  string `json:"title"`
+	State  string `json:"state"`
+}
+
+// Review represents a pull request review
+type Review struct {
+	User  User   `json:"user"`
+	State string `json:"state"` // APPROVED, CHANGES_REQUESTED, COMMENTED, ...
+}
+
+// Comparison is how a head commit relates to a base, from the compare API
+type Comparison struct {
+	Status          string `json:"status"` // ahead, behind, identical or diverged
+	MergeBaseCommit struct {
+		SHA    string `json:"sha"`
+		Commit struct {
+			Message string `json:"message"`
+		} `json:"commit"`
+	} `json:"merge_base_commit"`
+}
+
 // GetUser returns the authenticated user
 func (c *GitHubClient) GetUser() (*User, error) {
 	var user User
@@ -92,7 +143,73 @@ func (c *GitHubClient) DeleteRepo(owner, name string) error {
 	return c.request("DELETE", url, nil, nil)
 }
 
+// GetLatestRelease returns the latest published release of a repository
+func (c *GitHubClient) GetLatestRelease(owner, name string) (*Release, error) {
+	var release Release
+	url := fmt.Sprintf("https://api.github.com/repos/%s/%s/releases/latest", owner, name)
+	err := c.request("GET", url, nil, &release)
+	return &release, err
+}
+
+// ListCommitPulls returns the pull requests that contain a commit
+func (c *GitHubClient) ListCommitPulls(owner, name, sha string) ([]PullRequest, error) {
+	var pulls []PullRequest
+	url := fmt.Sprintf("https://api.github.com/repos/%s/%s/commits/%s/pulls", owner, name, sha)
+	err := c.request("GET", url, nil, &pulls)
+	return pulls, err
+}
+
+// CompareCommits compares head with base, either of which can be a branch
+// or a commit SHA
+func (c *GitHubClient) CompareCommits(owner, name, base, head string) (*Comparison, error) {
+	var comparison Comparison
+	url := fmt.Sprintf("https://api.github.com/repos/%s/%s/compare/%s...%s", owner, name, base, head)
+	err := c.request("GET", url, nil, &comparison)
+	return &comparison, err
+}
+
+// ListReviews returns the reviews submitted on a pull request
+func (c *GitHubClient) ListReviews(owner, name string, number int) ([]Review, error) {
+	var reviews []Review
+	url := fmt.Sprintf("https://api.github.com/repos/%s/%s/pulls/%d/reviews", owner, name, number)
+	err := c.request("GET", url, nil, &reviews)
+	return reviews, err
+}
+
+// TokenScopes returns the OAuth scopes of a classic token. Fine-grained
+// tokens don't report scopes, so ok is false for them.
+func (c *GitHubClient) TokenScopes() (scopes []string, ok bool, err error) {
+	defer profile.Start(profile.GitHub)()
+	req, err := http.NewRequest("GET", "https://api.github.com/user", nil)
+	if err != nil {
+		return nil, false, err
+	}
+	req.Header.Set("Authorization", "Bearer "+c.token)
+	req.Header.Set("Accept", "application/vnd.github+json")
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, false, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode >= 400 {
+		return nil, false, fmt.Errorf("GitHub API error (status %d)", resp.StatusCode)
+	}
+
+	header, ok := resp.Header["X-Oauth-Scopes"]
+	if !ok {
+		return nil, false, nil
+	}
+	for _, scope := range strings.Split(strings.Join(header, ","), ",") {
+		if scope = strings.TrimSpace(scope); scope != "" {
+			scopes = append(scopes, scope)
+		}
+	}
+	return scopes, true, nil
+}
+
 func (c *GitHubClient) request(method, url string, body interface{}, result interface{}) error {
+	defer profile.Start(profile.GitHub)()
 	debug := os.Getenv("CDP_DEBUG") != ""
 	if debug {
 		fmt.Fprintf(os.Stderr, "[DEBUG] GitHub API: %s %s\n", method, url)
@@ -115,7 +232,9 @@ func (c *GitHubClient) request(method, url string, body interface{}, result inte
 		return err
 	}
 
-	req.Header.Set("Authorization", "Bearer "+c.token)
+	if c.token != "" {
+		req.Header.Set("Authorization", "Bearer "+c.token)
+	}
 	req.Header.Set("Accept", "application/vnd.github+json")
 	req.Header.Set("X-GitHub-Api-Version", "2022-11-28")
 	if body != nil {
@@ -148,7 +267,7 @@ func (c *GitHubClient) request(method, url string, body interface{}, result inte
 	}
 
 	if resp.StatusCode >= 400 {
-		return fmt.Errorf("GitHub API error (status %d): %s", resp.StatusCode, string(respBody))
+		return &APIError{StatusCode: resp.StatusCode, Body: string(respBody)}
 	}
 
 	if result != nil && len(respBody) > 0 {
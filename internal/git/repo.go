@@ -2,13 +2,17 @@ package git
 
 import (
 	"bufio"
+	"crypto/sha256"
+	"encoding/hex"
 	"fmt"
+	"io"
 	"os"
 	"os/exec"
 	"path/filepath"
 	"strings"
 
 	"github.com/dropalltables/cdp/internal/config"
+	"github.com/dropalltables/cdp/internal/profile"
 	"github.com/dropalltables/cdp/internal/ui"
 )
 
@@ -24,6 +28,7 @@ func IsRepo(dir string) bool {
 
 // Init initializes a new git repository
 func Init(dir string) error {
+	defer profile.Start(profile.Git)()
 	cmd := exec.Command("git", "init")
 	cmd.Dir = dir
 	return cmd.Run()
@@ -31,6 +36,7 @@ func Init(dir string) error {
 
 // GetRemoteURL returns the remote URL for the given remote name
 func GetRemoteURL(dir, remoteName string) (string, error) {
+	defer profile.Start(profile.Git)()
 	cmd := exec.Command("git", "remote", "get-url", remoteName)
 	cmd.Dir = dir
 	output, err := cmd.Output()
@@ -42,6 +48,7 @@ func GetRemoteURL(dir, remoteName string) (string, error) {
 
 // SetRemote sets or updates a remote URL
 func SetRemote(dir, remoteName, url string) error {
+	defer profile.Start(profile.Git)()
 	// Try to add first, if it fails, update
 	cmd := exec.Command("git", "remote", "add", remoteName, url)
 	cmd.Dir = dir
@@ -55,6 +62,7 @@ func SetRemote(dir, remoteName, url string) error {
 
 // GetCurrentBranch returns the current branch name
 func GetCurrentBranch(dir string) (string, error) {
+	defer profile.Start(profile.Git)()
 	cmd := exec.Command("git", "branch", "--show-current")
 	cmd.Dir = dir
 	output, err := cmd.Output()
@@ -68,8 +76,21 @@ func GetCurrentBranch(dir string) (string, error) {
 	return branch, nil
 }
 
+// GetUserEmail returns the configured git user.email, or "" if unset
+func GetUserEmail(dir string) string {
+	defer profile.Start(profile.Git)()
+	cmd := exec.Command("git", "config", "user.email")
+	cmd.Dir = dir
+	output, err := cmd.Output()
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSpace(string(output))
+}
+
 // HasChanges checks if there are uncommitted changes
 func HasChanges(dir string) bool {
+	defer profile.Start(profile.Git)()
 	cmd := exec.Command("git", "status", "--porcelain")
 	cmd.Dir = dir
 	output, err := cmd.Output()
@@ -81,6 +102,7 @@ func HasChanges(dir string) bool {
 
 // AddAll stages all changes
 func AddAll(dir string) error {
+	defer profile.Start(profile.Git)()
 	cmd := exec.Command("git", "add", "-A")
 	cmd.Dir = dir
 	return cmd.Run()
@@ -93,6 +115,7 @@ func Commit(dir, message string) error {
 
 // CommitVerbose creates a commit with optional output
 func CommitVerbose(dir, message string, verbose bool) error {
+	defer profile.Start(profile.Git)()
 	cmd := exec.Command("git", "commit", "-m", message)
 	cmd.Dir = dir
 	if verbose {
@@ -142,6 +165,7 @@ func CommitVerbose(dir, message string, verbose bool) error {
 
 // Push pushes to the remote
 func Push(dir, remoteName, branch string) error {
+	defer profile.Start(profile.Git)()
 	cmd := exec.Command("git", "push", "-u", remoteName, branch)
 	cmd.Dir = dir
 	// Silence output during deployment
@@ -180,6 +204,7 @@ func PushWithTokenVerbose(dir, remoteName, branch, token string, verbose bool) e
 	defer SetRemote(dir, remoteName, currentURL)
 
 	// Push
+	defer profile.Start(profile.Git)()
 	cmd := exec.Command("git", "push", "-u", remoteName, branch)
 	cmd.Dir = dir
 
@@ -231,6 +256,7 @@ func PushWithTokenVerbose(dir, remoteName, branch, token string, verbose bool) e
 
 // GetLatestCommitHash returns the latest commit hash
 func GetLatestCommitHash(dir string) (string, error) {
+	defer profile.Start(profile.Git)()
 	cmd := exec.Command("git", "rev-parse", "--short", "HEAD")
 	cmd.Dir = dir
 	output, err := cmd.Output()
@@ -240,6 +266,51 @@ func GetLatestCommitHash(dir string) (string, error) {
 	return strings.TrimSpace(string(output)), nil
 }
 
+// SourceHash hashes the state of the sources under path: the HEAD commit,
+// uncommitted changes to tracked files, and the contents of untracked files
+// that aren't gitignored. Paths in exclude are left out.
+func SourceHash(dir, path string, exclude ...string) (string, error) {
+	defer profile.Start(profile.Git)()
+	pathspec := []string{"--", path}
+	for _, e := range exclude {
+		pathspec = append(pathspec, ":(exclude,literal)"+e)
+	}
+
+	h := sha256.New()
+	for _, args := range [][]string{
+		{"rev-parse", "HEAD"},
+		append([]string{"diff", "--binary", "HEAD"}, pathspec...),
+	} {
+		cmd := exec.Command("git", args...)
+		cmd.Dir = dir
+		output, err := cmd.Output()
+		if err != nil {
+			return "", err
+		}
+		h.Write(output)
+	}
+
+	cmd := exec.Command("git", append([]string{"ls-files", "--others", "--exclude-standard", "-z"}, pathspec...)...)
+	cmd.Dir = dir
+	output, err := cmd.Output()
+	if err != nil {
+		return "", err
+	}
+	for _, file := range strings.Split(string(output), "\x00") {
+		if file == "" {
+			continue
+		}
+		data, err := os.ReadFile(filepath.Join(dir, file))
+		if err != nil {
+			return "", err
+		}
+		content := sha256.Sum256(data)
+		io.WriteString(h, file+"\x00")
+		h.Write(content[:])
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
 // AutoCommit stages all changes and creates a commit
 func AutoCommit(dir string) error {
 	return AutoCommitVerbose(dir, false)
@@ -251,14 +322,171 @@ func AutoCommitVerbose(dir string, verbose bool) error {
 		return nil // Nothing to commit
 	}
 
-	if err := AddAll(dir); err != nil {
+	if err := addAllExceptIgnored(dir); err != nil {
 		return fmt.Errorf("failed to stage changes: %w", err)
 	}
+	if !hasStagedChanges(dir) {
+		return nil // Every change is in .cdpignore
+	}
 
 	message := fmt.Sprintf("Deploy via cdp")
 	return CommitVerbose(dir, message, verbose)
 }
 
+// addAllExceptIgnored stages all changes except paths matched by the
+// project's .cdpignore, which applies to tracked files too
+func addAllExceptIgnored(dir string) error {
+	if _, err := os.Stat(filepath.Join(dir, config.IgnoreFile)); err != nil {
+		return AddAll(dir)
+	}
+	ignored, err := cdpIgnored(dir, changedPaths(dir))
+	if err != nil {
+		return err
+	}
+
+	args := []string{"add", "-A", "--", "."}
+	for _, path := range ignored {
+		args = append(args, ":(exclude,literal)"+path)
+	}
+	defer profile.Start(profile.Git)()
+	cmd := exec.Command("git", args...)
+	cmd.Dir = dir
+	return cmd.Run()
+}
+
+// changedPaths lists the paths with changes in the working tree, with
+// untracked directories expanded to their files
+func changedPaths(dir string) []string {
+	defer profile.Start(profile.Git)()
+	cmd := exec.Command("git", "status", "--porcelain", "-z", "--untracked-files=all")
+	cmd.Dir = dir
+	output, err := cmd.Output()
+	if err != nil {
+		return nil
+	}
+
+	var paths []string
+	entries := strings.Split(string(output), "\x00")
+	for i := 0; i < len(entries); i++ {
+		entry := entries[i]
+		if len(entry) < 4 {
+			continue
+		}
+		paths = append(paths, entry[3:])
+		// Renames and copies are followed by their source path
+		if entry[0] == 'R' || entry[0] == 'C' {
+			i++
+			if i < len(entries) {
+				paths = append(paths, entries[i])
+			}
+		}
+	}
+	return paths
+}
+
+// cdpIgnored returns the paths matched by .cdpignore. Git evaluates the
+// patterns itself, with .cdpignore standing in for the user's global
+// excludes file, and reports which file matched each path.
+func cdpIgnored(dir string, paths []string) ([]string, error) {
+	defer profile.Start(profile.Git)()
+	if len(paths) == 0 {
+		return nil, nil
+	}
+	ignoreFile, err := filepath.Abs(filepath.Join(dir, config.IgnoreFile))
+	if err != nil {
+		return nil, err
+	}
+
+	cmd := exec.Command("git", "-c", "core.excludesFile="+ignoreFile, "check-ignore", "--no-index", "-v", "-z", "--stdin")
+	cmd.Dir = dir
+	cmd.Stdin = strings.NewReader(strings.Join(paths, "\x00") + "\x00")
+	output, err := cmd.Output()
+	if err != nil {
+		// Exit status 1 means no path is ignored
+		if exitErr, ok := err.(*exec.ExitError); ok && exitErr.ExitCode() == 1 {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to check %s: %w", config.IgnoreFile, err)
+	}
+
+	// Records are source, line number, pattern and path
+	var ignored []string
+	fields := strings.Split(string(output), "\x00")
+	for i := 0; i+3 < len(fields); i += 4 {
+		source, pattern, path := fields[i], fields[i+2], fields[i+3]
+		if source == ignoreFile && !strings.HasPrefix(pattern, "!") {
+			ignored = append(ignored, path)
+		}
+	}
+	return ignored, nil
+}
+
+// hasStagedChanges reports whether the index differs from HEAD
+func hasStagedChanges(dir string) bool {
+	defer profile.Start(profile.Git)()
+	cmd := exec.Command("git", "diff", "--cached", "--quiet")
+	cmd.Dir = dir
+	return cmd.Run() != nil
+}
+
+// EnsureIgnored adds pattern to the .gitignore in dir unless it is already listed.
+// Returns true if the file was changed.
+func EnsureIgnored(dir, pattern string) (bool, error) {
+	path := filepath.Join(dir, ".gitignore")
+	data, err := os.ReadFile(path)
+	if err != nil && !os.IsNotExist(err) {
+		return false, err
+	}
+
+	for _, line := range strings.Split(string(data), "\n") {
+		line = strings.TrimSpace(line)
+		if line == pattern || line == "/"+pattern {
+			return false, nil
+		}
+	}
+
+	content := string(data)
+	if content != "" && !strings.HasSuffix(content, "\n") {
+		content += "\n"
+	}
+	content += pattern + "\n"
+	return true, os.WriteFile(path, []byte(content), 0644)
+}
+
+// IsTracked reports whether path is tracked by git
+func IsTracked(dir, path string) bool {
+	defer profile.Start(profile.Git)()
+	cmd := exec.Command("git", "ls-files", "--error-unmatch", path)
+	cmd.Dir = dir
+	return cmd.Run() == nil
+}
+
+// Untrack removes path from the index while keeping the file on disk
+func Untrack(dir, path string) error {
+	defer profile.Start(profile.Git)()
+	cmd := exec.Command("git", "rm", "--cached", "--quiet", path)
+	cmd.Dir = dir
+	return cmd.Run()
+}
+
+// RepoSlug normalizes a repository URL or "owner/name" to lowercase "owner/name"
+func RepoSlug(repo string) string {
+	slug := strings.TrimSpace(repo)
+	slug = strings.TrimSuffix(slug, "/")
+	slug = strings.TrimSuffix(slug, ".git")
+	if i := strings.Index(slug, "://"); i >= 0 {
+		slug = slug[i+3:]
+		// Drop host (and any credentials)
+		if j := strings.Index(slug, "/"); j >= 0 {
+			slug = slug[j+1:]
+		}
+	} else if i := strings.Index(slug, ":"); i >= 0 {
+		// scp-like syntax: git@github.com:owner/name
+		slug = slug[i+1:]
+	}
+	return strings.ToLower(slug)
+}
+
 // CommitInfo represents a git commit
 type CommitInfo struct {
 	Hash    string
@@ -267,6 +495,7 @@ type CommitInfo struct {
 
 // GetRecentCommits returns recent commits from the git log
 func GetRecentCommits(dir string, limit int) ([]CommitInfo, error) {
+	defer profile.Start(profile.Git)()
 	// Format: hash<SEP>message
 	cmd := exec.Command("git", "log", fmt.Sprintf("-%d", limit), "--format=%H<SEP>%s")
 	cmd.Dir = dir
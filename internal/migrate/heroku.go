@@ -0,0 +1,179 @@
+package migrate
+
+import (
+	"bufio"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// herokuApp is the subset of app.json cdp understands
+type herokuApp struct {
+	Name      string                     `json:"name"`
+	Env       map[string]json.RawMessage `json:"env"`
+	Formation map[string]struct {
+		Quantity int    `json:"quantity"`
+		Size     string `json:"size"`
+	} `json:"formation"`
+	Scripts    map[string]string `json:"scripts"`
+	Addons     []json.RawMessage `json:"addons"`
+	Buildpacks []struct {
+		URL string `json:"url"`
+	} `json:"buildpacks"`
+}
+
+// herokuEnv is the object form of an app.json env entry
+type herokuEnv struct {
+	Value     string `json:"value"`
+	Generator string `json:"generator"`
+}
+
+func loadHeroku(dir string) (*Plan, error) {
+	plan := &Plan{Source: SourceHeroku}
+
+	if fileExists(filepath.Join(dir, "Procfile")) {
+		plan.Files = append(plan.Files, "Procfile")
+		if err := loadProcfile(filepath.Join(dir, "Procfile"), plan); err != nil {
+			return nil, err
+		}
+	}
+
+	if fileExists(filepath.Join(dir, "app.json")) {
+		plan.Files = append(plan.Files, "app.json")
+		if err := loadAppJSON(filepath.Join(dir, "app.json"), plan); err != nil {
+			return nil, err
+		}
+	}
+
+	return plan, nil
+}
+
+func loadProcfile(path string, plan *Plan) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		process, command, ok := strings.Cut(line, ":")
+		if !ok {
+			continue
+		}
+		process, command = strings.TrimSpace(process), strings.TrimSpace(command)
+
+		switch process {
+		case "web":
+			plan.StartCommand = command
+		case "release":
+			plan.PreDeployCommand = command
+		default:
+			plan.untranslated("%s process (%s): create a separate Coolify app for it", process, command)
+		}
+	}
+	return scanner.Err()
+}
+
+func loadAppJSON(path string, plan *Plan) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	var app herokuApp
+	if err := json.Unmarshal(data, &app); err != nil {
+		return fmt.Errorf("invalid app.json: %w", err)
+	}
+	plan.Name = app.Name
+
+	names := make([]string, 0, len(app.Env))
+	for name := range app.Env {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		raw := app.Env[name]
+
+		// Either "value" or {"value": ..., "generator": "secret", ...}
+		var value string
+		if json.Unmarshal(raw, &value) == nil {
+			plan.setEnv(EnvVar{Name: name, Value: value, Set: true})
+			continue
+		}
+		var env herokuEnv
+		if json.Unmarshal(raw, &env) != nil {
+			continue
+		}
+		switch {
+		case env.Generator == "secret":
+			secret, err := generateSecret()
+			if err != nil {
+				return err
+			}
+			plan.setEnv(EnvVar{Name: name, Value: secret, Set: true})
+		case env.Value != "":
+			plan.setEnv(EnvVar{Name: name, Value: env.Value, Set: true})
+		default:
+			plan.setEnv(EnvVar{Name: name})
+		}
+	}
+
+	for process, formation := range app.Formation {
+		if process != "web" {
+			continue
+		}
+		if formation.Quantity > 1 {
+			plan.Replicas = formation.Quantity
+		}
+		if formation.Size != "" {
+			plan.untranslated("dyno size %s: set CPU and memory limits with 'cdp scale'", formation.Size)
+		}
+	}
+
+	if script := app.Scripts["postdeploy"]; script != "" {
+		plan.untranslated("postdeploy script (%s): run it once after the first deploy", script)
+	}
+
+	for _, raw := range app.Addons {
+		// Either "heroku-postgresql" or {"plan": "heroku-postgresql:mini"}
+		var name string
+		if json.Unmarshal(raw, &name) != nil {
+			var addon struct {
+				Plan string `json:"plan"`
+			}
+			_ = json.Unmarshal(raw, &addon)
+			name = addon.Plan
+		}
+		if name != "" {
+			plan.untranslated("add-on %s: create an equivalent database or service in Coolify", name)
+		}
+	}
+
+	if len(app.Buildpacks) > 0 {
+		urls := make([]string, 0, len(app.Buildpacks))
+		for _, bp := range app.Buildpacks {
+			urls = append(urls, bp.URL)
+		}
+		plan.untranslated("buildpacks %s: Nixpacks detects the build instead; check the detected settings", strings.Join(urls, ", "))
+	}
+
+	return nil
+}
+
+// generateSecret produces a value for env vars Heroku would have generated
+func generateSecret() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
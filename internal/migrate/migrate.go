@@ -0,0 +1,101 @@
+package migrate
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// Platforms cdp can migrate from
+const (
+	SourceVercel  = "vercel"
+	SourceNetlify = "netlify"
+	SourceHeroku  = "heroku"
+)
+
+// Sources lists the supported platforms in the order they are detected
+var Sources = []string{SourceVercel, SourceNetlify, SourceHeroku}
+
+// Plan is what a platform's config translates to in cdp and Coolify
+type Plan struct {
+	Source string   // one of the Source constants
+	Files  []string // config files that were read
+
+	// Project name, when the platform config declares one
+	Name string
+
+	// Build settings for cdp.json
+	InstallCommand string
+	BuildCommand   string
+	StartCommand   string
+	PublishDir     string
+	BaseDirectory  string // e.g. "/apps/web"
+	Replicas       int
+
+	// Coolify settings that only exist once the app is created
+	PreDeployCommand string
+
+	// Environment variables the app expects
+	Env []EnvVar
+
+	// Settings with no cdp or Coolify equivalent, described for the user
+	Untranslated []string
+}
+
+// EnvVar is an environment variable declared by the platform config
+type EnvVar struct {
+	Name  string
+	Value string
+	Set   bool // whether Value is known; secrets usually live in the platform's dashboard
+
+	BuildTime bool // needed by the build, not only at runtime
+}
+
+// Detect returns the platforms whose config files exist in dir
+func Detect(dir string) []string {
+	var found []string
+	if fileExists(filepath.Join(dir, "vercel.json")) {
+		found = append(found, SourceVercel)
+	}
+	if fileExists(filepath.Join(dir, "netlify.toml")) {
+		found = append(found, SourceNetlify)
+	}
+	if fileExists(filepath.Join(dir, "Procfile")) || fileExists(filepath.Join(dir, "app.json")) {
+		found = append(found, SourceHeroku)
+	}
+	return found
+}
+
+// Load reads the config of source in dir and translates it
+func Load(dir, source string) (*Plan, error) {
+	switch source {
+	case SourceVercel:
+		return loadVercel(dir)
+	case SourceNetlify:
+		return loadNetlify(dir)
+	case SourceHeroku:
+		return loadHeroku(dir)
+	}
+	return nil, fmt.Errorf("unknown platform %q", source)
+}
+
+// untranslated records a setting that couldn't be mapped
+func (p *Plan) untranslated(format string, args ...interface{}) {
+	p.Untranslated = append(p.Untranslated, fmt.Sprintf(format, args...))
+}
+
+// setEnv adds or replaces an environment variable, keeping declaration order
+func (p *Plan) setEnv(v EnvVar) {
+	for i := range p.Env {
+		if p.Env[i].Name == v.Name {
+			p.Env[i] = v
+			return
+		}
+	}
+	p.Env = append(p.Env, v)
+}
+
+func fileExists(path string) bool {
+	info, err := os.Stat(path)
+	return err == nil && !info.IsDir()
+}
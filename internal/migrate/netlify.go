@@ -0,0 +1,129 @@
+package migrate
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/BurntSushi/toml"
+)
+
+func loadNetlify(dir string) (*Plan, error) {
+	data, err := os.ReadFile(filepath.Join(dir, "netlify.toml"))
+	if err != nil {
+		return nil, err
+	}
+	doc := map[string]interface{}{}
+	if err := toml.Unmarshal(data, &doc); err != nil {
+		return nil, fmt.Errorf("invalid netlify.toml: %w", err)
+	}
+
+	plan := &Plan{
+		Source: SourceNetlify,
+		Files:  []string{"netlify.toml"},
+	}
+
+	build := tomlTable(doc, "build")
+	plan.BuildCommand = tomlString(build, "command")
+	plan.PublishDir = tomlString(build, "publish")
+	if base := strings.Trim(tomlString(build, "base"), "/"); base != "" {
+		plan.BaseDirectory = "/" + base
+		// Netlify resolves publish relative to base; Coolify does too
+		plan.PublishDir = strings.TrimPrefix(strings.TrimPrefix(plan.PublishDir, base), "/")
+	}
+	addNetlifyEnv(plan, tomlTable(build, "environment"))
+
+	// Production context settings override [build]
+	contexts := tomlTable(doc, "context")
+	if prod := tomlTable(contexts, "production"); prod != nil {
+		if cmd := tomlString(prod, "command"); cmd != "" {
+			plan.BuildCommand = cmd
+		}
+		if publish := tomlString(prod, "publish"); publish != "" {
+			plan.PublishDir = publish
+		}
+		addNetlifyEnv(plan, tomlTable(prod, "environment"))
+	}
+	for _, name := range sortedTableKeys(contexts) {
+		if name != "production" {
+			plan.untranslated("context.%s: only production settings are migrated; previews use the same build on Coolify", name)
+		}
+	}
+
+	for _, r := range tomlTables(doc, "redirects") {
+		status := "301"
+		if s, ok := r["status"]; ok {
+			status = fmt.Sprint(s)
+		}
+		plan.untranslated("redirect %s → %s (%s): configure it in your app or proxy", tomlString(r, "from"), tomlString(r, "to"), status)
+	}
+	if headers := tomlTables(doc, "headers"); len(headers) > 0 {
+		plan.untranslated("%d header rule(s): set response headers in your app", len(headers))
+	}
+	for _, p := range tomlTables(doc, "plugins") {
+		plan.untranslated("build plugin %s: add its steps to the build command", tomlString(p, "package"))
+	}
+	if tomlString(build, "functions") != "" || tomlTable(doc, "functions") != nil {
+		plan.untranslated("Netlify Functions: serve these routes from your app instead")
+	}
+	if tomlString(build, "edge_functions") != "" || len(tomlTables(doc, "edge_functions")) > 0 {
+		plan.untranslated("Edge Functions: serve these routes from your app instead")
+	}
+
+	return plan, nil
+}
+
+// addNetlifyEnv adds the variables of a build environment table, which
+// Netlify only exposes to the build
+func addNetlifyEnv(plan *Plan, env map[string]interface{}) {
+	for _, name := range sortedTableKeys(env) {
+		plan.setEnv(EnvVar{Name: name, Value: fmt.Sprint(env[name]), Set: true, BuildTime: true})
+	}
+}
+
+func tomlTable(table map[string]interface{}, key string) map[string]interface{} {
+	if table == nil {
+		return nil
+	}
+	t, _ := table[key].(map[string]interface{})
+	return t
+}
+
+func tomlTables(table map[string]interface{}, key string) []map[string]interface{} {
+	if table == nil {
+		return nil
+	}
+	switch t := table[key].(type) {
+	case []map[string]interface{}:
+		return t
+	case []interface{}:
+		// An array of inline tables
+		var tables []map[string]interface{}
+		for _, v := range t {
+			if m, ok := v.(map[string]interface{}); ok {
+				tables = append(tables, m)
+			}
+		}
+		return tables
+	}
+	return nil
+}
+
+func tomlString(table map[string]interface{}, key string) string {
+	if table == nil {
+		return ""
+	}
+	s, _ := table[key].(string)
+	return s
+}
+
+func sortedTableKeys(table map[string]interface{}) []string {
+	keys := make([]string, 0, len(table))
+	for k := range table {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
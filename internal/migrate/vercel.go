@@ -0,0 +1,110 @@
+package migrate
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// vercelConfig is the subset of vercel.json cdp understands
+type vercelConfig struct {
+	Name            string            `json:"name"`
+	BuildCommand    *string           `json:"buildCommand"`
+	InstallCommand  *string           `json:"installCommand"`
+	OutputDirectory string            `json:"outputDirectory"`
+	Env             map[string]string `json:"env"`
+	Build           struct {
+		Env map[string]string `json:"env"`
+	} `json:"build"`
+	Regions   []string `json:"regions"`
+	Redirects []struct {
+		Source      string `json:"source"`
+		Destination string `json:"destination"`
+	} `json:"redirects"`
+	Rewrites []struct {
+		Source      string `json:"source"`
+		Destination string `json:"destination"`
+	} `json:"rewrites"`
+	Crons []struct {
+		Path     string `json:"path"`
+		Schedule string `json:"schedule"`
+	} `json:"crons"`
+	Headers       []json.RawMessage          `json:"headers"`
+	Functions     map[string]json.RawMessage `json:"functions"`
+	CleanURLs     bool                       `json:"cleanUrls"`
+	TrailingSlash *bool                      `json:"trailingSlash"`
+}
+
+func loadVercel(dir string) (*Plan, error) {
+	data, err := os.ReadFile(filepath.Join(dir, "vercel.json"))
+	if err != nil {
+		return nil, err
+	}
+	var cfg vercelConfig
+	if err := json.Unmarshal(data, &cfg); err != nil {
+		return nil, fmt.Errorf("invalid vercel.json: %w", err)
+	}
+
+	plan := &Plan{
+		Source:     SourceVercel,
+		Files:      []string{"vercel.json"},
+		Name:       cfg.Name,
+		PublishDir: cfg.OutputDirectory,
+	}
+	if cfg.BuildCommand != nil {
+		plan.BuildCommand = *cfg.BuildCommand
+	}
+	if cfg.InstallCommand != nil {
+		plan.InstallCommand = *cfg.InstallCommand
+	}
+
+	// Build-time variables first, so runtime values win on conflicts; a
+	// variable in both stays available to the build
+	for _, env := range []map[string]string{cfg.Build.Env, cfg.Env} {
+		for _, name := range sortedKeys(env) {
+			_, inBuild := cfg.Build.Env[name]
+			v := EnvVar{Name: name, BuildTime: inBuild}
+			// "@name" refers to a Vercel secret, whose value can't be read
+			if value := env[name]; !strings.HasPrefix(value, "@") {
+				v.Value, v.Set = value, true
+			}
+			plan.setEnv(v)
+		}
+	}
+
+	if len(cfg.Regions) > 0 {
+		plan.untranslated("regions %s: Coolify deploys to the server you choose, so pick one near these regions", strings.Join(cfg.Regions, ", "))
+	}
+	for _, r := range cfg.Redirects {
+		plan.untranslated("redirect %s → %s: configure it in your app or proxy", r.Source, r.Destination)
+	}
+	for _, r := range cfg.Rewrites {
+		plan.untranslated("rewrite %s → %s: configure it in your app or proxy", r.Source, r.Destination)
+	}
+	for _, c := range cfg.Crons {
+		plan.untranslated("cron %s (%s): add a cron job to cdp.json that requests this path", c.Path, c.Schedule)
+	}
+	if len(cfg.Headers) > 0 {
+		plan.untranslated("%d header rule(s): set response headers in your app", len(cfg.Headers))
+	}
+	if len(cfg.Functions) > 0 {
+		plan.untranslated("serverless function settings: functions run inside your app's container on Coolify")
+	}
+	if cfg.CleanURLs || cfg.TrailingSlash != nil {
+		plan.untranslated("cleanUrls/trailingSlash: handle URL normalization in your app")
+	}
+
+	return plan, nil
+}
+
+func sortedKeys(m map[string]string) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
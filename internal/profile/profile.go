@@ -0,0 +1,108 @@
+// Package profile records where a command spends its time, for --profile.
+// Recording is a no-op until Enable is called.
+package profile
+
+import (
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// Kinds of work that are timed
+const (
+	API    = "Coolify API"
+	GitHub = "GitHub API"
+	Git    = "git"
+	Docker = "docker"
+	Prompt = "prompts"
+)
+
+// kinds is the order totals are reported in
+var kinds = []string{API, GitHub, Git, Docker, Prompt}
+
+// Total is the time spent on one kind of work
+type Total struct {
+	Kind     string
+	Calls    int
+	Duration time.Duration
+}
+
+// Step is one named phase of a command, e.g. a task with a spinner
+type Step struct {
+	Name     string
+	Duration time.Duration
+}
+
+var (
+	enabled atomic.Bool
+
+	mu      sync.Mutex
+	started time.Time
+	totals  map[string]*Total
+	steps   []Step
+)
+
+// Enable starts recording
+func Enable() {
+	mu.Lock()
+	defer mu.Unlock()
+	started = time.Now()
+	totals = map[string]*Total{}
+	steps = nil
+	enabled.Store(true)
+}
+
+// Enabled reports whether recording is on
+func Enabled() bool {
+	return enabled.Load()
+}
+
+// Start times one piece of work of the given kind until the returned
+// function is called, typically as defer profile.Start(profile.Git)()
+func Start(kind string) func() {
+	if !enabled.Load() {
+		return func() {}
+	}
+	begin := time.Now()
+	return func() {
+		d := time.Since(begin)
+		mu.Lock()
+		defer mu.Unlock()
+		t := totals[kind]
+		if t == nil {
+			t = &Total{Kind: kind}
+			totals[kind] = t
+		}
+		t.Calls++
+		t.Duration += d
+	}
+}
+
+// StartStep times a named phase of the command until the returned
+// function is called
+func StartStep(name string) func() {
+	if !enabled.Load() {
+		return func() {}
+	}
+	begin := time.Now()
+	return func() {
+		d := time.Since(begin)
+		mu.Lock()
+		defer mu.Unlock()
+		steps = append(steps, Step{Name: name, Duration: d})
+	}
+}
+
+// Report returns the time spent on each kind of work that happened, the
+// steps in the order they finished, and the time since Enable. Work done
+// concurrently is summed, so totals can exceed the elapsed time.
+func Report() (byKind []Total, bySteps []Step, elapsed time.Duration) {
+	mu.Lock()
+	defer mu.Unlock()
+	for _, kind := range kinds {
+		if t := totals[kind]; t != nil {
+			byKind = append(byKind, *t)
+		}
+	}
+	return byKind, append([]Step(nil), steps...), time.Since(started)
+}
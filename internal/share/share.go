@@ -0,0 +1,114 @@
+package share
+
+import (
+	"crypto/rand"
+	"encoding/base32"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/dropalltables/cdp/internal/snapshot"
+)
+
+// prefix marks a share blob and its format version
+const prefix = "cdp1."
+
+// Sizes of the fixed-length fields of an encoded blob
+const (
+	saltSize  = 16
+	nonceSize = 12 // AES-GCM standard nonce
+)
+
+// maxIterations bounds the key derivation work a blob can ask for
+const maxIterations = 10000000
+
+// ErrNotShareLink is returned for strings that aren't share blobs
+var ErrNotShareLink = errors.New("not a cdp share link")
+
+// Link is what a teammate needs to link a directory to the same app. It
+// holds identifiers only, never credentials: the teammate uses their own
+// Coolify token.
+type Link struct {
+	CoolifyURL      string `json:"coolify_url"`
+	Name            string `json:"name"`
+	DeployMethod    string `json:"deploy_method"`
+	ProjectUUID     string `json:"project_uuid,omitempty"`
+	ServerUUID      string `json:"server_uuid,omitempty"`
+	EnvironmentUUID string `json:"environment_uuid,omitempty"`
+	AppUUID         string `json:"app_uuid,omitempty"`
+	ServiceUUID     string `json:"service_uuid,omitempty"`
+	ComposeFile     string `json:"compose_file,omitempty"`
+	GitHubRepo      string `json:"github_repo,omitempty"`
+	Branch          string `json:"branch,omitempty"`
+	BaseDirectory   string `json:"base_directory,omitempty"`
+	DockerImage     string `json:"docker_image,omitempty"`
+}
+
+// NewKey returns a random key for Encode, meant to be sent separately from
+// the blob
+func NewKey() (string, error) {
+	b := make([]byte, 15)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return strings.ToLower(base32.StdEncoding.EncodeToString(b)), nil
+}
+
+// Encode encrypts link under key into a single copyable string
+func Encode(link *Link, key string) (string, error) {
+	plaintext, err := json.Marshal(link)
+	if err != nil {
+		return "", err
+	}
+	sealed, err := snapshot.Encrypt(plaintext, key)
+	if err != nil {
+		return "", err
+	}
+	if len(sealed.Salt) != saltSize || len(sealed.Nonce) != nonceSize {
+		return "", fmt.Errorf("unexpected cipher parameters")
+	}
+
+	buf := make([]byte, 4, 4+saltSize+nonceSize+len(sealed.Ciphertext))
+	binary.BigEndian.PutUint32(buf, uint32(sealed.Iterations))
+	buf = append(buf, sealed.Salt...)
+	buf = append(buf, sealed.Nonce...)
+	buf = append(buf, sealed.Ciphertext...)
+	return prefix + base64.RawURLEncoding.EncodeToString(buf), nil
+}
+
+// Decode decrypts a blob made by Encode. A wrong key returns
+// snapshot.ErrBadPassphrase.
+func Decode(blob, key string) (*Link, error) {
+	blob = strings.TrimSpace(blob)
+	if !strings.HasPrefix(blob, prefix) {
+		return nil, ErrNotShareLink
+	}
+	buf, err := base64.RawURLEncoding.DecodeString(strings.TrimPrefix(blob, prefix))
+	if err != nil || len(buf) <= 4+saltSize+nonceSize {
+		return nil, ErrNotShareLink
+	}
+
+	iterations := binary.BigEndian.Uint32(buf)
+	if iterations == 0 || iterations > maxIterations {
+		return nil, ErrNotShareLink
+	}
+	sealed := &snapshot.Encrypted{
+		KDF:        "pbkdf2-sha256",
+		Iterations: int(iterations),
+		Salt:       buf[4 : 4+saltSize],
+		Nonce:      buf[4+saltSize : 4+saltSize+nonceSize],
+		Ciphertext: buf[4+saltSize+nonceSize:],
+	}
+	plaintext, err := snapshot.Decrypt(sealed, strings.TrimSpace(key))
+	if err != nil {
+		return nil, err
+	}
+	var link Link
+	if err := json.Unmarshal(plaintext, &link); err != nil {
+		return nil, fmt.Errorf("invalid share link: %w", err)
+	}
+	return &link, nil
+}
@@ -0,0 +1,76 @@
+package snapshot
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/pbkdf2"
+	"crypto/rand"
+	"crypto/sha256"
+	"errors"
+)
+
+// kdfIterations is the PBKDF2 work factor for new snapshots
+const kdfIterations = 600000
+
+// Encrypted is data sealed with AES-256-GCM under a passphrase-derived key
+type Encrypted struct {
+	KDF        string `json:"kdf"`
+	Iterations int    `json:"iterations"`
+	Salt       []byte `json:"salt"`
+	Nonce      []byte `json:"nonce"`
+	Ciphertext []byte `json:"ciphertext"`
+}
+
+// ErrBadPassphrase is returned when decryption fails authentication
+var ErrBadPassphrase = errors.New("wrong passphrase or corrupted snapshot")
+
+// Encrypt seals plaintext with a key derived from passphrase
+func Encrypt(plaintext []byte, passphrase string) (*Encrypted, error) {
+	e := &Encrypted{
+		KDF:        "pbkdf2-sha256",
+		Iterations: kdfIterations,
+		Salt:       make([]byte, 16),
+	}
+	if _, err := rand.Read(e.Salt); err != nil {
+		return nil, err
+	}
+
+	gcm, err := newGCM(passphrase, e.Salt, e.Iterations)
+	if err != nil {
+		return nil, err
+	}
+	e.Nonce = make([]byte, gcm.NonceSize())
+	if _, err := rand.Read(e.Nonce); err != nil {
+		return nil, err
+	}
+	e.Ciphertext = gcm.Seal(nil, e.Nonce, plaintext, nil)
+	return e, nil
+}
+
+// Decrypt opens data sealed by Encrypt
+func Decrypt(e *Encrypted, passphrase string) ([]byte, error) {
+	if e.KDF != "pbkdf2-sha256" {
+		return nil, errors.New("unsupported key derivation " + e.KDF)
+	}
+	gcm, err := newGCM(passphrase, e.Salt, e.Iterations)
+	if err != nil {
+		return nil, err
+	}
+	plaintext, err := gcm.Open(nil, e.Nonce, e.Ciphertext, nil)
+	if err != nil {
+		return nil, ErrBadPassphrase
+	}
+	return plaintext, nil
+}
+
+func newGCM(passphrase string, salt []byte, iterations int) (cipher.AEAD, error) {
+	key, err := pbkdf2.Key(sha256.New, passphrase, salt, iterations, 32)
+	if err != nil {
+		return nil, err
+	}
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
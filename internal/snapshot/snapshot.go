@@ -0,0 +1,236 @@
+package snapshot
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"time"
+
+	"github.com/dropalltables/cdp/internal/api"
+	"github.com/dropalltables/cdp/internal/config"
+)
+
+// FormatVersion is bumped when the archive layout changes incompatibly
+const FormatVersion = 1
+
+// Files inside a snapshot archive
+const (
+	manifestFile = "manifest.json"
+	projectFile  = "cdp.json"
+	appFile      = "application.json"
+	envFile      = "env.enc"
+	cronFile     = "scheduled_tasks.json"
+	volumesFile  = "volumes.json"
+)
+
+// AppSettings are the application fields copied from a snapshot onto the
+// recreated app; everything else is instance-specific
+var AppSettings = []string{
+	"description",
+	"install_command",
+	"build_command",
+	"start_command",
+	"ports_exposes",
+	"ports_mappings",
+	"base_directory",
+	"publish_directory",
+	"dockerfile_location",
+	"pre_deployment_command",
+	"post_deployment_command",
+	"health_check_enabled",
+	"health_check_path",
+	"health_check_port",
+	"health_check_interval",
+	"health_check_timeout",
+	"health_check_retries",
+	"limits_cpus",
+	"limits_memory",
+	"custom_labels",
+	"custom_docker_run_options",
+	"watch_paths",
+}
+
+// appIdentity are the fields the recreated app is created with
+var appIdentity = []string{
+	"name",
+	"fqdn",
+	"git_repository",
+	"git_branch",
+	"build_pack",
+	"docker_registry_image_name",
+	"docker_registry_image_tag",
+}
+
+// Manifest describes where and when a snapshot was taken
+type Manifest struct {
+	Version    int    `json:"version"`
+	CreatedAt  string `json:"created_at"`
+	CDPVersion string `json:"cdp_version"`
+	CoolifyURL string `json:"coolify_url"`
+	AppName    string `json:"app_name"`
+}
+
+// Snapshot is everything needed to recreate a deployment on another
+// Coolify instance. Env vars are kept encrypted until restore.
+type Snapshot struct {
+	Manifest       Manifest
+	Project        *config.ProjectConfig
+	Application    map[string]interface{}
+	Env            *Encrypted
+	ScheduledTasks []api.ScheduledTask
+	Volumes        []api.Storage
+}
+
+// New starts a snapshot of the app linked by projectCfg
+func New(projectCfg *config.ProjectConfig, coolifyURL string) *Snapshot {
+	return &Snapshot{
+		Manifest: Manifest{
+			Version:    FormatVersion,
+			CreatedAt:  time.Now().UTC().Format(time.RFC3339),
+			CDPVersion: config.CLIVersion,
+			CoolifyURL: coolifyURL,
+			AppName:    projectCfg.Name,
+		},
+		Project: projectCfg,
+	}
+}
+
+// SetEnv encrypts envVars with passphrase and stores them in the snapshot
+func (s *Snapshot) SetEnv(envVars []api.EnvVar, passphrase string) error {
+	data, err := json.Marshal(envVars)
+	if err != nil {
+		return err
+	}
+	s.Env, err = Encrypt(data, passphrase)
+	return err
+}
+
+// SetApplication stores the app's settings in the snapshot. Only the fields
+// a restore uses are kept, as the rest include secrets such as webhook and
+// basic auth credentials that would otherwise sit in the archive in
+// plaintext.
+func (s *Snapshot) SetApplication(settings map[string]interface{}) {
+	s.Application = map[string]interface{}{}
+	for _, fields := range [][]string{appIdentity, AppSettings} {
+		for _, key := range fields {
+			if v, ok := settings[key]; ok {
+				s.Application[key] = v
+			}
+		}
+	}
+}
+
+// DecryptEnv returns the snapshot's env vars, decrypted with passphrase
+func (s *Snapshot) DecryptEnv(passphrase string) ([]api.EnvVar, error) {
+	if s.Env == nil {
+		return nil, nil
+	}
+	data, err := Decrypt(s.Env, passphrase)
+	if err != nil {
+		return nil, err
+	}
+	var envVars []api.EnvVar
+	if err := json.Unmarshal(data, &envVars); err != nil {
+		return nil, err
+	}
+	return envVars, nil
+}
+
+// Write saves the snapshot as a gzipped tar archive at path
+func (s *Snapshot) Write(path string) error {
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0600)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	gz := gzip.NewWriter(f)
+	tw := tar.NewWriter(gz)
+
+	entries := []struct {
+		name  string
+		value interface{}
+	}{
+		{manifestFile, s.Manifest},
+		{projectFile, s.Project},
+		{appFile, s.Application},
+		{envFile, s.Env},
+		{cronFile, s.ScheduledTasks},
+		{volumesFile, s.Volumes},
+	}
+	for _, e := range entries {
+		data, err := json.MarshalIndent(e.value, "", "  ")
+		if err != nil {
+			return err
+		}
+		hdr := &tar.Header{
+			Name:    e.name,
+			Mode:    0600,
+			Size:    int64(len(data)),
+			ModTime: time.Now(),
+		}
+		if err := tw.WriteHeader(hdr); err != nil {
+			return err
+		}
+		if _, err := tw.Write(data); err != nil {
+			return err
+		}
+	}
+
+	if err := tw.Close(); err != nil {
+		return err
+	}
+	return gz.Close()
+}
+
+// Read loads a snapshot archive written by Write
+func Read(path string) (*Snapshot, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	gz, err := gzip.NewReader(f)
+	if err != nil {
+		return nil, fmt.Errorf("not a snapshot archive: %w", err)
+	}
+	tr := tar.NewReader(gz)
+
+	s := &Snapshot{}
+	targets := map[string]interface{}{
+		manifestFile: &s.Manifest,
+		projectFile:  &s.Project,
+		appFile:      &s.Application,
+		envFile:      &s.Env,
+		cronFile:     &s.ScheduledTasks,
+		volumesFile:  &s.Volumes,
+	}
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("not a snapshot archive: %w", err)
+		}
+		target, ok := targets[hdr.Name]
+		if !ok {
+			continue
+		}
+		if err := json.NewDecoder(tr).Decode(target); err != nil {
+			return nil, fmt.Errorf("invalid %s: %w", hdr.Name, err)
+		}
+	}
+
+	if s.Manifest.Version == 0 || s.Project == nil {
+		return nil, fmt.Errorf("not a snapshot archive: missing %s", manifestFile)
+	}
+	if s.Manifest.Version > FormatVersion {
+		return nil, fmt.Errorf("snapshot format %d is newer than this cdp supports; upgrade cdp", s.Manifest.Version)
+	}
+	return s, nil
+}
@@ -0,0 +1,68 @@
+package ui
+
+import (
+	"errors"
+	"os"
+	"os/exec"
+	"runtime"
+	"strings"
+)
+
+// ErrNoClipboard is returned by CopyToClipboard when no clipboard tool is
+// available, e.g. on a headless Linux server
+var ErrNoClipboard = errors.New("no clipboard tool found")
+
+// OpenBrowser opens url in the default browser without waiting for it
+func OpenBrowser(url string) error {
+	var c *exec.Cmd
+	switch runtime.GOOS {
+	case "darwin":
+		c = exec.Command("open", url)
+	case "windows":
+		c = exec.Command("rundll32", "url.dll,FileProtocolHandler", url)
+	default:
+		c = exec.Command("xdg-open", url)
+	}
+	return c.Start()
+}
+
+// Interactive reports whether stdout is a terminal, so someone is there to
+// use what's put on their clipboard, rather than CI or a pipe
+func Interactive() bool {
+	info, err := os.Stdout.Stat()
+	return err == nil && info.Mode()&os.ModeCharDevice != 0
+}
+
+// CopyToClipboard puts text on the system clipboard using the platform's
+// clipboard tool: pbcopy, clip, or on Linux wl-copy, xclip or xsel
+func CopyToClipboard(text string) error {
+	var candidates [][]string
+	switch runtime.GOOS {
+	case "darwin":
+		candidates = [][]string{{"pbcopy"}}
+	case "windows":
+		candidates = [][]string{{"clip"}}
+	default:
+		candidates = [][]string{
+			{"wl-copy"},
+			{"xclip", "-selection", "clipboard"},
+			{"xsel", "--clipboard", "--input"},
+		}
+	}
+
+	// A tool can be installed without a display to talk to, e.g. wl-copy
+	// under X11, so a failure falls through to the next one
+	lastErr := ErrNoClipboard
+	for _, args := range candidates {
+		path, err := exec.LookPath(args[0])
+		if err != nil {
+			continue
+		}
+		c := exec.Command(path, args[1:]...)
+		c.Stdin = strings.NewReader(text)
+		if lastErr = c.Run(); lastErr == nil {
+			return nil
+		}
+	}
+	return lastErr
+}
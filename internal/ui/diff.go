@@ -0,0 +1,206 @@
+package ui
+
+import (
+	"fmt"
+	"sort"
+)
+
+// DiffContext is the number of unchanged lines shown around each change
+const DiffContext = 3
+
+// DiffOp is the kind of change a diff line represents
+type DiffOp int
+
+const (
+	DiffEqual DiffOp = iota
+	DiffAdd
+	DiffRemove
+)
+
+// DiffLine is a single line of a computed diff
+type DiffLine struct {
+	Op   DiffOp
+	Text string
+}
+
+// Diff renders a colored unified diff between two sets of lines.
+// Returns false if there are no differences.
+func Diff(before, after []string) bool {
+	lines := ComputeDiff(before, after)
+	if !hasChanges(lines) {
+		Dim("No changes")
+		return false
+	}
+
+	for _, hunk := range diffHunks(lines, DiffContext) {
+		fmt.Println(CyanStyle.Render(hunk.header()))
+		for _, l := range hunk.lines {
+			switch l.Op {
+			case DiffAdd:
+				fmt.Println(GreenStyle.Render("+ " + l.Text))
+			case DiffRemove:
+				fmt.Println(RedStyle.Render("- " + l.Text))
+			default:
+				fmt.Println(DimStyle.Render("  " + l.Text))
+			}
+		}
+	}
+	return true
+}
+
+// DiffKeyValues renders a diff of two key/value maps as sorted KEY=value lines.
+// Returns false if there are no differences.
+func DiffKeyValues(before, after map[string]string) bool {
+	return Diff(keyValueLines(before), keyValueLines(after))
+}
+
+// ComputeDiff returns the line-level diff between before and after
+func ComputeDiff(before, after []string) []DiffLine {
+	// Longest common subsequence table
+	n, m := len(before), len(after)
+	lcs := make([][]int, n+1)
+	for i := range lcs {
+		lcs[i] = make([]int, m+1)
+	}
+	for i := n - 1; i >= 0; i-- {
+		for j := m - 1; j >= 0; j-- {
+			if before[i] == after[j] {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else if lcs[i+1][j] >= lcs[i][j+1] {
+				lcs[i][j] = lcs[i+1][j]
+			} else {
+				lcs[i][j] = lcs[i][j+1]
+			}
+		}
+	}
+
+	var lines []DiffLine
+	i, j := 0, 0
+	for i < n && j < m {
+		switch {
+		case before[i] == after[j]:
+			lines = append(lines, DiffLine{Op: DiffEqual, Text: before[i]})
+			i++
+			j++
+		case lcs[i+1][j] >= lcs[i][j+1]:
+			lines = append(lines, DiffLine{Op: DiffRemove, Text: before[i]})
+			i++
+		default:
+			lines = append(lines, DiffLine{Op: DiffAdd, Text: after[j]})
+			j++
+		}
+	}
+	for ; i < n; i++ {
+		lines = append(lines, DiffLine{Op: DiffRemove, Text: before[i]})
+	}
+	for ; j < m; j++ {
+		lines = append(lines, DiffLine{Op: DiffAdd, Text: after[j]})
+	}
+	return lines
+}
+
+type diffHunk struct {
+	oldStart, oldLen int
+	newStart, newLen int
+	lines            []DiffLine
+}
+
+func (h diffHunk) header() string {
+	return fmt.Sprintf("@@ -%d,%d +%d,%d @@", h.oldStart, h.oldLen, h.newStart, h.newLen)
+}
+
+// diffHunks groups changed lines with surrounding context
+func diffHunks(lines []DiffLine, context int) []diffHunk {
+	var hunks []diffHunk
+	oldLine, newLine := 1, 1
+	var current *diffHunk
+	lastChange := -1
+
+	// closeHunk appends trailing context after the last change
+	closeHunk := func() {
+		for k := lastChange + 1; k < len(lines) && k <= lastChange+context; k++ {
+			current.lines = append(current.lines, lines[k])
+		}
+		hunks = append(hunks, countHunk(*current))
+	}
+
+	for idx, l := range lines {
+		if l.Op != DiffEqual {
+			if current == nil || idx-lastChange > 2*context {
+				if current != nil {
+					closeHunk()
+				}
+				// Start a new hunk including leading context
+				start := idx - context
+				if start < 0 {
+					start = 0
+				}
+				h := diffHunk{oldStart: oldLine, newStart: newLine}
+				for k := start; k < idx; k++ {
+					h.lines = append(h.lines, lines[k])
+					h.oldStart--
+					h.newStart--
+				}
+				current = &h
+			} else {
+				for k := lastChange + 1; k < idx; k++ {
+					current.lines = append(current.lines, lines[k])
+				}
+			}
+			current.lines = append(current.lines, l)
+			lastChange = idx
+		}
+
+		switch l.Op {
+		case DiffEqual:
+			oldLine++
+			newLine++
+		case DiffRemove:
+			oldLine++
+		case DiffAdd:
+			newLine++
+		}
+	}
+
+	if current != nil {
+		closeHunk()
+	}
+	return hunks
+}
+
+// countHunk computes the line counts of a hunk
+func countHunk(h diffHunk) diffHunk {
+	h.oldLen, h.newLen = 0, 0
+	for _, l := range h.lines {
+		if l.Op != DiffAdd {
+			h.oldLen++
+		}
+		if l.Op != DiffRemove {
+			h.newLen++
+		}
+	}
+	return h
+}
+
+func hasChanges(lines []DiffLine) bool {
+	for _, l := range lines {
+		if l.Op != DiffEqual {
+			return true
+		}
+	}
+	return false
+}
+
+func keyValueLines(values map[string]string) []string {
+	keys := make([]string, 0, len(values))
+	for k := range values {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	lines := make([]string, 0, len(keys))
+	for _, k := range keys {
+		lines = append(lines, fmt.Sprintf("%s=%s", k, values[k]))
+	}
+	return lines
+}
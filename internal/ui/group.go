@@ -0,0 +1,78 @@
+package ui
+
+import (
+	"fmt"
+	"time"
+)
+
+// IconGroup marks the start of a group in verbose output
+const IconGroup = "=>"
+
+// Group delineates one phase of a multi-phase flow.
+// In verbose mode it renders a header and footer around its output;
+// otherwise it stays out of the way and callers print a single line.
+type Group struct {
+	title   string
+	verbose bool
+	started time.Time
+}
+
+// StartGroup begins a new output group
+func StartGroup(title string, verbose bool) *Group {
+	trace("StartGroup")
+	g := &Group{title: title, verbose: verbose, started: time.Now()}
+	if verbose {
+		fmt.Println(BoldStyle.Render(IconGroup + " " + title))
+	} else {
+		Info(title + "...")
+	}
+	return g
+}
+
+// End closes the group, reporting its duration in verbose mode
+func (g *Group) End(err error) {
+	if !g.verbose {
+		return
+	}
+	elapsed := formatElapsed(time.Since(g.started))
+	if err != nil {
+		fmt.Println(RedStyle.Render(fmt.Sprintf("%s %s failed after %s", IconError, g.title, elapsed)))
+	} else {
+		fmt.Println(DimStyle.Render(fmt.Sprintf("%s %s (%s)", IconSuccess, g.title, elapsed)))
+	}
+	Spacer()
+}
+
+// RunGroup runs tasks as a single named phase.
+// In normal mode the whole phase collapses into one line;
+// in verbose mode each task is shown inside a delimited section.
+func RunGroup(title string, tasks []Task, verbose bool) error {
+	if verbose {
+		g := StartGroup(title, true)
+		err := RunTasksVerbose(tasks, true)
+		g.End(err)
+		return err
+	}
+
+	started := time.Now()
+	for _, task := range tasks {
+		spinner := NewSpinner(fmt.Sprintf("%s %s", title, DimStyle.Render(task.ActiveName)))
+		spinner.Start()
+		err := task.Action()
+		spinner.Stop()
+		if err != nil {
+			Error(fmt.Sprintf("%s: %s", title, task.ActiveName))
+			return err
+		}
+	}
+	Success(fmt.Sprintf("%s %s", title, DimStyle.Render("("+formatElapsed(time.Since(started))+")")))
+	return nil
+}
+
+// formatElapsed renders a duration rounded for display
+func formatElapsed(d time.Duration) string {
+	if d < time.Second {
+		return d.Round(time.Millisecond).String()
+	}
+	return d.Round(100 * time.Millisecond).String()
+}
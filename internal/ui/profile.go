@@ -0,0 +1,51 @@
+package ui
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/AlecAivazis/survey/v2"
+	"github.com/dropalltables/cdp/internal/profile"
+)
+
+// ask is survey.AskOne, timed as a prompt for --profile
+func ask(p survey.Prompt, response interface{}, opts ...survey.AskOpt) error {
+	defer profile.Start(profile.Prompt)()
+	return survey.AskOne(p, response, opts...)
+}
+
+// runTask runs a task's action, timed as a step for --profile
+func runTask(task Task) error {
+	defer profile.StartStep(strings.TrimSuffix(task.ActiveName, "..."))()
+	return task.Action()
+}
+
+// PrintProfile shows where the command spent its time, when --profile
+// enabled recording. It prints to stderr, so it never mixes into output
+// that's piped or parsed.
+func PrintProfile() {
+	if !profile.Enabled() {
+		return
+	}
+	totals, steps, elapsed := profile.Report()
+
+	fmt.Fprintln(os.Stderr)
+	fmt.Fprintln(os.Stderr, BoldStyle.Render(fmt.Sprintf("Profile (%s total)", formatElapsed(elapsed))))
+	if len(totals) > 0 {
+		rows := make([][]string, len(totals))
+		for i, t := range totals {
+			rows[i] = []string{t.Kind, fmt.Sprint(t.Calls), formatElapsed(t.Duration)}
+		}
+		writeTable(os.Stderr, []string{"Waiting on", "Calls", "Time"}, rows)
+	}
+	if len(steps) > 0 {
+		rows := make([][]string, len(steps))
+		for i, s := range steps {
+			rows[i] = []string{s.Name, formatElapsed(s.Duration)}
+		}
+		fmt.Fprintln(os.Stderr)
+		writeTable(os.Stderr, []string{"Step", "Time"}, rows)
+	}
+	fmt.Fprintln(os.Stderr, DimStyle.Render("Concurrent calls are summed, so times can add up to more than the total"))
+}
@@ -0,0 +1,74 @@
+package ui
+
+import (
+	"fmt"
+	"sync"
+)
+
+// ResultItem is the outcome of one item of a bulk operation
+type ResultItem struct {
+	Item   string // what was acted on, e.g. an env var key
+	Action string // what was done to it, e.g. "create"
+	Err    error
+}
+
+// Results collects per-item outcomes of a bulk operation so that failures
+// can be reported together once it finishes. It is safe for concurrent use.
+type Results struct {
+	mu    sync.Mutex
+	items []ResultItem
+}
+
+// Add records the outcome of acting on item; a nil err means it succeeded
+func (r *Results) Add(item, action string, err error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.items = append(r.items, ResultItem{Item: item, Action: action, Err: err})
+}
+
+// Len returns the number of items recorded
+func (r *Results) Len() int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return len(r.items)
+}
+
+// Failed returns the items that failed, in the order they were added
+func (r *Results) Failed() []ResultItem {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var failed []ResultItem
+	for _, item := range r.items {
+		if item.Err != nil {
+			failed = append(failed, item)
+		}
+	}
+	return failed
+}
+
+// Render prints a table of the failed items with their errors, followed by
+// a summary line. It prints nothing when every item succeeded.
+func (r *Results) Render() {
+	failed := r.Failed()
+	if len(failed) == 0 {
+		return
+	}
+	rows := make([][]string, len(failed))
+	for i, item := range failed {
+		rows[i] = []string{item.Item, item.Action, item.Err.Error()}
+	}
+	Spacer()
+	Table([]string{"Item", "Action", "Error"}, rows)
+	Spacer()
+	Warning(fmt.Sprintf("%d of %d failed", len(failed), r.Len()))
+}
+
+// Err returns an error summarising the failures, or nil when every item
+// succeeded, so commands exit non-zero after a partial failure
+func (r *Results) Err() error {
+	failed := len(r.Failed())
+	if failed == 0 {
+		return nil
+	}
+	return fmt.Errorf("%d of %d failed", failed, r.Len())
+}
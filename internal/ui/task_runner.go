@@ -2,6 +2,7 @@ package ui
 
 import (
 	"fmt"
+	"sync"
 	"time"
 )
 
@@ -27,33 +28,61 @@ func RunTasksVerbose(tasks []Task, verbose bool) error {
 	for _, task := range tasks {
 		if verbose {
 			// In verbose mode, skip spinner and run action directly
-			err := task.Action()
+			err := runTask(task)
 			if err != nil {
 				Error(task.ActiveName)
+				flushTaskWarnings()
 				return err
 			}
 			Success(task.CompleteName)
+			flushTaskWarnings()
 		} else {
 			// In normal mode, use spinner
 			spinner := NewSpinner(task.ActiveName)
 			spinner.Start()
 
-			err := task.Action()
+			err := runTask(task)
 
 			if err != nil {
 				spinner.StopWithError(task.ActiveName)
+				flushTaskWarnings()
 				return err
 			}
 
 			spinner.StopWithSuccess(task.CompleteName)
+			flushTaskWarnings()
 		}
 	}
 
 	return nil
 }
 
+var (
+	taskWarningsMu sync.Mutex
+	taskWarnings   []string
+)
+
+// TaskWarning queues a warning from a running task's Action. It is printed
+// once the task's spinner stops, so it doesn't tear the spinner line.
+func TaskWarning(msg string) {
+	taskWarningsMu.Lock()
+	defer taskWarningsMu.Unlock()
+	taskWarnings = append(taskWarnings, msg)
+}
+
+func flushTaskWarnings() {
+	taskWarningsMu.Lock()
+	pending := taskWarnings
+	taskWarnings = nil
+	taskWarningsMu.Unlock()
+	for _, msg := range pending {
+		Warning(msg)
+	}
+}
+
 // Spinner provides a simple streaming spinner
 type Spinner struct {
+	mu      sync.Mutex
 	message string
 	frames  []string
 	done    chan struct{}
@@ -82,7 +111,10 @@ func (s *Spinner) Start() {
 				close(s.stopped)
 				return
 			default:
-				fmt.Printf("\r%s %s", CyanStyle.Render(s.frames[frame%len(s.frames)]), s.message)
+				s.mu.Lock()
+				message := s.message
+				s.mu.Unlock()
+				fmt.Printf("\r\033[K%s %s", CyanStyle.Render(s.frames[frame%len(s.frames)]), message)
 				frame++
 				time.Sleep(80 * time.Millisecond)
 			}
@@ -90,6 +122,13 @@ func (s *Spinner) Start() {
 	}()
 }
 
+// SetMessage replaces the spinner's message while it is running
+func (s *Spinner) SetMessage(message string) {
+	s.mu.Lock()
+	s.message = message
+	s.mu.Unlock()
+}
+
 // Stop stops the spinner and clears the line
 func (s *Spinner) Stop() {
 	if s.stopped_bool {
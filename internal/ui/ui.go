@@ -107,6 +107,13 @@ func Warning(msg string) {
 	fmt.Println(YellowStyle.Render(IconWarning) + " " + msg)
 }
 
+// StderrWarning prints a warning to stderr, keeping it out of a command's
+// output when that is piped, e.g. env export
+func StderrWarning(msg string) {
+	trace("StderrWarning")
+	fmt.Fprintln(os.Stderr, YellowStyle.Render(IconWarning)+" "+msg)
+}
+
 func Info(msg string) {
 	trace("Info")
 	fmt.Println(CyanStyle.Render(IconDot) + " " + msg)
@@ -177,7 +184,11 @@ func Table(headers []string, rows [][]string) {
 		Dim("No data to display")
 		return
 	}
+	writeTable(os.Stdout, headers, rows)
+}
 
+// writeTable prints the rows of a table to w, aligned under headers
+func writeTable(w io.Writer, headers []string, rows [][]string) {
 	widths := make([]int, len(headers))
 	for i, h := range headers {
 		widths[i] = len(h)
@@ -197,16 +208,16 @@ func Table(headers []string, rows [][]string) {
 		}
 		headerLine += fmt.Sprintf("%-*s", widths[i], h)
 	}
-	fmt.Println(headerLine)
+	fmt.Fprintln(w, headerLine)
 
 	totalWidth := 0
-	for i, w := range widths {
-		totalWidth += w
+	for i, width := range widths {
+		totalWidth += width
 		if i > 0 {
 			totalWidth += 2
 		}
 	}
-	fmt.Println(strings.Repeat("-", totalWidth))
+	fmt.Fprintln(w, strings.Repeat("-", totalWidth))
 
 	for _, row := range rows {
 		rowLine := ""
@@ -218,17 +229,98 @@ func Table(headers []string, rows [][]string) {
 				rowLine += fmt.Sprintf("%-*s", widths[i], cell)
 			}
 		}
-		fmt.Println(rowLine)
+		fmt.Fprintln(w, rowLine)
 	}
 }
 
+// Sparkline renders values as a compact block-character chart
+func Sparkline(values []float64) string {
+	if len(values) == 0 {
+		return ""
+	}
+	blocks := []rune("▁▂▃▄▅▆▇█")
+
+	min, max := values[0], values[0]
+	for _, v := range values {
+		if v < min {
+			min = v
+		}
+		if v > max {
+			max = v
+		}
+	}
+
+	var b strings.Builder
+	for _, v := range values {
+		idx := 0
+		if max > min {
+			idx = int((v - min) / (max - min) * float64(len(blocks)-1))
+		}
+		b.WriteRune(blocks[idx])
+	}
+	return b.String()
+}
+
 // --- Prompt Functions (GitHub CLI style using survey) ---
 
+// AssumeYes answers plain confirmation prompts with yes (set by --yes).
+// Prompts that require a typed phrase are never skipped.
+var AssumeYes bool
+
+// ConfirmOptions controls how a confirmation prompt looks and behaves
+type ConfirmOptions struct {
+	Default bool   // Answer used when the user just presses enter
+	Danger  bool   // Render with destructive styling
+	Phrase  string // If set, the user must type this phrase to confirm
+}
+
 func Confirm(prompt string) (bool, error) {
+	return ConfirmWithOptions(prompt, ConfirmOptions{})
+}
+
+// ConfirmDanger asks for confirmation of a destructive operation.
+// If phrase is non-empty the user must type it exactly.
+func ConfirmDanger(prompt, phrase string) (bool, error) {
+	return ConfirmWithOptions(prompt, ConfirmOptions{Danger: true, Phrase: phrase})
+}
+
+func ConfirmWithOptions(prompt string, opts ConfirmOptions) (bool, error) {
+	if opts.Danger {
+		fmt.Println(RedStyle.Bold(true).Render(IconWarning + " " + prompt))
+	}
+
+	if AssumeYes && opts.Phrase == "" {
+		LogChoice(prompt, "yes (--yes)")
+		return true, nil
+	}
+
+	if opts.Phrase != "" {
+		var typed string
+		err := ask(&survey.Input{
+			Message: fmt.Sprintf("Type %s to confirm", opts.Phrase),
+		}, &typed, surveyIcons)
+		if err != nil {
+			if err == terminal.InterruptErr {
+				return false, fmt.Errorf("interrupted")
+			}
+			return false, err
+		}
+		if strings.TrimSpace(typed) != opts.Phrase {
+			Dim("Confirmation did not match")
+			return false, nil
+		}
+		return true, nil
+	}
+
+	message := prompt
+	if opts.Danger {
+		message = "Are you sure?"
+	}
+
 	var value bool
-	err := survey.AskOne(&survey.Confirm{
-		Message: prompt,
-		Default: false,
+	err := ask(&survey.Confirm{
+		Message: message,
+		Default: opts.Default,
 	}, &value, surveyIcons)
 
 	if err != nil {
@@ -243,7 +335,7 @@ func Confirm(prompt string) (bool, error) {
 
 func Input(prompt, placeholder string) (string, error) {
 	var value string
-	err := survey.AskOne(&survey.Input{
+	err := ask(&survey.Input{
 		Message: prompt,
 		Default: placeholder,
 	}, &value, surveyIcons)
@@ -261,7 +353,7 @@ func Input(prompt, placeholder string) (string, error) {
 
 func InputWithDefault(prompt, defaultValue string) (string, error) {
 	var value string
-	err := survey.AskOne(&survey.Input{
+	err := ask(&survey.Input{
 		Message: prompt,
 		Default: defaultValue,
 	}, &value, surveyIcons)
@@ -281,7 +373,7 @@ func InputWithDefault(prompt, defaultValue string) (string, error) {
 
 func Password(prompt string) (string, error) {
 	var value string
-	err := survey.AskOne(&survey.Password{
+	err := ask(&survey.Password{
 		Message: prompt,
 	}, &value, surveyIcons)
 
@@ -301,7 +393,7 @@ func Select(prompt string, options []string) (string, error) {
 	}
 
 	var value string
-	err := survey.AskOne(&survey.Select{
+	err := ask(&survey.Select{
 		Message: prompt,
 		Options: options,
 	}, &value, surveyIcons)
@@ -332,7 +424,7 @@ func SelectWithKeys(prompt string, options map[string]string) (string, error) {
 	}
 
 	var selected string
-	err := survey.AskOne(&survey.Select{
+	err := ask(&survey.Select{
 		Message: prompt,
 		Options: displayOptions,
 	}, &selected, surveyIcons)
@@ -362,7 +454,7 @@ func SelectWithKeysOrdered(prompt string, options []struct{ Key, Display string
 	}
 
 	var selected string
-	err := survey.AskOne(&survey.Select{
+	err := ask(&survey.Select{
 		Message: prompt,
 		Options: displayOptions,
 	}, &selected, surveyIcons)
@@ -383,7 +475,7 @@ func MultiSelect(prompt string, options []string) ([]string, error) {
 	}
 
 	var values []string
-	err := survey.AskOne(&survey.MultiSelect{
+	err := ask(&survey.MultiSelect{
 		Message: prompt,
 		Options: options,
 	}, &values, surveyIcons)
@@ -399,9 +491,7 @@ func MultiSelect(prompt string, options []string) ([]string, error) {
 }
 
 func ConfirmAction(action, resource string) (bool, error) {
-	Warning(fmt.Sprintf("This will %s: %s", action, resource))
-	Spacer()
-	return Confirm(fmt.Sprintf("Are you sure you want to %s?", action))
+	return ConfirmDanger(fmt.Sprintf("This will %s: %s", action, resource), "")
 }
 
 // --- Log Stream ---
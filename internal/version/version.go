@@ -0,0 +1,51 @@
+package version
+
+import (
+	"strconv"
+	"strings"
+)
+
+// IsRelease reports whether v looks like a tagged release rather than a dev build
+func IsRelease(v string) bool {
+	v = strings.TrimSpace(v)
+	return v != "" && v != "dev"
+}
+
+// Compare compares two semantic versions like "v1.2.3".
+// Returns -1 if a < b, 0 if equal, and 1 if a > b.
+func Compare(a, b string) int {
+	pa := parse(a)
+	pb := parse(b)
+	for i := 0; i < 3; i++ {
+		if pa[i] != pb[i] {
+			if pa[i] > pb[i] {
+				return 1
+			}
+			return -1
+		}
+	}
+	return 0
+}
+
+// IsNewer reports whether latest is newer than current.
+// Dev builds are always considered out of date.
+func IsNewer(latest, current string) bool {
+	if !IsRelease(current) {
+		return true
+	}
+	return Compare(latest, current) > 0
+}
+
+// parse parses "v1.2.3" into its numeric components
+func parse(v string) [3]int {
+	var parts [3]int
+	v = strings.TrimPrefix(strings.TrimSpace(v), "v")
+	if i := strings.IndexAny(v, "-+"); i >= 0 {
+		v = v[:i]
+	}
+	for i, p := range strings.SplitN(v, ".", 3) {
+		n, _ := strconv.Atoi(p)
+		parts[i] = n
+	}
+	return parts
+}